@@ -21,6 +21,15 @@ import (
 // Hence. keeping the map as a string->interface and not string->string.
 // At the moment, FieldDefinitions could be an overkill, so keeping it out.
 
+// The modes in which a user is notified by email of the changes of what the
+// user watches
+const (
+	NotificationModeImmediate = "immediate"
+	NotificationModeHourly    = "hourly"
+	NotificationModeDaily     = "daily"
+	NotificationModeNone      = "none"
+)
+
 // User describes a User account. A few identities can be assosiated with one user account
 type User struct {
 	gormsupport.Lifecycle
@@ -33,6 +42,9 @@ type User struct {
 	Company            string          // The (optional) Company of the User
 	Identities         []Identity      // has many Identities from different IDPs
 	ContextInformation workitem.Fields `sql:"type:jsonb"` // context information of the user activity
+	// NotificationMode tells how the user is notified of the changes of what
+	// the user watches: immediately, in an hourly or daily digest, or not at all
+	NotificationMode string `sql:"default:'immediate'"`
 }
 
 // TableName overrides the table name settings in Gorm to force a specific table name
@@ -98,6 +110,9 @@ func (m *GormUserRepository) Create(ctx context.Context, u *User) error {
 	if u.ID == uuid.Nil {
 		u.ID = uuid.NewV4()
 	}
+	if u.NotificationMode == "" {
+		u.NotificationMode = NotificationModeImmediate
+	}
 	err := m.db.Create(u).Error
 	if err != nil {
 		log.Error(ctx, map[string]interface{}{
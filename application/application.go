@@ -8,9 +8,15 @@ import (
 
 	"github.com/fabric8io/almighty-core/comment"
 	"github.com/fabric8io/almighty-core/iteration"
+	"github.com/fabric8io/almighty-core/label"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/reaction"
 	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/subscription"
+	"github.com/fabric8io/almighty-core/webhook"
 	"github.com/fabric8io/almighty-core/workitem"
 	"github.com/fabric8io/almighty-core/workitem/link"
+	"github.com/fabric8io/almighty-core/workitem/template"
 )
 
 //An Application stands for a particular implementation of the business logic of our application
@@ -32,6 +38,15 @@ type Application interface {
 	Areas() area.Repository
 	OauthStates() auth.OauthStateReferenceRepository
 	Codebases() codebase.Repository
+	WorkItemRevisions() workitem.RevisionRepository
+	Labels() label.Repository
+	WorkItemTemplates() template.Repository
+	ChecklistItems() workitem.ChecklistRepository
+	Subscriptions() subscription.Repository
+	Webhooks() webhook.Repository
+	WebhookDeliveries() webhook.DeliveryRepository
+	Mentions() mention.Repository
+	Reactions() reaction.Repository
 }
 
 // A Transaction abstracts a database transaction. The repositories created for the transaction object make changes inside the the transaction
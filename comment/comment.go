@@ -8,6 +8,10 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+// MaxReplyDepth is how deep replies can be nested: a reply to a comment has a
+// depth of 1, a reply to that reply a depth of 2, and so on.
+const MaxReplyDepth = 3
+
 // Comment describes a single comment
 type Comment struct {
 	gormsupport.Lifecycle
@@ -16,6 +20,8 @@ type Comment struct {
 	CreatedBy uuid.UUID `sql:"type:uuid"` // Belongs To Identity
 	Body      string
 	Markup    string
+	// ReplyTo is the comment this comment replies to, if any
+	ReplyTo *uuid.UUID `sql:"type:uuid"`
 }
 
 // GetETagData returns the field values to use to generate the ETag
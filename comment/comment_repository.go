@@ -2,11 +2,15 @@ package comment
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
 	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/mention"
 	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/subscription"
 	"github.com/goadesign/goa"
 	"github.com/jinzhu/gorm"
 
@@ -19,7 +23,13 @@ type Repository interface {
 	Create(ctx context.Context, comment *Comment, creator uuid.UUID) error
 	Save(ctx context.Context, comment *Comment, modifier uuid.UUID) error
 	Delete(ctx context.Context, commentID uuid.UUID, suppressor uuid.UUID) error
+	DeleteByParent(ctx context.Context, parent string, suppressor uuid.UUID) error
+	Restore(ctx context.Context, commentID uuid.UUID, restorer uuid.UUID) (*Comment, error)
+	RestoreByParent(ctx context.Context, parent string, deletedSince time.Time, restorer uuid.UUID) error
+	ListDeleted(ctx context.Context, spaceID uuid.UUID, since *time.Time, start *int, limit *int) ([]Comment, uint64, error)
 	List(ctx context.Context, parent string, start *int, limit *int) ([]Comment, uint64, error)
+	ListThreads(ctx context.Context, parent string, start *int, limit *int) ([]Comment, uint64, error)
+	ListReplies(ctx context.Context, ids ...uuid.UUID) ([]Comment, error)
 	Load(ctx context.Context, id uuid.UUID) (*Comment, error)
 	Count(ctx context.Context, parent string) (int, error)
 }
@@ -49,6 +59,11 @@ func (m *GormCommentRepository) Create(ctx context.Context, comment *Comment, cr
 	if comment.Markup == "" {
 		comment.Markup = rendering.SystemMarkupDefault
 	}
+	if comment.ReplyTo != nil {
+		if err := m.checkReplyTo(ctx, *comment); err != nil {
+			return err
+		}
+	}
 	if err := m.db.Create(comment).Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
 			"comment_id": comment.ID,
@@ -60,6 +75,17 @@ func (m *GormCommentRepository) Create(ctx context.Context, comment *Comment, cr
 	if err := m.revisionRepository.Create(ctx, creatorID, RevisionTypeCreate, *comment); err != nil {
 		return errs.Wrapf(err, "error while creating comment")
 	}
+	// the commenter watches the commented work item from now on
+	if err := subscription.NewRepository(m.db).WatchWorkItem(ctx, comment.ParentID, []uuid.UUID{creatorID}, subscription.ReasonCommenter); err != nil {
+		return errs.Wrapf(err, "error while subscribing the commenter")
+	}
+	added := event.CommentAdded{CommentID: comment.ID, WorkItemID: comment.ParentID, ReplyTo: comment.ReplyTo, Body: comment.Body, Markup: comment.Markup}
+	if err := event.Record(ctx, m.db, m.spaceOf(comment.ParentID), creatorID, added); err != nil {
+		return errs.Wrapf(err, "error while creating comment")
+	}
+	if err := m.mention(ctx, *comment, comment.ParentID, creatorID); err != nil {
+		return errs.Wrapf(err, "error while creating comment")
+	}
 	log.Debug(ctx, map[string]interface{}{
 		"comment_id": comment.ID,
 	}, "Comment created!")
@@ -67,6 +93,35 @@ func (m *GormCommentRepository) Create(ctx context.Context, comment *Comment, cr
 	return nil
 }
 
+// checkReplyTo checks that the comment the given comment replies to belongs to
+// the same work item, and that the reply is not nested too deep
+// returns BadParameterError or InternalError
+func (m *GormCommentRepository) checkReplyTo(ctx context.Context, c Comment) error {
+	replied := Comment{}
+	tx := m.db.Where("id = ?", *c.ReplyTo).First(&replied)
+	if tx.RecordNotFound() || (tx.Error == nil && replied.ParentID != c.ParentID) {
+		return errors.NewBadParameterError("replyTo", *c.ReplyTo).Expected("comment of the same work item")
+	}
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	// the depth of the new reply is the number of comments it replies to up
+	// the thread, i.e. 1 for a reply to a comment which is not a reply itself
+	var depth int
+	err := m.db.Raw(`WITH RECURSIVE thread AS (
+			SELECT id, reply_to, 1 AS depth FROM comments WHERE id = ?
+			UNION ALL
+			SELECT c.id, c.reply_to, t.depth + 1 FROM comments c JOIN thread t ON c.id = t.reply_to
+		) SELECT max(depth) FROM thread`, replied.ID).Row().Scan(&depth)
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	if depth > MaxReplyDepth {
+		return errors.NewBadParameterError("replyTo", *c.ReplyTo).Expected(fmt.Sprintf("reply nested at most %d replies deep", MaxReplyDepth))
+	}
+	return nil
+}
+
 // Save a single comment
 func (m *GormCommentRepository) Save(ctx context.Context, comment *Comment, modifierID uuid.UUID) error {
 	c := Comment{}
@@ -90,6 +145,8 @@ func (m *GormCommentRepository) Save(ctx context.Context, comment *Comment, modi
 	if comment.Markup == "" {
 		comment.Markup = rendering.SystemMarkupDefault
 	}
+	// a comment stays in its thread
+	comment.ReplyTo = c.ReplyTo
 	tx = tx.Save(comment)
 	if err := tx.Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -103,6 +160,13 @@ func (m *GormCommentRepository) Save(ctx context.Context, comment *Comment, modi
 	if err := m.revisionRepository.Create(ctx, modifierID, RevisionTypeUpdate, *comment); err != nil {
 		return errs.Wrapf(err, "error while saving work item")
 	}
+	updated := event.CommentUpdated{CommentID: comment.ID, WorkItemID: c.ParentID, Body: comment.Body, Markup: comment.Markup}
+	if err := event.Record(ctx, m.db, m.spaceOf(c.ParentID), modifierID, updated); err != nil {
+		return errs.Wrapf(err, "error while saving comment")
+	}
+	if err := m.mention(ctx, *comment, c.ParentID, modifierID); err != nil {
+		return errs.Wrapf(err, "error while saving comment")
+	}
 	log.Debug(ctx, map[string]interface{}{
 		"comment_id": comment.ID,
 	}, "Comment updated!")
@@ -110,7 +174,7 @@ func (m *GormCommentRepository) Save(ctx context.Context, comment *Comment, modi
 	return nil
 }
 
-// Delete a single comment
+// Delete a single comment, along with its replies
 func (m *GormCommentRepository) Delete(ctx context.Context, commentID uuid.UUID, suppressorID uuid.UUID) error {
 	if commentID == uuid.Nil {
 		return errors.NewNotFoundError("comment", commentID.String())
@@ -118,25 +182,229 @@ func (m *GormCommentRepository) Delete(ctx context.Context, commentID uuid.UUID,
 	// fetch the id and parent id of the comment to delete, to store them in the new revision.
 	c := Comment{}
 	tx := m.db.Select("id, parent_id").Where("id = ?", commentID).Find(&c)
-	m.db.Delete(c)
 	if tx.RowsAffected == 0 {
 		return errors.NewNotFoundError("comment", commentID.String())
 	}
 	if err := tx.Error; err != nil {
 		return errors.NewInternalError(err)
 	}
+	replies, err := m.ListReplies(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if err := m.delete(ctx, c, suppressorID); err != nil {
+		return err
+	}
+	for _, reply := range replies {
+		if err := m.delete(ctx, reply, suppressorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteByParent deletes all the comments of the given parent, e.g. when the
+// parent work item is deleted
+func (m *GormCommentRepository) DeleteByParent(ctx context.Context, parent string, suppressorID uuid.UUID) error {
+	comments := []Comment{}
+	if err := m.db.Select("id, parent_id").Where("parent_id = ?", parent).Find(&comments).Error; err != nil {
+		return errors.NewInternalError(err)
+	}
+	for _, c := range comments {
+		if err := m.delete(ctx, c, suppressorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *GormCommentRepository) delete(ctx context.Context, c Comment, suppressorID uuid.UUID) error {
+	if err := m.db.Delete(c).Error; err != nil {
+		return errors.NewInternalError(err)
+	}
 	// save a revision of the deleted comment
 	if err := m.revisionRepository.Create(ctx, suppressorID, RevisionTypeDelete, c); err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	deleted := event.CommentDeleted{CommentID: c.ID, WorkItemID: c.ParentID}
+	if err := event.Record(ctx, m.db, m.spaceOf(c.ParentID), suppressorID, deleted); err != nil {
+		return errs.Wrapf(err, "error while deleting comment")
+	}
+	return nil
+}
+
+// mention stores the mentions of users in the body of the given comment of
+// the work item with the given ID
+func (m *GormCommentRepository) mention(ctx context.Context, c Comment, parentID string, actorID uuid.UUID) error {
+	target := mention.Target{SpaceID: m.spaceOf(parentID), WorkItemID: parentID, Kind: mention.KindComment, ID: c.ID.String()}
+	return mention.NewRepository(m.db).Sync(ctx, target, c.Body, c.Markup, actorID)
+}
+
+// spaceOf returns the space of the work item with the given ID, even if the
+// work item was deleted, or uuid.Nil if there is no such work item
+func (m *GormCommentRepository) spaceOf(parentID string) uuid.UUID {
+	var spaceID uuid.UUID
+	row := m.db.Raw("SELECT space_id FROM work_items WHERE id::text = ?", parentID).Row()
+	if err := row.Scan(&spaceID); err != nil {
+		return uuid.Nil
+	}
+	return spaceID
+}
+
+// Restore brings back a single soft-deleted comment
+func (m *GormCommentRepository) Restore(ctx context.Context, commentID uuid.UUID, restorerID uuid.UUID) (*Comment, error) {
+	c := Comment{}
+	tx := m.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", commentID).First(&c)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("deleted comment", commentID.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	if c.ReplyTo != nil {
+		var count int
+		if err := m.db.Model(&Comment{}).Where("id = ?", *c.ReplyTo).Count(&count).Error; err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		if count == 0 {
+			// the reply can only be restored along with the comment it replies to
+			return nil, errors.NewBadParameterError("commentID", commentID).Expected("reply to a comment which is not deleted")
+		}
+	}
+	// the replies which were deleted along with the comment are restored too
+	replies, err := m.replies(ctx, c.DeletedAt, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.restore(ctx, &c, restorerID); err != nil {
+		return nil, err
+	}
+	for i := range replies {
+		if err := m.restore(ctx, &replies[i], restorerID); err != nil {
+			return nil, err
+		}
+	}
+	return &c, nil
+}
+
+// RestoreByParent brings back the comments of the given parent which were
+// deleted at or after the given time, i.e. along with their parent
+func (m *GormCommentRepository) RestoreByParent(ctx context.Context, parent string, deletedSince time.Time, restorerID uuid.UUID) error {
+	comments := []Comment{}
+	tx := m.db.Unscoped().Where("parent_id = ? AND deleted_at >= ?", parent, deletedSince).Order("created_at").Find(&comments)
+	if err := tx.Error; err != nil {
+		return errors.NewInternalError(err)
+	}
+	// restore one by one to trigger the creation of a new comment revision
+	for i := range comments {
+		if err := m.restore(ctx, &comments[i], restorerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListDeleted returns the deleted comments of the work items of the given
+// space, most recently deleted first. If since is given, only the comments
+// deleted after that time are returned.
+func (m *GormCommentRepository) ListDeleted(ctx context.Context, spaceID uuid.UUID, since *time.Time, start *int, limit *int) ([]Comment, uint64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "deleted"}, time.Now())
+	db := m.db.Unscoped().Model(&Comment{}).Where("deleted_at IS NOT NULL AND parent_id IN (SELECT id::text FROM work_items WHERE space_id = ?)", spaceID)
+	if since != nil {
+		db = db.Where("deleted_at > ?", *since)
+	}
+	var count uint64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to count deleted comments"))
+	}
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		db = db.Offset(*start)
+	}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		db = db.Limit(*limit)
+	}
+	result := []Comment{}
+	if err := db.Order("deleted_at desc").Find(&result).Error; err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to list deleted comments"))
+	}
+	return result, count, nil
+}
+
+func (m *GormCommentRepository) restore(ctx context.Context, c *Comment, restorerID uuid.UUID) error {
+	c.DeletedAt = nil
+	if err := m.db.Unscoped().Model(c).Update("deleted_at", nil).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"comment_id": c.ID,
+			"err":        err,
+		}, "unable to restore the comment")
+		return errors.NewInternalError(err)
+	}
+	// save a revision of the restored comment
+	if err := m.revisionRepository.Create(ctx, restorerID, RevisionTypeRestore, *c); err != nil {
+		return errs.Wrapf(err, "error while restoring comment")
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"comment_id": c.ID,
+	}, "Comment restored!")
 	return nil
 }
 
 // List all comments related to a single item
 func (m *GormCommentRepository) List(ctx context.Context, parent string, start *int, limit *int) ([]Comment, uint64, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "comment", "query"}, time.Now())
+	return m.list(ctx, m.db.Model(&Comment{}).Where("parent_id = ?", parent), start, limit)
+}
+
+// ListThreads lists the comments related to a single item which are not
+// replies, latest first. ListReplies returns their replies.
+func (m *GormCommentRepository) ListThreads(ctx context.Context, parent string, start *int, limit *int) ([]Comment, uint64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "threads"}, time.Now())
+	return m.list(ctx, m.db.Model(&Comment{}).Where("parent_id = ? AND reply_to IS NULL", parent), start, limit)
+}
+
+// ListReplies returns the replies to the comments with the given IDs, the
+// replies to these replies and so on, oldest first
+// returns InternalError
+func (m *GormCommentRepository) ListReplies(ctx context.Context, ids ...uuid.UUID) ([]Comment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "replies"}, time.Now())
+	return m.replies(ctx, nil, ids...)
+}
+
+// replies returns the replies to the comments with the given IDs, the
+// replies to these replies and so on, oldest first. The replies are the ones
+// which are not deleted, or the ones which were deleted at or after the given
+// time if it is not nil.
+func (m *GormCommentRepository) replies(ctx context.Context, deletedSince *time.Time, ids ...uuid.UUID) ([]Comment, error) {
+	result := []Comment{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+	condition := "deleted_at IS NULL"
+	args := []interface{}{ids}
+	if deletedSince != nil {
+		condition = "deleted_at >= ?"
+		args = []interface{}{ids, *deletedSince, *deletedSince}
+	}
+	err := m.db.Raw(`WITH RECURSIVE replies AS (
+			SELECT * FROM comments WHERE reply_to IN (?) AND `+condition+`
+			UNION ALL
+			SELECT c.* FROM comments c JOIN replies r ON c.reply_to = r.id WHERE c.`+condition+`
+		) SELECT * FROM replies ORDER BY created_at`, args...).Scan(&result).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return result, nil
+}
 
-	db := m.db.Model(&Comment{}).Where("parent_id = ?", parent)
+// list returns the comments selected by the given query, latest first, along
+// with their total count
+func (m *GormCommentRepository) list(ctx context.Context, db *gorm.DB, start *int, limit *int) ([]Comment, uint64, error) {
 	orgDB := db
 	if start != nil {
 		if *start < 0 {
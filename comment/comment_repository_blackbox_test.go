@@ -8,6 +8,7 @@ import (
 
 	"github.com/fabric8io/almighty-core/account"
 	"github.com/fabric8io/almighty-core/comment"
+	"github.com/fabric8io/almighty-core/errors"
 	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
 	"github.com/fabric8io/almighty-core/gormtestsupport"
 	"github.com/fabric8io/almighty-core/migration"
@@ -15,6 +16,7 @@ import (
 	"github.com/fabric8io/almighty-core/resource"
 	testsupport "github.com/fabric8io/almighty-core/test"
 
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -222,3 +224,157 @@ func (s *TestCommentRepository) TestLoadComment() {
 	assert.Equal(s.T(), comment.ID, loadedComment.ID)
 	assert.Equal(s.T(), comment.Body, loadedComment.Body)
 }
+
+func (s *TestCommentRepository) TestRestoreComment() {
+	// given
+	c := newComment("AA", "Test AA", rendering.SystemMarkupMarkdown)
+	s.createComment(c, s.testIdentity.ID)
+	require.Nil(s.T(), s.repo.Delete(s.ctx, c.ID, s.testIdentity.ID))
+	// when
+	restored, err := s.repo.Restore(s.ctx, c.ID, s.testIdentity.ID)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), "Test AA", restored.Body)
+	loaded, err := s.repo.Load(s.ctx, c.ID)
+	require.Nil(s.T(), err)
+	assert.Nil(s.T(), loaded.DeletedAt)
+	// a comment which is not deleted cannot be restored
+	_, err = s.repo.Restore(s.ctx, c.ID, s.testIdentity.ID)
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+}
+
+func (s *TestCommentRepository) TestRestoreCommentsByParent() {
+	// given
+	parentID := "AB"
+	earlier := newComment(parentID, "deleted earlier", rendering.SystemMarkupMarkdown)
+	later := newComment(parentID, "deleted later", rendering.SystemMarkupMarkdown)
+	s.createComments([]*comment.Comment{earlier, later}, s.testIdentity.ID)
+	require.Nil(s.T(), s.repo.Delete(s.ctx, earlier.ID, s.testIdentity.ID))
+	deletedSince := time.Now()
+	require.Nil(s.T(), s.repo.DeleteByParent(s.ctx, parentID, s.testIdentity.ID))
+	_, count, err := s.repo.List(s.ctx, parentID, nil, nil)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), uint64(0), count)
+	// when
+	err = s.repo.RestoreByParent(s.ctx, parentID, deletedSince, s.testIdentity.ID)
+	// then only the comment deleted along with the parent is restored
+	require.Nil(s.T(), err)
+	comments, count, err := s.repo.List(s.ctx, parentID, nil, nil)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), uint64(1), count)
+	require.Len(s.T(), comments, 1)
+	assert.Equal(s.T(), later.ID, comments[0].ID)
+}
+
+func newReply(replyTo *comment.Comment, body string) *comment.Comment {
+	c := newComment(replyTo.ParentID, body, rendering.SystemMarkupMarkdown)
+	c.ReplyTo = &replyTo.ID
+	return c
+}
+
+func (s *TestCommentRepository) TestReplies() {
+	s.T().Run("create reply", func(t *testing.T) {
+		// given
+		c := newComment("AC", "thread", rendering.SystemMarkupMarkdown)
+		s.createComment(c, s.testIdentity.ID)
+		// when
+		reply := newReply(c, "reply")
+		err := s.repo.Create(s.ctx, reply, s.testIdentity.ID)
+		// then
+		require.Nil(t, err)
+		loaded, err := s.repo.Load(s.ctx, reply.ID)
+		require.Nil(t, err)
+		require.NotNil(t, loaded.ReplyTo)
+		assert.Equal(t, c.ID, *loaded.ReplyTo)
+	})
+
+	s.T().Run("reply to a comment of another work item", func(t *testing.T) {
+		// given
+		c := newComment("AD", "thread", rendering.SystemMarkupMarkdown)
+		s.createComment(c, s.testIdentity.ID)
+		// when
+		reply := newComment("AE", "reply", rendering.SystemMarkupMarkdown)
+		reply.ReplyTo = &c.ID
+		err := s.repo.Create(s.ctx, reply, s.testIdentity.ID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("reply nested as deep as allowed", func(t *testing.T) {
+		// given
+		c := newComment("AI", "thread", rendering.SystemMarkupMarkdown)
+		s.createComment(c, s.testIdentity.ID)
+		for i := 1; i < comment.MaxReplyDepth; i++ {
+			reply := newReply(c, "reply")
+			s.createComment(reply, s.testIdentity.ID)
+			c = reply
+		}
+		// when
+		err := s.repo.Create(s.ctx, newReply(c, "deepest"), s.testIdentity.ID)
+		// then
+		require.Nil(t, err)
+	})
+
+	s.T().Run("reply nested too deep", func(t *testing.T) {
+		// given
+		c := newComment("AF", "thread", rendering.SystemMarkupMarkdown)
+		s.createComment(c, s.testIdentity.ID)
+		for i := 0; i < comment.MaxReplyDepth; i++ {
+			reply := newReply(c, "reply")
+			s.createComment(reply, s.testIdentity.ID)
+			c = reply
+		}
+		// when
+		err := s.repo.Create(s.ctx, newReply(c, "too deep"), s.testIdentity.ID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("list threads and replies", func(t *testing.T) {
+		// given
+		first := newComment("AG", "first", rendering.SystemMarkupMarkdown)
+		second := newComment("AG", "second", rendering.SystemMarkupMarkdown)
+		s.createComments([]*comment.Comment{first, second}, s.testIdentity.ID)
+		reply := newReply(first, "reply")
+		s.createComment(reply, s.testIdentity.ID)
+		nested := newReply(reply, "nested reply")
+		s.createComment(nested, s.testIdentity.ID)
+		// when
+		threads, count, err := s.repo.ListThreads(s.ctx, "AG", nil, nil)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, uint64(2), count)
+		require.Len(t, threads, 2)
+		// when
+		replies, err := s.repo.ListReplies(s.ctx, first.ID, second.ID)
+		// then
+		require.Nil(t, err)
+		require.Len(t, replies, 2)
+		assert.Equal(t, reply.ID, replies[0].ID)
+		assert.Equal(t, nested.ID, replies[1].ID)
+	})
+
+	s.T().Run("delete and restore a thread", func(t *testing.T) {
+		// given
+		c := newComment("AH", "thread", rendering.SystemMarkupMarkdown)
+		s.createComment(c, s.testIdentity.ID)
+		reply := newReply(c, "reply")
+		s.createComment(reply, s.testIdentity.ID)
+		// when
+		err := s.repo.Delete(s.ctx, c.ID, s.testIdentity.ID)
+		// then
+		require.Nil(t, err)
+		_, err = s.repo.Load(s.ctx, reply.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		// a reply cannot be restored while the replied comment is deleted
+		_, err = s.repo.Restore(s.ctx, reply.ID, s.testIdentity.ID)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		// when
+		_, err = s.repo.Restore(s.ctx, c.ID, s.testIdentity.ID)
+		// then
+		require.Nil(t, err)
+		loaded, err := s.repo.Load(s.ctx, reply.ID)
+		require.Nil(t, err)
+		assert.Nil(t, loaded.DeletedAt)
+	})
+}
@@ -18,6 +18,8 @@ const (
 	_                  // ignore 3rd value
 	// RevisionTypeUpdate a comment update
 	RevisionTypeUpdate // 4
+	// RevisionTypeRestore a comment restoration after its deletion
+	RevisionTypeRestore // 5
 )
 
 // Revision represents a version of a comment
@@ -45,6 +45,17 @@ const (
 	varPostgresConnectionMaxOpen        = "postgres.connection.maxopen"
 	varFeatureWorkitemRemote            = "feature.workitem.remote"
 	varPopulateCommonTypes              = "populate.commontypes"
+	varEventRelayInterval               = "event.relay.interval"
+	varWebhookDeliveryInterval          = "webhook.delivery.interval"
+	varWebhookDeliveryTimeout           = "webhook.delivery.timeout"
+	varWebhookDeliveryBackoff           = "webhook.delivery.backoff"
+	varWebhookDeliveryMaxAttempts       = "webhook.delivery.maxattempts"
+	varNotificationEnabled              = "notification.enabled"
+	varNotificationFrom                 = "notification.from"
+	varNotificationSMTPAddress          = "notification.smtp.address"
+	varNotificationSMTPUsername         = "notification.smtp.username"
+	varNotificationSMTPPassword         = "notification.smtp.password"
+	varNotificationFileDir              = "notification.file.dir"
 	varHTTPAddress                      = "http.address"
 	varDeveloperModeEnabled             = "developer.mode.enabled"
 	varGithubAuthToken                  = "github.auth.token"
@@ -78,6 +89,8 @@ const (
 	varCacheControlSpaces               = "cachecontrol.spaces"
 	varCacheControlIterations           = "cachecontrol.iterations"
 	varCacheControlAreas                = "cachecontrol.areas"
+	varCacheControlLabels               = "cachecontrol.labels"
+	varCacheControlWorkItemTemplates    = "cachecontrol.workitemtemplates"
 	varCacheControlComments             = "cachecontrol.comments"
 	varCacheControlFilters              = "cachecontrol.filters"
 	varCacheControlUsers                = "cachecontrol.users"
@@ -193,6 +206,8 @@ func (c *ConfigurationData) setConfigDefaults() {
 	c.v.SetDefault(varCacheControlSpaces, "max-age=2")
 	c.v.SetDefault(varCacheControlIterations, "max-age=2")
 	c.v.SetDefault(varCacheControlAreas, "max-age=2")
+	c.v.SetDefault(varCacheControlLabels, "max-age=2")
+	c.v.SetDefault(varCacheControlWorkItemTemplates, "max-age=2")
 	c.v.SetDefault(varCacheControlComments, "max-age=2")
 	c.v.SetDefault(varCacheControlFilters, "max-age=86400")
 	c.v.SetDefault(varCacheControlUsers, "max-age=2")
@@ -204,6 +219,20 @@ func (c *ConfigurationData) setConfigDefaults() {
 	// Features
 	c.v.SetDefault(varFeatureWorkitemRemote, false)
 
+	// Events
+	c.v.SetDefault(varEventRelayInterval, time.Duration(10*time.Second))
+
+	// Webhooks
+	c.v.SetDefault(varWebhookDeliveryInterval, time.Duration(10*time.Second))
+	c.v.SetDefault(varWebhookDeliveryTimeout, time.Duration(10*time.Second))
+	c.v.SetDefault(varWebhookDeliveryBackoff, time.Duration(30*time.Second))
+	c.v.SetDefault(varWebhookDeliveryMaxAttempts, 8)
+
+	// Notifications
+	c.v.SetDefault(varNotificationEnabled, false)
+	c.v.SetDefault(varNotificationFrom, "almighty@localhost")
+	c.v.SetDefault(varNotificationSMTPAddress, "localhost:25")
+
 	c.v.SetDefault(varKeycloakTesUser2Name, defaultKeycloakTesUser2Name)
 	c.v.SetDefault(varKeycloakTesUser2Secret, defaultKeycloakTesUser2Secret)
 	c.v.SetDefault(varOpenshiftTenantMasterURL, defaultOpenshiftTenantMasterURL)
@@ -225,6 +254,80 @@ func (c *ConfigurationData) GetFeatureWorkitemRemote() bool {
 	return c.v.GetBool(varFeatureWorkitemRemote)
 }
 
+// GetEventRelayInterval returns the interval at which the domain events which
+// were not published after their commit are published (as set via default,
+// config file, or environment variable)
+func (c *ConfigurationData) GetEventRelayInterval() time.Duration {
+	return c.v.GetDuration(varEventRelayInterval)
+}
+
+// GetWebhookDeliveryInterval returns the interval at which the webhook
+// deliveries which are due are sent (as set via default, config file, or
+// environment variable)
+func (c *ConfigurationData) GetWebhookDeliveryInterval() time.Duration {
+	return c.v.GetDuration(varWebhookDeliveryInterval)
+}
+
+// GetWebhookDeliveryTimeout returns the time to wait for the response of a
+// webhook (as set via default, config file, or environment variable)
+func (c *ConfigurationData) GetWebhookDeliveryTimeout() time.Duration {
+	return c.v.GetDuration(varWebhookDeliveryTimeout)
+}
+
+// GetWebhookDeliveryBackoff returns the time to wait before retrying a failed
+// webhook delivery for the first time, it doubles with each further attempt
+// (as set via default, config file, or environment variable)
+func (c *ConfigurationData) GetWebhookDeliveryBackoff() time.Duration {
+	return c.v.GetDuration(varWebhookDeliveryBackoff)
+}
+
+// GetWebhookDeliveryMaxAttempts returns the number of attempts after which a
+// webhook delivery is given up (as set via default, config file, or
+// environment variable)
+func (c *ConfigurationData) GetWebhookDeliveryMaxAttempts() int {
+	return c.v.GetInt(varWebhookDeliveryMaxAttempts)
+}
+
+// IsNotificationEnabled returns true if the users are notified by email of
+// the changes of what they watch (as set via default, config file, or
+// environment variable)
+func (c *ConfigurationData) IsNotificationEnabled() bool {
+	return c.v.GetBool(varNotificationEnabled)
+}
+
+// GetNotificationFrom returns the sender address of the notification emails
+// (as set via default, config file, or environment variable)
+func (c *ConfigurationData) GetNotificationFrom() string {
+	return c.v.GetString(varNotificationFrom)
+}
+
+// GetNotificationSMTPAddress returns the host:port of the SMTP server which
+// sends the notification emails (as set via default, config file, or
+// environment variable)
+func (c *ConfigurationData) GetNotificationSMTPAddress() string {
+	return c.v.GetString(varNotificationSMTPAddress)
+}
+
+// GetNotificationSMTPUsername returns the username to authenticate with the
+// SMTP server, or an empty string to send without authentication (as set via
+// default, config file, or environment variable)
+func (c *ConfigurationData) GetNotificationSMTPUsername() string {
+	return c.v.GetString(varNotificationSMTPUsername)
+}
+
+// GetNotificationSMTPPassword returns the password to authenticate with the
+// SMTP server (as set via default, config file, or environment variable)
+func (c *ConfigurationData) GetNotificationSMTPPassword() string {
+	return c.v.GetString(varNotificationSMTPPassword)
+}
+
+// GetNotificationFileDir returns the directory where the notification emails
+// are written instead of being sent, or an empty string to send them with
+// SMTP (as set via default, config file, or environment variable)
+func (c *ConfigurationData) GetNotificationFileDir() string {
+	return c.v.GetString(varNotificationFileDir)
+}
+
 // GetPostgresUser returns the postgres user as set via default, config file, or environment variable
 func (c *ConfigurationData) GetPostgresUser() string {
 	return c.v.GetString(varPostgresUser)
@@ -335,6 +438,18 @@ func (c *ConfigurationData) GetCacheControlAreas() string {
 	return c.v.GetString(varCacheControlAreas)
 }
 
+// GetCacheControlLabels returns the value to set in the "Cache-Control" HTTP response header
+// when returning labels.
+func (c *ConfigurationData) GetCacheControlLabels() string {
+	return c.v.GetString(varCacheControlLabels)
+}
+
+// GetCacheControlWorkItemTemplates returns the value to set in the "Cache-Control" HTTP response header
+// when returning work item templates.
+func (c *ConfigurationData) GetCacheControlWorkItemTemplates() string {
+	return c.v.GetString(varCacheControlWorkItemTemplates)
+}
+
 // GetCacheControlSpaces returns the value to set in the "Cache-Control" HTTP response header
 // when returning spaces.
 func (c *ConfigurationData) GetCacheControlSpaces() string {
@@ -58,7 +58,8 @@ func (c *CommentsController) Show(ctx *app.ShowCommentsContext) error {
 			res.Data = ConvertComment(
 				ctx.RequestData,
 				*cmt,
-				includeParentWorkItem)
+				includeParentWorkItem,
+				commentIncludeKeyLinks(ctx, appl), commentIncludeMentions(ctx, appl), commentIncludeReactions(ctx, appl))
 			return ctx.OK(res)
 		})
 	})
@@ -97,7 +98,7 @@ func (c *CommentsController) Update(ctx *app.UpdateCommentsContext) error {
 		}
 
 		res := &app.CommentSingle{
-			Data: ConvertComment(ctx.RequestData, *cm, includeParentWorkItem),
+			Data: ConvertComment(ctx.RequestData, *cm, includeParentWorkItem, commentIncludeKeyLinks(ctx, appl), commentIncludeMentions(ctx, appl), commentIncludeReactions(ctx, appl)),
 		}
 		return ctx.OK(res)
 	})
@@ -111,6 +112,8 @@ func (c *CommentsController) Delete(ctx *app.DeleteCommentsContext) error {
 	}
 	var cm *comment.Comment
 	var wi *workitem.WorkItem
+	// whether other users replied to the comment
+	var repliedByOthers bool
 	// Following transaction verifies if a user is allowed to delete or not
 	err = application.Transactional(c.db, func(appl application.Application) error {
 		cm, err = appl.Comments().Load(ctx.Context, ctx.CommentID)
@@ -122,13 +125,24 @@ func (c *CommentsController) Delete(ctx *app.DeleteCommentsContext) error {
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, err)
 		}
+		replies, err := appl.Comments().ListReplies(ctx.Context, cm.ID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		for _, reply := range replies {
+			if reply.CreatedBy != *identityID {
+				repliedByOthers = true
+			}
+		}
 		return nil
 	})
 	if err != nil {
 		return err
 	}
-	// User is allowed to delete if user is creator of the comment OR user is a space collaborator
-	if *identityID == cm.CreatedBy {
+	// User is allowed to delete if user is creator of the comment OR user is a space collaborator.
+	// The replies are deleted along with the comment, so only a space collaborator may delete
+	// a comment other users replied to.
+	if *identityID == cm.CreatedBy && !repliedByOthers {
 		return c.performDelete(ctx, cm, identityID)
 	}
 
@@ -152,6 +166,50 @@ func (c *CommentsController) performDelete(ctx *app.DeleteCommentsContext, cm *c
 	})
 }
 
+// Restore does POST comment restore
+func (c *CommentsController) Restore(ctx *app.RestoreCommentsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var res *app.CommentSingle
+	// errors are returned from the transaction in order to roll back the
+	// restoration when the user is not allowed to perform it
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		cm, err := appl.Comments().Restore(ctx.Context, ctx.CommentID, *identityID)
+		if err != nil {
+			return err
+		}
+		wi, err := appl.WorkItems().LoadByID(ctx.Context, cm.ParentID)
+		if err != nil {
+			// the comments of a deleted work item can only be restored along with the work item
+			return errors.NewBadParameterError("commentId", ctx.CommentID).Expected("comment of an existing work item")
+		}
+		// User is allowed to restore if user is creator of the comment OR user is a space collaborator
+		if *identityID != cm.CreatedBy {
+			authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+			if err != nil {
+				return errors.NewUnauthorizedError(err.Error())
+			}
+			if !authorized {
+				return errors.NewForbiddenError("user is not a space collaborator")
+			}
+		}
+		includeParentWorkItem, err := CommentIncludeParentWorkItem(ctx, appl, cm)
+		if err != nil {
+			return err
+		}
+		res = &app.CommentSingle{
+			Data: ConvertComment(ctx.RequestData, *cm, includeParentWorkItem, commentIncludeKeyLinks(ctx, appl), commentIncludeMentions(ctx, appl), commentIncludeReactions(ctx, appl)),
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(res)
+}
+
 // CommentConvertFunc is a open ended function to add additional links/data/relations to a Comment during
 // conversion from internal to API
 type CommentConvertFunc func(*goa.RequestData, *comment.Comment, *app.Comment)
@@ -217,12 +275,61 @@ func ConvertComment(request *goa.RequestData, comment comment.Comment, additiona
 			Self: &selfURL,
 		},
 	}
+	if comment.ReplyTo != nil {
+		c.Relationships.ReplyTo = &app.RelationGeneric{
+			Data: convertCommentSimple(request, *comment.ReplyTo),
+		}
+	}
 	for _, add := range additional {
 		add(request, &comment, c)
 	}
 	return c
 }
 
+// convertCommentSimple converts the ID of a comment into a generic relationship
+func convertCommentSimple(request *goa.RequestData, id uuid.UUID) *app.GenericData {
+	t := "comments"
+	i := id.String()
+	selfURL := rest.AbsoluteURL(request, app.CommentsHref(id))
+	return &app.GenericData{
+		Type: &t,
+		ID:   &i,
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
+
+// commentIncludeReplies adds the direct replies to the comment among the
+// given ones as relationships
+func commentIncludeReplies(replies []comment.Comment) CommentConvertFunc {
+	byComment := map[uuid.UUID][]uuid.UUID{}
+	for _, reply := range replies {
+		byComment[*reply.ReplyTo] = append(byComment[*reply.ReplyTo], reply.ID)
+	}
+	return func(request *goa.RequestData, c *comment.Comment, c2 *app.Comment) {
+		data := []*app.GenericData{}
+		for _, id := range byComment[c.ID] {
+			data = append(data, convertCommentSimple(request, id))
+		}
+		c2.Relationships.Replies = &app.RelationGenericList{Data: data}
+	}
+}
+
+// commentReplyTo returns the ID of the comment the given new comment replies
+// to, or nil if it is not a reply
+// returns BadParameterError if the ID is not valid
+func commentReplyTo(c *app.CreateComment) (*uuid.UUID, error) {
+	if c.Relationships == nil || c.Relationships.ReplyTo == nil || c.Relationships.ReplyTo.Data == nil || c.Relationships.ReplyTo.Data.ID == nil {
+		return nil, nil
+	}
+	id, err := uuid.FromString(*c.Relationships.ReplyTo.Data.ID)
+	if err != nil {
+		return nil, errors.NewBadParameterError("data.relationships.reply-to.data.id", *c.Relationships.ReplyTo.Data.ID)
+	}
+	return &id, nil
+}
+
 // HrefFunc generic function to greate a relative Href to a resource
 type HrefFunc func(id interface{}) string
 
@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/label"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/rest"
+	"github.com/fabric8io/almighty-core/space"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// LabelController implements the label resource.
+type LabelController struct {
+	*goa.Controller
+	db     application.DB
+	config LabelControllerConfiguration
+}
+
+// LabelControllerConfiguration the configuration for the LabelController
+type LabelControllerConfiguration interface {
+	GetCacheControlLabels() string
+}
+
+// NewLabelController creates a label controller.
+func NewLabelController(service *goa.Service, db application.DB, config LabelControllerConfiguration) *LabelController {
+	return &LabelController{
+		Controller: service.NewController("LabelController"),
+		db:         db,
+		config:     config}
+}
+
+// List runs the list action.
+func (c *LabelController) List(ctx *app.ListLabelContext) error {
+	return application.Transactional(c.db, func(appl application.Application) error {
+		_, err := appl.Spaces().Load(ctx, ctx.SpaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		labels, err := appl.Labels().List(ctx, ctx.SpaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		counts, err := appl.WorkItems().GetCountsPerLabel(ctx, ctx.SpaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.ConditionalEntities(labels, c.config.GetCacheControlLabels, func() error {
+			res := &app.LabelList{
+				Data: ConvertLabels(ctx.RequestData, labels, addLabelCount(counts)),
+				Meta: &app.WorkItemListResponseMeta{TotalCount: len(labels)},
+			}
+			return ctx.OK(res)
+		})
+	})
+}
+
+// Show runs the show action.
+func (c *LabelController) Show(ctx *app.ShowLabelContext) error {
+	return application.Transactional(c.db, func(appl application.Application) error {
+		l, err := appl.Labels().Load(ctx, ctx.SpaceID, ctx.LabelID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		counts, err := appl.WorkItems().GetCountsPerLabel(ctx, ctx.SpaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.ConditionalEntity(*l, c.config.GetCacheControlLabels, func() error {
+			res := &app.LabelSingle{
+				Data: ConvertLabel(ctx.RequestData, *l, addLabelCount(counts)),
+			}
+			return ctx.OK(res)
+		})
+	})
+}
+
+// Create runs the create action.
+func (c *LabelController) Create(ctx *app.CreateLabelContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil || ctx.Payload.Data.Attributes.Name == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.name", nil).Expected("not nil"))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		l := label.Label{
+			SpaceID: ctx.SpaceID,
+			Name:    *ctx.Payload.Data.Attributes.Name,
+		}
+		if ctx.Payload.Data.Attributes.Color != nil {
+			l.Color = *ctx.Payload.Data.Attributes.Color
+		}
+		if ctx.Payload.Data.Attributes.Description != nil {
+			l.Description = *ctx.Payload.Data.Attributes.Description
+		}
+		err := appl.Labels().Create(ctx, &l)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.LabelSingle{
+			Data: ConvertLabel(ctx.RequestData, l),
+		}
+		ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.RequestData, app.LabelHref(ctx.SpaceID.String(), l.ID.String())))
+		return ctx.Created(res)
+	})
+}
+
+// Update runs the update action. Since work items refer to labels by their
+// ID a new name or color is visible on all work items at once.
+func (c *LabelController) Update(ctx *app.UpdateLabelContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Attributes.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		l, err := appl.Labels().Load(ctx, ctx.SpaceID, ctx.LabelID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		l.Version = *ctx.Payload.Data.Attributes.Version
+		if ctx.Payload.Data.Attributes.Name != nil {
+			l.Name = *ctx.Payload.Data.Attributes.Name
+		}
+		if ctx.Payload.Data.Attributes.Color != nil {
+			l.Color = *ctx.Payload.Data.Attributes.Color
+		}
+		if ctx.Payload.Data.Attributes.Description != nil {
+			l.Description = *ctx.Payload.Data.Attributes.Description
+		}
+		l, err = appl.Labels().Save(ctx, *l)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.LabelSingle{
+			Data: ConvertLabel(ctx.RequestData, *l),
+		}
+		return ctx.OK(res)
+	})
+}
+
+// Delete runs the delete action. The label is detached from all work items
+// of the space.
+func (c *LabelController) Delete(ctx *app.DeleteLabelContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		if err := appl.WorkItems().DetachLabel(ctx, ctx.SpaceID, ctx.LabelID, *currentUser); err != nil {
+			return err
+		}
+		return appl.Labels().Delete(ctx, ctx.SpaceID, ctx.LabelID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// checkSpaceOwner returns a ForbiddenError if the given user is not allowed
+// to manage the labels and the work item templates of the given space
+func checkSpaceOwner(ctx context.Context, appl application.Application, spaceID uuid.UUID, currentUser uuid.UUID) error {
+	s, err := appl.Spaces().Load(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+	if !uuid.Equal(currentUser, s.OwnerId) {
+		log.Warn(ctx, map[string]interface{}{
+			"space_id":     spaceID,
+			"space_owner":  s.OwnerId,
+			"current_user": currentUser,
+		}, "user is not the space owner")
+		return errors.NewForbiddenError("user is not the space owner")
+	}
+	return nil
+}
+
+// LabelConvertFunc is a open ended function to add additional links/data/relations to a label during
+// conversion from internal to API
+type LabelConvertFunc func(*goa.RequestData, *label.Label, *app.Label)
+
+// addLabelCount adds the number of work items which have the label attached
+// to the meta of the "workitems" relationship
+func addLabelCount(counts map[string]int) LabelConvertFunc {
+	return func(request *goa.RequestData, l *label.Label, appLabel *app.Label) {
+		appLabel.Relationships.Workitems.Meta = map[string]interface{}{
+			"total": counts[l.ID.String()],
+		}
+	}
+}
+
+// ConvertLabels converts between internal and external REST representation
+func ConvertLabels(request *goa.RequestData, labels []label.Label, additional ...LabelConvertFunc) []*app.Label {
+	var ls = []*app.Label{}
+	for _, l := range labels {
+		ls = append(ls, ConvertLabel(request, l, additional...))
+	}
+	return ls
+}
+
+// ConvertLabel converts between internal and external REST representation
+func ConvertLabel(request *goa.RequestData, l label.Label, additional ...LabelConvertFunc) *app.Label {
+	labelType := label.APIStringTypeLabels
+	spaceID := l.SpaceID.String()
+	selfURL := rest.AbsoluteURL(request, app.LabelHref(spaceID, l.ID.String()))
+	spaceSelfURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	workitemsRelatedURL := rest.AbsoluteURL(request, app.WorkitemHref(spaceID, "?filter[label]="+l.ID.String()))
+	res := &app.Label{
+		Type: labelType,
+		ID:   &l.ID,
+		Attributes: &app.LabelAttributes{
+			Name:        &l.Name,
+			Color:       &l.Color,
+			Description: &l.Description,
+			CreatedAt:   &l.CreatedAt,
+			UpdatedAt:   &l.UpdatedAt,
+			Version:     &l.Version,
+		},
+		Relationships: &app.LabelRelations{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self: &spaceSelfURL,
+				},
+			},
+			Workitems: &app.RelationGeneric{
+				Links: &app.GenericLinks{
+					Related: &workitemsRelatedURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+	for _, add := range additional {
+		add(request, &l, res)
+	}
+	return res
+}
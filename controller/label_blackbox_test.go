@@ -0,0 +1,142 @@
+package controller_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/app/test"
+	. "github.com/fabric8io/almighty-core/controller"
+	"github.com/fabric8io/almighty-core/gormapplication"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+	testsupport "github.com/fabric8io/almighty-core/test"
+	almtoken "github.com/fabric8io/almighty-core/token"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestLabelREST struct {
+	gormtestsupport.DBTestSuite
+	db    *gormapplication.GormDB
+	clean func()
+	owner account.Identity
+	space *space.Space
+}
+
+func TestRunLabelREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	pwd, err := os.Getwd()
+	if err != nil {
+		require.Nil(t, err)
+	}
+	suite.Run(t, &TestLabelREST{DBTestSuite: gormtestsupport.NewDBTestSuite(pwd + "/../config.yaml")})
+}
+
+func (rest *TestLabelREST) SetupTest() {
+	rest.db = gormapplication.NewGormDB(rest.DB)
+	rest.clean = cleaner.DeleteCreatedEntities(rest.DB)
+	owner, err := testsupport.CreateTestIdentity(rest.DB, "TestLabelREST-"+uuid.NewV4().String(), "test provider")
+	require.Nil(rest.T(), err)
+	rest.owner = owner
+	rest.space, err = rest.db.Spaces().Create(context.Background(), &space.Space{
+		Name:    "TestLabelREST-" + uuid.NewV4().String(),
+		OwnerId: owner.ID,
+	})
+	require.Nil(rest.T(), err)
+}
+
+func (rest *TestLabelREST) TearDownTest() {
+	rest.clean()
+}
+
+func (rest *TestLabelREST) SecuredControllerWithIdentity(idn account.Identity) (*goa.Service, *LabelController) {
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	svc := testsupport.ServiceAsUser("Label-Service", almtoken.NewManagerWithPrivateKey(priv), idn)
+	return svc, NewLabelController(svc, rest.db, rest.Configuration)
+}
+
+func newCreateLabelPayload(name string, color string) *app.CreateLabelPayload {
+	return &app.CreateLabelPayload{
+		Data: &app.Label{
+			Type: "labels",
+			Attributes: &app.LabelAttributes{
+				Name:  &name,
+				Color: &color,
+			},
+		},
+	}
+}
+
+func (rest *TestLabelREST) TestCreateLabel() {
+	rest.T().Run("owner", func(t *testing.T) {
+		// given
+		svc, ctrl := rest.SecuredControllerWithIdentity(rest.owner)
+		// when
+		_, created := test.CreateLabelCreated(t, svc.Context, svc, ctrl, rest.space.ID, newCreateLabelPayload("bug", "#ff0000"))
+		// then
+		require.NotNil(t, created.Data.ID)
+		assert.Equal(t, "bug", *created.Data.Attributes.Name)
+		assert.Equal(t, "#ff0000", *created.Data.Attributes.Color)
+		assert.Contains(t, *created.Data.Relationships.Workitems.Links.Related, "filter[label]="+created.Data.ID.String())
+	})
+
+	rest.T().Run("same name", func(t *testing.T) {
+		// given
+		svc, ctrl := rest.SecuredControllerWithIdentity(rest.owner)
+		// when/then
+		test.CreateLabelBadRequest(t, svc.Context, svc, ctrl, rest.space.ID, newCreateLabelPayload("bug", "#00ff00"))
+	})
+
+	rest.T().Run("not the owner", func(t *testing.T) {
+		// given
+		otherIdentity, err := testsupport.CreateTestIdentity(rest.DB, "TestCreateLabel-"+uuid.NewV4().String(), "test provider")
+		require.Nil(t, err)
+		svc, ctrl := rest.SecuredControllerWithIdentity(otherIdentity)
+		// when/then
+		test.CreateLabelForbidden(t, svc.Context, svc, ctrl, rest.space.ID, newCreateLabelPayload("feature", "#0000ff"))
+	})
+}
+
+func (rest *TestLabelREST) TestUpdateAndDeleteLabel() {
+	// given
+	svc, ctrl := rest.SecuredControllerWithIdentity(rest.owner)
+	_, created := test.CreateLabelCreated(rest.T(), svc.Context, svc, ctrl, rest.space.ID, newCreateLabelPayload("bug", "#ff0000"))
+
+	rest.T().Run("rename", func(t *testing.T) {
+		// given
+		payload := &app.UpdateLabelPayload{Data: created.Data}
+		newName := "defect"
+		payload.Data.Attributes.Name = &newName
+		// when
+		_, updated := test.UpdateLabelOK(t, svc.Context, svc, ctrl, rest.space.ID, *created.Data.ID, payload)
+		// then
+		assert.Equal(t, "defect", *updated.Data.Attributes.Name)
+		_, list := test.ListLabelOK(t, svc.Context, svc, ctrl, rest.space.ID, nil, nil)
+		require.Len(t, list.Data, 1)
+		assert.Equal(t, "defect", *list.Data[0].Attributes.Name)
+		assert.Equal(t, 0, list.Data[0].Relationships.Workitems.Meta["total"])
+	})
+
+	rest.T().Run("version conflict", func(t *testing.T) {
+		// given the version of the label before the rename
+		payload := &app.UpdateLabelPayload{Data: created.Data}
+		// when/then
+		test.UpdateLabelConflict(t, svc.Context, svc, ctrl, rest.space.ID, *created.Data.ID, payload)
+	})
+
+	rest.T().Run("delete", func(t *testing.T) {
+		// when
+		test.DeleteLabelOK(t, svc.Context, svc, ctrl, rest.space.ID, *created.Data.ID)
+		// then
+		test.ShowLabelNotFound(t, svc.Context, svc, ctrl, rest.space.ID, *created.Data.ID, nil, nil)
+	})
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/comment"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/rest"
+	"github.com/fabric8io/almighty-core/space"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// MentionController implements the mention resource.
+type MentionController struct {
+	*goa.Controller
+	db     application.DB
+	config MentionControllerConfiguration
+}
+
+// MentionControllerConfiguration the configuration for the MentionController
+type MentionControllerConfiguration interface {
+	GetCacheControlUser() string
+}
+
+// NewMentionController creates a mention controller.
+func NewMentionController(service *goa.Service, db application.DB, config MentionControllerConfiguration) *MentionController {
+	return &MentionController{
+		Controller: service.NewController("MentionController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+// List runs the list action.
+func (c *MentionController) List(ctx *app.ListMentionContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	return application.Transactional(c.db, func(appl application.Application) error {
+		mentions, tc, err := appl.Mentions().ListByIdentity(ctx, *currentUser, &offset, &limit)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		count := int(tc)
+		return ctx.ConditionalEntities(mentions, c.config.GetCacheControlUser, func() error {
+			res := &app.MentionList{
+				Data:  make([]*app.Mention, len(mentions)),
+				Links: &app.PagingLinks{},
+				Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
+			}
+			for i := range mentions {
+				res.Data[i] = ConvertMention(ctx.RequestData, mentions[i])
+			}
+			setPagingLinks(res.Links, buildAbsoluteURL(ctx.RequestData), len(mentions), offset, limit, count)
+			return ctx.OK(res)
+		})
+	})
+}
+
+// ConvertMention converts from internal to external REST representation
+func ConvertMention(request *goa.RequestData, m mention.Mention) *app.Mention {
+	spaceID := m.SpaceID.String()
+	spaceSelfURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	workItemSelfURL := rest.AbsoluteURL(request, app.WorkitemHref(spaceID, m.WorkItemID))
+	workItemType := APIStringTypeWorkItem
+	res := &app.Mention{
+		Type: mention.APIStringTypeMentions,
+		ID:   &m.ID,
+		Attributes: &app.MentionAttributes{
+			Kind:      &m.Kind,
+			CreatedAt: &m.CreatedAt,
+		},
+		Relationships: &app.MentionRelations{
+			Workitem: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &workItemType,
+					ID:   &m.WorkItemID,
+				},
+				Links: &app.GenericLinks{
+					Self: &workItemSelfURL,
+				},
+			},
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self: &spaceSelfURL,
+				},
+			},
+		},
+	}
+	if m.Kind == mention.KindComment {
+		commentType := mention.KindComment
+		commentSelfURL := rest.AbsoluteURL(request, app.CommentsHref(m.TargetID))
+		res.Relationships.Comment = &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: &commentType,
+				ID:   &m.TargetID,
+			},
+			Links: &app.GenericLinks{
+				Self: &commentSelfURL,
+			},
+		}
+	}
+	return res
+}
+
+// convertMentionedUsers converts the IDs of the mentioned identities into a
+// generic relationship list
+func convertMentionedUsers(request *goa.RequestData, identityIDs []uuid.UUID) *app.RelationGenericList {
+	ids := make([]interface{}, len(identityIDs))
+	for i, id := range identityIDs {
+		ids[i] = id
+	}
+	return &app.RelationGenericList{Data: ConvertUsersSimple(request, ids)}
+}
+
+// linkMentions turns the mentions of the given identities in the given
+// rendered markup into links to the users. Mentions of other usernames, which
+// did not resolve to a user, are left as they are.
+func linkMentions(ctx context.Context, appl application.Application, request *goa.RequestData, rendered string, identityIDs []uuid.UUID) string {
+	if len(identityIDs) == 0 {
+		return rendered
+	}
+	users := map[string]uuid.UUID{}
+	for _, id := range identityIDs {
+		identity, err := appl.Identities().Load(ctx, id)
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"identity_id": id,
+				"err":         err,
+			}, "unable to load the mentioned identity: %s", id)
+			continue
+		}
+		users[identity.Username] = id
+	}
+	return rendering.LinkMentions(rendered, func(username string) (string, bool) {
+		id, ok := users[username]
+		if !ok {
+			return "", false
+		}
+		return rest.AbsoluteURL(request, app.UsersHref(id)), true
+	})
+}
+
+// commentIncludeMentions adds the users mentioned in the comment body as
+// relationships and links their mentions in the rendered body of Markdown
+// comments
+func commentIncludeMentions(ctx context.Context, appl application.Application) CommentConvertFunc {
+	return func(request *goa.RequestData, c *comment.Comment, c2 *app.Comment) {
+		identityIDs, err := appl.Mentions().List(ctx, mention.KindComment, c.ID.String())
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"comment_id": c.ID,
+				"err":        err,
+			}, "unable to list the users mentioned in the comment: %s", c.ID)
+			return
+		}
+		c2.Relationships.Mentions = convertMentionedUsers(request, identityIDs)
+		if c.Markup == rendering.SystemMarkupMarkdown && c2.Attributes != nil && c2.Attributes.BodyRendered != nil {
+			bodyRendered := linkMentions(ctx, appl, request, *c2.Attributes.BodyRendered, identityIDs)
+			c2.Attributes.BodyRendered = &bodyRendered
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/comment"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/rest"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// NamedWorkItemsControllerConfig the config interface for the NamedworkitemsController
+type NamedWorkItemsControllerConfig interface {
+	GetCacheControlWorkItems() string
+}
+
+// NamedworkitemsController implements the namedworkitems resource.
+type NamedworkitemsController struct {
+	*goa.Controller
+	db     application.DB
+	config NamedWorkItemsControllerConfig
+}
+
+// NewNamedworkitemsController creates a namedworkitems controller.
+func NewNamedworkitemsController(service *goa.Service, db application.DB, config NamedWorkItemsControllerConfig) *NamedworkitemsController {
+	return &NamedworkitemsController{Controller: service.NewController("NamedworkitemsController"), db: db, config: config}
+}
+
+// Show runs the show action.
+func (c *NamedworkitemsController) Show(ctx *app.ShowNamedworkitemsContext) error {
+	if _, _, ok := workitem.ParseWorkItemKey(ctx.WiKey); !ok {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrBadRequest(fmt.Sprintf("invalid work item key: %s", ctx.WiKey)))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		wi, err := appl.WorkItems().LoadByID(ctx, ctx.WiKey)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to load work item %s", ctx.WiKey))
+		}
+		comments := workItemIncludeCommentsAndTotal(ctx, c.db, wi.ID)
+		hasChildren := workItemIncludeHasChildren(appl, ctx)
+		references := workItemIncludeReferences(appl, ctx)
+		key := workItemIncludeKey(appl, ctx)
+		checklist := workItemIncludeChecklist(appl, ctx)
+		reactions := workItemIncludeReactions(appl, ctx)
+		return ctx.ConditionalEntity(*wi, c.config.GetCacheControlWorkItems, func() error {
+			wi2 := ConvertWorkItem(ctx.RequestData, *wi, comments, hasChildren, references, key, checklist, reactions)
+			return ctx.OK(&app.WorkItemSingle{
+				Data: wi2,
+			})
+		})
+	})
+}
+
+// workItemKeyLinker returns a function which turns the keys of work items
+// in rendered markup into links to the work items. Only the keys of
+// existing spaces are linked, so that e.g. "UTF-8" is left as it is.
+func workItemKeyLinker(ctx context.Context, appl application.Application) func(request *goa.RequestData, rendered string) string {
+	spaceKeys := map[string]bool{}
+	return func(request *goa.RequestData, rendered string) string {
+		return rendering.LinkWorkItemKeys(rendered, func(spaceKey string, number int) (string, bool) {
+			exists, ok := spaceKeys[spaceKey]
+			if !ok {
+				_, err := appl.Spaces().LoadByKey(ctx, spaceKey)
+				exists = err == nil
+				spaceKeys[spaceKey] = exists
+			}
+			if !exists {
+				return "", false
+			}
+			return rest.AbsoluteURL(request, app.NamedworkitemsHref(fmt.Sprintf("%s-%d", spaceKey, number))), true
+		})
+	}
+}
+
+// workItemIncludeKey adds the key of the work item, e.g. PLAT-123, if its
+// space has a key, and links the keys of work items in its rendered
+// Markdown description
+func workItemIncludeKey(appl application.Application, ctx context.Context) WorkItemConvertFunc {
+	spaceKeys := map[uuid.UUID]*string{}
+	linkKeys := workItemKeyLinker(ctx, appl)
+	return func(request *goa.RequestData, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+		spaceKey, ok := spaceKeys[wi.SpaceID]
+		if !ok {
+			if s, err := appl.Spaces().Load(ctx, wi.SpaceID); err == nil {
+				spaceKey = s.Key
+			}
+			spaceKeys[wi.SpaceID] = spaceKey
+		}
+		if spaceKey != nil && wi.Number > 0 {
+			wi2.Attributes["key"] = fmt.Sprintf("%s-%d", *spaceKey, wi.Number)
+		}
+		if wi2.Attributes[workitem.SystemDescriptionMarkup] == rendering.SystemMarkupMarkdown {
+			if rendered, ok := wi2.Attributes[workitem.SystemDescriptionRendered].(string); ok {
+				wi2.Attributes[workitem.SystemDescriptionRendered] = linkKeys(request, rendered)
+			}
+		}
+	}
+}
+
+// commentIncludeKeyLinks links the keys of work items in the rendered body
+// of Markdown comments
+func commentIncludeKeyLinks(ctx context.Context, appl application.Application) CommentConvertFunc {
+	linkKeys := workItemKeyLinker(ctx, appl)
+	return func(request *goa.RequestData, c *comment.Comment, c2 *app.Comment) {
+		if c.Markup != rendering.SystemMarkupMarkdown || c2.Attributes == nil || c2.Attributes.BodyRendered == nil {
+			return
+		}
+		bodyRendered := linkKeys(request, *c2.Attributes.BodyRendered)
+		c2.Attributes.BodyRendered = &bodyRendered
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/fabric8io/almighty-core/criteria"
 	"github.com/fabric8io/almighty-core/errors"
 	"github.com/fabric8io/almighty-core/jsonapi"
-	query "github.com/fabric8io/almighty-core/query/simple"
+	"github.com/fabric8io/almighty-core/query"
 	"github.com/fabric8io/almighty-core/workitem"
 
 	"github.com/goadesign/goa"
@@ -46,11 +46,18 @@ func (c *PlannerBacklogController) List(ctx *app.ListPlannerBacklogContext) erro
 		exp = criteria.And(exp, criteria.Equals(criteria.Field("system.assignees"), criteria.Literal([]string{*ctx.FilterAssignee})))
 	}
 	if ctx.FilterWorkitemtype != nil {
-		exp = criteria.And(exp, criteria.Equals(criteria.Field("Type"), criteria.Literal([]uuid.UUID{*ctx.FilterWorkitemtype})))
+		typeExp, err := workItemTypeExpression(ctx.Context, c.db, *ctx.FilterWorkitemtype)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		exp = criteria.And(exp, typeExp)
 	}
 	if ctx.FilterArea != nil {
 		exp = criteria.And(exp, criteria.Equals(criteria.Field(workitem.SystemArea), criteria.Literal(string(*ctx.FilterArea))))
 	}
+	if ctx.FilterLabel != nil {
+		exp = criteria.And(exp, criteria.Equals(criteria.Field(workitem.SystemLabels), criteria.Literal([]string{ctx.FilterLabel.String()})))
+	}
 
 	// Get the list of work items for the following criteria
 	result, count, err := getBacklogItems(ctx.Context, c.db, ctx.SpaceID, exp, &offset, &limit)
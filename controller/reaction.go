@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/comment"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/reaction"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// React does POST comment reactions
+func (c *CommentsController) React(ctx *app.ReactCommentsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var res *app.ReactionSingle
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		cm, err := appl.Comments().Load(ctx, ctx.CommentID)
+		if err != nil {
+			return err
+		}
+		res, err = toggleReaction(ctx, appl, reaction.KindComment, cm.ID.String(), ctx.Payload.Data, *identityID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(res)
+}
+
+// React does POST workitem reactions
+func (c *WorkitemController) React(ctx *app.ReactWorkitemContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var res *app.ReactionSingle
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err := appl.WorkItems().Load(ctx, ctx.SpaceID, ctx.WiID)
+		if err != nil {
+			return err
+		}
+		res, err = toggleReaction(ctx, appl, reaction.KindWorkItem, wi.ID, ctx.Payload.Data, *identityID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(res)
+}
+
+// toggleReaction toggles the given reaction of the given user, and returns
+// the reaction with its new count
+func toggleReaction(ctx context.Context, appl application.Application, kind string, targetID string, data *app.Reaction, identityID uuid.UUID) (*app.ReactionSingle, error) {
+	if data == nil || data.Attributes == nil {
+		return nil, errors.NewBadParameterError("data.attributes.content", nil).Expected("not nil")
+	}
+	content := data.Attributes.Content
+	reacted, err := appl.Reactions().Toggle(ctx, kind, targetID, content, identityID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := appl.Reactions().Count(ctx, kind, targetID)
+	if err != nil {
+		return nil, err
+	}
+	count := counts[content]
+	return &app.ReactionSingle{
+		Data: &app.Reaction{
+			Type: reaction.APIStringTypeReactions,
+			Attributes: &app.ReactionAttributes{
+				Content: content,
+				Reacted: &reacted,
+				Count:   &count,
+			},
+		},
+	}, nil
+}
+
+// reactionsMeta returns the meta of a work item or a comment with the number
+// of reactions to it by emoji
+func reactionsMeta(ctx context.Context, appl application.Application, kind string, targetID string) map[string]interface{} {
+	counts, err := appl.Reactions().Count(ctx, kind, targetID)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"kind":      kind,
+			"target_id": targetID,
+			"err":       err,
+		}, "unable to count the reactions: %s", targetID)
+		return nil
+	}
+	return map[string]interface{}{
+		"reactions": counts,
+	}
+}
+
+// commentIncludeReactions adds the number of reactions to the comment to its meta
+func commentIncludeReactions(ctx context.Context, appl application.Application) CommentConvertFunc {
+	return func(request *goa.RequestData, c *comment.Comment, c2 *app.Comment) {
+		c2.Meta = reactionsMeta(ctx, appl, reaction.KindComment, c.ID.String())
+	}
+}
+
+// workItemIncludeReactions adds the number of reactions to the work item to its meta
+func workItemIncludeReactions(appl application.Application, ctx context.Context) WorkItemConvertFunc {
+	return func(request *goa.RequestData, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+		wi2.Meta = reactionsMeta(ctx, appl, reaction.KindWorkItem, wi.ID)
+	}
+}
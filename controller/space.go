@@ -81,6 +81,7 @@ func (c *SpaceController) Create(ctx *app.CreateSpaceContext) error {
 		if reqSpace.Attributes.Description != nil {
 			newSpace.Description = *reqSpace.Attributes.Description
 		}
+		newSpace.Key = reqSpace.Attributes.Key
 
 		rSpace, err = appl.Spaces().Create(ctx, &newSpace)
 		if err != nil {
@@ -303,6 +304,9 @@ func (c *SpaceController) Update(ctx *app.UpdateSpaceContext) error {
 		if ctx.Payload.Data.Attributes.Description != nil {
 			s.Description = *ctx.Payload.Data.Attributes.Description
 		}
+		if ctx.Payload.Data.Attributes.Key != nil {
+			s.Key = ctx.Payload.Data.Attributes.Key
+		}
 
 		s, err = appl.Spaces().Save(ctx.Context, s)
 		if err != nil {
@@ -377,6 +381,7 @@ func ConvertSpaceToModel(appSpace app.Space) space.Space {
 		if appSpace.Attributes.Description != nil {
 			modelSpace.Description = *appSpace.Attributes.Description
 		}
+		modelSpace.Key = appSpace.Attributes.Key
 	}
 	if appSpace.Relationships != nil && appSpace.Relationships.OwnedBy != nil &&
 		appSpace.Relationships.OwnedBy.Data != nil && appSpace.Relationships.OwnedBy.Data.ID != nil {
@@ -428,6 +433,7 @@ func ConvertSpaceFromModel(ctx context.Context, db application.DB, request *goa.
 		Attributes: &app.SpaceAttributes{
 			Name:        &sp.Name,
 			Description: &sp.Description,
+			Key:         sp.Key,
 			CreatedAt:   &sp.CreatedAt,
 			UpdatedAt:   &sp.UpdatedAt,
 			Version:     &sp.Version,
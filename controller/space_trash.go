@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/jsonapi"
+
+	"github.com/goadesign/goa"
+)
+
+// SpaceTrashController implements the space_trash resource.
+type SpaceTrashController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewSpaceTrashController creates a space_trash controller.
+func NewSpaceTrashController(service *goa.Service, db application.DB) *SpaceTrashController {
+	return &SpaceTrashController{Controller: service.NewController("SpaceTrashController"), db: db}
+}
+
+// List runs the list action.
+func (c *SpaceTrashController) List(ctx *app.ListSpaceTrashContext) error {
+	var additionalQuery []string
+	if ctx.Since != nil {
+		additionalQuery = append(additionalQuery, "since="+ctx.Since.UTC().Format(time.RFC3339Nano))
+	}
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, ctx.SpaceID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		workitems, tc, err := appl.WorkItems().ListDeleted(ctx, ctx.SpaceID, ctx.Since, &offset, &limit)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		count := int(tc)
+		response := app.WorkItemList{
+			Links: &app.PagingLinks{},
+			Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
+			Data:  ConvertWorkItems(ctx.RequestData, workitems),
+		}
+		setPagingLinks(response.Links, buildAbsoluteURL(ctx.RequestData), len(workitems), offset, limit, count, additionalQuery...)
+		return ctx.OK(&response)
+	})
+}
+
+// Comments runs the comments action.
+func (c *SpaceTrashController) Comments(ctx *app.CommentsSpaceTrashContext) error {
+	var additionalQuery []string
+	if ctx.Since != nil {
+		additionalQuery = append(additionalQuery, "since="+ctx.Since.UTC().Format(time.RFC3339Nano))
+	}
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, ctx.SpaceID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		comments, tc, err := appl.Comments().ListDeleted(ctx, ctx.SpaceID, ctx.Since, &offset, &limit)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		count := int(tc)
+		response := app.CommentList{
+			Links: &app.PagingLinks{},
+			Meta:  &app.CommentListMeta{TotalCount: count},
+			Data:  ConvertComments(ctx.RequestData, comments),
+		}
+		setPagingLinks(response.Links, buildAbsoluteURL(ctx.RequestData), len(comments), offset, limit, count, additionalQuery...)
+		return ctx.OK(&response)
+	})
+}
+
+// Links runs the links action.
+func (c *SpaceTrashController) Links(ctx *app.LinksSpaceTrashContext) error {
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, ctx.SpaceID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		links, tc, err := appl.WorkItemLinks().ListDeleted(ctx, ctx.SpaceID, ctx.Since, &offset, &limit)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		response := app.WorkItemLinkList{
+			Meta: &app.WorkItemLinkListMeta{TotalCount: int(tc)},
+			Data: make([]*app.WorkItemLinkData, len(links)),
+		}
+		for i, l := range links {
+			response.Data[i] = ConvertLinkFromModel(l).Data
+		}
+		return ctx.OK(&response)
+	})
+}
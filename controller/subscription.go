@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/rest"
+	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/subscription"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SubscriptionController implements the subscription resource.
+type SubscriptionController struct {
+	*goa.Controller
+	db     application.DB
+	config SubscriptionControllerConfiguration
+}
+
+// SubscriptionControllerConfiguration the configuration for the SubscriptionController
+type SubscriptionControllerConfiguration interface {
+	GetCacheControlUser() string
+}
+
+// NewSubscriptionController creates a subscription controller.
+func NewSubscriptionController(service *goa.Service, db application.DB, config SubscriptionControllerConfiguration) *SubscriptionController {
+	return &SubscriptionController{
+		Controller: service.NewController("SubscriptionController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+// List runs the list action.
+func (c *SubscriptionController) List(ctx *app.ListSubscriptionContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		subscriptions, err := appl.Subscriptions().List(ctx, *currentUser)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.ConditionalEntities(subscriptions, c.config.GetCacheControlUser, func() error {
+			data := make([]*app.Subscription, len(subscriptions))
+			for i := range subscriptions {
+				data[i] = ConvertSubscription(ctx.RequestData, subscriptions[i])
+			}
+			res := &app.SubscriptionList{
+				Data: data,
+				Meta: &app.SubscriptionListMeta{TotalCount: len(subscriptions)},
+			}
+			return ctx.OK(res)
+		})
+	})
+}
+
+// Show runs the show action.
+func (c *SubscriptionController) Show(ctx *app.ShowSubscriptionContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		s, err := appl.Subscriptions().Load(ctx, *currentUser, ctx.SubscriptionID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.ConditionalEntity(*s, c.config.GetCacheControlUser, func() error {
+			res := &app.SubscriptionSingle{
+				Data: ConvertSubscription(ctx.RequestData, *s),
+			}
+			return ctx.OK(res)
+		})
+	})
+}
+
+// Create runs the create action.
+func (c *SubscriptionController) Create(ctx *app.CreateSubscriptionContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil || ctx.Payload.Data.Attributes.Kind == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.kind", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Attributes.Target == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.target", nil).Expected("not nil"))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		s := subscription.Subscription{
+			IdentityID: *currentUser,
+			Kind:       *ctx.Payload.Data.Attributes.Kind,
+			Target:     *ctx.Payload.Data.Attributes.Target,
+			Reason:     subscription.ReasonManual,
+		}
+		if err := resolveSubscriptionTarget(ctx, appl, *ctx.Payload.Data, &s); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		if err := appl.Subscriptions().Create(ctx, &s); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.SubscriptionSingle{
+			Data: ConvertSubscription(ctx.RequestData, s),
+		}
+		ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.RequestData, app.SubscriptionHref(s.ID)))
+		return ctx.Created(res)
+	})
+}
+
+// Delete runs the delete action.
+func (c *SubscriptionController) Delete(ctx *app.DeleteSubscriptionContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Subscriptions().Delete(ctx, *currentUser, ctx.SubscriptionID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK([]byte{})
+	})
+}
+
+// resolveSubscriptionTarget checks that the target of the given subscription
+// exists and sets the space of the subscription to the one of the target.
+// Work items can be given by their ID or by their key, e.g. PLAT-123.
+func resolveSubscriptionTarget(ctx context.Context, appl application.Application, source app.Subscription, target *subscription.Subscription) error {
+	if target.Kind == subscription.KindWorkItem {
+		wi, err := appl.WorkItems().LoadByID(ctx, target.Target)
+		if err != nil {
+			return errors.NewBadParameterError("data.attributes.target", target.Target).Expected("ID or key of a work item")
+		}
+		target.Target = wi.ID
+		target.SpaceID = wi.SpaceID
+		return nil
+	}
+	if target.Kind == subscription.KindFilter {
+		if source.Relationships == nil || source.Relationships.Space == nil || source.Relationships.Space.Data == nil || source.Relationships.Space.Data.ID == nil {
+			return errors.NewBadParameterError("data.relationships.space", nil).Expected("not nil")
+		}
+		spaceID, err := uuid.FromString(*source.Relationships.Space.Data.ID)
+		if err != nil {
+			return errors.NewBadParameterError("data.relationships.space.data.id", *source.Relationships.Space.Data.ID)
+		}
+		if _, err := appl.Spaces().Load(ctx, spaceID); err != nil {
+			return errors.NewBadParameterError("data.relationships.space.data.id", *source.Relationships.Space.Data.ID).Expected("ID of a space")
+		}
+		target.SpaceID = spaceID
+		return nil
+	}
+	id, err := uuid.FromString(target.Target)
+	if err != nil {
+		return errors.NewBadParameterError("data.attributes.target", target.Target).Expected("ID of " + target.Kind)
+	}
+	switch target.Kind {
+	case subscription.KindArea:
+		a, err := appl.Areas().Load(ctx, id)
+		if err != nil {
+			return errors.NewBadParameterError("data.attributes.target", target.Target).Expected("ID of an area")
+		}
+		target.SpaceID = a.SpaceID
+	case subscription.KindIteration:
+		i, err := appl.Iterations().Load(ctx, id)
+		if err != nil {
+			return errors.NewBadParameterError("data.attributes.target", target.Target).Expected("ID of an iteration")
+		}
+		target.SpaceID = i.SpaceID
+	case subscription.KindSpace:
+		if _, err := appl.Spaces().Load(ctx, id); err != nil {
+			return errors.NewBadParameterError("data.attributes.target", target.Target).Expected("ID of a space")
+		}
+		target.SpaceID = id
+	}
+	return nil
+}
+
+// ConvertSubscription converts from internal to external REST representation
+func ConvertSubscription(request *goa.RequestData, s subscription.Subscription) *app.Subscription {
+	selfURL := rest.AbsoluteURL(request, app.SubscriptionHref(s.ID))
+	spaceID := s.SpaceID.String()
+	spaceSelfURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	return &app.Subscription{
+		Type: subscription.APIStringTypeSubscriptions,
+		ID:   &s.ID,
+		Attributes: &app.SubscriptionAttributes{
+			Kind:      &s.Kind,
+			Target:    &s.Target,
+			Reason:    &s.Reason,
+			CreatedAt: &s.CreatedAt,
+			UpdatedAt: &s.UpdatedAt,
+		},
+		Relationships: &app.SubscriptionRelations{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self: &spaceSelfURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/fabric8io/almighty-core/application"
 	"github.com/fabric8io/almighty-core/jsonapi"
 	"github.com/fabric8io/almighty-core/log"
-	query "github.com/fabric8io/almighty-core/query/simple"
+	"github.com/fabric8io/almighty-core/query"
 	"github.com/fabric8io/almighty-core/remoteworkitem"
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
@@ -21,11 +21,17 @@ import (
 	. "github.com/fabric8io/almighty-core/controller"
 	"github.com/fabric8io/almighty-core/gormsupport"
 	"github.com/fabric8io/almighty-core/iteration"
+	"github.com/fabric8io/almighty-core/label"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/reaction"
 	"github.com/fabric8io/almighty-core/resource"
 	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/subscription"
 	almtoken "github.com/fabric8io/almighty-core/token"
+	"github.com/fabric8io/almighty-core/webhook"
 	"github.com/fabric8io/almighty-core/workitem"
 	"github.com/fabric8io/almighty-core/workitem/link"
+	"github.com/fabric8io/almighty-core/workitem/template"
 	token "github.com/dgrijalva/jwt-go"
 	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/middleware/security/jwt"
@@ -356,6 +362,51 @@ func (g *GormTestBase) Codebases() codebase.Repository {
 	return nil
 }
 
+// WorkItemRevisions returns a work item revision repository
+func (g *GormTestBase) WorkItemRevisions() workitem.RevisionRepository {
+	return nil
+}
+
+// Labels returns a label repository
+func (g *GormTestBase) Labels() label.Repository {
+	return nil
+}
+
+// WorkItemTemplates returns a work item template repository
+func (g *GormTestBase) WorkItemTemplates() template.Repository {
+	return nil
+}
+
+// ChecklistItems returns a checklist item repository
+func (g *GormTestBase) ChecklistItems() workitem.ChecklistRepository {
+	return nil
+}
+
+// Subscriptions returns a subscription repository
+func (g *GormTestBase) Subscriptions() subscription.Repository {
+	return nil
+}
+
+// Webhooks webhooks
+func (g *GormTestBase) Webhooks() webhook.Repository {
+	return nil
+}
+
+// WebhookDeliveries webhook deliveries
+func (g *GormTestBase) WebhookDeliveries() webhook.DeliveryRepository {
+	return nil
+}
+
+// Mentions returns a mention repository
+func (g *GormTestBase) Mentions() mention.Repository {
+	return nil
+}
+
+// Reactions returns a reaction repository
+func (g *GormTestBase) Reactions() reaction.Repository {
+	return nil
+}
+
 func (g *GormTestBase) DB() *gorm.DB {
 	return nil
 }
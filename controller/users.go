@@ -318,6 +318,11 @@ func (c *UsersController) Update(ctx *app.UpdateUsersContext) error {
 			}
 		}
 
+		updatedNotificationMode := ctx.Payload.Data.Attributes.NotificationMode
+		if updatedNotificationMode != nil {
+			user.NotificationMode = *updatedNotificationMode
+		}
+
 		err = appl.Users().Save(ctx, user)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, err)
@@ -539,6 +544,7 @@ func ConvertToAppUser(request *goa.RequestData, user *account.User, identity *ac
 	var updatedAt time.Time
 	var company string
 	var contextInformation workitem.Fields
+	var notificationMode string
 
 	if user != nil {
 		fullName = user.FullName
@@ -548,6 +554,7 @@ func ConvertToAppUser(request *goa.RequestData, user *account.User, identity *ac
 		email = user.Email
 		company = user.Company
 		contextInformation = user.ContextInformation
+		notificationMode = user.NotificationMode
 		// CreatedAt and UpdatedAt fields in the resulting app.Identity are based on the 'user' entity
 		createdAt = user.CreatedAt
 		updatedAt = user.UpdatedAt
@@ -580,6 +587,7 @@ func ConvertToAppUser(request *goa.RequestData, user *account.User, identity *ac
 				Email:                 &email,
 				Company:               &company,
 				ContextInformation:    workitem.Fields{},
+				NotificationMode:      &notificationMode,
 				RegistrationCompleted: &registrationCompleted,
 			},
 			Links: createUserLinks(request, &identity.ID),
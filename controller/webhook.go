@@ -0,0 +1,292 @@
+package controller
+
+import (
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/rest"
+	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/webhook"
+
+	"github.com/goadesign/goa"
+)
+
+// WebhookController implements the webhook resource.
+type WebhookController struct {
+	*goa.Controller
+	db     application.DB
+	config WebhookControllerConfiguration
+}
+
+// WebhookControllerConfiguration the configuration for the WebhookController
+type WebhookControllerConfiguration interface {
+	GetCacheControlUser() string
+}
+
+// NewWebhookController creates a webhook controller.
+func NewWebhookController(service *goa.Service, db application.DB, config WebhookControllerConfiguration) *WebhookController {
+	return &WebhookController{
+		Controller: service.NewController("WebhookController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+// List runs the list action.
+func (c *WebhookController) List(ctx *app.ListWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		webhooks, err := appl.Webhooks().List(ctx, ctx.SpaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.ConditionalEntities(webhooks, c.config.GetCacheControlUser, func() error {
+			data := make([]*app.Webhook, len(webhooks))
+			for i := range webhooks {
+				data[i] = ConvertWebhook(ctx.RequestData, webhooks[i], false)
+			}
+			res := &app.WebhookList{
+				Data: data,
+				Meta: &app.WorkItemListResponseMeta{TotalCount: len(webhooks)},
+			}
+			return ctx.OK(res)
+		})
+	})
+}
+
+// Show runs the show action.
+func (c *WebhookController) Show(ctx *app.ShowWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		w, err := appl.Webhooks().Load(ctx, ctx.SpaceID, ctx.WebhookID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.ConditionalEntity(*w, c.config.GetCacheControlUser, func() error {
+			res := &app.WebhookSingle{
+				Data: ConvertWebhook(ctx.RequestData, *w, false),
+			}
+			return ctx.OK(res)
+		})
+	})
+}
+
+// Create runs the create action. A secret is generated if none is given, the
+// secret is only returned in the response of this action.
+func (c *WebhookController) Create(ctx *app.CreateWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil || ctx.Payload.Data.Attributes.URL == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.url", nil).Expected("not nil"))
+	}
+	w := webhook.Webhook{
+		SpaceID: ctx.SpaceID,
+	}
+	convertWebhookToModel(*ctx.Payload.Data, &w)
+	if w.Secret == "" {
+		w.Secret, err = webhook.NewSecret()
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewInternalError(err))
+		}
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		return appl.Webhooks().Create(ctx, &w)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WebhookSingle{
+		Data: ConvertWebhook(ctx.RequestData, w, true),
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.RequestData, app.WebhookHref(ctx.SpaceID.String(), w.ID.String())))
+	return ctx.Created(res)
+}
+
+// Update runs the update action.
+func (c *WebhookController) Update(ctx *app.UpdateWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Attributes.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	var w *webhook.Webhook
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		existing, err := appl.Webhooks().Load(ctx, ctx.SpaceID, ctx.WebhookID)
+		if err != nil {
+			return err
+		}
+		existing.Version = *ctx.Payload.Data.Attributes.Version
+		convertWebhookToModel(*ctx.Payload.Data, existing)
+		w, err = appl.Webhooks().Save(ctx, *existing)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WebhookSingle{
+		Data: ConvertWebhook(ctx.RequestData, *w, false),
+	}
+	return ctx.OK(res)
+}
+
+// Delete runs the delete action.
+func (c *WebhookController) Delete(ctx *app.DeleteWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		return appl.Webhooks().Delete(ctx, ctx.SpaceID, ctx.WebhookID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// Deliveries runs the deliveries action.
+func (c *WebhookController) Deliveries(ctx *app.DeliveriesWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		if _, err := appl.Webhooks().Load(ctx, ctx.SpaceID, ctx.WebhookID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		deliveries, tc, err := appl.WebhookDeliveries().List(ctx, ctx.WebhookID, &offset, &limit)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		count := int(tc)
+		res := &app.WebhookDeliveryList{
+			Data:  make([]*app.WebhookDelivery, len(deliveries)),
+			Links: &app.PagingLinks{},
+			Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
+		}
+		for i := range deliveries {
+			res.Data[i] = ConvertWebhookDelivery(deliveries[i])
+		}
+		setPagingLinks(res.Links, buildAbsoluteURL(ctx.RequestData), len(deliveries), offset, limit, count)
+		return ctx.OK(res)
+	})
+}
+
+// convertWebhookToModel applies the attributes given in the request to the
+// given webhook
+func convertWebhookToModel(source app.Webhook, target *webhook.Webhook) {
+	if source.Attributes == nil {
+		return
+	}
+	if source.Attributes.URL != nil {
+		target.URL = *source.Attributes.URL
+	}
+	if source.Attributes.Secret != nil {
+		target.Secret = *source.Attributes.Secret
+	}
+	if source.Attributes.Events != nil {
+		target.Events = webhook.Events(source.Attributes.Events)
+	}
+}
+
+// ConvertWebhook converts from internal to external REST representation. The
+// secret is only included if withSecret is true.
+func ConvertWebhook(request *goa.RequestData, w webhook.Webhook, withSecret bool) *app.Webhook {
+	spaceID := w.SpaceID.String()
+	selfURL := rest.AbsoluteURL(request, app.WebhookHref(spaceID, w.ID.String()))
+	deliveriesURL := selfURL + "/deliveries"
+	spaceSelfURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	events := []string(w.Events)
+	res := &app.Webhook{
+		Type: webhook.APIStringTypeWebhooks,
+		ID:   &w.ID,
+		Attributes: &app.WebhookAttributes{
+			URL:       &w.URL,
+			Events:    events,
+			CreatedAt: &w.CreatedAt,
+			UpdatedAt: &w.UpdatedAt,
+			Version:   &w.Version,
+		},
+		Relationships: &app.WebhookRelations{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self: &spaceSelfURL,
+				},
+			},
+			Deliveries: &app.RelationGeneric{
+				Links: &app.GenericLinks{
+					Related: &deliveriesURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+	if withSecret {
+		res.Attributes.Secret = &w.Secret
+	}
+	return res
+}
+
+// ConvertWebhookDelivery converts from internal to external REST representation
+func ConvertWebhookDelivery(d webhook.Delivery) *app.WebhookDelivery {
+	res := &app.WebhookDelivery{
+		Type: webhook.APIStringTypeDeliveries,
+		ID:   &d.ID,
+		Attributes: &app.WebhookDeliveryAttributes{
+			EventID:    &d.EventID,
+			EventType:  &d.EventType,
+			State:      &d.State,
+			Attempts:   &d.Attempts,
+			StatusCode: &d.StatusCode,
+			CreatedAt:  &d.CreatedAt,
+			UpdatedAt:  &d.UpdatedAt,
+		},
+	}
+	if d.Error != "" {
+		res.Attributes.Error = &d.Error
+	}
+	if d.NextAttemptAt != nil {
+		res.Attributes.NextAttemptAt = d.NextAttemptAt
+	}
+	return res
+}
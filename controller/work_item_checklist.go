@@ -0,0 +1,307 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/rest"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WorkItemChecklistController implements the work_item_checklist resource.
+type WorkItemChecklistController struct {
+	*goa.Controller
+	db     application.DB
+	config WorkItemChecklistControllerConfiguration
+}
+
+// WorkItemChecklistControllerConfiguration the configuration for the WorkItemChecklistController
+type WorkItemChecklistControllerConfiguration interface {
+	GetCacheControlWorkItems() string
+}
+
+// NewWorkItemChecklistController creates a work_item_checklist controller.
+func NewWorkItemChecklistController(service *goa.Service, db application.DB, config WorkItemChecklistControllerConfiguration) *WorkItemChecklistController {
+	return &WorkItemChecklistController{
+		Controller: service.NewController("WorkItemChecklistController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemChecklistController) List(ctx *app.ListWorkItemChecklistContext) error {
+	return application.Transactional(c.db, func(appl application.Application) error {
+		wi, err := appl.WorkItems().Load(ctx, ctx.SpaceID, ctx.WiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		items, err := appl.ChecklistItems().List(ctx, ctx.SpaceID, wi.ID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.ConditionalEntities(items, c.config.GetCacheControlWorkItems, func() error {
+			return ctx.OK(convertChecklistItemList(ctx.RequestData, *wi, items))
+		})
+	})
+}
+
+// Create runs the create action.
+func (c *WorkItemChecklistController) Create(ctx *app.CreateWorkItemChecklistContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil || ctx.Payload.Data.Attributes.Text == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.text", nil).Expected("not nil"))
+	}
+	var wi *workitem.WorkItem
+	item := workitem.ChecklistItem{}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = authorizeChecklistEditor(ctx, c.db, appl, ctx.SpaceID, ctx.WiID, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		if err := convertChecklistItemToModel(ctx, appl, *ctx.Payload.Data, &item); err != nil {
+			return err
+		}
+		return appl.ChecklistItems().Create(ctx, ctx.SpaceID, wi.ID, &item, *currentUserIdentityID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.ChecklistItemSingle{
+		Data: ConvertChecklistItem(ctx.RequestData, *wi, item),
+	}
+	ctx.ResponseData.Header().Set("Location", checklistItemURL(ctx.RequestData, *wi, item.ID))
+	return ctx.Created(res)
+}
+
+// Update runs the update action.
+func (c *WorkItemChecklistController) Update(ctx *app.UpdateWorkItemChecklistContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil || ctx.Payload.Data.Attributes.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	var wi *workitem.WorkItem
+	var saved *workitem.ChecklistItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = authorizeChecklistEditor(ctx, c.db, appl, ctx.SpaceID, ctx.WiID, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		items, err := appl.ChecklistItems().List(ctx, ctx.SpaceID, wi.ID)
+		if err != nil {
+			return err
+		}
+		var item *workitem.ChecklistItem
+		for i := range items {
+			if uuid.Equal(items[i].ID, ctx.ItemID) {
+				item = &items[i]
+			}
+		}
+		if item == nil {
+			return errors.NewNotFoundError("checklist item", ctx.ItemID.String())
+		}
+		if err := convertChecklistItemToModel(ctx, appl, *ctx.Payload.Data, item); err != nil {
+			return err
+		}
+		item.Version = *ctx.Payload.Data.Attributes.Version
+		saved, err = appl.ChecklistItems().Save(ctx, ctx.SpaceID, wi.ID, *item, *currentUserIdentityID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.ChecklistItemSingle{
+		Data: ConvertChecklistItem(ctx.RequestData, *wi, *saved),
+	}
+	return ctx.OK(res)
+}
+
+// Delete runs the delete action.
+func (c *WorkItemChecklistController) Delete(ctx *app.DeleteWorkItemChecklistContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err := authorizeChecklistEditor(ctx, c.db, appl, ctx.SpaceID, ctx.WiID, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		return appl.ChecklistItems().Delete(ctx, ctx.SpaceID, wi.ID, ctx.ItemID, *currentUserIdentityID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// Reorder runs the reorder action.
+func (c *WorkItemChecklistController) Reorder(ctx *app.ReorderWorkItemChecklistContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var wi *workitem.WorkItem
+	var items []workitem.ChecklistItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = authorizeChecklistEditor(ctx, c.db, appl, ctx.SpaceID, ctx.WiID, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		items, err = appl.ChecklistItems().Reorder(ctx, ctx.SpaceID, wi.ID, ctx.Payload.Items, *currentUserIdentityID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(convertChecklistItemList(ctx.RequestData, *wi, items))
+}
+
+// authorizeChecklistEditor loads the work item with the given ID and checks
+// that the given user may change its checklist, i.e. may edit the work item
+func authorizeChecklistEditor(ctx context.Context, db application.DB, appl application.Application, spaceID uuid.UUID, wiID string, editorID uuid.UUID) (*workitem.WorkItem, error) {
+	wi, err := appl.WorkItems().Load(ctx, spaceID, wiID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load work item with id %s", wiID)
+	}
+	creator := wi.Fields[workitem.SystemCreator]
+	if creator == nil {
+		return nil, errors.NewInternalError(errs.New("work item doesn't have creator"))
+	}
+	authorized, err := authorizeWorkitemEditor(ctx, db, spaceID, creator.(string), editorID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !authorized {
+		return nil, errors.NewForbiddenError("user is not authorized to access the space")
+	}
+	return wi, nil
+}
+
+// convertChecklistItemToModel sets the values of the given checklist item
+// from the attributes and relationships given in the payload
+func convertChecklistItemToModel(ctx context.Context, appl application.Application, source app.ChecklistItem, target *workitem.ChecklistItem) error {
+	if source.Attributes != nil {
+		if source.Attributes.Text != nil {
+			target.Text = *source.Attributes.Text
+		}
+		if source.Attributes.Done != nil {
+			target.Done = *source.Attributes.Done
+		}
+	}
+	if source.Relationships != nil && source.Relationships.Assignee != nil {
+		target.Assignee = nil
+		if source.Relationships.Assignee.Data != nil && source.Relationships.Assignee.Data.ID != nil {
+			assigneeID, err := uuid.FromString(*source.Relationships.Assignee.Data.ID)
+			if err != nil || !appl.Identities().IsValid(ctx, assigneeID) {
+				return errors.NewBadParameterError("data.relationships.assignee.data.id", *source.Relationships.Assignee.Data.ID)
+			}
+			target.Assignee = &assigneeID
+		}
+	}
+	return nil
+}
+
+func checklistURL(request *goa.RequestData, wi workitem.WorkItem) string {
+	return rest.AbsoluteURL(request, app.WorkitemHref(wi.SpaceID, wi.ID)) + "/checklist"
+}
+
+func checklistItemURL(request *goa.RequestData, wi workitem.WorkItem, id uuid.UUID) string {
+	return checklistURL(request, wi) + "/" + id.String()
+}
+
+func convertChecklistItemList(request *goa.RequestData, wi workitem.WorkItem, items []workitem.ChecklistItem) *app.ChecklistItemList {
+	meta := &app.ChecklistListMeta{Total: len(items)}
+	data := make([]*app.ChecklistItem, len(items))
+	for i, item := range items {
+		if item.Done {
+			meta.Done++
+		}
+		data[i] = ConvertChecklistItem(request, wi, item)
+	}
+	selfURL := checklistURL(request, wi)
+	return &app.ChecklistItemList{
+		Data:  data,
+		Meta:  meta,
+		Links: &app.GenericLinks{Self: &selfURL},
+	}
+}
+
+// ConvertChecklistItem converts from internal to external REST representation
+func ConvertChecklistItem(request *goa.RequestData, wi workitem.WorkItem, item workitem.ChecklistItem) *app.ChecklistItem {
+	selfURL := checklistItemURL(request, wi, item.ID)
+	wiSelfURL := rest.AbsoluteURL(request, app.WorkitemHref(wi.SpaceID, wi.ID))
+	wiType := APIStringTypeWorkItem
+	id := item.ID
+	res := &app.ChecklistItem{
+		Type: workitem.APIStringTypeChecklistItems,
+		ID:   &id,
+		Attributes: &app.ChecklistItemAttributes{
+			Text:      &item.Text,
+			Done:      &item.Done,
+			Position:  &item.Position,
+			CreatedAt: &item.CreatedAt,
+			UpdatedAt: &item.UpdatedAt,
+			Version:   &item.Version,
+		},
+		Relationships: &app.ChecklistItemRelations{
+			Assignee: &app.RelationGeneric{Data: nil},
+			Workitem: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &wiType,
+					ID:   &wi.ID,
+				},
+				Links: &app.GenericLinks{
+					Self: &wiSelfURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+	if item.Assignee != nil {
+		res.Relationships.Assignee.Data = ConvertUserSimple(request, *item.Assignee)
+	}
+	return res
+}
+
+// workItemIncludeChecklist adds the link to the checklist of the work item
+// along with the number of done and total items, e.g. "3/7 done"
+func workItemIncludeChecklist(appl application.Application, ctx context.Context) WorkItemConvertFunc {
+	return func(request *goa.RequestData, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+		related := checklistURL(request, *wi)
+		wi2.Relationships.Checklist = &app.RelationGeneric{
+			Links: &app.GenericLinks{
+				Related: &related,
+			},
+		}
+		progress, err := appl.ChecklistItems().Progress(ctx, wi.ID)
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"wi_id": wi.ID,
+				"err":   err,
+			}, "unable to count the checklist items of the work item: %s", wi.ID)
+			return
+		}
+		wi2.Relationships.Checklist.Meta = map[string]interface{}{
+			"done":  progress.Done,
+			"total": progress.Total,
+		}
+	}
+}
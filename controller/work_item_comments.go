@@ -12,6 +12,7 @@ import (
 	"github.com/fabric8io/almighty-core/workitem"
 	"github.com/goadesign/goa"
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 // WorkItemCommentsController implements the work-item-comments resource.
@@ -37,7 +38,7 @@ func NewWorkItemCommentsController(service *goa.Service, db application.DB, conf
 // Create runs the create action.
 func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsContext) error {
 	return application.Transactional(c.db, func(appl application.Application) error {
-		_, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		wi, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
 		}
@@ -49,20 +50,25 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 
 		reqComment := ctx.Payload.Data
 		markup := rendering.NilSafeGetMarkup(reqComment.Attributes.Markup)
+		replyTo, err := commentReplyTo(reqComment)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
 		newComment := comment.Comment{
-			ParentID:  ctx.WiID,
+			ParentID:  wi.ID,
 			Body:      reqComment.Attributes.Body,
 			Markup:    markup,
 			CreatedBy: *currentUserIdentityID,
+			ReplyTo:   replyTo,
 		}
 
 		err = appl.Comments().Create(ctx, &newComment, *currentUserIdentityID)
 		if err != nil {
-			return jsonapi.JSONErrorResponse(ctx, goa.ErrInternal(err.Error()))
+			return jsonapi.JSONErrorResponse(ctx, err)
 		}
 
 		res := &app.CommentSingle{
-			Data: ConvertComment(ctx.RequestData, newComment),
+			Data: ConvertComment(ctx.RequestData, newComment, commentIncludeKeyLinks(ctx, appl), commentIncludeMentions(ctx, appl), commentIncludeReactions(ctx, appl)),
 		}
 		return ctx.OK(res)
 	})
@@ -72,11 +78,14 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext) error {
 	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
 	return application.Transactional(c.db, func(appl application.Application) error {
-		_, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		wi, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
 		}
-		comments, tc, err := appl.Comments().List(ctx, ctx.WiID, &offset, &limit)
+		if ctx.Include != nil && *ctx.Include == includeReplies {
+			return c.listThreads(ctx, appl, wi.ID, offset, limit)
+		}
+		comments, tc, err := appl.Comments().List(ctx, wi.ID, &offset, &limit)
 		count := int(tc)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrInternal(err.Error()))
@@ -85,7 +94,7 @@ func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext)
 			res := &app.CommentList{}
 			res.Data = []*app.Comment{}
 			res.Meta = &app.CommentListMeta{TotalCount: count}
-			res.Data = ConvertComments(ctx.RequestData, comments)
+			res.Data = ConvertComments(ctx.RequestData, comments, commentIncludeKeyLinks(ctx, appl), commentIncludeMentions(ctx, appl), commentIncludeReactions(ctx, appl))
 			res.Links = &app.PagingLinks{}
 			setPagingLinks(res.Links, buildAbsoluteURL(ctx.RequestData), len(comments), offset, limit, count)
 			return ctx.OK(res)
@@ -93,6 +102,44 @@ func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext)
 	})
 }
 
+// includeReplies is the value of the include parameter to list the comments
+// with their replies
+const includeReplies = "replies"
+
+// listThreads lists the comments which are not replies, along with their
+// replies in the included resources. The direct replies to each comment are
+// listed in its replies relationship.
+func (c *WorkItemCommentsController) listThreads(ctx *app.ListWorkItemCommentsContext, appl application.Application, parentID string, offset, limit int) error {
+	threads, tc, err := appl.Comments().ListThreads(ctx, parentID, &offset, &limit)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ids := make([]uuid.UUID, len(threads))
+	for i := range threads {
+		ids[i] = threads[i].ID
+	}
+	replies, err := appl.Comments().ListReplies(ctx, ids...)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	count := int(tc)
+	// a new reply changes the response as well
+	entities := append(append([]comment.Comment{}, threads...), replies...)
+	return ctx.ConditionalEntities(entities, c.config.GetCacheControlComments, func() error {
+		additional := []CommentConvertFunc{commentIncludeKeyLinks(ctx, appl), commentIncludeMentions(ctx, appl), commentIncludeReactions(ctx, appl), commentIncludeReplies(replies)}
+		res := &app.CommentList{
+			Data:  ConvertComments(ctx.RequestData, threads, additional...),
+			Meta:  &app.CommentListMeta{TotalCount: count},
+			Links: &app.PagingLinks{},
+		}
+		for _, reply := range ConvertComments(ctx.RequestData, replies, additional...) {
+			res.Included = append(res.Included, reply)
+		}
+		setPagingLinks(res.Links, buildAbsoluteURL(ctx.RequestData), len(threads), offset, limit, count)
+		return ctx.OK(res)
+	})
+}
+
 // Relations runs the relation action.
 // TODO: Should only return Resource Identifier Objects, not complete object (See List)
 func (c *WorkItemCommentsController) Relations(ctx *app.RelationsWorkItemCommentsContext) error {
@@ -103,7 +150,7 @@ func (c *WorkItemCommentsController) Relations(ctx *app.RelationsWorkItemComment
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
 		}
 
-		comments, tc, err := appl.Comments().List(ctx, ctx.WiID, &offset, &limit)
+		comments, tc, err := appl.Comments().List(ctx, wi.ID, &offset, &limit)
 		count := int(tc)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrInternal(err.Error()))
@@ -319,6 +319,26 @@ func (c *WorkItemLinkController) Delete(ctx *app.DeleteWorkItemLinkContext) erro
 	})
 }
 
+// Restore runs the restore action
+func (c *WorkItemLinkController) Restore(ctx *app.RestoreWorkItemLinkContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		modelLink, err := appl.WorkItemLinks().Restore(ctx.Context, ctx.LinkID, *currentUserIdentityID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		linkCtx := newWorkItemLinkContext(ctx.Context, appl, c.db, ctx.RequestData, ctx.ResponseData, app.WorkItemLinkHref, currentUserIdentityID)
+		appLink := ConvertLinkFromModel(*modelLink)
+		if err := enrichLinkSingle(linkCtx, &appLink); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK(&appLink)
+	})
+}
+
 type listWorkItemLinkFuncs interface {
 	OK(r *app.WorkItemLinkList) error
 	BadRequest(r *app.JSONAPIErrors) error
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/rest"
+	"github.com/fabric8io/almighty-core/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WorkItemRevisionsController implements the work_item_revisions resource.
+type WorkItemRevisionsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemRevisionsController creates a work_item_revisions controller.
+func NewWorkItemRevisionsController(service *goa.Service, db application.DB) *WorkItemRevisionsController {
+	return &WorkItemRevisionsController{
+		Controller: service.NewController("WorkItemRevisionsController"),
+		db:         db,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemRevisionsController) List(ctx *app.ListWorkItemRevisionsContext) error {
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	return application.Transactional(c.db, func(appl application.Application) error {
+		wi, err := appl.WorkItems().Load(ctx, ctx.SpaceID, ctx.WiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		// also load the two revisions preceeding the requested page in order
+		// to compute the changes of the first revision on the page: if the
+		// revision right before it is a deletion, the changes are computed
+		// against the one before the deletion
+		start := offset - 2
+		if start < 0 {
+			start = 0
+		}
+		revisions, count, err := appl.WorkItemRevisions().ListPage(ctx, wi.ID, start, limit+offset-start)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		var previous *workitem.Revision
+		for len(revisions) > 0 && start < offset {
+			if revisions[0].Type != workitem.RevisionTypeDelete {
+				previous = &revisions[0]
+			}
+			revisions = revisions[1:]
+			start++
+		}
+		wits := map[uuid.UUID]*workitem.WorkItemType{}
+		res := &app.WorkItemRevisionList{
+			Data:  []*app.WorkItemRevision{},
+			Meta:  &app.WorkItemRevisionListMeta{TotalCount: int(count)},
+			Links: &app.PagingLinks{},
+		}
+		for i := range revisions {
+			wit, ok := wits[revisions[i].WorkItemTypeID]
+			if !ok {
+				wit, err = appl.WorkItemTypes().LoadByID(ctx, revisions[i].WorkItemTypeID)
+				if err != nil {
+					return jsonapi.JSONErrorResponse(ctx, err)
+				}
+				wits[revisions[i].WorkItemTypeID] = wit
+			}
+			res.Data = append(res.Data, ConvertWorkItemRevision(ctx.RequestData, *wi, revisions[i], previous, wit))
+			// a deletion stores no fields, so the changes of a restoration
+			// are computed against the revision before the deletion
+			if revisions[i].Type != workitem.RevisionTypeDelete {
+				previous = &revisions[i]
+			}
+		}
+		setPagingLinks(res.Links, buildAbsoluteURL(ctx.RequestData), len(revisions), offset, limit, int(count))
+		return ctx.OK(res)
+	})
+}
+
+// ConvertWorkItemRevision converts a revision of the given work item into its
+// JSON-API representation. The changes are computed against the previous
+// revision, which is nil for the first revision of the work item.
+func ConvertWorkItemRevision(request *goa.RequestData, wi workitem.WorkItem, revision workitem.Revision, previous *workitem.Revision, wit *workitem.WorkItemType) *app.WorkItemRevision {
+	relatedModifierLink := rest.AbsoluteURL(request, fmt.Sprintf("%s/%s", usersEndpoint, revision.ModifierIdentity.String()))
+	relatedWorkItemLink := rest.AbsoluteURL(request, app.WorkitemHref(wi.SpaceID, wi.ID))
+	workItemType := APIStringTypeWorkItem
+	changes := []*app.WorkItemFieldChange{}
+	for _, change := range revision.Changes(previous, wit) {
+		oldValue, newValue := change.OldValue, change.NewValue
+		c := &app.WorkItemFieldChange{
+			Field:    change.Name,
+			OldValue: &oldValue,
+			NewValue: &newValue,
+		}
+		if change.TextDiff != nil {
+			c.TextDiff = make([]*app.TextDiffChunk, len(change.TextDiff))
+			for i, d := range change.TextDiff {
+				c.TextDiff[i] = &app.TextDiffChunk{
+					Operation: string(d.Operation),
+					Text:      d.Text,
+				}
+			}
+		}
+		changes = append(changes, c)
+	}
+	return &app.WorkItemRevision{
+		Type: "workitemrevisions",
+		ID:   &revision.ID,
+		Attributes: &app.WorkItemRevisionAttributes{
+			RevisionTime: revision.Time,
+			RevisionType: revision.Type.String(),
+			Version:      revision.WorkItemVersion,
+			Changes:      changes,
+		},
+		Relationships: &app.WorkItemRevisionRelationships{
+			Modifier: &app.RevisionModifier{
+				Data: &app.IdentityRelationData{
+					Type: "identities",
+					ID:   &revision.ModifierIdentity,
+				},
+				Links: &app.GenericLinks{
+					Related: &relatedModifierLink,
+				},
+			},
+			Workitem: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &workItemType,
+					ID:   &wi.ID,
+				},
+				Links: &app.GenericLinks{
+					Related: &relatedWorkItemLink,
+				},
+			},
+		},
+	}
+}
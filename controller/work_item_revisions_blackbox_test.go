@@ -0,0 +1,132 @@
+package controller_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/app/test"
+	. "github.com/fabric8io/almighty-core/controller"
+	"github.com/fabric8io/almighty-core/gormapplication"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+	testsupport "github.com/fabric8io/almighty-core/test"
+	"github.com/fabric8io/almighty-core/workitem"
+	"github.com/goadesign/goa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemRevisionsREST struct {
+	gormtestsupport.DBTestSuite
+	db           *gormapplication.GormDB
+	clean        func()
+	testIdentity account.Identity
+	ctx          context.Context
+}
+
+func TestRunWorkItemRevisionsREST(t *testing.T) {
+	suite.Run(t, &TestWorkItemRevisionsREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (rest *TestWorkItemRevisionsREST) SetupTest() {
+	resource.Require(rest.T(), resource.Database)
+	rest.db = gormapplication.NewGormDB(rest.DB)
+	rest.clean = cleaner.DeleteCreatedEntities(rest.DB)
+	testIdentity, err := testsupport.CreateTestIdentity(rest.DB, "TestWorkItemRevisionsREST setup user", "test provider")
+	require.Nil(rest.T(), err)
+	rest.testIdentity = testIdentity
+	req := &http.Request{Host: "localhost"}
+	params := url.Values{}
+	rest.ctx = goa.NewContext(context.Background(), nil, req, params)
+}
+
+func (rest *TestWorkItemRevisionsREST) TearDownTest() {
+	rest.clean()
+}
+
+func (rest *TestWorkItemRevisionsREST) TestListRevisions() {
+	// given
+	repo := workitem.NewWorkItemRepository(rest.DB)
+	wi, err := repo.Create(rest.ctx, space.SystemSpace, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle:       "Title",
+			workitem.SystemState:       workitem.SystemStateNew,
+			workitem.SystemDescription: rendering.NewMarkupContentFromLegacy("the quick fox"),
+		}, rest.testIdentity.ID)
+	require.Nil(rest.T(), err)
+	wi.Fields[workitem.SystemState] = workitem.SystemStateOpen
+	wi, err = repo.Save(rest.ctx, space.SystemSpace, *wi, rest.testIdentity.ID)
+	require.Nil(rest.T(), err)
+	wi.Fields[workitem.SystemDescription] = rendering.NewMarkupContentFromLegacy("the quick brown fox")
+	wi, err = repo.Save(rest.ctx, space.SystemSpace, *wi, rest.testIdentity.ID)
+	require.Nil(rest.T(), err)
+	svc := goa.New("WorkItemRevisions-Service")
+	ctrl := NewWorkItemRevisionsController(svc, rest.db)
+
+	rest.T().Run("all revisions", func(t *testing.T) {
+		// when
+		_, res := test.ListWorkItemRevisionsOK(t, svc.Context, svc, ctrl, space.SystemSpace, wi.ID, nil, nil)
+		// then
+		require.Len(t, res.Data, 3)
+		assert.Equal(t, 3, res.Meta.TotalCount)
+		assert.Equal(t, "create", res.Data[0].Attributes.RevisionType)
+		assert.Equal(t, rest.testIdentity.ID, *res.Data[0].Relationships.Modifier.Data.ID)
+		state := res.Data[1].Attributes.Changes
+		require.Len(t, state, 1)
+		assert.Equal(t, workitem.SystemState, state[0].Field)
+		assert.Equal(t, workitem.SystemStateNew, *state[0].OldValue)
+		assert.Equal(t, workitem.SystemStateOpen, *state[0].NewValue)
+		description := res.Data[2].Attributes.Changes
+		require.Len(t, description, 1)
+		assert.Equal(t, workitem.SystemDescription, description[0].Field)
+		assert.Equal(t, "the quick brown fox", *description[0].NewValue)
+		require.Len(t, description[0].TextDiff, 3)
+		assert.Equal(t, "insert", description[0].TextDiff[1].Operation)
+		assert.Equal(t, "brown ", description[0].TextDiff[1].Text)
+	})
+
+	rest.T().Run("second page", func(t *testing.T) {
+		// when
+		limit := 1
+		offset := "1"
+		_, res := test.ListWorkItemRevisionsOK(t, svc.Context, svc, ctrl, space.SystemSpace, wi.ID, &limit, &offset)
+		// then
+		require.Len(t, res.Data, 1)
+		assert.Equal(t, 3, res.Meta.TotalCount)
+		assert.Equal(t, "update", res.Data[0].Attributes.RevisionType)
+		// the changes are computed against the revision on the previous page
+		require.Len(t, res.Data[0].Attributes.Changes, 1)
+		assert.Equal(t, workitem.SystemState, res.Data[0].Attributes.Changes[0].Field)
+	})
+
+	rest.T().Run("unknown work item", func(t *testing.T) {
+		test.ListWorkItemRevisionsNotFound(t, svc.Context, svc, ctrl, space.SystemSpace, "88888888", nil, nil)
+	})
+
+	rest.T().Run("restore", func(t *testing.T) {
+		// given
+		require.Nil(t, repo.Delete(rest.ctx, space.SystemSpace, wi.ID, rest.testIdentity.ID))
+		_, _, err := repo.Restore(rest.ctx, space.SystemSpace, wi.ID, rest.testIdentity.ID)
+		require.Nil(t, err)
+		// when
+		_, all := test.ListWorkItemRevisionsOK(t, svc.Context, svc, ctrl, space.SystemSpace, wi.ID, nil, nil)
+		limit := 1
+		offset := "4"
+		_, page := test.ListWorkItemRevisionsOK(t, svc.Context, svc, ctrl, space.SystemSpace, wi.ID, &limit, &offset)
+		// then the restoration is compared to the revision before the deletion
+		require.Len(t, all.Data, 5)
+		assert.Equal(t, "delete", all.Data[3].Attributes.RevisionType)
+		assert.Equal(t, "restore", all.Data[4].Attributes.RevisionType)
+		assert.Empty(t, all.Data[4].Attributes.Changes)
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, "restore", page.Data[0].Attributes.RevisionType)
+		assert.Empty(t, page.Data[0].Attributes.Changes)
+	})
+}
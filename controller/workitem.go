@@ -14,9 +14,11 @@ import (
 	"github.com/fabric8io/almighty-core/criteria"
 	"github.com/fabric8io/almighty-core/errors"
 	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/label"
 	"github.com/fabric8io/almighty-core/log"
 	"github.com/fabric8io/almighty-core/login"
-	query "github.com/fabric8io/almighty-core/query/simple"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/query"
 	"github.com/fabric8io/almighty-core/rendering"
 	"github.com/fabric8io/almighty-core/rest"
 	"github.com/fabric8io/almighty-core/space/authz"
@@ -58,6 +60,26 @@ func NewWorkitemController(service *goa.Service, db application.DB, config WorkI
 		config:     config}
 }
 
+// workItemTypeExpression returns an expression that matches the work items of
+// the given type and of all types that extend it
+func workItemTypeExpression(ctx context.Context, db application.DB, typeID uuid.UUID) (criteria.Expression, error) {
+	values := []criteria.Expression{criteria.Literal(typeID)}
+	err := application.Transactional(db, func(appl application.Application) error {
+		subtypes, err := appl.WorkItemTypes().ListSubtypes(ctx, typeID)
+		if err != nil {
+			return errs.Wrap(err, "unable to fetch the subtypes of the work item type")
+		}
+		for _, subtype := range subtypes {
+			values = append(values, criteria.Literal(subtype.ID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return criteria.In(criteria.Field("Type"), values...), nil
+}
+
 // List runs the list action.
 // Prev and Next links will be present only when there actually IS a next or previous page.
 // Last will always be present. Total Item count needs to be computed from the "Last" link.
@@ -99,7 +121,11 @@ func (c *WorkitemController) List(ctx *app.ListWorkitemContext) error {
 		})
 	}
 	if ctx.FilterWorkitemtype != nil {
-		exp = criteria.And(exp, criteria.Equals(criteria.Field("Type"), criteria.Literal([]uuid.UUID{*ctx.FilterWorkitemtype})))
+		typeExp, err := workItemTypeExpression(ctx.Context, c.db, *ctx.FilterWorkitemtype)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		exp = criteria.And(exp, typeExp)
 		additionalQuery = append(additionalQuery, "filter[workitemtype]="+ctx.FilterWorkitemtype.String())
 	}
 	if ctx.FilterArea != nil {
@@ -110,25 +136,55 @@ func (c *WorkitemController) List(ctx *app.ListWorkitemContext) error {
 		exp = criteria.And(exp, criteria.Equals(criteria.Field(workitem.SystemState), criteria.Literal(string(*ctx.FilterWorkitemstate))))
 		additionalQuery = append(additionalQuery, "filter[workitemstate]="+*ctx.FilterWorkitemstate)
 	}
+	if ctx.FilterLabel != nil {
+		exp = criteria.And(exp, criteria.Equals(criteria.Field(workitem.SystemLabels), criteria.Literal([]string{ctx.FilterLabel.String()})))
+		additionalQuery = append(additionalQuery, "filter[label]="+ctx.FilterLabel.String())
+	}
+	if ctx.FilterChecklist != nil {
+		exp = criteria.And(exp, criteria.GreaterThan(criteria.Field(workitem.SystemChecklistOpen), criteria.Literal(0)))
+		additionalQuery = append(additionalQuery, "filter[checklist]="+*ctx.FilterChecklist)
+	}
 	if ctx.FilterParentexists != nil {
 		// no need to build expression: it is taken care in wi.List call
 		// we need additionalQuery to make sticky filters in URL links
 		additionalQuery = append(additionalQuery, "filter[parentexists]="+strconv.FormatBool(*ctx.FilterParentexists))
 	}
+	if ctx.AsOf != nil {
+		additionalQuery = append(additionalQuery, "asOf="+ctx.AsOf.UTC().Format(time.RFC3339Nano))
+	}
+	if ctx.Sort != nil {
+		if ctx.AsOf != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("sort", *ctx.Sort).Expected("no sort when listing the work items as of a point in time"))
+		}
+		additionalQuery = append(additionalQuery, "sort="+*ctx.Sort)
+	}
 
 	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
 	return application.Transactional(c.db, func(tx application.Application) error {
-		workitems, tc, err := tx.WorkItems().List(ctx.Context, ctx.SpaceID, exp, ctx.FilterParentexists, &offset, &limit)
+		var workitems []workitem.WorkItem
+		var tc uint64
+		var err error
+		if ctx.AsOf != nil {
+			workitems, tc, err = tx.WorkItems().ListAsOf(ctx.Context, ctx.SpaceID, exp, ctx.FilterParentexists, *ctx.AsOf, &offset, &limit)
+		} else if ctx.Sort != nil {
+			workitems, tc, err = tx.WorkItems().ListSorted(ctx.Context, ctx.SpaceID, exp, ctx.FilterParentexists, *ctx.Sort, &offset, &limit)
+		} else {
+			workitems, tc, err = tx.WorkItems().List(ctx.Context, ctx.SpaceID, exp, ctx.FilterParentexists, &offset, &limit)
+		}
 		count := int(tc)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "Error listing work items"))
 		}
 		return ctx.ConditionalEntities(workitems, c.config.GetCacheControlWorkItems, func() error {
 			hasChildren := workItemIncludeHasChildren(tx, ctx)
+			references := workItemIncludeReferences(tx, ctx)
+			key := workItemIncludeKey(tx, ctx)
+			checklist := workItemIncludeChecklist(tx, ctx)
+			reactions := workItemIncludeReactions(tx, ctx)
 			response := app.WorkItemList{
 				Links: &app.PagingLinks{},
 				Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
-				Data:  ConvertWorkItems(ctx.RequestData, workitems, hasChildren),
+				Data:  ConvertWorkItems(ctx.RequestData, workitems, hasChildren, references, key, checklist, reactions),
 			}
 			setPagingLinks(response.Links, buildAbsoluteURL(ctx.RequestData), len(workitems), offset, limit, count, additionalQuery...)
 			addFilterLinks(response.Links, ctx.RequestData)
@@ -196,7 +252,11 @@ func (c *WorkitemController) Update(ctx *app.UpdateWorkitemContext) error {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "Error updating work item"))
 		}
 		hasChildren := workItemIncludeHasChildren(appl, ctx)
-		wi2 := ConvertWorkItem(ctx.RequestData, *wi, hasChildren)
+		references := workItemIncludeReferences(appl, ctx)
+		key := workItemIncludeKey(appl, ctx)
+		checklist := workItemIncludeChecklist(appl, ctx)
+		reactions := workItemIncludeReactions(appl, ctx)
+		wi2 := ConvertWorkItem(ctx.RequestData, *wi, hasChildren, references, key, checklist, reactions)
 		resp := &app.WorkItemSingle{
 			Data: wi2,
 			Links: &app.WorkItemLinks{
@@ -244,7 +304,11 @@ func (c *WorkitemController) Reorder(ctx *app.ReorderWorkitemContext) error {
 				return jsonapi.JSONErrorResponse(ctx, err)
 			}
 			hasChildren := workItemIncludeHasChildren(appl, ctx)
-			wi2 := ConvertWorkItem(ctx.RequestData, *wi, hasChildren)
+			references := workItemIncludeReferences(appl, ctx)
+			key := workItemIncludeKey(appl, ctx)
+			checklist := workItemIncludeChecklist(appl, ctx)
+			reactions := workItemIncludeReactions(appl, ctx)
+			wi2 := ConvertWorkItem(ctx.RequestData, *wi, hasChildren, references, key, checklist, reactions)
 			dataArray = append(dataArray, wi2)
 		}
 		resp := &app.WorkItemReorder{
@@ -297,7 +361,11 @@ func (c *WorkitemController) Create(ctx *app.CreateWorkitemContext) error {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Error creating work item")))
 		}
 		hasChildren := workItemIncludeHasChildren(appl, ctx)
-		wi2 := ConvertWorkItem(ctx.RequestData, *wi, hasChildren)
+		references := workItemIncludeReferences(appl, ctx)
+		key := workItemIncludeKey(appl, ctx)
+		checklist := workItemIncludeChecklist(appl, ctx)
+		reactions := workItemIncludeReactions(appl, ctx)
+		wi2 := ConvertWorkItem(ctx.RequestData, *wi, hasChildren, references, key, checklist, reactions)
 		resp := &app.WorkItemSingle{
 			Data: wi2,
 			Links: &app.WorkItemLinks{
@@ -313,14 +381,25 @@ func (c *WorkitemController) Create(ctx *app.CreateWorkitemContext) error {
 // Show does GET workitem
 func (c *WorkitemController) Show(ctx *app.ShowWorkitemContext) error {
 	return application.Transactional(c.db, func(appl application.Application) error {
-		comments := workItemIncludeCommentsAndTotal(ctx, c.db, ctx.WiID)
 		hasChildren := workItemIncludeHasChildren(appl, ctx)
-		wi, err := appl.WorkItems().Load(ctx, ctx.SpaceID, ctx.WiID)
+		references := workItemIncludeReferences(appl, ctx)
+		key := workItemIncludeKey(appl, ctx)
+		checklist := workItemIncludeChecklist(appl, ctx)
+		reactions := workItemIncludeReactions(appl, ctx)
+		var wi *workitem.WorkItem
+		var err error
+		if ctx.AsOf != nil {
+			wi, err = appl.WorkItems().LoadAsOf(ctx, ctx.SpaceID, ctx.WiID, *ctx.AsOf)
+		} else {
+			wi, err = appl.WorkItems().Load(ctx, ctx.SpaceID, ctx.WiID)
+		}
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Fail to load work item with id %v", ctx.WiID)))
 		}
+		// the ID may as well be the key of the work item, e.g. PLAT-123
+		comments := workItemIncludeCommentsAndTotal(ctx, c.db, wi.ID)
 		return ctx.ConditionalEntity(*wi, c.config.GetCacheControlWorkItems, func() error {
-			wi2 := ConvertWorkItem(ctx.RequestData, *wi, comments, hasChildren)
+			wi2 := ConvertWorkItem(ctx.RequestData, *wi, comments, hasChildren, references, key, checklist, reactions)
 			resp := &app.WorkItemSingle{
 				Data: wi2,
 			}
@@ -349,17 +428,63 @@ func (c *WorkitemController) Delete(ctx *app.DeleteWorkitemContext) error {
 		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
 	}
 	return application.Transactional(c.db, func(appl application.Application) error {
-		err := appl.WorkItems().Delete(ctx, ctx.SpaceID, ctx.WiID, *currentUserIdentityID)
+		// the ID may as well be the key of the work item, e.g. PLAT-123
+		wi, err := appl.WorkItems().Load(ctx, ctx.SpaceID, ctx.WiID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "error deleting work item %s", ctx.WiID))
 		}
-		if err := appl.WorkItemLinks().DeleteRelatedLinks(ctx, ctx.WiID, *currentUserIdentityID); err != nil {
+		err = appl.WorkItems().Delete(ctx, ctx.SpaceID, wi.ID, *currentUserIdentityID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "error deleting work item %s", ctx.WiID))
+		}
+		if err := appl.WorkItemLinks().DeleteRelatedLinks(ctx, wi.ID, *currentUserIdentityID); err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to delete work item links related to work item %s", ctx.WiID))
 		}
+		if err := appl.Comments().DeleteByParent(ctx, wi.ID, *currentUserIdentityID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to delete the comments of work item %s", ctx.WiID))
+		}
 		return ctx.OK([]byte{})
 	})
 }
 
+// Restore does POST workitem restore
+func (c *WorkitemController) Restore(ctx *app.RestoreWorkitemContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	authorized, err := authz.Authorize(ctx, ctx.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		wi, deletedAt, err := appl.WorkItems().Restore(ctx, ctx.SpaceID, ctx.WiID, *currentUserIdentityID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "error restoring work item %s", ctx.WiID))
+		}
+		if ctx.Related != nil && *ctx.Related {
+			if err := appl.WorkItemLinks().RestoreRelatedLinks(ctx, wi.ID, deletedAt, *currentUserIdentityID); err != nil {
+				return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to restore work item links related to work item %s", ctx.WiID))
+			}
+			if err := appl.Comments().RestoreByParent(ctx, wi.ID, deletedAt, *currentUserIdentityID); err != nil {
+				return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to restore comments of work item %s", ctx.WiID))
+			}
+		}
+		hasChildren := workItemIncludeHasChildren(appl, ctx)
+		references := workItemIncludeReferences(appl, ctx)
+		key := workItemIncludeKey(appl, ctx)
+		checklist := workItemIncludeChecklist(appl, ctx)
+		reactions := workItemIncludeReactions(appl, ctx)
+		ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+		return ctx.OK(&app.WorkItemSingle{
+			Data: ConvertWorkItem(ctx.RequestData, *wi, hasChildren, references, key, checklist, reactions),
+		})
+	})
+}
+
 // Time is default value if no UpdatedAt field is found
 func updatedAt(wi workitem.WorkItem) time.Time {
 	var t time.Time
@@ -416,17 +541,24 @@ func ConvertJSONAPIToWorkItem(ctx context.Context, appl application.Application,
 			target.Fields[workitem.SystemAssignees] = ids
 		}
 	}
+	// new work items get the root iteration and area through the default
+	// values of the fields, existing ones are reassigned here
+	isNew := target.ID == ""
 	if source.Relationships != nil {
 		if source.Relationships.Iteration == nil || (source.Relationships.Iteration != nil && source.Relationships.Iteration.Data == nil) {
-			log.Debug(ctx, map[string]interface{}{
-				"wi_id":    target.ID,
-				"space_id": spaceID,
-			}, "assigning the work item to the root iteration of the space.")
-			rootIteration, err := appl.Iterations().Root(ctx, spaceID)
-			if err != nil {
-				return errors.NewBadParameterError("space", spaceID).Expected("valid space ID")
+			if isNew {
+				delete(target.Fields, workitem.SystemIteration)
+			} else {
+				log.Debug(ctx, map[string]interface{}{
+					"wi_id":    target.ID,
+					"space_id": spaceID,
+				}, "assigning the work item to the root iteration of the space.")
+				rootIteration, err := appl.Iterations().Root(ctx, spaceID)
+				if err != nil {
+					return errors.NewBadParameterError("space", spaceID).Expected("valid space ID")
+				}
+				target.Fields[workitem.SystemIteration] = rootIteration.ID.String()
 			}
-			target.Fields[workitem.SystemIteration] = rootIteration.ID.String()
 		} else if source.Relationships.Iteration != nil && source.Relationships.Iteration.Data != nil {
 			d := source.Relationships.Iteration.Data
 			iterationUUID, err := uuid.FromString(*d.ID)
@@ -442,15 +574,19 @@ func ConvertJSONAPIToWorkItem(ctx context.Context, appl application.Application,
 
 	if source.Relationships != nil {
 		if source.Relationships.Area == nil || (source.Relationships.Area != nil && source.Relationships.Area.Data == nil) {
-			log.Debug(ctx, map[string]interface{}{
-				"wi_id":    target.ID,
-				"space_id": spaceID,
-			}, "assigning the work item to the root area of the space.")
-			rootArea, err := appl.Areas().Root(ctx, spaceID)
-			if err != nil {
-				return errors.NewBadParameterError("space", spaceID).Expected("valid space ID")
+			if isNew {
+				delete(target.Fields, workitem.SystemArea)
+			} else {
+				log.Debug(ctx, map[string]interface{}{
+					"wi_id":    target.ID,
+					"space_id": spaceID,
+				}, "assigning the work item to the root area of the space.")
+				rootArea, err := appl.Areas().Root(ctx, spaceID)
+				if err != nil {
+					return errors.NewBadParameterError("space", spaceID).Expected("valid space ID")
+				}
+				target.Fields[workitem.SystemArea] = rootArea.ID.String()
 			}
-			target.Fields[workitem.SystemArea] = rootArea.ID.String()
 		} else if source.Relationships.Area != nil && source.Relationships.Area.Data != nil {
 			d := source.Relationships.Area.Data
 			areaUUID, err := uuid.FromString(*d.ID)
@@ -554,6 +690,7 @@ func ConvertWorkItem(request *goa.RequestData, wi workitem.WorkItem, additional
 		Type: APIStringTypeWorkItem,
 		Attributes: map[string]interface{}{
 			"version": wi.Version,
+			"number":  wi.Number,
 		},
 		Relationships: &app.WorkItemRelationships{
 			BaseType: &app.RelationBaseType{
@@ -677,6 +814,99 @@ func workItemIncludeHasChildren(appl application.Application, ctx context.Contex
 	}
 }
 
+// workItemIncludeReferences adds the work items and labels referenced by
+// fields of kind workitem or label, or lists of them, as well as the users
+// mentioned in the description as relationships. The mentions of these users
+// in the rendered Markdown description are turned into links.
+func workItemIncludeReferences(appl application.Application, ctx context.Context) WorkItemConvertFunc {
+	return func(request *goa.RequestData, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+		identityIDs, err := appl.Mentions().List(ctx, mention.KindWorkItem, wi.ID)
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"wi_id": wi.ID,
+				"err":   err,
+			}, "unable to list the users mentioned in the work item: %s", wi.ID)
+		} else {
+			wi2.Relationships.Mentions = convertMentionedUsers(request, identityIDs)
+			if wi2.Attributes[workitem.SystemDescriptionMarkup] == rendering.SystemMarkupMarkdown {
+				if rendered, ok := wi2.Attributes[workitem.SystemDescriptionRendered].(string); ok {
+					wi2.Attributes[workitem.SystemDescriptionRendered] = linkMentions(ctx, appl, request, rendered, identityIDs)
+				}
+			}
+		}
+		wit, err := appl.WorkItemTypes().LoadByID(ctx, wi.Type)
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"wi_id":  wi.ID,
+				"wit_id": wi.Type,
+				"err":    err,
+			}, "unable to load the type of the work item")
+			return
+		}
+		for name, def := range wit.Fields {
+			kind, ids := referencedIDs(def.Type, wi.Fields[name])
+			if len(ids) == 0 {
+				continue
+			}
+			data := make([]*app.GenericData, len(ids))
+			for i, id := range ids {
+				data[i] = convertReferenceSimple(request, wi.SpaceID, kind, id)
+			}
+			if wi2.Relationships.References == nil {
+				wi2.Relationships.References = map[string]*app.RelationGenericList{}
+			}
+			wi2.Relationships.References[name] = &app.RelationGenericList{Data: data}
+		}
+	}
+}
+
+// referencedIDs returns the kind and the IDs of the work items or labels
+// referenced by a field value
+func referencedIDs(fieldType workitem.FieldType, value interface{}) (workitem.Kind, []string) {
+	if value == nil {
+		return "", nil
+	}
+	kind := fieldType.GetKind()
+	if kind == workitem.KindWorkitemReference || kind == workitem.KindLabel {
+		return kind, []string{fmt.Sprint(value)}
+	}
+	switch t := fieldType.(type) {
+	case workitem.ListType:
+		kind = t.ComponentType.Kind
+	case *workitem.ListType:
+		kind = t.ComponentType.Kind
+	default:
+		return "", nil
+	}
+	values, ok := value.([]interface{})
+	if !ok || (kind != workitem.KindWorkitemReference && kind != workitem.KindLabel) {
+		return "", nil
+	}
+	ids := make([]string, len(values))
+	for i, v := range values {
+		ids[i] = fmt.Sprint(v)
+	}
+	return kind, ids
+}
+
+// convertReferenceSimple converts the ID of a referenced work item or label
+// into a generic relationship
+func convertReferenceSimple(request *goa.RequestData, spaceID uuid.UUID, kind workitem.Kind, id string) *app.GenericData {
+	t := APIStringTypeWorkItem
+	selfURL := rest.AbsoluteURL(request, app.WorkitemHref(spaceID.String(), id))
+	if kind == workitem.KindLabel {
+		t = label.APIStringTypeLabels
+		selfURL = rest.AbsoluteURL(request, app.LabelHref(spaceID.String(), id))
+	}
+	return &app.GenericData{
+		Type: &t,
+		ID:   &id,
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
+
 // ListChildren runs the list action.
 func (c *WorkitemController) ListChildren(ctx *app.ListChildrenWorkitemContext) error {
 	// WorkItemChildrenController_List: start_implement
@@ -684,17 +914,26 @@ func (c *WorkitemController) ListChildren(ctx *app.ListChildrenWorkitemContext)
 	var additionalQuery []string
 	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
 	return application.Transactional(c.db, func(appl application.Application) error {
-		result, tc, err := appl.WorkItemLinks().ListWorkItemChildren(ctx, ctx.WiID, &offset, &limit)
+		// the ID may as well be the key of the work item, e.g. PLAT-123
+		wi, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "unable to load work item %s", ctx.WiID))
+		}
+		result, tc, err := appl.WorkItemLinks().ListWorkItemChildren(ctx, wi.ID, &offset, &limit)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "unable to list work item children"))
 		}
 		count := int(tc)
 		return ctx.ConditionalEntities(result, c.config.GetCacheControlWorkItems, func() error {
 			hasChildren := workItemIncludeHasChildren(appl, ctx)
+			references := workItemIncludeReferences(appl, ctx)
+			key := workItemIncludeKey(appl, ctx)
+			checklist := workItemIncludeChecklist(appl, ctx)
+			reactions := workItemIncludeReactions(appl, ctx)
 			response := app.WorkItemList{
 				Links: &app.PagingLinks{},
 				Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
-				Data:  ConvertWorkItems(ctx.RequestData, result, hasChildren),
+				Data:  ConvertWorkItems(ctx.RequestData, result, hasChildren, references, key, checklist, reactions),
 			}
 			setPagingLinks(response.Links, buildAbsoluteURL(ctx.RequestData), len(result), offset, limit, count, additionalQuery...)
 			return ctx.OK(&response)
@@ -18,6 +18,7 @@ import (
 	"github.com/fabric8io/almighty-core/app/test"
 	"github.com/fabric8io/almighty-core/area"
 	"github.com/fabric8io/almighty-core/codebase"
+	"github.com/fabric8io/almighty-core/comment"
 	"github.com/fabric8io/almighty-core/configuration"
 	. "github.com/fabric8io/almighty-core/controller"
 	"github.com/fabric8io/almighty-core/gormapplication"
@@ -35,6 +36,7 @@ import (
 	testsupport "github.com/fabric8io/almighty-core/test"
 	almtoken "github.com/fabric8io/almighty-core/token"
 	"github.com/fabric8io/almighty-core/workitem"
+	"github.com/fabric8io/almighty-core/workitem/link"
 
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/goadesign/goa"
@@ -101,7 +103,7 @@ func (s *WorkItemSuite) SetupTest() {
 
 func (s *WorkItemSuite) TestGetWorkItemWithLegacyDescription() {
 	// given
-	_, wi := test.ShowWorkitemOK(s.T(), nil, nil, s.controller, *s.wi.Relationships.Space.Data.ID, *s.wi.ID, nil, nil)
+	_, wi := test.ShowWorkitemOK(s.T(), nil, nil, s.controller, *s.wi.Relationships.Space.Data.ID, *s.wi.ID, nil, nil, nil)
 	require.NotNil(s.T(), wi)
 	assert.Equal(s.T(), s.wi.ID, wi.Data.ID)
 	assert.NotNil(s.T(), wi.Data.Attributes[workitem.SystemCreatedAt])
@@ -378,17 +380,47 @@ func (s *WorkItemSuite) TestListByFields() {
 	filter := "{\"system.title\":\"run integration test\"}"
 	offset := "0"
 	limit := 1
-	_, result := test.ListWorkitemOK(s.T(), nil, nil, s.controller, *payload.Data.Relationships.Space.Data.ID, &filter, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil)
+	_, result := test.ListWorkitemOK(s.T(), nil, nil, s.controller, *payload.Data.Relationships.Space.Data.ID, nil, &filter, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), result)
 	require.Equal(s.T(), 1, len(result.Data))
 	// when
 	filter = fmt.Sprintf("{\"system.creator\":\"%s\"}", s.testIdentity.ID.String())
 	// then
-	_, result = test.ListWorkitemOK(s.T(), nil, nil, s.controller, *payload.Data.Relationships.Space.Data.ID, &filter, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil)
+	_, result = test.ListWorkitemOK(s.T(), nil, nil, s.controller, *payload.Data.Relationships.Space.Data.ID, nil, &filter, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil, nil)
 	require.NotNil(s.T(), result)
 	require.Equal(s.T(), 1, len(result.Data))
 }
+
+func (s *WorkItemSuite) TestListByQuery() {
+	// given
+	payload := minimumRequiredCreateWithType(workitem.SystemBug)
+	payload.Data.Attributes[workitem.SystemTitle] = "run query language test"
+	payload.Data.Attributes[workitem.SystemState] = workitem.SystemStateClosed
+	test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.controller, *payload.Data.Relationships.Space.Data.ID, &payload)
+	offset := "0"
+	limit := 10
+	// when
+	filter := `system.title = "run query language test" AND system.state IN ("new", "closed") AND NOT system.state = "open"`
+	_, result := test.ListWorkitemOK(s.T(), nil, nil, s.controller, *payload.Data.Relationships.Space.Data.ID, nil, &filter, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil, nil)
+	// then
+	require.NotNil(s.T(), result)
+	require.Equal(s.T(), 1, len(result.Data))
+	// when
+	filter = `system.title = "run query language test" AND (system.state = "new" OR system.assignees IS NOT NULL)`
+	_, result = test.ListWorkitemOK(s.T(), nil, nil, s.controller, *payload.Data.Relationships.Space.Data.ID, nil, &filter, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil, nil)
+	// then
+	require.NotNil(s.T(), result)
+	require.Equal(s.T(), 0, len(result.Data))
+}
+
+func (s *WorkItemSuite) TestListByInvalidQuery() {
+	// given
+	spaceID := space.SystemSpace
+	filter := `system.title = "unterminated`
+	// when/then
+	test.ListWorkitemBadRequest(s.T(), nil, nil, s.controller, spaceID, nil, &filter, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+}
 func getWorkItemTestDataFunc(config configuration.ConfigurationData) func(t *testing.T) []testSecureAPI {
 	return func(t *testing.T) []testSecureAPI {
 		privatekey, err := jwt.ParseRSAPrivateKeyFromPEM(config.GetTokenPrivateKey())
@@ -535,7 +567,7 @@ func createPagingTest(t *testing.T, ctx context.Context, controller *WorkitemCon
 		repo.ListReturns(makeWorkItems(count), uint64(totalCount), nil)
 		offset := strconv.Itoa(start)
 
-		_, response := test.ListWorkitemOK(t, ctx, nil, controller, spaceID, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil)
+		_, response := test.ListWorkitemOK(t, ctx, nil, controller, spaceID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil, nil)
 		assertLink(t, "first", first, response.Links.First)
 		assertLink(t, "last", last, response.Links.Last)
 		assertLink(t, "prev", prev, response.Links.Prev)
@@ -821,6 +853,27 @@ func (s *WorkItem2Suite) TestWI2UpdateOnlyState() {
 	assert.Equal(s.T(), updatedWI.Data.Attributes[workitem.SystemState], newStateValue)
 }
 
+func (s *WorkItem2Suite) TestWI2ShowAndListAsOf() {
+	// given
+	s.minimumPayload.Data.Attributes[workitem.SystemTitle] = "Test title"
+	before := time.Now()
+	s.minimumPayload.Data.Attributes[workitem.SystemState] = workitem.SystemStateClosed
+	test.UpdateWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *s.wi.Relationships.Space.Data.ID, *s.wi.ID, s.minimumPayload)
+	// when
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *s.wi.Relationships.Space.Data.ID, *s.wi.ID, &before, nil, nil)
+	filter := fmt.Sprintf(`system.state = "%s"`, s.wi.Attributes[workitem.SystemState])
+	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *s.wi.Relationships.Space.Data.ID, &before, &filter, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	// then
+	assert.Equal(s.T(), s.wi.Attributes[workitem.SystemState], fetchedWI.Data.Attributes[workitem.SystemState])
+	require.NotEmpty(s.T(), list.Data)
+	found := false
+	for _, wi := range list.Data {
+		found = found || *wi.ID == *s.wi.ID
+	}
+	assert.True(s.T(), found)
+	assert.Contains(s.T(), *list.Links.First, "asOf=")
+}
+
 func (s *WorkItem2Suite) TestWI2UpdateVersionConflict() {
 	// given
 	s.minimumPayload.Data.Attributes[workitem.SystemTitle] = "Test title"
@@ -1252,7 +1305,7 @@ func (s *WorkItem2Suite) TestWI2ListByAssigneeFilter() {
 	assert.Len(s.T(), wi.Data.Relationships.Assignees.Data, 1)
 	assert.Equal(s.T(), newUser.ID.String(), *wi.Data.Relationships.Assignees.Data[0].ID)
 	newUserID := newUser.ID.String()
-	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, &newUserID, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, nil, &newUserID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Len(s.T(), list.Data, 1)
 	assert.Equal(s.T(), newUser.ID.String(), *list.Data[0].Relationships.Assignees.Data[0].ID)
 	assert.True(s.T(), strings.Contains(*list.Links.First, "filter[assignee]"))
@@ -1277,7 +1330,7 @@ func (s *WorkItem2Suite) TestWI2ListByNoAssigneeFilter() {
 	assignee := none
 
 	s.T().Run("default work item created in fixture", func(t *testing.T) {
-		_, list0 := test.ListWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, &assignee, nil, nil, nil, nil, nil, nil, nil, nil)
+		_, list0 := test.ListWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, nil, &assignee, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		// data coming from test fixture
 		assert.Len(t, list0.Data, 1)
 		assert.True(t, strings.Contains(*list0.Links.First, "filter[assignee]=none"))
@@ -1292,7 +1345,7 @@ func (s *WorkItem2Suite) TestWI2ListByNoAssigneeFilter() {
 		assert.NotNil(t, wi.Data.Relationships.Assignees.Data)
 		assert.NotNil(t, wi.Data.Relationships.Assignees.Data[0].ID)
 
-		_, list := test.ListWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, &newUserID, nil, nil, nil, nil, nil, nil, nil, nil)
+		_, list := test.ListWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, nil, &newUserID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		assert.Len(t, list.Data, 1)
 		require.NotNil(t, *list.Data[0].Relationships.Assignees.Data[0])
 		assert.Equal(t, newUser.ID.String(), *list.Data[0].Relationships.Assignees.Data[0].ID)
@@ -1300,13 +1353,13 @@ func (s *WorkItem2Suite) TestWI2ListByNoAssigneeFilter() {
 	})
 
 	s.T().Run("work item with assignee value as none", func(t *testing.T) {
-		_, list2 := test.ListWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, &assignee, nil, nil, nil, nil, nil, nil, nil, nil)
+		_, list2 := test.ListWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, nil, &assignee, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		assert.Len(t, list2.Data, 1)
 		assert.True(t, strings.Contains(*list2.Links.First, "filter[assignee]=none"))
 	})
 
 	s.T().Run("work item without specifying assignee", func(t *testing.T) {
-		_, list3 := test.ListWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		_, list3 := test.ListWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		assert.Len(t, list3.Data, 2)
 		assert.False(t, strings.Contains(*list3.Links.First, "filter[assignee]=none"))
 	})
@@ -1324,7 +1377,7 @@ func (s *WorkItem2Suite) TestWI2ListByTypeFilter() {
 	assert.NotNil(s.T(), expected.Data)
 	require.NotNil(s.T(), expected.Data.ID)
 	require.NotNil(s.T(), expected.Data.Type)
-	_, actual := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, nil, &workitem.SystemBug, nil, nil, nil, nil)
+	_, actual := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, nil, nil, nil, nil, &workitem.SystemBug, nil, nil, nil, nil, nil)
 	require.NotNil(s.T(), actual)
 	require.True(s.T(), len(actual.Data) > 1)
 	assert.Contains(s.T(), *actual.Links.First, fmt.Sprintf("filter[workitemtype]=%s", workitem.SystemBug))
@@ -1353,7 +1406,7 @@ func (s *WorkItem2Suite) TestWI2ListByStateFilterOK() {
 	inprogressWI := s.createWorkItem("title", workitem.SystemStateInProgress)
 	// when
 	stateNew := workitem.SystemStateNew
-	_, actualWIs := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, nil, nil)
+	_, actualWIs := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), actualWIs)
 	require.True(s.T(), len(actualWIs.Data) > 1)
@@ -1372,7 +1425,7 @@ func (s *WorkItem2Suite) TestWI2ListByStateFilterNotModifiedUsingIfNoneMatchIfMo
 	inprogressWI := s.createWorkItem("title", workitem.SystemStateInProgress)
 	// when
 	stateNew := workitem.SystemStateNew
-	res, actualWIs := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, nil, nil)
+	res, actualWIs := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), actualWIs)
 	require.True(s.T(), len(actualWIs.Data) > 1)
@@ -1385,7 +1438,7 @@ func (s *WorkItem2Suite) TestWI2ListByStateFilterNotModifiedUsingIfNoneMatchIfMo
 	// retain conditional headers in response and submit the request again
 	etag, lastModified, _ := assertResponseHeaders(s.T(), res)
 	// when calling again
-	res = test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, &lastModified, &etag)
+	res = test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, nil, &lastModified, &etag)
 	// then
 	assertResponseHeaders(s.T(), res)
 }
@@ -1397,7 +1450,7 @@ func (s *WorkItem2Suite) TestWI2ListByStateFilterOKModifiedUsingIfNoneMatchIfMod
 	inprogressWI := s.createWorkItem("title", workitem.SystemStateInProgress)
 	// when
 	stateNew := workitem.SystemStateNew
-	res, actualWIs := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, nil, nil)
+	res, actualWIs := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), actualWIs)
 	require.True(s.T(), len(actualWIs.Data) > 1)
@@ -1418,7 +1471,7 @@ func (s *WorkItem2Suite) TestWI2ListByStateFilterOKModifiedUsingIfNoneMatchIfMod
 	update.Data.Attributes["version"] = inprogressWI.Data.Attributes["version"]
 	test.UpdateWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, *inprogressWI.Data.ID, &update)
 	// when calling again (with expired validation headers)
-	res, actualWIs = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, &lastModified, &etag)
+	res, actualWIs = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, nil, nil, nil, &stateNew, nil, nil, nil, nil, &lastModified, &etag)
 	// then expect the new data
 	assertResponseHeaders(s.T(), res)
 	require.NotNil(s.T(), actualWIs)
@@ -1469,7 +1522,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterOK() {
 	// given
 	spaceID, areaID, _ := s.setupAreaWorkItem(true)
 	// when
-	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	// then
 	assertAreaWorkItems(s.T(), areaID, workitems)
 	assertResponseHeaders(s.T(), res)
@@ -1479,7 +1532,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterOKEmptyList() {
 	// given
 	spaceID, areaID, _ := s.setupAreaWorkItem(false)
 	// when
-	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), *workitems)
 	require.Empty(s.T(), workitems.Data)
@@ -1495,7 +1548,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterOKUsingExpiredIfModifiedSinceHea
 	// when
 	updatedAt := wi.Data.Attributes[workitem.SystemUpdatedAt].(time.Time)
 	ifModifiedSince := app.ToHTTPTime(updatedAt.Add(-1 * time.Hour))
-	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, &ifModifiedSince, nil)
+	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &ifModifiedSince, nil)
 	// then
 	assertAreaWorkItems(s.T(), areaID, workitems)
 	assertResponseHeaders(s.T(), res)
@@ -1506,7 +1559,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterOKUsingExpiredIfNoneMatchHeader(
 	spaceID, areaID, _ := s.setupAreaWorkItem(true)
 	// when
 	ifNoneMatch := "foo"
-	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, &ifNoneMatch)
+	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &ifNoneMatch)
 	// then
 	assertAreaWorkItems(s.T(), areaID, workitems)
 	assertResponseHeaders(s.T(), res)
@@ -1518,7 +1571,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterNotModifiedUsingIfModifiedSinceH
 	// when
 	updatedAt := wi.Data.Attributes[workitem.SystemUpdatedAt].(time.Time)
 	ifModifiedSince := app.ToHTTPTime(updatedAt)
-	res := test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, &ifModifiedSince, nil)
+	res := test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &ifModifiedSince, nil)
 	// then
 	assertResponseHeaders(s.T(), res)
 }
@@ -1528,7 +1581,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterNotModifiedUsingIfNoneMatchHeade
 	spaceID, areaID, wi := s.setupAreaWorkItem(true)
 	// when
 	ifNoneMatch := app.GenerateEntityTag(convertWorkItemToConditionalResponseEntity(*wi))
-	res := test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, &ifNoneMatch)
+	res := test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &ifNoneMatch)
 	// then
 	assertResponseHeaders(s.T(), res)
 }
@@ -1554,7 +1607,7 @@ func (s *WorkItem2Suite) TestWI2ListByIterationFilter() {
 	require.NotNil(s.T(), wi.Data.Relationships.Iteration)
 	assert.Equal(s.T(), iterationID, *wi.Data.Relationships.Iteration.Data.ID)
 
-	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, nil, &iterationID, nil, nil, nil, nil, nil, nil, nil)
+	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, nil, nil, nil, nil, nil, &iterationID, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.Len(s.T(), list.Data, 1)
 	assert.Equal(s.T(), iterationID, *list.Data[0].Relationships.Iteration.Data.ID)
 	assert.True(s.T(), strings.Contains(*list.Links.First, "filter[iteration]"))
@@ -1631,7 +1684,7 @@ func (s *WorkItem2Suite) TestWI2ShowOK() {
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
 	// when
-	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil)
+	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, nil)
 	// then
 	assertSingleWorkItem(s.T(), *createdWI, *fetchedWI)
 	assertResponseHeaders(s.T(), res)
@@ -1646,7 +1699,7 @@ func (s *WorkItem2Suite) TestWI2ShowOKUsingExpiredIfModifiedSinceHeader() {
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
 	// when
 	ifModifiedSince := app.ToHTTPTime(createdWI.Data.Attributes[workitem.SystemUpdatedAt].(time.Time).Add(-10 * time.Hour))
-	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, &ifModifiedSince, nil)
+	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, &ifModifiedSince, nil)
 	// then
 	assertSingleWorkItem(s.T(), *createdWI, *fetchedWI)
 	assertResponseHeaders(s.T(), res)
@@ -1661,7 +1714,7 @@ func (s *WorkItem2Suite) TestWI2ShowOKUsingExpiredIfNoneMatchHeader() {
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
 	// when
 	ifNoneMatch := "foo"
-	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, &ifNoneMatch)
+	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, &ifNoneMatch)
 	// then
 	assertSingleWorkItem(s.T(), *createdWI, *fetchedWI)
 	assertResponseHeaders(s.T(), res)
@@ -1676,7 +1729,7 @@ func (s *WorkItem2Suite) TestWI2ShowNotModifiedUsingIfModifiedSinceHeader() {
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
 	// when
 	ifModifiedSince := app.ToHTTPTime(createdWI.Data.Attributes[workitem.SystemUpdatedAt].(time.Time))
-	res := test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, &ifModifiedSince, nil)
+	res := test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, &ifModifiedSince, nil)
 	// then
 	assertResponseHeaders(s.T(), res)
 }
@@ -1690,7 +1743,7 @@ func (s *WorkItem2Suite) TestWI2ShowNotModifiedUsingIfNoneMatchHeader() {
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
 	// when
 	ifNoneMatch := app.GenerateEntityTag(convertWorkItemToConditionalResponseEntity(*createdWI))
-	res := test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, &ifNoneMatch)
+	res := test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, &ifNoneMatch)
 	// then
 	assertResponseHeaders(s.T(), res)
 }
@@ -1718,7 +1771,7 @@ func assertResponseHeaders(t *testing.T, res http.ResponseWriter) (string, strin
 
 // Temporarly disabled, See https://github.com/fabric8io/almighty-core/issues/1036
 func (s *WorkItem2Suite) xTestWI2FailShowMissing() {
-	test.ShowWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, "00000000", nil, nil)
+	test.ShowWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, "00000000", nil, nil, nil)
 }
 
 // Temporarly disabled, See https://github.com/fabric8io/almighty-core/issues/1036
@@ -1729,7 +1782,7 @@ func (s *WorkItem2Suite) TestWI2FailOnDelete() {
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
-	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil)
+	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, nil)
 	test.DeleteWorkitemMethodNotAllowed(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, *createdWI.Data.ID)
 }
 
@@ -1741,9 +1794,9 @@ func (s *WorkItem2Suite) xTestWI2SuccessDelete() {
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
-	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil)
+	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, nil)
 	test.DeleteWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, *createdWI.Data.ID)
-	test.ShowWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil)
+	test.ShowWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, nil)
 }
 
 // TestWI2DeleteLinksOnWIDeletionOK creates two work items (WI1 and WI2) and
@@ -1792,7 +1845,7 @@ func (s *WorkItem2Suite) xTestWI2DeleteLinksOnWIDeletionOK() {
 	test.ShowWorkItemLinkNotFound(s.T(), s.svc.Context, s.svc, s.linkCtrl, *workItemLink.Data.ID, nil, nil)
 
 	// Check that we can query for wi2 without problems
-	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *wi2.Data.Relationships.Space.Data.ID, *wi2.Data.ID, nil, nil)
+	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *wi2.Data.Relationships.Space.Data.ID, *wi2.Data.ID, nil, nil, nil)
 }
 
 // Temporarly disabled, See https://github.com/fabric8io/almighty-core/issues/1036
@@ -2142,7 +2195,7 @@ func (s *WorkItem2Suite) TestWI2SuccessCreateAndPreventJavascriptInjectionWithLe
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
 	// when
-	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil)
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), fetchedWI.Data)
 	require.NotNil(s.T(), fetchedWI.Data.Attributes)
@@ -2161,7 +2214,7 @@ func (s *WorkItem2Suite) TestWI2SuccessCreateAndPreventJavascriptInjectionWithPl
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
 	// when
-	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil)
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), fetchedWI.Data)
 	require.NotNil(s.T(), fetchedWI.Data.Attributes)
@@ -2180,7 +2233,7 @@ func (s *WorkItem2Suite) TestWI2SuccessCreateAndPreventJavascriptInjectionWithMa
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
 	// when
-	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil)
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), fetchedWI.Data)
 	require.NotNil(s.T(), fetchedWI.Data.Attributes)
@@ -2209,7 +2262,7 @@ func (s *WorkItem2Suite) TestCreateWIWithCodebase() {
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *c.Data.Relationships.Space.Data.ID, &c)
 	require.NotNil(s.T(), createdWI)
 	// when
-	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil)
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *createdWI.Data.Relationships.Space.Data.ID, *createdWI.Data.ID, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), fetchedWI.Data)
 	require.NotNil(s.T(), fetchedWI.Data.Attributes)
@@ -2347,7 +2400,7 @@ func (s *WorkItem2Suite) xTestWI2IfModifiedSince() {
 		}
 	})
 	// when/then
-	test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *wi.Data.Relationships.Space.Data.ID, *wi.Data.ID, nil, nil)
+	test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, *wi.Data.Relationships.Space.Data.ID, *wi.Data.ID, nil, nil, nil)
 }
 
 func (s *WorkItem2Suite) TestWI2ListForChildIteration() {
@@ -2418,15 +2471,15 @@ func (s *WorkItem2Suite) TestWI2ListForChildIteration() {
 	}
 
 	// list workitems for grandParentIteration
-	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, &grandParentIterationID, nil, nil, nil, nil, nil, nil, nil)
+	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, &grandParentIterationID, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.Len(s.T(), list.Data, 7)
 
 	// list workitems for parentIteration
-	_, list = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, &parentIterationID, nil, nil, nil, nil, nil, nil, nil)
+	_, list = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, &parentIterationID, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.Len(s.T(), list.Data, 4)
 
 	// list workitems for childIteraiton
-	_, list = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, &childIteraitonID, nil, nil, nil, nil, nil, nil, nil)
+	_, list = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, nil, nil, nil, nil, nil, &childIteraitonID, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.Len(s.T(), list.Data, 2)
 }
 
@@ -2540,7 +2593,7 @@ func (s *workItemChildSuite) TestWorkItemListFilterByNoParents() {
 		// given
 		var pe *bool
 		// when
-		_, result := test.ListWorkitemOK(t, nil, nil, s.workItemCtrl, s.userSpaceID, nil, nil, nil, nil, pe, nil, nil, nil, nil, nil, nil)
+		_, result := test.ListWorkitemOK(t, nil, nil, s.workItemCtrl, s.userSpaceID, nil, nil, nil, nil, nil, nil, nil, pe, nil, nil, nil, nil, nil, nil, nil)
 		// then
 		assert.Len(t, result.Data, 3)
 		assert.Nil(t, result.Links.Prev)
@@ -2554,7 +2607,7 @@ func (s *workItemChildSuite) TestWorkItemListFilterByNoParents() {
 		// given
 		pe := false
 		// when
-		_, result2 := test.ListWorkitemOK(t, nil, nil, s.workItemCtrl, s.userSpaceID, nil, nil, nil, nil, &pe, nil, nil, nil, nil, nil, nil)
+		_, result2 := test.ListWorkitemOK(t, nil, nil, s.workItemCtrl, s.userSpaceID, nil, nil, nil, nil, nil, nil, nil, &pe, nil, nil, nil, nil, nil, nil, nil)
 		// then
 		assert.Len(t, result2.Data, 1)
 		assert.Nil(t, result2.Links.Prev)
@@ -2568,7 +2621,7 @@ func (s *workItemChildSuite) TestWorkItemListFilterByNoParents() {
 		// given
 		pe := true
 		// when
-		_, result2 := test.ListWorkitemOK(t, nil, nil, s.workItemCtrl, s.userSpaceID, nil, nil, nil, nil, &pe, nil, nil, nil, nil, nil, nil)
+		_, result2 := test.ListWorkitemOK(t, nil, nil, s.workItemCtrl, s.userSpaceID, nil, nil, nil, nil, nil, nil, nil, &pe, nil, nil, nil, nil, nil, nil, nil)
 		// then
 		assert.Len(t, result2.Data, 3)
 		assert.Nil(t, result2.Links.Prev)
@@ -2579,3 +2632,296 @@ func (s *workItemChildSuite) TestWorkItemListFilterByNoParents() {
 	})
 
 }
+
+func (s *WorkItem2Suite) TestWI2Bulk() {
+	// given a second work item next to the default one
+	payload := minimumRequiredCreateWithType(workitem.SystemBug)
+	payload.Data.Attributes[workitem.SystemTitle] = "Test WI bulk"
+	payload.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
+	_, other := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wiCtrl, space.SystemSpace, &payload)
+	bulkItem := func(wi *app.WorkItem) *app.WorkItemBulkItem {
+		version := wi.Attributes["version"].(int)
+		return &app.WorkItemBulkItem{ID: *wi.ID, Version: &version}
+	}
+
+	s.T().Run("update", func(t *testing.T) {
+		// when
+		_, result := test.BulkWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, &app.WorkItemBulkPayload{
+			Operation:  "update",
+			Data:       []*app.WorkItemBulkItem{bulkItem(s.wi), bulkItem(other.Data)},
+			Attributes: map[string]interface{}{workitem.SystemState: workitem.SystemStateOpen},
+		})
+		// then
+		assert.Equal(t, 2, result.Meta.TotalCount)
+		require.Len(t, result.Data, 2)
+		for _, wi := range result.Data {
+			assert.Equal(t, workitem.SystemStateOpen, wi.Attributes[workitem.SystemState])
+		}
+	})
+
+	s.T().Run("version conflict", func(t *testing.T) {
+		// when the versions the client knows are outdated
+		_, jerrs := test.BulkWorkitemConflict(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, &app.WorkItemBulkPayload{
+			Operation:  "update",
+			Data:       []*app.WorkItemBulkItem{bulkItem(s.wi), bulkItem(other.Data)},
+			Attributes: map[string]interface{}{workitem.SystemState: workitem.SystemStateClosed},
+		})
+		// then there is one error per work item and nothing is changed
+		require.Len(t, jerrs.Errors, 2)
+		assert.Equal(t, *s.wi.ID, jerrs.Errors[0].Meta["id"])
+		assert.Equal(t, "/data/1", jerrs.Errors[1].Source["pointer"])
+		_, loaded := test.ShowWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, *other.Data.ID, nil, nil, nil)
+		assert.Equal(t, workitem.SystemStateOpen, loaded.Data.Attributes[workitem.SystemState])
+	})
+
+	s.T().Run("unsupported field", func(t *testing.T) {
+		// when/then
+		test.BulkWorkitemBadRequest(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, &app.WorkItemBulkPayload{
+			Operation:  "move",
+			Data:       []*app.WorkItemBulkItem{{ID: *s.wi.ID}},
+			Attributes: map[string]interface{}{workitem.SystemState: workitem.SystemStateClosed},
+		})
+	})
+
+	s.T().Run("update by filter", func(t *testing.T) {
+		// given
+		filter := `system.title = "Test WI bulk"`
+		// when
+		_, result := test.BulkWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, &app.WorkItemBulkPayload{
+			Operation:  "update",
+			Filter:     &filter,
+			Attributes: map[string]interface{}{workitem.SystemState: workitem.SystemStateClosed},
+		})
+		// then
+		assert.Equal(t, 1, result.Meta.TotalCount)
+		require.Len(t, result.Data, 1)
+		assert.Equal(t, *other.Data.ID, *result.Data[0].ID)
+	})
+
+	s.T().Run("too many work items", func(t *testing.T) {
+		// given
+		items := make([]*app.WorkItemBulkItem, 101)
+		for i := range items {
+			items[i] = &app.WorkItemBulkItem{ID: strconv.Itoa(i + 1)}
+		}
+		// when/then
+		test.BulkWorkitemBadRequest(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, &app.WorkItemBulkPayload{
+			Operation:  "update",
+			Data:       items,
+			Attributes: map[string]interface{}{workitem.SystemState: workitem.SystemStateClosed},
+		})
+	})
+
+	s.T().Run("delete is not an operation", func(t *testing.T) {
+		// given
+		filter := `system.title = "Test WI bulk"`
+		// when
+		test.BulkWorkitemBadRequest(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, &app.WorkItemBulkPayload{
+			Operation: "delete",
+			Filter:    &filter,
+		})
+		// then
+		test.ShowWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, *other.Data.ID, nil, nil, nil)
+	})
+}
+
+func (s *WorkItem2Suite) TestWI2RestoreAndTrash() {
+	// given the default work item and its comment, deleted through the
+	// repositories since the delete action is disabled for now
+	suppressor := createOneRandomUserIdentity(s.svc.Context, s.DB)
+	require.NotNil(s.T(), suppressor)
+	spaceID := *s.wi.Relationships.Space.Data.ID
+	c := comment.Comment{ParentID: *s.wi.ID, Body: "Test WI comment", Markup: rendering.SystemMarkupPlainText}
+	require.Nil(s.T(), comment.NewRepository(s.DB).Create(s.svc.Context, &c, suppressor.ID))
+	since := time.Now()
+	require.Nil(s.T(), workitem.NewWorkItemRepository(s.DB).Delete(s.svc.Context, spaceID, *s.wi.ID, suppressor.ID))
+	require.Nil(s.T(), comment.NewRepository(s.DB).DeleteByParent(s.svc.Context, *s.wi.ID, suppressor.ID))
+	trashCtrl := NewSpaceTrashController(s.svc, gormapplication.NewGormDB(s.DB))
+
+	s.T().Run("trash", func(t *testing.T) {
+		// when
+		_, workItems := test.ListSpaceTrashOK(t, s.svc.Context, s.svc, trashCtrl, spaceID, nil, nil, &since)
+		_, comments := test.CommentsSpaceTrashOK(t, s.svc.Context, s.svc, trashCtrl, spaceID, nil, nil, &since)
+		// then
+		require.Len(t, workItems.Data, 1)
+		assert.Equal(t, *s.wi.ID, *workItems.Data[0].ID)
+		require.Len(t, comments.Data, 1)
+		assert.Equal(t, c.ID, *comments.Data[0].ID)
+	})
+
+	s.T().Run("restore", func(t *testing.T) {
+		// when
+		related := true
+		_, restored := test.RestoreWorkitemOK(t, s.svc.Context, s.svc, s.wi2Ctrl, spaceID, *s.wi.ID, &related)
+		// then
+		assert.Equal(t, *s.wi.ID, *restored.Data.ID)
+		comments, _, err := comment.NewRepository(s.DB).List(s.svc.Context, *s.wi.ID, nil, nil)
+		require.Nil(t, err)
+		require.Len(t, comments, 1)
+		assert.Equal(t, c.ID, comments[0].ID)
+		_, workItems := test.ListSpaceTrashOK(t, s.svc.Context, s.svc, trashCtrl, spaceID, nil, nil, &since)
+		assert.Empty(t, workItems.Data)
+	})
+}
+
+func (s *WorkItem2Suite) TestWI2Copy() {
+	// given a comment on the default work item, and a reply to it
+	commenter := createOneRandomUserIdentity(s.svc.Context, s.DB)
+	require.NotNil(s.T(), commenter)
+	c := comment.Comment{ParentID: *s.wi.ID, Body: "Test WI comment", Markup: rendering.SystemMarkupPlainText}
+	require.Nil(s.T(), comment.NewRepository(s.DB).Create(s.svc.Context, &c, commenter.ID))
+	reply := comment.Comment{ParentID: *s.wi.ID, ReplyTo: &c.ID, Body: "Test WI reply", Markup: rendering.SystemMarkupPlainText}
+	require.Nil(s.T(), comment.NewRepository(s.DB).Create(s.svc.Context, &reply, commenter.ID))
+
+	s.T().Run("with comments", func(t *testing.T) {
+		// when
+		copyComments := true
+		_, result := test.CopyWorkitemCreated(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, *s.wi.ID, &app.WorkItemCopyPayload{
+			Comments: &copyComments,
+		})
+		// then
+		require.NotNil(t, result.Data.ID)
+		assert.NotEqual(t, *s.wi.ID, *result.Data.ID)
+		assert.Equal(t, map[string]string{*s.wi.ID: *result.Data.ID}, result.Mapping)
+		assert.Equal(t, s.wi.Attributes[workitem.SystemTitle], result.Data.Attributes[workitem.SystemTitle])
+		comments, _, err := comment.NewRepository(s.DB).List(s.svc.Context, *result.Data.ID, nil, nil)
+		require.Nil(t, err)
+		// comments are listed latest first
+		require.Len(t, comments, 2)
+		assert.Equal(t, c.Body, comments[1].Body)
+		assert.Equal(t, commenter.ID, comments[1].CreatedBy)
+		assert.Nil(t, comments[1].ReplyTo)
+		assert.Equal(t, reply.Body, comments[0].Body)
+		require.NotNil(t, comments[0].ReplyTo)
+		assert.Equal(t, comments[1].ID, *comments[0].ReplyTo)
+	})
+
+	s.T().Run("with children and links", func(t *testing.T) {
+		// given a child of the default work item, which is related to
+		// another work item
+		wiRepo := workitem.NewWorkItemRepository(s.DB)
+		linkRepo := link.NewWorkItemLinkRepository(s.DB)
+		child, err := wiRepo.Create(s.svc.Context, space.SystemSpace, workitem.SystemTask, map[string]interface{}{
+			workitem.SystemTitle: "Test WI child",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, commenter.ID)
+		require.Nil(t, err)
+		related, err := wiRepo.Create(s.svc.Context, space.SystemSpace, workitem.SystemTask, map[string]interface{}{
+			workitem.SystemTitle: "Test WI related",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, commenter.ID)
+		require.Nil(t, err)
+		parentID, err := strconv.ParseUint(*s.wi.ID, 10, 64)
+		require.Nil(t, err)
+		childID, err := strconv.ParseUint(child.ID, 10, 64)
+		require.Nil(t, err)
+		relatedID, err := strconv.ParseUint(related.ID, 10, 64)
+		require.Nil(t, err)
+		_, err = linkRepo.Create(s.svc.Context, parentID, childID, link.SystemWorkItemLinkTypeParentChildID, commenter.ID)
+		require.Nil(t, err)
+		_, err = linkRepo.Create(s.svc.Context, childID, relatedID, link.SystemWorkItemLinkPlannerItemRelatedID, commenter.ID)
+		require.Nil(t, err)
+		// when
+		copyChildren := true
+		copyLinks := true
+		_, result := test.CopyWorkitemCreated(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, *s.wi.ID, &app.WorkItemCopyPayload{
+			Children: &copyChildren,
+			Links:    &copyLinks,
+		})
+		// then the copy of the child is a child of the copy
+		require.Len(t, result.Mapping, 2)
+		assert.Equal(t, *result.Data.ID, result.Mapping[*s.wi.ID])
+		childCopyID, ok := result.Mapping[child.ID]
+		require.True(t, ok)
+		children, _, err := linkRepo.ListWorkItemChildren(s.svc.Context, *result.Data.ID, nil, nil)
+		require.Nil(t, err)
+		require.Len(t, children, 1)
+		assert.Equal(t, childCopyID, children[0].ID)
+		assert.Equal(t, child.Fields[workitem.SystemTitle], children[0].Fields[workitem.SystemTitle])
+		// and the copy of the child is related to the same work item
+		links, err := linkRepo.ListByWorkItemID(s.svc.Context, childCopyID)
+		require.Nil(t, err)
+		relatedLinks := 0
+		for _, l := range links {
+			if uuid.Equal(l.LinkTypeID, link.SystemWorkItemLinkPlannerItemRelatedID) {
+				assert.Equal(t, relatedID, l.TargetID)
+				relatedLinks++
+			}
+		}
+		assert.Equal(t, 1, relatedLinks)
+		// and the original child keeps its parent only
+		parents, _, err := linkRepo.ListWorkItemChildren(s.svc.Context, *s.wi.ID, nil, nil)
+		require.Nil(t, err)
+		require.Len(t, parents, 1)
+		assert.Equal(t, child.ID, parents[0].ID)
+	})
+
+	s.T().Run("with another type", func(t *testing.T) {
+		// when
+		witID := workitem.SystemTask
+		_, result := test.CopyWorkitemCreated(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, *s.wi.ID, &app.WorkItemCopyPayload{
+			Workitemtype: &witID,
+		})
+		// then
+		assert.Equal(t, workitem.SystemTask, result.Data.Relationships.BaseType.Data.ID)
+		assert.Equal(t, s.wi.Attributes[workitem.SystemTitle], result.Data.Attributes[workitem.SystemTitle])
+	})
+
+	s.T().Run("into another space", func(t *testing.T) {
+		// given two spaces with a type of the same name, and a work item of
+		// that type with a child in the first space
+		witRepo := workitem.NewWorkItemTypeRepository(s.DB)
+		wiRepo := workitem.NewWorkItemRepository(s.DB)
+		linkRepo := link.NewWorkItemLinkRepository(s.DB)
+		newSpace := func() (*space.Space, *workitem.WorkItemType) {
+			sp, err := space.NewRepository(s.DB).Create(s.svc.Context, &space.Space{Name: "Copy space " + uuid.NewV4().String()})
+			require.Nil(t, err)
+			wit, err := witRepo.Create(s.svc.Context, sp.ID, nil, &workitem.SystemPlannerItem, "incident", nil, "fa-fire", nil)
+			require.Nil(t, err)
+			return sp, wit
+		}
+		source, sourceType := newSpace()
+		target, targetType := newSpace()
+		parent, err := wiRepo.Create(s.svc.Context, source.ID, sourceType.ID, map[string]interface{}{
+			workitem.SystemTitle: "Test WI incident",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, commenter.ID)
+		require.Nil(t, err)
+		child, err := wiRepo.Create(s.svc.Context, source.ID, sourceType.ID, map[string]interface{}{
+			workitem.SystemTitle: "Test WI incident child",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, commenter.ID)
+		require.Nil(t, err)
+		parentID, err := strconv.ParseUint(parent.ID, 10, 64)
+		require.Nil(t, err)
+		childID, err := strconv.ParseUint(child.ID, 10, 64)
+		require.Nil(t, err)
+		_, err = linkRepo.Create(s.svc.Context, parentID, childID, link.SystemWorkItemLinkTypeParentChildID, commenter.ID)
+		require.Nil(t, err)
+		// when
+		copyChildren := true
+		_, result := test.CopyWorkitemCreated(t, s.svc.Context, s.svc, s.wi2Ctrl, source.ID, parent.ID, &app.WorkItemCopyPayload{
+			Space:    &target.ID,
+			Children: &copyChildren,
+		})
+		// then both copies belong to the target space and have its type
+		assert.Equal(t, target.ID, *result.Data.Relationships.Space.Data.ID)
+		assert.Equal(t, targetType.ID, result.Data.Relationships.BaseType.Data.ID)
+		require.Len(t, result.Mapping, 2)
+		childCopy, err := wiRepo.Load(s.svc.Context, target.ID, result.Mapping[child.ID])
+		require.Nil(t, err)
+		assert.Equal(t, targetType.ID, childCopy.Type)
+		children, _, err := linkRepo.ListWorkItemChildren(s.svc.Context, *result.Data.ID, nil, nil)
+		require.Nil(t, err)
+		require.Len(t, children, 1)
+		assert.Equal(t, childCopy.ID, children[0].ID)
+	})
+
+	s.T().Run("unknown type", func(t *testing.T) {
+		// when/then
+		witID := uuid.NewV4()
+		test.CopyWorkitemBadRequest(t, s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace, *s.wi.ID, &app.WorkItemCopyPayload{
+			Workitemtype: &witID,
+		})
+	})
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/query"
+	"github.com/fabric8io/almighty-core/space/authz"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	errs "github.com/pkg/errors"
+)
+
+// Operations of a bulk request
+const (
+	bulkOperationUpdate = "update"
+	bulkOperationMove   = "move"
+)
+
+// bulkItemsMax is the maximum number of work items a bulk request can change,
+// whether they are listed or selected by a filter
+const bulkItemsMax = pageSizeMax
+
+// bulkFields lists for each operation the fields which it can change
+var bulkFields = map[string]map[string]bool{
+	bulkOperationUpdate: {
+		workitem.SystemState:     true,
+		workitem.SystemIteration: true,
+		workitem.SystemArea:      true,
+		workitem.SystemAssignees: true,
+		workitem.SystemLabels:    true,
+	},
+	bulkOperationMove: {
+		workitem.SystemIteration: true,
+		workitem.SystemArea:      true,
+	},
+}
+
+// bulkConflictsError aborts the transaction of a bulk request when some of
+// the work items have been changed since the client has seen them. It holds
+// one error per work item.
+type bulkConflictsError struct {
+	errors []*app.JSONAPIError
+}
+
+func (err bulkConflictsError) Error() string {
+	return fmt.Sprintf("%d work items have been changed in the meantime", len(err.errors))
+}
+
+// Bulk does PATCH on a set of work items
+func (c *WorkitemController) Bulk(ctx *app.BulkWorkitemContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	authorized, err := authz.Authorize(ctx, ctx.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	if err := validateBulkPayload(ctx.Payload); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var data []*app.WorkItem
+	var count int
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		items, versions, err := loadBulkItems(ctx, appl, ctx.Payload)
+		if err != nil {
+			return err
+		}
+		var conflicts []*app.JSONAPIError
+		var changed []workitem.WorkItem
+		for i, wi := range items {
+			if versions[i] != nil && *versions[i] != wi.Version {
+				conflicts = append(conflicts, bulkConflict(ctx.Payload, i, wi))
+				continue
+			}
+			for fieldName, value := range ctx.Payload.Attributes {
+				wi.Fields[fieldName] = value
+			}
+			saved, err := appl.WorkItems().Save(ctx, ctx.SpaceID, wi, *currentUserIdentityID)
+			if _, ok := errs.Cause(err).(errors.VersionConflictError); ok {
+				conflicts = append(conflicts, bulkConflict(ctx.Payload, i, wi))
+				continue
+			}
+			if err != nil {
+				return errs.Wrapf(err, "error updating work item %s", wi.ID)
+			}
+			changed = append(changed, *saved)
+			count++
+		}
+		if len(conflicts) > 0 {
+			return bulkConflictsError{errors: conflicts}
+		}
+		hasChildren := workItemIncludeHasChildren(appl, ctx)
+		references := workItemIncludeReferences(appl, ctx)
+		key := workItemIncludeKey(appl, ctx)
+		checklist := workItemIncludeChecklist(appl, ctx)
+		reactions := workItemIncludeReactions(appl, ctx)
+		data = ConvertWorkItems(ctx.RequestData, changed, hasChildren, references, key, checklist, reactions)
+		return nil
+	})
+	if err != nil {
+		if conflicts, ok := errs.Cause(err).(bulkConflictsError); ok {
+			return ctx.Conflict(&app.JSONAPIErrors{Errors: conflicts.errors})
+		}
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WorkItemBulk{
+		Data: data,
+		Meta: &app.WorkItemListResponseMeta{TotalCount: count},
+	})
+}
+
+// validateBulkPayload checks that the payload selects the work items either
+// by their IDs or by a filter and that the operation only changes the fields
+// it is meant for
+func validateBulkPayload(payload *app.WorkItemBulkPayload) error {
+	if (len(payload.Data) == 0) == (payload.Filter == nil) {
+		return errors.NewBadParameterError("data/filter", nil).Expected("either a list of work items or a filter")
+	}
+	if len(payload.Data) > bulkItemsMax {
+		return errors.NewBadParameterError("data", len(payload.Data)).Expected(fmt.Sprintf("at most %d work items", bulkItemsMax))
+	}
+	if len(payload.Attributes) == 0 {
+		return errors.NewBadParameterError("attributes", nil).Expected("not empty")
+	}
+	for fieldName := range payload.Attributes {
+		if !bulkFields[payload.Operation][fieldName] {
+			return errors.NewBadParameterError("attributes", fieldName).Expected(fmt.Sprintf("a field which can be changed by the %s operation", payload.Operation))
+		}
+	}
+	return nil
+}
+
+// loadBulkItems loads the work items selected by the payload along with the
+// version the client has based the change on, if any
+func loadBulkItems(ctx *app.BulkWorkitemContext, appl application.Application, payload *app.WorkItemBulkPayload) ([]workitem.WorkItem, []*int, error) {
+	if payload.Filter != nil {
+		exp, err := query.Parse(payload.Filter)
+		if err != nil {
+			return nil, nil, errors.NewBadParameterError("could not parse filter", err)
+		}
+		limit := bulkItemsMax
+		items, count, err := appl.WorkItems().List(ctx, ctx.SpaceID, exp, nil, nil, &limit)
+		if err != nil {
+			return nil, nil, errs.Wrap(err, "error listing work items")
+		}
+		if count > uint64(bulkItemsMax) {
+			return nil, nil, errors.NewBadParameterError("filter", *payload.Filter).Expected(fmt.Sprintf("a filter selecting at most %d work items", bulkItemsMax))
+		}
+		return items, make([]*int, len(items)), nil
+	}
+	items := make([]workitem.WorkItem, len(payload.Data))
+	versions := make([]*int, len(payload.Data))
+	for i, item := range payload.Data {
+		wi, err := appl.WorkItems().Load(ctx, ctx.SpaceID, item.ID)
+		if err != nil {
+			return nil, nil, errs.Wrapf(err, "failed to load work item %s", item.ID)
+		}
+		items[i] = *wi
+		versions[i] = item.Version
+	}
+	return items, versions, nil
+}
+
+// bulkConflict returns the error reported for a work item of a bulk request
+// which has been changed in the meantime
+func bulkConflict(payload *app.WorkItemBulkPayload, index int, wi workitem.WorkItem) *app.JSONAPIError {
+	jerr, _ := jsonapi.ErrorToJSONAPIError(errors.NewVersionConflictError(fmt.Sprintf("work item %s has been changed in the meantime", wi.ID)))
+	jerr.Meta = map[string]interface{}{
+		"id":      wi.ID,
+		"version": wi.Version,
+	}
+	if len(payload.Data) > 0 {
+		jerr.Source = map[string]interface{}{
+			"pointer": fmt.Sprintf("/data/%d", index),
+		}
+	}
+	return &jerr
+}
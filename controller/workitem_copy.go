@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/comment"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/space/authz"
+	"github.com/fabric8io/almighty-core/workitem"
+	"github.com/fabric8io/almighty-core/workitem/link"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Copy does POST workitem copy
+func (c *WorkitemController) Copy(ctx *app.CopyWorkitemContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	targetSpaceID := ctx.SpaceID
+	if ctx.Payload.Space != nil {
+		targetSpaceID = *ctx.Payload.Space
+	}
+	authorized, err := authz.Authorize(ctx, targetSpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	var data *app.WorkItem
+	var mapping map[string]string
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, targetSpaceID); err != nil {
+			return errs.Wrapf(err, "failed to load space %s", targetSpaceID)
+		}
+		wi, err := appl.WorkItems().Load(ctx, ctx.SpaceID, ctx.WiID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load work item %s", ctx.WiID)
+		}
+		typeID := wi.Type
+		if ctx.Payload.Workitemtype != nil {
+			typeID = *ctx.Payload.Workitemtype
+		} else {
+			wit, err := appl.WorkItemTypes().LoadForSpace(ctx, wi.Type, targetSpaceID)
+			if err != nil {
+				return errs.WithStack(err)
+			}
+			typeID = wit.ID
+		}
+		copier := workItemCopier{
+			appl:      appl,
+			spaceID:   targetSpaceID,
+			creatorID: *currentUserIdentityID,
+			children:  ctx.Payload.Children != nil && *ctx.Payload.Children,
+			comments:  ctx.Payload.Comments != nil && *ctx.Payload.Comments,
+			mapping:   map[string]string{},
+		}
+		copied, err := copier.copy(ctx, *wi, typeID)
+		if err != nil {
+			return err
+		}
+		if err := copier.copyLinks(ctx, ctx.Payload.Links != nil && *ctx.Payload.Links); err != nil {
+			return err
+		}
+		// load the copy again to include the links in the response
+		copied, err = appl.WorkItems().Load(ctx, targetSpaceID, copied.ID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		hasChildren := workItemIncludeHasChildren(appl, ctx)
+		references := workItemIncludeReferences(appl, ctx)
+		key := workItemIncludeKey(appl, ctx)
+		checklist := workItemIncludeChecklist(appl, ctx)
+		reactions := workItemIncludeReactions(appl, ctx)
+		data = ConvertWorkItem(ctx.RequestData, *copied, hasChildren, references, key, checklist, reactions)
+		mapping = copier.mapping
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Location", app.WorkitemHref(targetSpaceID.String(), *data.ID))
+	return ctx.Created(&app.WorkItemCopy{
+		Data:    data,
+		Mapping: mapping,
+	})
+}
+
+// workItemCopier copies work items, optionally along with their children,
+// links and comments, and keeps track of the IDs of the copies
+type workItemCopier struct {
+	appl      application.Application
+	spaceID   uuid.UUID
+	creatorID uuid.UUID
+	children  bool
+	comments  bool
+	// mapping holds the IDs of the copies by the IDs of the copied work items
+	mapping map[string]string
+}
+
+// copy creates a copy of the given work item with the given type in the
+// target space. Fields which the type does not define are left out, and so
+// are the references to iterations, areas, labels and work items when the
+// copy is created in another space.
+func (c *workItemCopier) copy(ctx context.Context, wi workitem.WorkItem, typeID uuid.UUID) (*workitem.WorkItem, error) {
+	wit, err := c.appl.WorkItemTypes().LoadByID(ctx, typeID)
+	if err != nil {
+		return nil, errors.NewBadParameterError("workitemtype", typeID)
+	}
+	fields := map[string]interface{}{}
+	for fieldName, fieldDef := range wit.Fields {
+		switch fieldName {
+		case workitem.SystemCreator, workitem.SystemCreatedAt, workitem.SystemUpdatedAt, workitem.SystemOrder:
+			continue
+		}
+		value := wi.Fields[fieldName]
+		if value == nil {
+			continue
+		}
+		if !uuid.Equal(wi.SpaceID, c.spaceID) && isSpaceReference(fieldDef.Type) {
+			continue
+		}
+		fields[fieldName] = value
+	}
+	copied, err := c.appl.WorkItems().Create(ctx, c.spaceID, typeID, fields, c.creatorID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to copy work item %s", wi.ID)
+	}
+	c.mapping[wi.ID] = copied.ID
+	if c.comments {
+		comments, _, err := c.appl.Comments().List(ctx, wi.ID, nil, nil)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to list the comments of work item %s", wi.ID)
+		}
+		// the copies of the comments reply to the copies of the comments
+		// the original ones reply to
+		commentCopies := map[uuid.UUID]uuid.UUID{}
+		// comments are listed latest first, so the replied comments are copied first
+		for i := len(comments) - 1; i >= 0; i-- {
+			commentCopy := comment.Comment{
+				ParentID:  copied.ID,
+				CreatedBy: comments[i].CreatedBy,
+				Body:      comments[i].Body,
+				Markup:    comments[i].Markup,
+			}
+			if comments[i].ReplyTo != nil {
+				if replyTo, ok := commentCopies[*comments[i].ReplyTo]; ok {
+					commentCopy.ReplyTo = &replyTo
+				}
+			}
+			if err := c.appl.Comments().Create(ctx, &commentCopy, c.creatorID); err != nil {
+				return nil, errs.Wrapf(err, "failed to copy comment %s", comments[i].ID)
+			}
+			commentCopies[comments[i].ID] = commentCopy.ID
+		}
+	}
+	if c.children {
+		children, _, err := c.appl.WorkItemLinks().ListWorkItemChildren(ctx, wi.ID, nil, nil)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to list the children of work item %s", wi.ID)
+		}
+		for _, child := range children {
+			if _, copied := c.mapping[child.ID]; copied {
+				continue
+			}
+			// like a moved work item, a child copied into another space gets
+			// the type of that space with the same name
+			wit, err := c.appl.WorkItemTypes().LoadForSpace(ctx, child.Type, c.spaceID)
+			if err != nil {
+				return nil, errs.Wrapf(err, "failed to copy child %s", child.ID)
+			}
+			if _, err := c.copy(ctx, child, wit.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return copied, nil
+}
+
+// copyLinks recreates the links of the copied work items. Links between two
+// copied work items connect the copies. Other links connect the copy with
+// the work item at the other end, unless they are parent/child links: a copy
+// does not become another child of the parent of the original. Links which
+// are not parent/child links are only recreated if nonTree is true.
+func (c *workItemCopier) copyLinks(ctx context.Context, nonTree bool) error {
+	ids := make([]string, 0, len(c.mapping))
+	for id := range c.mapping {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	done := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		links, err := c.appl.WorkItemLinks().ListByWorkItemID(ctx, id)
+		if err != nil {
+			return errs.Wrapf(err, "failed to list the links of work item %s", id)
+		}
+		for _, l := range links {
+			if done[l.ID] {
+				continue
+			}
+			done[l.ID] = true
+			linkType, err := c.appl.WorkItemLinkTypes().Load(ctx, l.LinkTypeID)
+			if err != nil {
+				return errs.Wrapf(err, "failed to load link type %s", l.LinkTypeID)
+			}
+			sourceID, sourceCopied := c.copiedID(l.SourceID)
+			targetID, targetCopied := c.copiedID(l.TargetID)
+			if isParentChildLinkType(*linkType) {
+				if !sourceCopied || !targetCopied {
+					continue
+				}
+			} else if !nonTree {
+				continue
+			}
+			if _, err := c.appl.WorkItemLinks().Create(ctx, sourceID, targetID, l.LinkTypeID, c.creatorID); err != nil {
+				return errs.Wrapf(err, "failed to copy link %s", l.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// copiedID returns the ID of the copy of the work item with the given ID and
+// true, or the given ID and false if the work item has not been copied
+func (c *workItemCopier) copiedID(id uint64) (uint64, bool) {
+	copyID, ok := c.mapping[strconv.FormatUint(id, 10)]
+	if !ok {
+		return id, false
+	}
+	result, err := strconv.ParseUint(copyID, 10, 64)
+	if err != nil {
+		return id, false
+	}
+	return result, true
+}
+
+// isParentChildLinkType returns true if the links of the given type make work
+// items the children of others: links of a tree topology, and links which are
+// listed as children, like those of the system parent/child link type
+func isParentChildLinkType(linkType link.WorkItemLinkType) bool {
+	return linkType.Topology == link.TopologyTree || linkType.ForwardName == "parent of"
+}
+
+// isSpaceReference returns true if the values of the given field type refer
+// to entities which belong to a space
+func isSpaceReference(fieldType workitem.FieldType) bool {
+	kind := fieldType.GetKind()
+	switch t := fieldType.(type) {
+	case workitem.ListType:
+		kind = t.ComponentType.Kind
+	case *workitem.ListType:
+		kind = t.ComponentType.Kind
+	}
+	switch kind {
+	case workitem.KindIteration, workitem.KindArea, workitem.KindLabel, workitem.KindWorkitemReference:
+		return true
+	}
+	return false
+}
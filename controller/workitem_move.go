@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/space/authz"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Move does POST workitem move. The work item keeps its ID, revisions,
+// comments and links, and its iteration, area and type are replaced with the
+// ones of the target space.
+func (c *WorkitemController) Move(ctx *app.MoveWorkitemContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	targetSpaceID := ctx.Payload.Space
+	if uuid.Equal(targetSpaceID, ctx.SpaceID) {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("space", targetSpaceID).Expected("another space than the one of the work item"))
+	}
+	for _, spaceID := range []uuid.UUID{ctx.SpaceID, targetSpaceID} {
+		authorized, err := authz.Authorize(ctx, spaceID.String())
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+		}
+		if !authorized {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+		}
+	}
+	var data *app.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, targetSpaceID); err != nil {
+			return errs.Wrapf(err, "failed to load space %s", targetSpaceID)
+		}
+		wi, err := appl.WorkItems().Load(ctx, ctx.SpaceID, ctx.WiID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load work item %s", ctx.WiID)
+		}
+		wi.Version = ctx.Payload.Version
+		if ctx.Payload.Workitemtype != nil {
+			wi.Type = *ctx.Payload.Workitemtype
+		}
+		if ctx.Payload.Iteration != nil {
+			itr, err := appl.Iterations().Load(ctx, *ctx.Payload.Iteration)
+			if err != nil || !uuid.Equal(itr.SpaceID, targetSpaceID) {
+				return errors.NewBadParameterError("iteration", *ctx.Payload.Iteration).Expected("iteration of the target space")
+			}
+			wi.Fields[workitem.SystemIteration] = itr.ID.String()
+		}
+		if ctx.Payload.Area != nil {
+			a, err := appl.Areas().Load(ctx, *ctx.Payload.Area)
+			if err != nil || !uuid.Equal(a.SpaceID, targetSpaceID) {
+				return errors.NewBadParameterError("area", *ctx.Payload.Area).Expected("area of the target space")
+			}
+			wi.Fields[workitem.SystemArea] = a.ID.String()
+		}
+		moved, err := appl.WorkItems().Move(ctx, ctx.SpaceID, *wi, targetSpaceID, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to move work item %s", ctx.WiID)
+		}
+		if err := appl.WorkItemLinks().ValidateLinksInSpace(ctx, moved.ID, targetSpaceID); err != nil {
+			return errs.Wrapf(err, "the links of work item %s are not valid in space %s", ctx.WiID, targetSpaceID)
+		}
+		hasChildren := workItemIncludeHasChildren(appl, ctx)
+		references := workItemIncludeReferences(appl, ctx)
+		key := workItemIncludeKey(appl, ctx)
+		checklist := workItemIncludeChecklist(appl, ctx)
+		reactions := workItemIncludeReactions(appl, ctx)
+		data = ConvertWorkItem(ctx.RequestData, *moved, hasChildren, references, key, checklist, reactions)
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WorkItemSingle{
+		Data: data,
+	})
+}
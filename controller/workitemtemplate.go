@@ -0,0 +1,472 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/login"
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/rest"
+	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/space/authz"
+	"github.com/fabric8io/almighty-core/workitem"
+	"github.com/fabric8io/almighty-core/workitem/link"
+	"github.com/fabric8io/almighty-core/workitem/template"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WorkitemtemplateController implements the workitemtemplate resource.
+type WorkitemtemplateController struct {
+	*goa.Controller
+	db     application.DB
+	config WorkitemtemplateControllerConfiguration
+}
+
+// WorkitemtemplateControllerConfiguration the configuration for the WorkitemtemplateController
+type WorkitemtemplateControllerConfiguration interface {
+	GetCacheControlWorkItemTemplates() string
+}
+
+// NewWorkitemtemplateController creates a workitemtemplate controller.
+func NewWorkitemtemplateController(service *goa.Service, db application.DB, config WorkitemtemplateControllerConfiguration) *WorkitemtemplateController {
+	return &WorkitemtemplateController{
+		Controller: service.NewController("WorkitemtemplateController"),
+		db:         db,
+		config:     config}
+}
+
+// List runs the list action.
+func (c *WorkitemtemplateController) List(ctx *app.ListWorkitemtemplateContext) error {
+	return application.Transactional(c.db, func(appl application.Application) error {
+		_, err := appl.Spaces().Load(ctx, ctx.SpaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		templates, err := appl.WorkItemTemplates().List(ctx, ctx.SpaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.ConditionalEntities(templates, c.config.GetCacheControlWorkItemTemplates, func() error {
+			res := &app.WorkItemTemplateList{
+				Data: ConvertWorkItemTemplates(ctx.RequestData, templates),
+				Meta: &app.WorkItemListResponseMeta{TotalCount: len(templates)},
+			}
+			return ctx.OK(res)
+		})
+	})
+}
+
+// Show runs the show action.
+func (c *WorkitemtemplateController) Show(ctx *app.ShowWorkitemtemplateContext) error {
+	return application.Transactional(c.db, func(appl application.Application) error {
+		t, err := appl.WorkItemTemplates().Load(ctx, ctx.SpaceID, ctx.TemplateID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.ConditionalEntity(*t, c.config.GetCacheControlWorkItemTemplates, func() error {
+			res := &app.WorkItemTemplateSingle{
+				Data: ConvertWorkItemTemplate(ctx.RequestData, *t),
+			}
+			return ctx.OK(res)
+		})
+	})
+}
+
+// Create runs the create action.
+func (c *WorkitemtemplateController) Create(ctx *app.CreateWorkitemtemplateContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil || ctx.Payload.Data.Attributes.Name == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.name", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Attributes.Title == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.title", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Relationships == nil || ctx.Payload.Data.Relationships.BaseType == nil || ctx.Payload.Data.Relationships.BaseType.Data == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.relationships.baseType", nil).Expected("not nil"))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		t := template.Template{
+			SpaceID: ctx.SpaceID,
+			Markup:  rendering.SystemMarkupDefault,
+		}
+		if err := convertWorkItemTemplateToModel(ctx, appl, *ctx.Payload.Data, &t); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		err := appl.WorkItemTemplates().Create(ctx, &t)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.WorkItemTemplateSingle{
+			Data: ConvertWorkItemTemplate(ctx.RequestData, t),
+		}
+		ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.RequestData, app.WorkitemtemplateHref(ctx.SpaceID.String(), t.ID.String())))
+		return ctx.Created(res)
+	})
+}
+
+// Update runs the update action.
+func (c *WorkitemtemplateController) Update(ctx *app.UpdateWorkitemtemplateContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Attributes.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		t, err := appl.WorkItemTemplates().Load(ctx, ctx.SpaceID, ctx.TemplateID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		t.Version = *ctx.Payload.Data.Attributes.Version
+		if err := convertWorkItemTemplateToModel(ctx, appl, *ctx.Payload.Data, t); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		t, err = appl.WorkItemTemplates().Save(ctx, *t)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.WorkItemTemplateSingle{
+			Data: ConvertWorkItemTemplate(ctx.RequestData, *t),
+		}
+		return ctx.OK(res)
+	})
+}
+
+// Delete runs the delete action.
+func (c *WorkitemtemplateController) Delete(ctx *app.DeleteWorkitemtemplateContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID, *currentUser); err != nil {
+			return err
+		}
+		return appl.WorkItemTemplates().Delete(ctx, ctx.SpaceID, ctx.TemplateID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// Instantiate runs the instantiate action. The work item and its children
+// are created in a single transaction, so that either all of them or none
+// are created.
+func (c *WorkitemtemplateController) Instantiate(ctx *app.InstantiateWorkitemtemplateContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	authorized, err := authz.Authorize(ctx, ctx.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	title := ""
+	if ctx.Payload != nil && ctx.Payload.Title != nil {
+		title = *ctx.Payload.Title
+	}
+	var data *app.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		t, err := appl.WorkItemTemplates().Load(ctx, ctx.SpaceID, ctx.TemplateID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load work item template %s", ctx.TemplateID)
+		}
+		now := time.Now()
+		fields := map[string]interface{}{
+			workitem.SystemTitle: template.ExpandTitle(t.Title, title, now),
+		}
+		if t.Description != "" {
+			fields[workitem.SystemDescription] = rendering.NewMarkupContent(t.Description, t.Markup)
+		}
+		if len(t.Assignees) > 0 {
+			fields[workitem.SystemAssignees] = []string(t.Assignees)
+		}
+		if t.Area != nil {
+			fields[workitem.SystemArea] = t.Area.String()
+		}
+		if len(t.Labels) > 0 {
+			fields[workitem.SystemLabels] = []string(t.Labels)
+		}
+		wi, err := createFromTemplate(ctx, appl, ctx.SpaceID, t.Type, fields, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		parentID, err := workitem.ParseWorkItemIDToUint64(wi.ID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		for _, child := range t.Children {
+			childFields := map[string]interface{}{
+				workitem.SystemTitle: template.ExpandTitle(child.Title, title, now),
+			}
+			if child.Description != "" {
+				childFields[workitem.SystemDescription] = rendering.NewMarkupContent(child.Description, child.Markup)
+			}
+			if t.Area != nil {
+				childFields[workitem.SystemArea] = t.Area.String()
+			}
+			childWI, err := createFromTemplate(ctx, appl, ctx.SpaceID, child.Type, childFields, *currentUserIdentityID)
+			if err != nil {
+				return err
+			}
+			childID, err := workitem.ParseWorkItemIDToUint64(childWI.ID)
+			if err != nil {
+				return errs.WithStack(err)
+			}
+			if _, err := appl.WorkItemLinks().Create(ctx, parentID, childID, link.SystemWorkItemLinkTypeParentChildID, *currentUserIdentityID); err != nil {
+				return errs.Wrapf(err, "failed to link work item %s to its parent", childWI.ID)
+			}
+		}
+		hasChildren := workItemIncludeHasChildren(appl, ctx)
+		references := workItemIncludeReferences(appl, ctx)
+		key := workItemIncludeKey(appl, ctx)
+		checklist := workItemIncludeChecklist(appl, ctx)
+		reactions := workItemIncludeReactions(appl, ctx)
+		data = ConvertWorkItem(ctx.RequestData, *wi, hasChildren, references, key, checklist, reactions)
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Location", app.WorkitemHref(ctx.SpaceID.String(), *data.ID))
+	return ctx.Created(&app.WorkItemSingle{
+		Data: data,
+	})
+}
+
+// createFromTemplate creates a work item of the given type with the given
+// fields. The work item gets the initial state of its type, i.e. the first
+// state of the workflow of the type or else "new".
+func createFromTemplate(ctx context.Context, appl application.Application, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*workitem.WorkItem, error) {
+	wit, err := appl.WorkItemTypes().LoadByID(ctx, typeID)
+	if err != nil {
+		return nil, errors.NewBadParameterError("workitemtype", typeID)
+	}
+	if _, ok := wit.Fields[workitem.SystemState]; ok {
+		fields[workitem.SystemState] = workitem.SystemStateNew
+		if len(wit.Workflow.States) > 0 {
+			fields[workitem.SystemState] = wit.Workflow.States[0]
+		}
+	}
+	wi, err := appl.WorkItems().Create(ctx, spaceID, typeID, fields, creatorID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to create work item of type %s from template", typeID)
+	}
+	return wi, nil
+}
+
+// convertWorkItemTemplateToModel applies the attributes and relationships
+// given in the request to the given template and verifies that the type,
+// area, labels and assignees can be used in the space of the template
+func convertWorkItemTemplateToModel(ctx context.Context, appl application.Application, source app.WorkItemTemplate, target *template.Template) error {
+	if source.Attributes != nil {
+		if source.Attributes.Name != nil {
+			target.Name = *source.Attributes.Name
+		}
+		if source.Attributes.Title != nil {
+			target.Title = *source.Attributes.Title
+		}
+		if source.Attributes.Description != nil {
+			target.Description = *source.Attributes.Description
+		}
+		if source.Attributes.Markup != nil {
+			target.Markup = *source.Attributes.Markup
+		}
+		if source.Attributes.Children != nil {
+			target.Children = template.Children{}
+			for _, child := range source.Attributes.Children {
+				c := template.Child{
+					Type:   child.Workitemtype,
+					Title:  child.Title,
+					Markup: rendering.SystemMarkupDefault,
+				}
+				if child.Description != nil {
+					c.Description = *child.Description
+				}
+				if child.Markup != nil {
+					c.Markup = *child.Markup
+				}
+				target.Children = append(target.Children, c)
+			}
+		}
+	}
+	if source.Relationships != nil {
+		if source.Relationships.BaseType != nil && source.Relationships.BaseType.Data != nil {
+			target.Type = source.Relationships.BaseType.Data.ID
+		}
+		if source.Relationships.Assignees != nil {
+			target.Assignees = template.IDs{}
+			for _, d := range source.Relationships.Assignees.Data {
+				if d.ID == nil {
+					return errors.NewBadParameterError("data.relationships.assignees.data.id", nil)
+				}
+				assigneeID, err := uuid.FromString(*d.ID)
+				if err != nil || !appl.Identities().IsValid(ctx, assigneeID) {
+					return errors.NewBadParameterError("data.relationships.assignees.data.id", *d.ID)
+				}
+				target.Assignees = append(target.Assignees, assigneeID.String())
+			}
+		}
+		if source.Relationships.Area != nil {
+			target.Area = nil
+			if source.Relationships.Area.Data != nil && source.Relationships.Area.Data.ID != nil {
+				areaID, err := uuid.FromString(*source.Relationships.Area.Data.ID)
+				if err != nil {
+					return errors.NewBadParameterError("data.relationships.area.data.id", *source.Relationships.Area.Data.ID)
+				}
+				a, err := appl.Areas().Load(ctx, areaID)
+				if err != nil || !uuid.Equal(a.SpaceID, target.SpaceID) {
+					return errors.NewBadParameterError("data.relationships.area.data.id", areaID).Expected("area of the space")
+				}
+				target.Area = &areaID
+			}
+		}
+		if source.Relationships.Labels != nil {
+			target.Labels = template.IDs{}
+			for _, d := range source.Relationships.Labels.Data {
+				if d.ID == nil {
+					return errors.NewBadParameterError("data.relationships.labels.data.id", nil)
+				}
+				target.Labels = append(target.Labels, *d.ID)
+			}
+			if err := appl.Labels().CheckExists(ctx, target.SpaceID, target.Labels); err != nil {
+				return err
+			}
+		}
+	}
+	if target.Title == "" {
+		return errors.NewBadParameterError("data.attributes.title", target.Title).Expected("not empty")
+	}
+	if !rendering.IsMarkupSupported(target.Markup) {
+		return errors.NewBadParameterError("data.attributes.markup", target.Markup)
+	}
+	if err := checkTemplateType(ctx, appl, target.SpaceID, target.Type); err != nil {
+		return errs.Wrapf(err, "invalid type of the work item template")
+	}
+	for _, child := range target.Children {
+		if !rendering.IsMarkupSupported(child.Markup) {
+			return errors.NewBadParameterError("data.attributes.children.markup", child.Markup)
+		}
+		if err := checkTemplateType(ctx, appl, target.SpaceID, child.Type); err != nil {
+			return errs.Wrapf(err, "invalid type of the child of the work item template")
+		}
+	}
+	return nil
+}
+
+// checkTemplateType returns a BadParameterError if work items of the given
+// type cannot be created in the given space
+func checkTemplateType(ctx context.Context, appl application.Application, spaceID uuid.UUID, typeID uuid.UUID) error {
+	wit, err := appl.WorkItemTypes().LoadByID(ctx, typeID)
+	if err != nil || (!uuid.Equal(wit.SpaceID, spaceID) && !uuid.Equal(wit.SpaceID, space.SystemSpace)) {
+		return errors.NewBadParameterError("workitemtype", typeID).Expected("work item type of the space or of the system space")
+	}
+	return nil
+}
+
+// ConvertWorkItemTemplates converts between internal and external REST representation
+func ConvertWorkItemTemplates(request *goa.RequestData, templates []template.Template) []*app.WorkItemTemplate {
+	var ts = []*app.WorkItemTemplate{}
+	for _, t := range templates {
+		ts = append(ts, ConvertWorkItemTemplate(request, t))
+	}
+	return ts
+}
+
+// ConvertWorkItemTemplate converts between internal and external REST representation
+func ConvertWorkItemTemplate(request *goa.RequestData, t template.Template) *app.WorkItemTemplate {
+	spaceID := t.SpaceID.String()
+	selfURL := rest.AbsoluteURL(request, app.WorkitemtemplateHref(spaceID, t.ID.String()))
+	spaceSelfURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	witSelfURL := rest.AbsoluteURL(request, app.WorkitemtypeHref(spaceID, t.Type))
+	children := make([]*app.WorkItemTemplateChild, len(t.Children))
+	for i := range t.Children {
+		children[i] = &app.WorkItemTemplateChild{
+			Workitemtype: t.Children[i].Type,
+			Title:        t.Children[i].Title,
+			Description:  &t.Children[i].Description,
+			Markup:       &t.Children[i].Markup,
+		}
+	}
+	assignees := make([]interface{}, len(t.Assignees))
+	for i, id := range t.Assignees {
+		assignees[i] = id
+	}
+	labels := make([]*app.GenericData, len(t.Labels))
+	for i, id := range t.Labels {
+		labels[i] = convertReferenceSimple(request, t.SpaceID, workitem.KindLabel, id)
+	}
+	res := &app.WorkItemTemplate{
+		Type: template.APIStringTypeTemplates,
+		ID:   &t.ID,
+		Attributes: &app.WorkItemTemplateAttributes{
+			Name:        &t.Name,
+			Title:       &t.Title,
+			Description: &t.Description,
+			Markup:      &t.Markup,
+			Children:    children,
+			CreatedAt:   &t.CreatedAt,
+			UpdatedAt:   &t.UpdatedAt,
+			Version:     &t.Version,
+		},
+		Relationships: &app.WorkItemTemplateRelations{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self: &spaceSelfURL,
+				},
+			},
+			BaseType: &app.RelationBaseType{
+				Data: &app.BaseTypeData{
+					ID:   t.Type,
+					Type: APIStringTypeWorkItemType,
+				},
+				Links: &app.GenericLinks{
+					Self: &witSelfURL,
+				},
+			},
+			Assignees: &app.RelationGenericList{
+				Data: ConvertUsersSimple(request, assignees),
+			},
+			Labels: &app.RelationGenericList{
+				Data: labels,
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+	if t.Area != nil {
+		res.Relationships.Area = &app.RelationGeneric{
+			Data: ConvertAreaSimple(request, t.Area.String()),
+		}
+	}
+	return res
+}
@@ -0,0 +1,149 @@
+package controller_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/app/test"
+	. "github.com/fabric8io/almighty-core/controller"
+	"github.com/fabric8io/almighty-core/gormapplication"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+	testsupport "github.com/fabric8io/almighty-core/test"
+	almtoken "github.com/fabric8io/almighty-core/token"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemTemplateREST struct {
+	gormtestsupport.DBTestSuite
+	db    *gormapplication.GormDB
+	clean func()
+	owner account.Identity
+	space *space.Space
+}
+
+func TestRunWorkItemTemplateREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	pwd, err := os.Getwd()
+	if err != nil {
+		require.Nil(t, err)
+	}
+	suite.Run(t, &TestWorkItemTemplateREST{DBTestSuite: gormtestsupport.NewDBTestSuite(pwd + "/../config.yaml")})
+}
+
+func (rest *TestWorkItemTemplateREST) SetupTest() {
+	rest.db = gormapplication.NewGormDB(rest.DB)
+	rest.clean = cleaner.DeleteCreatedEntities(rest.DB)
+	owner, err := testsupport.CreateTestIdentity(rest.DB, "TestWorkItemTemplateREST-"+uuid.NewV4().String(), "test provider")
+	require.Nil(rest.T(), err)
+	rest.owner = owner
+	rest.space, err = rest.db.Spaces().Create(context.Background(), &space.Space{
+		Name:    "TestWorkItemTemplateREST-" + uuid.NewV4().String(),
+		OwnerId: owner.ID,
+	})
+	require.Nil(rest.T(), err)
+}
+
+func (rest *TestWorkItemTemplateREST) TearDownTest() {
+	rest.clean()
+}
+
+func (rest *TestWorkItemTemplateREST) SecuredControllerWithIdentity(idn account.Identity) (*goa.Service, *WorkitemtemplateController) {
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	svc := testsupport.ServiceAsUser("WorkItemTemplate-Service", almtoken.NewManagerWithPrivateKey(priv), idn)
+	return svc, NewWorkitemtemplateController(svc, rest.db, rest.Configuration)
+}
+
+func newCreateWorkItemTemplatePayload(name string, title string, children ...*app.WorkItemTemplateChild) *app.CreateWorkitemtemplatePayload {
+	return &app.CreateWorkitemtemplatePayload{
+		Data: &app.WorkItemTemplate{
+			Type: "workitemtemplates",
+			Attributes: &app.WorkItemTemplateAttributes{
+				Name:     &name,
+				Title:    &title,
+				Children: children,
+			},
+			Relationships: &app.WorkItemTemplateRelations{
+				BaseType: &app.RelationBaseType{
+					Data: &app.BaseTypeData{
+						ID:   workitem.SystemFeature,
+						Type: APIStringTypeWorkItemType,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (rest *TestWorkItemTemplateREST) TestCreateWorkItemTemplate() {
+	rest.T().Run("owner", func(t *testing.T) {
+		// given
+		svc, ctrl := rest.SecuredControllerWithIdentity(rest.owner)
+		// when
+		_, created := test.CreateWorkitemtemplateCreated(t, svc.Context, svc, ctrl, rest.space.ID, newCreateWorkItemTemplatePayload("release", "Release {title}"))
+		// then
+		require.NotNil(t, created.Data.ID)
+		assert.Equal(t, "release", *created.Data.Attributes.Name)
+		assert.Equal(t, "Release {title}", *created.Data.Attributes.Title)
+		assert.Equal(t, workitem.SystemFeature, created.Data.Relationships.BaseType.Data.ID)
+	})
+
+	rest.T().Run("unknown child type", func(t *testing.T) {
+		// given
+		svc, ctrl := rest.SecuredControllerWithIdentity(rest.owner)
+		payload := newCreateWorkItemTemplatePayload("unknown", "Unknown", &app.WorkItemTemplateChild{Workitemtype: uuid.NewV4(), Title: "child"})
+		// when/then
+		test.CreateWorkitemtemplateBadRequest(t, svc.Context, svc, ctrl, rest.space.ID, payload)
+	})
+
+	rest.T().Run("not the owner", func(t *testing.T) {
+		// given
+		otherIdentity, err := testsupport.CreateTestIdentity(rest.DB, "TestCreateWorkItemTemplate-"+uuid.NewV4().String(), "test provider")
+		require.Nil(t, err)
+		svc, ctrl := rest.SecuredControllerWithIdentity(otherIdentity)
+		// when/then
+		test.CreateWorkitemtemplateForbidden(t, svc.Context, svc, ctrl, rest.space.ID, newCreateWorkItemTemplatePayload("other", "Other"))
+	})
+}
+
+func (rest *TestWorkItemTemplateREST) TestInstantiateWorkItemTemplate() {
+	// given a template with two children
+	svc, ctrl := rest.SecuredControllerWithIdentity(rest.owner)
+	payload := newCreateWorkItemTemplatePayload("release", "Release {title}",
+		&app.WorkItemTemplateChild{Workitemtype: workitem.SystemTask, Title: "Write release notes for {title}"},
+		&app.WorkItemTemplateChild{Workitemtype: workitem.SystemTask, Title: "Tag {title}"},
+	)
+	_, created := test.CreateWorkitemtemplateCreated(rest.T(), svc.Context, svc, ctrl, rest.space.ID, payload)
+
+	rest.T().Run("ok", func(t *testing.T) {
+		// when
+		title := "1.2"
+		_, result := test.InstantiateWorkitemtemplateCreated(t, svc.Context, svc, ctrl, rest.space.ID, *created.Data.ID, &app.WorkItemTemplateInstantiatePayload{Title: &title})
+		// then
+		require.NotNil(t, result.Data.ID)
+		assert.Equal(t, "Release 1.2", result.Data.Attributes[workitem.SystemTitle])
+		assert.Equal(t, workitem.SystemStateNew, result.Data.Attributes[workitem.SystemState])
+		children, _, err := rest.db.WorkItemLinks().ListWorkItemChildren(svc.Context, *result.Data.ID, nil, nil)
+		require.Nil(t, err)
+		require.Len(t, children, 2)
+		titles := []interface{}{children[0].Fields[workitem.SystemTitle], children[1].Fields[workitem.SystemTitle]}
+		assert.Contains(t, titles, "Write release notes for 1.2")
+		assert.Contains(t, titles, "Tag 1.2")
+	})
+
+	rest.T().Run("unknown template", func(t *testing.T) {
+		// when/then
+		test.InstantiateWorkitemtemplateNotFound(t, svc.Context, svc, ctrl, rest.space.ID, uuid.NewV4(), &app.WorkItemTemplateInstantiatePayload{})
+	})
+}
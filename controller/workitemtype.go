@@ -5,14 +5,17 @@ import (
 
 	"github.com/fabric8io/almighty-core/app"
 	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/errors"
 	"github.com/fabric8io/almighty-core/jsonapi"
 	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/login"
 	"github.com/fabric8io/almighty-core/rest"
 	"github.com/fabric8io/almighty-core/space"
 	"github.com/fabric8io/almighty-core/workitem"
 
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 const (
@@ -58,6 +61,25 @@ func (c *WorkitemtypeController) Show(ctx *app.ShowWorkitemtypeContext) error {
 // Create runs the create action.
 func (c *WorkitemtypeController) Create(ctx *app.CreateWorkitemtypeContext) error {
 	return application.Transactional(c.db, func(appl application.Application) error {
+		if !uuid.Equal(ctx.SpaceID, space.SystemSpace) {
+			// only the owner can define the work item types of a space
+			currentUser, err := login.ContextIdentity(ctx)
+			if err != nil {
+				return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+			}
+			s, err := appl.Spaces().Load(ctx, ctx.SpaceID)
+			if err != nil {
+				return jsonapi.JSONErrorResponse(ctx, err)
+			}
+			if !uuid.Equal(*currentUser, s.OwnerId) {
+				log.Warn(ctx, map[string]interface{}{
+					"space_id":     ctx.SpaceID,
+					"space_owner":  s.OwnerId,
+					"current_user": *currentUser,
+				}, "user is not the space owner")
+				return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not the space owner"))
+			}
+		}
 		var fields = map[string]app.FieldDefinition{}
 		for key, fd := range ctx.Payload.Data.Attributes.Fields {
 			fields[key] = *fd
@@ -91,6 +113,73 @@ func (c *WorkitemtypeController) Create(ctx *app.CreateWorkitemtypeContext) erro
 	})
 }
 
+// Update runs the update action.
+func (c *WorkitemtypeController) Update(ctx *app.UpdateWorkitemtypeContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Attributes.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	if uuid.Equal(ctx.SpaceID, space.SystemSpace) {
+		// the system work item types are (re-)created by the migration
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("work item types of the system space cannot be updated"))
+	}
+	// the cached definitions of the type and its subtypes may have been read
+	// again before the update was committed
+	defer workitem.ClearGlobalWorkItemTypeCache()
+	return application.Transactional(c.db, func(appl application.Application) error {
+		s, err := appl.Spaces().Load(ctx, ctx.SpaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		if !uuid.Equal(*currentUser, s.OwnerId) {
+			log.Warn(ctx, map[string]interface{}{
+				"space_id":     ctx.SpaceID,
+				"space_owner":  s.OwnerId,
+				"current_user": *currentUser,
+			}, "user is not the space owner")
+			return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not the space owner"))
+		}
+		var fields = map[string]app.FieldDefinition{}
+		for key, fd := range ctx.Payload.Data.Attributes.Fields {
+			fields[key] = *fd
+		}
+		modelFields, err := ConvertFieldDefinitionsToModel(fields)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		witToUpdate := workitem.WorkItemType{
+			ID:          ctx.WitID,
+			Version:     *ctx.Payload.Data.Attributes.Version,
+			Name:        ctx.Payload.Data.Attributes.Name,
+			Description: ctx.Payload.Data.Attributes.Description,
+			Icon:        ctx.Payload.Data.Attributes.Icon,
+			Fields:      modelFields,
+			Workflow:    convertWorkflowToModel(ctx.Payload.Data.Attributes.Workflow),
+		}
+		if ctx.Payload.Data.Attributes.Workflow == nil {
+			// keep the stored workflow when the payload does not define one
+			existing, err := appl.WorkItemTypes().Load(ctx.Context, ctx.SpaceID, ctx.WitID)
+			if err != nil {
+				return jsonapi.JSONErrorResponse(ctx, err)
+			}
+			witToUpdate.Workflow = existing.Workflow
+		}
+		convert := ctx.Convert != nil && *ctx.Convert
+		witModel, err := appl.WorkItemTypes().Update(ctx.Context, ctx.SpaceID, witToUpdate, *currentUser, convert)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		witData := ConvertWorkItemTypeFromModel(ctx.RequestData, witModel)
+		return ctx.OK(&app.WorkItemTypeSingle{Data: &witData})
+	})
+}
+
 // List runs the list action
 func (c *WorkitemtypeController) List(ctx *app.ListWorkitemtypeContext) error {
 	log.Debug(ctx, map[string]interface{}{"space_id": ctx.SpaceID}, "Listing work item types per space")
@@ -111,14 +200,6 @@ func (c *WorkitemtypeController) List(ctx *app.ListWorkitemtypeContext) error {
 			}
 		}
 		return ctx.ConditionalEntities(witModels, c.config.GetCacheControlWorkItemTypes, func() error {
-			// TEMP!!!!! Until Space Template can setup a Space, redirect to SystemSpace WITs if non are found
-			// for the space.
-			if len(witModels) == 0 {
-				witModels, err = appl.WorkItemTypes().List(ctx.Context, space.SystemSpace, start, &limit)
-				if err != nil {
-					return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "Error listing work item types"))
-				}
-			}
 			// convert from model to app
 			result := &app.WorkItemTypeList{}
 			result.Data = make([]*app.WorkItemTypeData, len(witModels))
@@ -215,6 +296,7 @@ func ConvertWorkItemTypeFromModel(request *goa.RequestData, t *workitem.WorkItem
 			Icon:        t.Icon,
 			Name:        t.Name,
 			Fields:      map[string]*app.FieldDefinition{},
+			Workflow:    convertWorkflowFromModel(t.Workflow),
 		},
 		Relationships: &app.WorkItemTypeRelationships{
 			Space: app.NewSpaceRelation(t.SpaceID, spaceSelfURL),
@@ -227,11 +309,72 @@ func ConvertWorkItemTypeFromModel(request *goa.RequestData, t *workitem.WorkItem
 			Label:       def.Label,
 			Description: def.Description,
 			Type:        &ct,
+			Constraints: convertFieldConstraintsFromModel(def.Constraints),
+			Default:     convertFieldDefaultFromModel(def.Default),
 		}
 	}
 	return converted
 }
 
+// converts the field constraints from model to app representation
+func convertFieldConstraintsFromModel(c *workitem.FieldConstraints) *app.FieldConstraints {
+	if c == nil {
+		return nil
+	}
+	return &app.FieldConstraints{
+		Minimum:    c.Minimum,
+		Maximum:    c.Maximum,
+		MinLength:  c.MinLength,
+		MaxLength:  c.MaxLength,
+		Pattern:    c.Pattern,
+		URLSchemes: c.URLSchemes,
+		MaxItems:   c.MaxItems,
+	}
+}
+
+// converts the field default from model to app representation
+func convertFieldDefaultFromModel(d *workitem.FieldDefault) *app.FieldDefault {
+	if d == nil {
+		return nil
+	}
+	value := d.Value
+	return &app.FieldDefault{
+		Kind:  string(d.Kind),
+		Value: &value,
+	}
+}
+
+// converts the field default from app to model representation
+func convertFieldDefaultToModel(d *app.FieldDefault) *workitem.FieldDefault {
+	if d == nil {
+		return nil
+	}
+	var value interface{}
+	if d.Value != nil {
+		value = *d.Value
+	}
+	return &workitem.FieldDefault{
+		Kind:  workitem.DefaultKind(d.Kind),
+		Value: value,
+	}
+}
+
+// converts the field constraints from app to model representation
+func convertFieldConstraintsToModel(c *app.FieldConstraints) *workitem.FieldConstraints {
+	if c == nil {
+		return nil
+	}
+	return &workitem.FieldConstraints{
+		Minimum:    c.Minimum,
+		Maximum:    c.Maximum,
+		MinLength:  c.MinLength,
+		MaxLength:  c.MaxLength,
+		Pattern:    c.Pattern,
+		URLSchemes: c.URLSchemes,
+		MaxItems:   c.MaxItems,
+	}
+}
+
 // converts the field type from modesl to app representation
 func convertFieldTypeFromModel(t workitem.FieldType) app.FieldType {
 	result := app.FieldType{}
@@ -300,6 +443,18 @@ func ConvertFieldDefinitionsToModel(fields map[string]app.FieldDefinition) (map[
 			Description: definition.Description,
 			Required:    definition.Required,
 			Type:        ct,
+			Constraints: convertFieldConstraintsToModel(definition.Constraints),
+			Default:     convertFieldDefaultToModel(definition.Default),
+		}
+		if converted.Constraints != nil {
+			if err := converted.Constraints.CheckApplicable(field, ct); err != nil {
+				return nil, errs.WithStack(err)
+			}
+		}
+		if converted.Default != nil {
+			if err := converted.Default.CheckApplicable(field, ct); err != nil {
+				return nil, errs.WithStack(err)
+			}
 		}
 		modelFields[field] = converted
 	}
@@ -915,3 +915,117 @@ func (s *workItemTypeSuite) getWorkItemTypeTestDataFunc() func(*testing.T) []tes
 		}
 	}
 }
+
+func (s *workItemTypeSuite) TestUpdateWorkItemType() {
+	// given
+	spacePayload := CreateSpacePayload("some-wit-space-"+uuid.NewV4().String(), "description")
+	_, sp := test.CreateSpaceCreated(s.T(), s.svc.Context, s.svc, s.spaceCtrl, spacePayload)
+	createPayload := CreateWorkItemType(uuid.NewV4(), *sp.Data.ID)
+	_, wit := test.CreateWorkitemtypeCreated(s.T(), s.svc.Context, s.svc, s.typeCtrl, *sp.Data.ID, &createPayload)
+	require.NotNil(s.T(), wit.Data.Attributes.Version)
+
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		label := "Test"
+		payload := app.UpdateWorkitemtypePayload{Data: wit.Data}
+		payload.Data.Attributes.Fields["test"].Label = label
+		_, updated := test.UpdateWorkitemtypeOK(t, s.svc.Context, s.svc, s.typeCtrl, *sp.Data.ID, *wit.Data.ID, nil, &payload)
+		// then
+		assert.Equal(t, *wit.Data.Attributes.Version+1, *updated.Data.Attributes.Version)
+		assert.Equal(t, label, updated.Data.Attributes.Fields["test"].Label)
+	})
+
+	s.T().Run("system space", func(t *testing.T) {
+		payload := app.UpdateWorkitemtypePayload{Data: wit.Data}
+		test.UpdateWorkitemtypeForbidden(t, s.svc.Context, s.svc, s.typeCtrl, space.SystemSpace, *wit.Data.ID, nil, &payload)
+	})
+
+	s.T().Run("workflow kept when omitted", func(t *testing.T) {
+		// given
+		createPayload := CreateWorkItemType(uuid.NewV4(), *sp.Data.ID)
+		_, wit := test.CreateWorkitemtypeCreated(t, s.svc.Context, s.svc, s.typeCtrl, *sp.Data.ID, &createPayload)
+		payload := app.UpdateWorkitemtypePayload{Data: wit.Data}
+		payload.Data.Attributes.Fields[workitem.SystemState] = &app.FieldDefinition{
+			Type: &app.FieldType{Kind: "string"},
+		}
+		payload.Data.Attributes.Workflow = &app.WorkflowDefinition{
+			States: []string{workitem.SystemStateNew, workitem.SystemStateOpen},
+			Transitions: []*app.WorkflowTransition{
+				{From: workitem.SystemStateNew, To: workitem.SystemStateOpen},
+			},
+		}
+		_, withWorkflow := test.UpdateWorkitemtypeOK(t, s.svc.Context, s.svc, s.typeCtrl, *sp.Data.ID, *wit.Data.ID, nil, &payload)
+		require.NotNil(t, withWorkflow.Data.Attributes.Workflow)
+		workflow := *withWorkflow.Data.Attributes.Workflow
+		// when
+		desc := "updated description"
+		payload = app.UpdateWorkitemtypePayload{Data: withWorkflow.Data}
+		payload.Data.Attributes.Description = &desc
+		payload.Data.Attributes.Workflow = nil
+		_, updated := test.UpdateWorkitemtypeOK(t, s.svc.Context, s.svc, s.typeCtrl, *sp.Data.ID, *wit.Data.ID, nil, &payload)
+		// then
+		assert.Equal(t, desc, *updated.Data.Attributes.Description)
+		require.NotNil(t, updated.Data.Attributes.Workflow)
+		assert.Equal(t, workflow.States, updated.Data.Attributes.Workflow.States)
+		require.Len(t, updated.Data.Attributes.Workflow.Transitions, 1)
+		assert.Equal(t, workitem.SystemStateOpen, updated.Data.Attributes.Workflow.Transitions[0].To)
+		// when
+		payload = app.UpdateWorkitemtypePayload{Data: updated.Data}
+		payload.Data.Attributes.Workflow = &app.WorkflowDefinition{
+			States:      []string{},
+			Transitions: []*app.WorkflowTransition{},
+		}
+		_, cleared := test.UpdateWorkitemtypeOK(t, s.svc.Context, s.svc, s.typeCtrl, *sp.Data.ID, *wit.Data.ID, nil, &payload)
+		// then
+		assert.Nil(t, cleared.Data.Attributes.Workflow)
+	})
+}
+
+func (s *workItemTypeSuite) TestShowWorkflow() {
+	ctrl := NewWorkitemtypeWorkflowController(s.svc, gormapplication.NewGormDB(s.DB))
+
+	s.T().Run("type without workflow", func(t *testing.T) {
+		// when
+		_, workflow := test.ShowWorkitemtypeWorkflowOK(t, s.svc.Context, s.svc, ctrl, space.SystemSpace, workitem.SystemBug)
+		// then
+		require.NotNil(t, workflow.Data)
+		assert.Equal(t, workitem.SystemBug, *workflow.Data.ID)
+		assert.False(t, workflow.Data.Attributes.Defined)
+		assert.Contains(t, workflow.Data.Attributes.States, workitem.SystemStateNew)
+		assert.NotEmpty(t, workflow.Data.Attributes.Transitions)
+	})
+
+	s.T().Run("unknown type", func(t *testing.T) {
+		test.ShowWorkitemtypeWorkflowNotFound(t, s.svc.Context, s.svc, ctrl, space.SystemSpace, uuid.NewV4())
+	})
+}
+
+func (s *workItemTypeSuite) TestCreateWorkItemTypeInSpace() {
+	// given
+	spacePayload := CreateSpacePayload("inheritance-space-"+uuid.NewV4().String(), "description")
+	_, sp := test.CreateSpaceCreated(s.T(), s.svc.Context, s.svc, s.spaceCtrl, spacePayload)
+	payload := CreateWorkItemType(uuid.NewV4(), *sp.Data.ID)
+	payload.Data.Attributes.ExtendedTypeName = &workitem.SystemPlannerItem
+
+	s.T().Run("owner", func(t *testing.T) {
+		// when
+		_, wit := test.CreateWorkitemtypeCreated(t, s.svc.Context, s.svc, s.typeCtrl, *sp.Data.ID, &payload)
+		// then
+		require.NotNil(t, wit.Data)
+		assert.Contains(t, wit.Data.Attributes.Fields, workitem.SystemTitle)
+		assert.Contains(t, wit.Data.Attributes.Fields, "test")
+		_, list := test.ListWorkitemtypeOK(t, s.svc.Context, s.svc, s.typeCtrl, *sp.Data.ID, nil, nil, nil)
+		assert.Condition(t, lookupWorkItemTypes(*list, *wit))
+	})
+
+	s.T().Run("not the owner", func(t *testing.T) {
+		// given
+		otherIdentity, err := testsupport.CreateTestIdentity(s.DB, "TestCreateWorkItemTypeInSpace-"+uuid.NewV4().String(), "test provider")
+		require.Nil(t, err)
+		priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+		svc := testsupport.ServiceAsUser("workItemType-Service", almtoken.NewManagerWithPrivateKey(priv), otherIdentity)
+		otherPayload := CreateWorkItemType(uuid.NewV4(), *sp.Data.ID)
+		// when/then
+		test.CreateWorkitemtypeForbidden(t, svc.Context, svc, s.typeCtrl, *sp.Data.ID, &otherPayload)
+	})
+}
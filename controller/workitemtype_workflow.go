@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"github.com/fabric8io/almighty-core/app"
+	"github.com/fabric8io/almighty-core/application"
+	"github.com/fabric8io/almighty-core/jsonapi"
+	"github.com/fabric8io/almighty-core/rest"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+)
+
+// WorkitemtypeWorkflowController implements the workitemtype_workflow resource.
+type WorkitemtypeWorkflowController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkitemtypeWorkflowController creates a workitemtype_workflow controller.
+func NewWorkitemtypeWorkflowController(service *goa.Service, db application.DB) *WorkitemtypeWorkflowController {
+	return &WorkitemtypeWorkflowController{
+		Controller: service.NewController("WorkitemtypeWorkflowController"),
+		db:         db,
+	}
+}
+
+// Show runs the show action.
+func (c *WorkitemtypeWorkflowController) Show(ctx *app.ShowWorkitemtypeWorkflowContext) error {
+	return application.Transactional(c.db, func(appl application.Application) error {
+		witModel, err := appl.WorkItemTypes().Load(ctx.Context, ctx.SpaceID, ctx.WitID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		witSelfURL := rest.AbsoluteURL(ctx.RequestData, app.WorkitemtypeHref(witModel.SpaceID.String(), witModel.ID.String()))
+		workflowSelfURL := rest.AbsoluteURL(ctx.RequestData, app.WorkitemtypeWorkflowHref(witModel.SpaceID.String(), witModel.ID.String()))
+		workflow := witModel.EffectiveWorkflow()
+		defined := !witModel.Workflow.IsEmpty()
+		id := witModel.ID
+		witType := "workitemtypes"
+		witID := witModel.ID.String()
+		return ctx.OK(&app.WorkflowSingle{
+			Data: &app.WorkflowData{
+				Type: "workflows",
+				ID:   &id,
+				Attributes: &app.WorkflowAttributes{
+					States:      workflow.States,
+					Transitions: convertWorkflowTransitionsFromModel(workflow.Transitions),
+					Defined:     defined,
+				},
+				Relationships: &app.WorkflowRelationships{
+					Workitemtype: &app.RelationGeneric{
+						Data: &app.GenericData{
+							Type: &witType,
+							ID:   &witID,
+						},
+						Links: &app.GenericLinks{
+							Self: &witSelfURL,
+						},
+					},
+				},
+				Links: &app.GenericLinks{
+					Self: &workflowSelfURL,
+				},
+			},
+		})
+	})
+}
+
+// converts the workflow from model to app representation
+func convertWorkflowFromModel(w workitem.Workflow) *app.WorkflowDefinition {
+	if w.IsEmpty() {
+		return nil
+	}
+	return &app.WorkflowDefinition{
+		States:      w.States,
+		Transitions: convertWorkflowTransitionsFromModel(w.Transitions),
+	}
+}
+
+func convertWorkflowTransitionsFromModel(transitions []workitem.WorkflowTransition) []*app.WorkflowTransition {
+	result := make([]*app.WorkflowTransition, len(transitions))
+	for i, t := range transitions {
+		result[i] = &app.WorkflowTransition{
+			From:           t.From,
+			To:             t.To,
+			RequiredFields: t.RequiredFields,
+		}
+	}
+	return result
+}
+
+// converts the workflow from app to model representation
+func convertWorkflowToModel(w *app.WorkflowDefinition) workitem.Workflow {
+	if w == nil {
+		return workitem.Workflow{}
+	}
+	result := workitem.Workflow{States: w.States}
+	for _, t := range w.Transitions {
+		if t == nil {
+			continue
+		}
+		result.Transitions = append(result.Transitions, workitem.WorkflowTransition{
+			From:           t.From,
+			To:             t.To,
+			RequiredFields: t.RequiredFields,
+		})
+	}
+	return result
+}
@@ -45,6 +45,14 @@ type ExpressionVisitor interface {
 	Literal(c *LiteralExpression) interface{}
 	Not(e *NotExpression) interface{}
 	IsNull(e *IsNullExpression) interface{}
+	Negate(e *NegateExpression) interface{}
+	GreaterThan(e *GreaterThanExpression) interface{}
+	GreaterOrEqual(e *GreaterOrEqualExpression) interface{}
+	LessThan(e *LessThanExpression) interface{}
+	LessOrEqual(e *LessOrEqualExpression) interface{}
+	In(e *InExpression) interface{}
+	Contains(e *ContainsExpression) interface{}
+	Between(e *BetweenExpression) interface{}
 }
 
 type expression struct {
@@ -143,6 +151,12 @@ func (exp *binaryExpression) Right() Expression {
 	return exp.right
 }
 
+// UnaryExpression represents expressions with a single child
+type UnaryExpression interface {
+	Expression
+	Operand() Expression
+}
+
 // make sure the children have the correct parent
 func reparent(parent BinaryExpression) Expression {
 	parent.Left().setParent(parent)
@@ -235,3 +249,188 @@ func (t *NotExpression) Accept(visitor ExpressionVisitor) interface{} {
 func Not(left Expression, right Expression) Expression {
 	return reparent(&NotExpression{binaryExpression{expression{}, left, right}})
 }
+
+// Negate
+
+// NegateExpression represents the logical negation of a term
+type NegateExpression struct {
+	expression
+	operand Expression
+}
+
+// Operand implements UnaryExpression
+func (t *NegateExpression) Operand() Expression {
+	return t.operand
+}
+
+// Accept implements ExpressionVisitor
+func (t *NegateExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.Negate(t)
+}
+
+// Negate constructs a NegateExpression
+func Negate(operand Expression) Expression {
+	result := &NegateExpression{expression{}, operand}
+	operand.setParent(result)
+	return result
+}
+
+// >
+
+// GreaterThanExpression represents the > operator
+type GreaterThanExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *GreaterThanExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.GreaterThan(t)
+}
+
+// GreaterThan constructs a GreaterThanExpression
+func GreaterThan(left Expression, right Expression) Expression {
+	return reparent(&GreaterThanExpression{binaryExpression{expression{}, left, right}})
+}
+
+// >=
+
+// GreaterOrEqualExpression represents the >= operator
+type GreaterOrEqualExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *GreaterOrEqualExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.GreaterOrEqual(t)
+}
+
+// GreaterOrEqual constructs a GreaterOrEqualExpression
+func GreaterOrEqual(left Expression, right Expression) Expression {
+	return reparent(&GreaterOrEqualExpression{binaryExpression{expression{}, left, right}})
+}
+
+// <
+
+// LessThanExpression represents the < operator
+type LessThanExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *LessThanExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.LessThan(t)
+}
+
+// LessThan constructs a LessThanExpression
+func LessThan(left Expression, right Expression) Expression {
+	return reparent(&LessThanExpression{binaryExpression{expression{}, left, right}})
+}
+
+// <=
+
+// LessOrEqualExpression represents the <= operator
+type LessOrEqualExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *LessOrEqualExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.LessOrEqual(t)
+}
+
+// LessOrEqual constructs a LessOrEqualExpression
+func LessOrEqual(left Expression, right Expression) Expression {
+	return reparent(&LessOrEqualExpression{binaryExpression{expression{}, left, right}})
+}
+
+// Contains
+
+// ContainsExpression represents a case insensitive substring match of the
+// right term in the left term (think SQL ILIKE '%right%')
+type ContainsExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *ContainsExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.Contains(t)
+}
+
+// Contains constructs a ContainsExpression
+func Contains(left Expression, right Expression) Expression {
+	return reparent(&ContainsExpression{binaryExpression{expression{}, left, right}})
+}
+
+// IN
+
+// InExpression represents the membership of a term in a list of values
+type InExpression struct {
+	expression
+	left   Expression
+	values []Expression
+}
+
+// Left returns the term that is tested for membership
+func (t *InExpression) Left() Expression {
+	return t.left
+}
+
+// Values returns the list of values the left term is compared with
+func (t *InExpression) Values() []Expression {
+	return t.values
+}
+
+// Accept implements ExpressionVisitor
+func (t *InExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.In(t)
+}
+
+// In constructs an InExpression
+func In(left Expression, values ...Expression) Expression {
+	result := &InExpression{expression{}, left, values}
+	left.setParent(result)
+	for _, v := range values {
+		v.setParent(result)
+	}
+	return result
+}
+
+// BETWEEN
+
+// BetweenExpression represents a range test of a term, both bounds are
+// inclusive
+type BetweenExpression struct {
+	expression
+	operand Expression
+	lower   Expression
+	upper   Expression
+}
+
+// Operand returns the term that is tested
+func (t *BetweenExpression) Operand() Expression {
+	return t.operand
+}
+
+// Lower returns the lower bound of the range
+func (t *BetweenExpression) Lower() Expression {
+	return t.lower
+}
+
+// Upper returns the upper bound of the range
+func (t *BetweenExpression) Upper() Expression {
+	return t.upper
+}
+
+// Accept implements ExpressionVisitor
+func (t *BetweenExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.Between(t)
+}
+
+// Between constructs a BetweenExpression
+func Between(operand Expression, lower Expression, upper Expression) Expression {
+	result := &BetweenExpression{expression{}, operand, lower, upper}
+	operand.setParent(result)
+	lower.setParent(result)
+	upper.setParent(result)
+	return result
+}
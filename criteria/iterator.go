@@ -43,6 +43,51 @@ func (i *postOrderIterator) IsNull(exp *IsNullExpression) interface{} {
 	return i.visit(exp)
 }
 
+func (i *postOrderIterator) Negate(exp *NegateExpression) interface{} {
+	if exp.Operand().Accept(i) == false {
+		return false
+	}
+	return i.visit(exp)
+}
+
+func (i *postOrderIterator) GreaterThan(exp *GreaterThanExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) GreaterOrEqual(exp *GreaterOrEqualExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) LessThan(exp *LessThanExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) LessOrEqual(exp *LessOrEqualExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) Contains(exp *ContainsExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) In(exp *InExpression) interface{} {
+	return i.all(exp, append([]Expression{exp.Left()}, exp.Values()...))
+}
+
+func (i *postOrderIterator) Between(exp *BetweenExpression) interface{} {
+	return i.all(exp, []Expression{exp.Operand(), exp.Lower(), exp.Upper()})
+}
+
+// all visits the given children of exp in order and then exp itself
+func (i *postOrderIterator) all(exp Expression, children []Expression) bool {
+	for _, child := range children {
+		if child.Accept(i) == false {
+			return false
+		}
+	}
+	return i.visit(exp)
+}
+
 func (i *postOrderIterator) binary(exp BinaryExpression) bool {
 	if exp.Left().Accept(i) == false {
 		return false
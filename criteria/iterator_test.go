@@ -39,5 +39,60 @@ func TestIterator(t *testing.T) {
 	if !reflect.DeepEqual(expected, visited) {
 		t.Errorf("Visited should be %v, but is %v", expected, visited)
 	}
+}
+
+func TestIteratorNegate(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
 
+	visited := []Expression{}
+	l := Field("a")
+	r := Literal(5)
+	eq := Equals(l, r)
+	expr := Negate(eq)
+	expected := []Expression{l, r, eq, expr}
+	IteratePostOrder(expr, func(expr Expression) bool {
+		visited = append(visited, expr)
+		return true
+	})
+	if !reflect.DeepEqual(expected, visited) {
+		t.Errorf("Visited should be %v, but is %v", expected, visited)
+	}
+	if eq.Parent() != expr {
+		t.Errorf("parent should be %v, but is %v", expr, eq.Parent())
+	}
+}
+
+func TestIteratorInAndBetween(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+
+	f := Field("a")
+	v1 := Literal(1)
+	v2 := Literal(2)
+	in := In(f, v1, v2)
+	visited := []Expression{}
+	IteratePostOrder(in, func(expr Expression) bool {
+		visited = append(visited, expr)
+		return true
+	})
+	expected := []Expression{f, v1, v2, in}
+	if !reflect.DeepEqual(expected, visited) {
+		t.Errorf("Visited should be %v, but is %v", expected, visited)
+	}
+
+	b := Field("b")
+	lower := Literal(1)
+	upper := Literal(5)
+	between := Between(b, lower, upper)
+	visited = []Expression{}
+	IteratePostOrder(between, func(expr Expression) bool {
+		visited = append(visited, expr)
+		return expr != lower
+	})
+	expected = []Expression{b, lower}
+	if !reflect.DeepEqual(expected, visited) {
+		t.Errorf("Visited should be %v, but is %v", expected, visited)
+	}
+	if upper.Parent() != between {
+		t.Errorf("parent should be %v, but is %v", between, upper.Parent())
+	}
 }
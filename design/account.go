@@ -174,6 +174,9 @@ var userDataAttributes = a.Type("UserDataAttributes", func() {
 	a.Attribute("contextInformation", a.HashOf(d.String, d.Any), "User context information of any type as a json", func() {
 		a.Example(map[string]interface{}{"last_visited_url": "https://a.openshift.io", "space": "3d6dab8d-f204-42e8-ab29-cdb1c93130ad"})
 	})
+	a.Attribute("notificationMode", d.String, "How the user is notified by email of the changes of what the user watches", func() {
+		a.Enum("immediate", "hourly", "daily", "none")
+	})
 })
 
 // updateidentityDataAttributes represents an identified user object attributes used for updating a user.
@@ -189,6 +192,9 @@ var updateUserDataAttributes = a.Type("UpdateIdentityDataAttributes", func() {
 	a.Attribute("contextInformation", a.HashOf(d.String, d.Any), "User context information of any type as a json", func() {
 		a.Example(map[string]interface{}{"last_visited_url": "https://a.openshift.io", "space": "3d6dab8d-f204-42e8-ab29-cdb1c93130ad"})
 	})
+	a.Attribute("notificationMode", d.String, "How the user is notified by email of the changes of what the user watches", func() {
+		a.Enum("immediate", "hourly", "daily", "none")
+	})
 })
 
 // identityData represents an identified identity object
@@ -0,0 +1,156 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var checklistItem = a.Type("ChecklistItem", func() {
+	a.Description(`JSONAPI store for the data of a checklist item. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("checklistitems")
+	})
+	a.Attribute("id", d.UUID, "ID of checklist item", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", checklistItemAttributes)
+	a.Attribute("relationships", checklistItemRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var checklistItemAttributes = a.Type("ChecklistItemAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a checklist item. See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("text", d.String, "The text of the checklist item", func() {
+		a.Example("Update the changelog")
+	})
+	a.Attribute("done", d.Boolean, "Whether the checklist item is done", func() {
+		a.Example(false)
+	})
+	a.Attribute("position", d.Integer, "The position of the item in the checklist, starting with 1 (read-only, use the reorder action to change it)", func() {
+		a.Example(1)
+	})
+	a.Attribute("created-at", d.DateTime, "When the checklist item was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the checklist item was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(23)
+	})
+})
+
+var checklistItemRelationships = a.Type("ChecklistItemRelations", func() {
+	a.Attribute("assignee", relationGeneric, "This defines the user assigned to the checklist item")
+	a.Attribute("workitem", relationGeneric, "This defines the work item of the checklist")
+})
+
+var checklistListMeta = a.Type("ChecklistListMeta", func() {
+	a.Attribute("done", d.Integer, "The number of items which are done")
+	a.Attribute("total", d.Integer, "The number of items")
+	a.Required("done", "total")
+})
+
+var checklistItemList = JSONList(
+	"ChecklistItem", "Holds the items of a checklist",
+	checklistItem,
+	genericLinks,
+	checklistListMeta)
+
+var checklistItemSingle = JSONSingle(
+	"ChecklistItem", "Holds a single checklist item",
+	checklistItem,
+	nil)
+
+// checklistReorderPayload holds the IDs of all items of a checklist in their new order
+var checklistReorderPayload = a.Type("ChecklistReorderPayload", func() {
+	a.Attribute("items", a.ArrayOf(d.UUID), "The IDs of all items of the checklist in their new order")
+	a.Required("items")
+})
+
+var _ = a.Resource("work_item_checklist", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("checklist"),
+		)
+		a.Description("List the items of the checklist of the given work item")
+		a.UseTrait("conditional")
+		a.Response(d.OK, checklistItemList)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("checklist"),
+		)
+		a.Description("Append an item to the checklist of the given work item")
+		a.Payload(checklistItemSingle)
+		a.Response(d.Created, func() {
+			a.Media(checklistItemSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("checklist/:itemID"),
+		)
+		a.Description("Update the checklist item with the given id, e.g. to mark it as done")
+		a.Params(func() {
+			a.Param("itemID", d.UUID, "Checklist item Identifier")
+		})
+		a.Payload(checklistItemSingle)
+		a.Response(d.OK, func() {
+			a.Media(checklistItemSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("checklist/:itemID"),
+		)
+		a.Description("Remove the checklist item with the given id from the checklist")
+		a.Params(func() {
+			a.Param("itemID", d.UUID, "Checklist item Identifier")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("reorder", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("checklist/reorder"),
+		)
+		a.Description("Put the items of the checklist of the given work item in the given order")
+		a.Payload(checklistReorderPayload)
+		a.Response(d.OK, checklistItemList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
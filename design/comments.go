@@ -16,6 +16,9 @@ var comment = a.Type("Comment", func() {
 	a.Attribute("attributes", commentAttributes)
 	a.Attribute("relationships", commentRelationships)
 	a.Attribute("links", genericLinks)
+	a.Attribute("meta", a.HashOf(d.String, d.Any), "The number of reactions to the comment by emoji", func() {
+		a.Example(map[string]interface{}{"reactions": map[string]interface{}{"+1": 2, "heart": 1}})
+	})
 	a.Required("type")
 })
 
@@ -25,9 +28,14 @@ var createComment = a.Type("CreateComment", func() {
 		a.Enum("comments")
 	})
 	a.Attribute("attributes", createCommentAttributes)
+	a.Attribute("relationships", createCommentRelationships)
 	a.Required("type", "attributes")
 })
 
+var createCommentRelationships = a.Type("CreateCommentRelations", func() {
+	a.Attribute("reply-to", relationGeneric, "This defines the comment of the same work item the new comment replies to")
+})
+
 var commentAttributes = a.Type("CommentAttributes", func() {
 	a.Description(`JSONAPI store for all the "attributes" of a comment. +See also see http://jsonapi.org/format/#document-resource-object-attributes`)
 	a.Attribute("created-at", d.DateTime, "When the comment was created", func() {
@@ -62,6 +70,9 @@ var createCommentAttributes = a.Type("CreateCommentAttributes", func() {
 var commentRelationships = a.Type("CommentRelations", func() {
 	a.Attribute("created-by", commentCreatedBy, "This defines the created by relation")
 	a.Attribute("parent", relationGeneric, "This defines the owning resource of the comment")
+	a.Attribute("mentions", relationGenericList, "This defines the users mentioned with @username in the comment body")
+	a.Attribute("reply-to", relationGeneric, "This defines the comment this comment replies to")
+	a.Attribute("replies", relationGenericList, "This defines the direct replies to this comment, when the comments are listed with their replies")
 })
 
 var commentCreatedBy = a.Type("CommentCreatedBy", func() {
@@ -161,6 +172,38 @@ var _ = a.Resource("comments", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+	a.Action("restore", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:commentId/restore"),
+		)
+		a.Description("Restore the deleted comment with given id.")
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+		})
+		a.Response(d.OK, commentSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("react", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:commentId/reactions"),
+		)
+		a.Description("Toggle the reaction of the authenticated user with the given emoji to the comment with given id.")
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+		})
+		a.Payload(reactionSingle)
+		a.Response(d.OK, reactionSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
 
 })
 
@@ -171,11 +214,15 @@ var _ = a.Resource("work_item_comments", func() {
 		a.Routing(
 			a.GET("comments"),
 		)
-		a.Description("List comments associated with the given work item")
+		a.Description(`List comments associated with the given work item. With include=replies,
+		only the comments which are not replies are listed and paged, and their replies are included.`)
 		a.Params(func() {
 			a.Param("page[offset]", d.String, `Paging start position is a string pointing to
 			the beginning of pagination.  The value starts from 0 onwards.`)
 			a.Param("page[limit]", d.Integer, `Paging size is the number of items in a page`)
+			a.Param("include", d.String, "Include the replies to the listed comments, nested in their replies relationship", func() {
+				a.Enum("replies")
+			})
 		})
 		a.UseTrait("conditional")
 		a.Response(d.OK, commentArray)
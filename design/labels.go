@@ -0,0 +1,142 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var label = a.Type("Label", func() {
+	a.Description(`JSONAPI store for the data of a label. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("labels")
+	})
+	a.Attribute("id", d.UUID, "ID of label", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", labelAttributes)
+	a.Attribute("relationships", labelRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var labelAttributes = a.Type("LabelAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a label. See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The label name", nameValidationFunction)
+	a.Attribute("color", d.String, "The color of the label as a hexadecimal RGB value", func() {
+		a.Pattern("^#[0-9a-fA-F]{6}$")
+		a.Example("#ff0000")
+	})
+	a.Attribute("description", d.String, "The description of the label", func() {
+		a.Example("Work items which block a release")
+	})
+	a.Attribute("created-at", d.DateTime, "When the label was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the label was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(23)
+	})
+})
+
+var labelRelationships = a.Type("LabelRelations", func() {
+	a.Attribute("space", relationGeneric, "This defines the owning space")
+	a.Attribute("workitems", relationGeneric, "This defines the work items which have the label attached, the meta holds their count")
+})
+
+var labelList = JSONList(
+	"Label", "Holds the list of labels",
+	label,
+	pagingLinks,
+	meta)
+
+var labelSingle = JSONSingle(
+	"Label", "Holds a single label",
+	label,
+	nil)
+
+var _ = a.Resource("label", func() {
+	a.Parent("space")
+	a.BasePath("/labels")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the labels of a space.")
+		a.UseTrait("conditional")
+		a.Response(d.OK, labelList)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:labelID"),
+		)
+		a.Description("Retrieve label with given id.")
+		a.Params(func() {
+			a.Param("labelID", d.UUID, "Label Identifier")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, labelSingle)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a label in the space.")
+		a.Payload(labelSingle)
+		a.Response(d.Created, "/labels/.*", func() {
+			a.Media(labelSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:labelID"),
+		)
+		a.Description("Update the label with the given id. A new name shows up on all work items which have the label attached.")
+		a.Params(func() {
+			a.Param("labelID", d.UUID, "Label Identifier")
+		})
+		a.Payload(labelSingle)
+		a.Response(d.OK, func() {
+			a.Media(labelSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:labelID"),
+		)
+		a.Description("Delete the label with the given id and detach it from all work items.")
+		a.Params(func() {
+			a.Param("labelID", d.UUID, "Label Identifier")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -0,0 +1,63 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var mention = a.Type("Mention", func() {
+	a.Description(`JSONAPI store for the data of a mention of a user. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("mentions")
+	})
+	a.Attribute("id", d.UUID, "ID of mention", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", mentionAttributes)
+	a.Attribute("relationships", mentionRelationships)
+	a.Required("type", "attributes")
+})
+
+var mentionAttributes = a.Type("MentionAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a mention. See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("kind", d.String, "Whether the user was mentioned in the description of a work item or in a comment", func() {
+		a.Enum("workitems", "comments")
+	})
+	a.Attribute("created-at", d.DateTime, "When the user was mentioned", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var mentionRelationships = a.Type("MentionRelations", func() {
+	a.Attribute("workitem", relationGeneric, "This defines the mentioning work item, or the parent of the mentioning comment")
+	a.Attribute("comment", relationGeneric, "This defines the mentioning comment, if any")
+	a.Attribute("space", relationGeneric, "This defines the space of the work item")
+})
+
+var mentionList = JSONList(
+	"Mention", "Holds the list of mentions",
+	mention,
+	pagingLinks,
+	meta)
+
+var _ = a.Resource("mention", func() {
+	a.BasePath("/user/mentions")
+
+	a.Action("list", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List where the authenticated user was mentioned with @username, latest first.")
+		a.Params(func() {
+			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[limit]", d.Integer, "Paging size")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, mentionList)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+})
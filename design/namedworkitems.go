@@ -0,0 +1,26 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = a.Resource("namedworkitems", func() {
+	a.BasePath("/namedworkitems")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:wiKey"),
+		)
+		a.Description("Retrieve the work item with the given key, i.e. the key of its space followed by its number within the space, e.g. PLAT-123.")
+		a.Params(func() {
+			a.Param("wiKey", d.String, "Key of the work item, e.g. PLAT-123")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, workItemSingle)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
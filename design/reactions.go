@@ -0,0 +1,35 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var reaction = a.Type("Reaction", func() {
+	a.Description(`JSONAPI store for the data of an emoji reaction to a work item or a comment. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("reactions")
+	})
+	a.Attribute("attributes", reactionAttributes)
+	a.Required("type", "attributes")
+})
+
+var reactionAttributes = a.Type("ReactionAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a reaction. See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("content", d.String, "The emoji", func() {
+		a.Enum("+1", "-1", "laugh", "confused", "heart", "hooray", "rocket", "eyes")
+		a.Example("+1")
+	})
+	a.Attribute("reacted", d.Boolean, "Whether the authenticated user has the reaction after the toggle (read-only)", func() {
+		a.Example(true)
+	})
+	a.Attribute("count", d.Integer, "The number of users who reacted with the emoji (read-only)", func() {
+		a.Example(3)
+	})
+	a.Required("content")
+})
+
+var reactionSingle = JSONSingle(
+	"Reaction", "Holds a single reaction",
+	reaction,
+	nil)
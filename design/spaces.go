@@ -61,6 +61,10 @@ var spaceAttributes = a.Type("SpaceAttributes", func() {
 	a.Attribute("description", d.String, "Description for the space", func() {
 		a.Example("This is the foobar collaboration space")
 	})
+	a.Attribute("key", d.String, "Short key of the space with which its work items can be addressed by their number, e.g. PLAT-123", func() {
+		a.Pattern("^[A-Z][A-Z0-9]{1,9}$")
+		a.Example("PLAT")
+	})
 	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
 		a.Example(23)
 	})
@@ -0,0 +1,123 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var subscription = a.Type("Subscription", func() {
+	a.Description(`JSONAPI store for the data of a subscription. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("subscriptions")
+	})
+	a.Attribute("id", d.UUID, "ID of subscription", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", subscriptionAttributes)
+	a.Attribute("relationships", subscriptionRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var subscriptionAttributes = a.Type("SubscriptionAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a subscription. See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("kind", d.String, "What is watched", func() {
+		a.Enum("workitems", "areas", "iterations", "spaces", "filters")
+	})
+	a.Attribute("target", d.String, "The ID of the watched work item, area, iteration or space, or the query of the watched filter", func() {
+		a.Example(`system.state = "open"`)
+	})
+	a.Attribute("reason", d.String, "Why the user watches, e.g. because of being the creator or an assignee of a work item (read-only)", func() {
+		a.Enum("manual", "creator", "assignee", "commenter")
+	})
+	a.Attribute("created-at", d.DateTime, "When the subscription was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the subscription was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var subscriptionRelationships = a.Type("SubscriptionRelations", func() {
+	a.Attribute("space", relationGeneric, "This defines the space of the watched entity, it is required to subscribe to a filter")
+})
+
+var subscriptionListMeta = a.Type("SubscriptionListMeta", func() {
+	a.Attribute("totalCount", d.Integer)
+	a.Required("totalCount")
+})
+
+var subscriptionList = JSONList(
+	"Subscription", "Holds the list of subscriptions",
+	subscription,
+	genericLinks,
+	subscriptionListMeta)
+
+var subscriptionSingle = JSONSingle(
+	"Subscription", "Holds a single subscription",
+	subscription,
+	nil)
+
+var _ = a.Resource("subscription", func() {
+	a.BasePath("/user/subscriptions")
+
+	a.Action("list", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the subscriptions of the authenticated user.")
+		a.UseTrait("conditional")
+		a.Response(d.OK, subscriptionList)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+	a.Action("show", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET("/:subscriptionID"),
+		)
+		a.Description("Retrieve the subscription of the authenticated user with the given id.")
+		a.Params(func() {
+			a.Param("subscriptionID", d.UUID, "Subscription Identifier")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, subscriptionSingle)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Subscribe the authenticated user to a work item, an area, an iteration, a space or a filter.")
+		a.Payload(subscriptionSingle)
+		a.Response(d.Created, "/user/subscriptions/.*", func() {
+			a.Media(subscriptionSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:subscriptionID"),
+		)
+		a.Description("Unsubscribe the authenticated user. The user is not subscribed automatically to the same target again.")
+		a.Params(func() {
+			a.Param("subscriptionID", d.UUID, "Subscription Identifier")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+})
@@ -0,0 +1,60 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = a.Resource("space_trash", func() {
+	a.Parent("space")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("trash"),
+		)
+		a.Description("List the deleted work items of the space, most recently deleted first.")
+		a.Params(func() {
+			a.Param("since", d.DateTime, "only list the work items deleted after the given point in time")
+			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[limit]", d.Integer, "Paging size")
+		})
+		a.Response(d.OK, workItemList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("comments", func() {
+		a.Routing(
+			a.GET("trash/comments"),
+		)
+		a.Description(`List the deleted comments of the work items of the space, most recently deleted first.
+		This includes the comments deleted along with their work item.`)
+		a.Params(func() {
+			a.Param("since", d.DateTime, "only list the comments deleted after the given point in time")
+			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[limit]", d.Integer, "Paging size")
+		})
+		a.Response(d.OK, commentArray)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("links", func() {
+		a.Routing(
+			a.GET("trash/links"),
+		)
+		a.Description(`List the deleted links whose source work item belongs to the space, most recently deleted first.
+		This includes the links deleted along with their work items.`)
+		a.Params(func() {
+			a.Param("since", d.DateTime, "only list the links deleted after the given point in time")
+			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[limit]", d.Integer, "Paging size")
+		})
+		a.Response(d.OK, workItemLinkList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
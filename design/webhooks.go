@@ -0,0 +1,206 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var webhook = a.Type("Webhook", func() {
+	a.Description(`JSONAPI store for the data of a webhook. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("webhooks")
+	})
+	a.Attribute("id", d.UUID, "ID of webhook", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", webhookAttributes)
+	a.Attribute("relationships", webhookRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var webhookAttributes = a.Type("WebhookAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a webhook. See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("url", d.String, "The URL to which the events are posted", func() {
+		a.Example("https://ci.example.com/hooks/planner")
+	})
+	a.Attribute("secret", d.String, `The key with which the deliveries are signed: the X-Hub-Signature-256 header holds "sha256=" followed by the hex encoded HMAC-SHA256 of the body. It is generated if not given on creation and is only returned on creation.`)
+	a.Attribute("events", a.ArrayOf(d.String), `The events to send: workitem.created, workitem.updated, workitem.state_changed, workitem.moved, workitem.deleted, link.created, link.deleted, comment.added, comment.updated, comment.deleted, user.mentioned, iteration.created, iteration.started, iteration.closed, space.updated or space.deleted`, func() {
+		a.Example([]string{"workitem.created", "workitem.state_changed", "comment.added", "iteration.closed"})
+	})
+	a.Attribute("created-at", d.DateTime, "When the webhook was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the webhook was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(23)
+	})
+})
+
+var webhookRelationships = a.Type("WebhookRelations", func() {
+	a.Attribute("space", relationGeneric, "This defines the owning space")
+	a.Attribute("deliveries", relationGeneric, "This links to the log of the deliveries")
+})
+
+var webhookList = JSONList(
+	"Webhook", "Holds the list of webhooks",
+	webhook,
+	genericLinks,
+	meta)
+
+var webhookSingle = JSONSingle(
+	"Webhook", "Holds a single webhook",
+	webhook,
+	nil)
+
+var webhookDelivery = a.Type("WebhookDelivery", func() {
+	a.Description(`JSONAPI store for the data of a delivery of an event to a webhook. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("webhookdeliveries")
+	})
+	a.Attribute("id", d.UUID, "ID of the delivery, sent in the X-Almighty-Delivery header", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", webhookDeliveryAttributes)
+	a.Required("type", "attributes")
+})
+
+var webhookDeliveryAttributes = a.Type("WebhookDeliveryAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a webhook delivery. See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("event-id", d.UUID, "ID of the delivered event")
+	a.Attribute("event-type", d.String, "Type of the delivered event, sent in the X-Almighty-Event header", func() {
+		a.Example("workitem.created")
+	})
+	a.Attribute("state", d.String, "Whether the delivery is pending, succeeded or failed for good", func() {
+		a.Enum("pending", "succeeded", "failed")
+	})
+	a.Attribute("attempts", d.Integer, "The number of attempts so far")
+	a.Attribute("status-code", d.Integer, "The HTTP status code of the response to the last attempt, or 0 if there was no response", func() {
+		a.Example(200)
+	})
+	a.Attribute("error", d.String, "Why the last attempt failed")
+	a.Attribute("next-attempt-at", d.DateTime, "When the delivery is attempted next, if it is pending")
+	a.Attribute("created-at", d.DateTime, "When the delivery was queued", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the delivery was last attempted", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var webhookDeliveryList = JSONList(
+	"WebhookDelivery", "Holds the list of the deliveries of a webhook",
+	webhookDelivery,
+	pagingLinks,
+	meta)
+
+var _ = a.Resource("webhook", func() {
+	a.Parent("space")
+	a.BasePath("/webhooks")
+
+	a.Action("list", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the webhooks of a space. Only the space owner can see them.")
+		a.UseTrait("conditional")
+		a.Response(d.OK, webhookList)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("show", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET("/:webhookID"),
+		)
+		a.Description("Retrieve the webhook with the given id. Only the space owner can see it.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "Webhook Identifier")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, webhookSingle)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Register a webhook for events of the space.")
+		a.Payload(webhookSingle)
+		a.Response(d.Created, "/webhooks/.*", func() {
+			a.Media(webhookSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:webhookID"),
+		)
+		a.Description("Update the URL, the secret or the events of the webhook with the given id.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "Webhook Identifier")
+		})
+		a.Payload(webhookSingle)
+		a.Response(d.OK, func() {
+			a.Media(webhookSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:webhookID"),
+		)
+		a.Description("Delete the webhook with the given id. Its pending deliveries are not sent anymore.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "Webhook Identifier")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("deliveries", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET("/:webhookID/deliveries"),
+		)
+		a.Description("List the deliveries of the webhook with the given id, latest first, with the status codes of the responses.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "Webhook Identifier")
+			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[limit]", d.Integer, "Paging size")
+		})
+		a.Response(d.OK, webhookDeliveryList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -128,6 +128,7 @@ var _ = a.Resource("work_item_link", func() {
 	a.Action("list", listWorkItemLinks)
 	a.Action("create", createWorkItemLink)
 	a.Action("delete", deleteWorkItemLink)
+	a.Action("restore", restoreWorkItemLink)
 	a.Action("update", updateWorkItemLink)
 })
 
@@ -210,6 +211,24 @@ func deleteWorkItemLink() {
 	a.Response(d.Unauthorized, JSONAPIErrors)
 }
 
+func restoreWorkItemLink() {
+	a.Description("Restore the deleted work item link with given id.")
+	a.Security("jwt")
+	a.Routing(
+		a.POST("/:linkId/restore"),
+	)
+	a.Params(func() {
+		a.Param("linkId", d.UUID, "ID of the work item link to be restored")
+	})
+	a.Response(d.OK, func() {
+		a.Media(workItemLink)
+	})
+	a.Response(d.BadRequest, JSONAPIErrors)
+	a.Response(d.InternalServerError, JSONAPIErrors)
+	a.Response(d.NotFound, JSONAPIErrors)
+	a.Response(d.Unauthorized, JSONAPIErrors)
+}
+
 func updateWorkItemLink() {
 	a.Description("Update the given work item link with given id.")
 	a.Security("jwt")
@@ -0,0 +1,104 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var workItemRevision = a.Type("WorkItemRevision", func() {
+	a.Description(`JSONAPI store for the data of a work item revision. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitemrevisions")
+	})
+	a.Attribute("id", d.UUID, "ID of the revision", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workItemRevisionAttributes)
+	a.Attribute("relationships", workItemRevisionRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var workItemRevisionAttributes = a.Type("WorkItemRevisionAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a work item revision. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("revision-time", d.DateTime, "When the work item was modified", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("revision-type", d.String, "The kind of modification", func() {
+		a.Enum("create", "update", "delete", "restore")
+	})
+	a.Attribute("version", d.Integer, "The version of the work item that was modified", func() {
+		a.Example(3)
+	})
+	a.Attribute("changes", a.ArrayOf(workItemFieldChange), "The fields that changed compared to the previous revision")
+	a.Required("revision-time", "revision-type", "version", "changes")
+})
+
+var workItemFieldChange = a.Type("WorkItemFieldChange", func() {
+	a.Description(`The change of a single field of a work item`)
+	a.Attribute("field", d.String, "The name of the field", func() {
+		a.Example("system.state")
+	})
+	a.Attribute("old-value", d.Any, "The value of the field in the previous revision", func() {
+		a.Example("new")
+	})
+	a.Attribute("new-value", d.Any, "The value of the field in this revision", func() {
+		a.Example("open")
+	})
+	a.Attribute("text-diff", a.ArrayOf(textDiffChunk), "The differences between the old and the new text of a markup field")
+	a.Required("field")
+})
+
+var textDiffChunk = a.Type("TextDiffChunk", func() {
+	a.Description(`A chunk of the difference between two texts`)
+	a.Attribute("operation", d.String, "Whether the text was inserted, deleted or left unchanged", func() {
+		a.Enum("insert", "delete", "equal")
+	})
+	a.Attribute("text", d.String, "The text of the chunk", func() {
+		a.Example("the quick brown fox")
+	})
+	a.Required("operation", "text")
+})
+
+var workItemRevisionRelationships = a.Type("WorkItemRevisionRelationships", func() {
+	a.Attribute("modifier", revisionModifier, "This defines the identity which modified the work item")
+	a.Attribute("workitem", relationGeneric, "This defines the work item of the revision")
+})
+
+var revisionModifier = a.Type("RevisionModifier", func() {
+	a.Attribute("data", identityRelationData)
+	a.Attribute("links", genericLinks)
+	a.Required("data")
+})
+
+var workItemRevisionListMeta = a.Type("WorkItemRevisionListMeta", func() {
+	a.Attribute("totalCount", d.Integer)
+	a.Required("totalCount")
+})
+
+var workItemRevisionList = JSONList(
+	"WorkItemRevision", "Holds the paginated response to a work item revision list request",
+	workItemRevision,
+	pagingLinks,
+	workItemRevisionListMeta,
+)
+
+var _ = a.Resource("work_item_revisions", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("revisions"),
+		)
+		a.Description("List the revisions of the given work item along with the changes made in each revision")
+		a.Params(func() {
+			a.Param("page[offset]", d.String, `Paging start position is a string pointing to
+			the beginning of pagination.  The value starts from 0 onwards.`)
+			a.Param("page[limit]", d.Integer, `Paging size is the number of items in a page`)
+		})
+		a.Response(d.OK, workItemRevisionList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
@@ -0,0 +1,82 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+// workflowTransition allows work items to move from one state to another
+var workflowTransition = a.Type("WorkflowTransition", func() {
+	a.Description("An allowed change of the state of a work item")
+	a.Attribute("from", d.String, "The state the work item is in", func() {
+		a.Example("open")
+	})
+	a.Attribute("to", d.String, "The state the work item can move to", func() {
+		a.Example("in progress")
+	})
+	a.Attribute("requiredFields", a.ArrayOf(d.String), "Fields which must have a value for the work item to move to the new state", func() {
+		a.Example([]string{"system.assignees"})
+	})
+	a.Required("from", "to")
+})
+
+// workflowDefinition defines the states of a work item type and the changes
+// allowed between them
+var workflowDefinition = a.Type("WorkflowDefinition", func() {
+	a.Description(`The states of the work items of a type and the allowed changes between
+	them. Work items of types without a workflow can move freely between all values of the
+	system.state field.`)
+	a.Attribute("states", a.ArrayOf(d.String), "The states in the order in which they should be shown", func() {
+		a.Example([]string{"new", "open", "in progress", "resolved", "closed"})
+	})
+	a.Attribute("transitions", a.ArrayOf(workflowTransition), "The allowed changes of the state")
+	a.Required("states", "transitions")
+})
+
+var workflowAttributes = a.Type("WorkflowAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a workflow. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("states", a.ArrayOf(d.String), "The states in the order in which they should be shown", func() {
+		a.Example([]string{"new", "open", "in progress", "resolved", "closed"})
+	})
+	a.Attribute("transitions", a.ArrayOf(workflowTransition), "The allowed changes of the state")
+	a.Attribute("defined", d.Boolean, "Whether the work item type defines the workflow or all changes between the states are allowed")
+	a.Required("states", "transitions", "defined")
+})
+
+var workflowRelationships = a.Type("WorkflowRelationships", func() {
+	a.Attribute("workitemtype", relationGeneric, "This defines the work item type of the workflow")
+})
+
+var workflowData = a.Type("WorkflowData", func() {
+	a.Attribute("type", d.String, func() {
+		a.Enum("workflows")
+	})
+	a.Attribute("id", d.UUID, "ID of the workflow, which is the ID of its work item type", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workflowAttributes)
+	a.Attribute("relationships", workflowRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+// workflowSingle is the media type for the workflow of a work item type
+var workflowSingle = JSONSingle(
+	"Workflow", "The workflow of a work item type",
+	workflowData,
+	nil)
+
+var _ = a.Resource("workitemtype_workflow", func() {
+	a.Parent("workitemtype")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("workflow"),
+		)
+		a.Description("Retrieve the workflow of the given work item type")
+		a.Response(d.OK, workflowSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
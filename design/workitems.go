@@ -32,6 +32,9 @@ var workItem = a.Type("WorkItem", func() {
 	})
 	a.Attribute("relationships", workItemRelationships)
 	a.Attribute("links", genericLinksForWorkItem)
+	a.Attribute("meta", a.HashOf(d.String, d.Any), "The number of reactions to the work item by emoji", func() {
+		a.Example(map[string]interface{}{"reactions": map[string]interface{}{"+1": 2, "heart": 1}})
+	})
 	a.Required("type", "attributes")
 })
 
@@ -44,7 +47,10 @@ var workItemRelationships = a.Type("WorkItemRelationships", func() {
 	a.Attribute("iteration", relationGeneric, "This defines the iteration this work item belong to")
 	a.Attribute("area", relationGeneric, "This defines the area this work item belongs to")
 	a.Attribute("children", relationGeneric, "This defines the children of this work item")
+	a.Attribute("checklist", relationGeneric, "This defines the checklist of this work item, with the number of done and total items in its meta")
 	a.Attribute("space", relationSpaces, "This defines the owning space of this work item.")
+	a.Attribute("references", a.HashOf(d.String, relationGenericList), "The work items and labels referenced by fields of kind workitem or label, or lists of them, by field name")
+	a.Attribute("mentions", relationGenericList, "This defines the users mentioned with @username in the description of the Work Item")
 })
 
 // relationBaseType is top level block for WorkItemType relationship
@@ -105,6 +111,78 @@ var workItemReorder = Reorder(
 	workItem,
 	position)
 
+// workItemBulkItem identifies a work item of a bulk request
+var workItemBulkItem = a.Type("WorkItemBulkItem", func() {
+	a.Attribute("id", d.String, "ID of the work item", func() {
+		a.Example("42")
+	})
+	a.Attribute("version", d.Integer, "Version of the work item the change is based on. A different stored version is reported as a conflict.", func() {
+		a.Example(3)
+	})
+	a.Required("id")
+})
+
+// workItemBulkPayload selects a set of work items and describes the
+// operation to apply to all of them
+var workItemBulkPayload = a.Type("WorkItemBulkPayload", func() {
+	a.Attribute("operation", d.String, `"update" applies the attributes to the work items and "move" changes their iteration and/or area`, func() {
+		a.Enum("update", "move")
+	})
+	a.Attribute("data", a.ArrayOf(workItemBulkItem), "The work items to change, at most 100")
+	a.Attribute("filter", d.String, "A query language expression selecting the work items to change, instead of data. It must not select more than 100 work items.")
+	a.Attribute("attributes", a.HashOf(d.String, d.Any), "The field values to set on all work items. Supported fields are system.state, system.iteration, system.area, system.assignees and system.labels.", func() {
+		a.Example(map[string]interface{}{"system.state": "closed"})
+	})
+	a.Required("operation")
+})
+
+// workItemBulk is the media type for the result of a bulk request
+var workItemBulk = a.MediaType("application/vnd.workitembulk+json", func() {
+	a.UseTrait("jsonapi-media-type")
+	a.TypeName("WorkItemBulk")
+	a.Description("Holds the work items changed by a bulk request")
+	a.Attribute("data", a.ArrayOf(workItem))
+	a.Attribute("meta", meta)
+	a.View("default", func() {
+		a.Attribute("data")
+		a.Attribute("meta")
+		a.Required("data", "meta")
+	})
+})
+
+// workItemCopyPayload describes where to copy a work item to and what to copy along with it
+var workItemCopyPayload = a.Type("WorkItemCopyPayload", func() {
+	a.Attribute("space", d.UUID, "ID of the space to copy the work item to. Defaults to the space of the work item.")
+	a.Attribute("workitemtype", d.UUID, "ID of the type of the copy. Defaults to the type of the work item. Fields which the type does not define are not copied.")
+	a.Attribute("children", d.Boolean, "Whether to copy the children of the work item as well, recursively")
+	a.Attribute("links", d.Boolean, "Whether to recreate the links of the copied work items which are not parent/child links")
+	a.Attribute("comments", d.Boolean, "Whether to copy the comments of the copied work items")
+})
+
+// workItemCopy is the media type for the result of a copy request
+var workItemCopy = a.MediaType("application/vnd.workitemcopy+json", func() {
+	a.UseTrait("jsonapi-media-type")
+	a.TypeName("WorkItemCopy")
+	a.Description("Holds the copy of a work item and the IDs of all copied work items")
+	a.Attribute("data", workItem)
+	a.Attribute("mapping", a.HashOf(d.String, d.String), "The IDs of the copies by the IDs of the copied work items")
+	a.View("default", func() {
+		a.Attribute("data")
+		a.Attribute("mapping")
+		a.Required("data", "mapping")
+	})
+})
+
+// workItemMovePayload describes where to move a work item to
+var workItemMovePayload = a.Type("WorkItemMovePayload", func() {
+	a.Attribute("space", d.UUID, "ID of the space to move the work item to")
+	a.Attribute("version", d.Integer, "Version of the work item which the client has seen")
+	a.Attribute("workitemtype", d.UUID, "ID of the type of the work item in the target space. Defaults to the type of the work item if it is available in the target space, or else to the type of the target space with the same name.")
+	a.Attribute("iteration", d.UUID, "ID of the iteration of the target space. Defaults to the iteration with the same name, or else to the root iteration.")
+	a.Attribute("area", d.UUID, "ID of the area of the target space. Defaults to the area with the same name, or else to the root area.")
+	a.Required("space", "version")
+})
+
 // new version of "list" for migration
 var _ = a.Resource("workitem", func() {
 	a.Parent("space")
@@ -116,6 +194,7 @@ var _ = a.Resource("workitem", func() {
 		a.Description("Retrieve work item with given id.")
 		a.Params(func() {
 			a.Param("wiId", d.String, "wiId")
+			a.Param("asOf", d.DateTime, "retrieve the work item as it was at the given point in time")
 		})
 		a.UseTrait("conditional")
 		a.Response(d.OK, workItemSingle)
@@ -138,7 +217,16 @@ var _ = a.Resource("workitem", func() {
 			a.Param("filter[workitemtype]", d.UUID, "ID of work item type to filter work items by")
 			a.Param("filter[area]", d.String, "AreaID to filter work items")
 			a.Param("filter[workitemstate]", d.String, "work item state to filter work items by")
+			a.Param("filter[label]", d.UUID, "ID of a label to filter work items by")
+			a.Param("filter[checklist]", d.String, "'open' to list the work items with checklist items which are not done", func() {
+				a.Enum("open")
+			})
 			a.Param("filter[parentexists]", d.Boolean, "if false list work items without any parent")
+			a.Param("asOf", d.DateTime, "list the work items as they were at the given point in time")
+			a.Param("sort", d.String, `the field to sort the work items by, prefixed with - for a descending order, e.g. -system.votes
+to list the work items with the most +1 reactions first. Not supported together with asOf.`, func() {
+				a.Enum("system.created_at", "-system.created_at", "system.updated_at", "-system.updated_at", "system.votes", "-system.votes")
+			})
 		})
 		a.UseTrait("conditional")
 		a.Response(d.OK, workItemList)
@@ -195,6 +283,40 @@ var _ = a.Resource("workitem", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+	a.Action("restore", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiId/restore"),
+		)
+		a.Description(`Restore the deleted work item with given id. Note that work items cannot be deleted
+		through the API for now, see https://github.com/fabric8io/almighty-core/issues/1036.`)
+		a.Params(func() {
+			a.Param("wiId", d.String, "wiId")
+			a.Param("related", d.Boolean, "if true also restore the links and comments which were deleted along with the work item")
+		})
+		a.Response(d.OK, workItemSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("react", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiId/reactions"),
+		)
+		a.Description("Toggle the reaction of the authenticated user with the given emoji to the work item with given id, e.g. a +1 to vote for it.")
+		a.Params(func() {
+			a.Param("wiId", d.String, "wiId")
+		})
+		a.Payload(reactionSingle)
+		a.Response(d.OK, reactionSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
 	a.Action("update", func() {
 		a.Security("jwt")
 		a.Routing(
@@ -232,6 +354,62 @@ var _ = a.Resource("workitem", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+	a.Action("copy", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiId/copy"),
+		)
+		a.Description("copy the work item, optionally along with its children, links and comments")
+		a.Params(func() {
+			a.Param("wiId", d.String, "ID of the work item to copy")
+		})
+		a.Payload(workItemCopyPayload)
+		a.Response(d.Created, "/workitems/.*", func() {
+			a.Media(workItemCopy)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("move", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiId/move"),
+		)
+		a.Description("move the work item to another space")
+		a.Params(func() {
+			a.Param("wiId", d.String, "ID of the work item to move")
+		})
+		a.Payload(workItemMovePayload)
+		a.Response(d.OK, workItemSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+	})
+	a.Action("bulk", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/bulk"),
+		)
+		a.Description(`update, move or delete a set of work items in a single transaction.
+A revision is stored for every work item. If some of the work items have been changed in the
+meantime nothing is changed and a conflict error is returned for each of them.`)
+		a.Payload(workItemBulkPayload)
+		a.Response(d.OK, func() {
+			a.Media(workItemBulk)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 })
 
 // new version of "list" for migration
@@ -313,6 +491,7 @@ var _ = a.Resource("planner_backlog", func() {
 			a.Param("filter[assignee]", d.String, "Work Items assigned to the given user")
 			a.Param("filter[workitemtype]", d.UUID, "ID of work item type to filter work items by")
 			a.Param("filter[area]", d.String, "AreaID to filter work items")
+			a.Param("filter[label]", d.UUID, "ID of a label to filter work items by")
 		})
 		a.UseTrait("conditional")
 		a.Response(d.OK, workItemList)
@@ -0,0 +1,183 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var workItemTemplate = a.Type("WorkItemTemplate", func() {
+	a.Description(`JSONAPI store for the data of a work item template. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitemtemplates")
+	})
+	a.Attribute("id", d.UUID, "ID of work item template", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workItemTemplateAttributes)
+	a.Attribute("relationships", workItemTemplateRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var workItemTemplateAttributes = a.Type("WorkItemTemplateAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a work item template. See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The template name", nameValidationFunction)
+	a.Attribute("title", d.String, "The title of the work item to create. {title} is replaced with the title given when creating the work item, {date} with the current date.", func() {
+		a.Example("Release {title}")
+	})
+	a.Attribute("description", d.String, "The description of the work item to create")
+	a.Attribute("markup", d.String, "The markup of the description", func() {
+		a.Example("Markdown")
+	})
+	a.Attribute("children", a.ArrayOf(workItemTemplateChild), "The work items to create below the work item, linked with parent/child links")
+	a.Attribute("created-at", d.DateTime, "When the template was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the template was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(23)
+	})
+})
+
+var workItemTemplateChild = a.Type("WorkItemTemplateChild", func() {
+	a.Description("A work item to create below the work item created from a template")
+	a.Attribute("workitemtype", d.UUID, "ID of the type of the work item")
+	a.Attribute("title", d.String, "The title of the work item, with the same placeholders as the title of the template", func() {
+		a.Example("Write release notes for {title}")
+	})
+	a.Attribute("description", d.String, "The description of the work item")
+	a.Attribute("markup", d.String, "The markup of the description")
+	a.Required("workitemtype", "title")
+})
+
+var workItemTemplateRelationships = a.Type("WorkItemTemplateRelations", func() {
+	a.Attribute("space", relationGeneric, "This defines the owning space")
+	a.Attribute("baseType", relationBaseType, "This defines the type of the work item to create")
+	a.Attribute("assignees", relationGenericList, "This defines the users assigned to the work item to create")
+	a.Attribute("area", relationGeneric, "This defines the area of the work item to create")
+	a.Attribute("labels", relationGenericList, "This defines the labels of the work item to create")
+})
+
+var workItemTemplateList = JSONList(
+	"WorkItemTemplate", "Holds the list of work item templates",
+	workItemTemplate,
+	pagingLinks,
+	meta)
+
+var workItemTemplateSingle = JSONSingle(
+	"WorkItemTemplate", "Holds a single work item template",
+	workItemTemplate,
+	nil)
+
+// workItemTemplateInstantiatePayload holds the values of the placeholders of a template
+var workItemTemplateInstantiatePayload = a.Type("WorkItemTemplateInstantiatePayload", func() {
+	a.Attribute("title", d.String, "The value of the {title} placeholder", func() {
+		a.Example("1.2")
+	})
+})
+
+var _ = a.Resource("workitemtemplate", func() {
+	a.Parent("space")
+	a.BasePath("/workitemtemplates")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the work item templates of a space.")
+		a.UseTrait("conditional")
+		a.Response(d.OK, workItemTemplateList)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:templateID"),
+		)
+		a.Description("Retrieve work item template with given id.")
+		a.Params(func() {
+			a.Param("templateID", d.UUID, "Template Identifier")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, workItemTemplateSingle)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a work item template in the space.")
+		a.Payload(workItemTemplateSingle)
+		a.Response(d.Created, "/workitemtemplates/.*", func() {
+			a.Media(workItemTemplateSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:templateID"),
+		)
+		a.Description("Update the work item template with the given id.")
+		a.Params(func() {
+			a.Param("templateID", d.UUID, "Template Identifier")
+		})
+		a.Payload(workItemTemplateSingle)
+		a.Response(d.OK, func() {
+			a.Media(workItemTemplateSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:templateID"),
+		)
+		a.Description("Delete the work item template with the given id. The work items created from it are left as they are.")
+		a.Params(func() {
+			a.Param("templateID", d.UUID, "Template Identifier")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("instantiate", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:templateID/workitems"),
+		)
+		a.Description("Create a work item from the work item template with the given id, along with its children.")
+		a.Params(func() {
+			a.Param("templateID", d.UUID, "Template Identifier")
+		})
+		a.Payload(workItemTemplateInstantiatePayload)
+		a.Response(d.Created, "/workitems/.*", func() {
+			a.Media(workItemSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
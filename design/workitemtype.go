@@ -8,7 +8,7 @@ import (
 // fieldType is the datatype of a single field in a work item type
 var fieldType = a.Type("fieldType", func() {
 	a.Description("A fieldType describes the values a particular field can hold")
-	a.Attribute("kind", d.String, "The constant indicating the kind of type, for example 'string' or 'enum' or 'instant'")
+	a.Attribute("kind", d.String, "The constant indicating the kind of type, for example 'string' or 'enum' or 'instant'. Values of kind 'date' are calendar days in the format YYYY-MM-DD")
 	a.Attribute("componentType", d.String, "The kind of type of the individual elements for a list type. Required for list types. Must be a simple type, not  enum or list")
 	a.Attribute("baseType", d.String, "The kind of type of the enumeration values for an enum type. Required for enum types. Must be a simple type, not  enum or list")
 	a.Attribute("values", a.ArrayOf(d.Any), "The possible values for an enum type. The values must be of a type convertible to the base type")
@@ -16,6 +16,45 @@ var fieldType = a.Type("fieldType", func() {
 	a.Required("kind")
 })
 
+// fieldConstraints restricts the values of a field beyond its type
+var fieldConstraints = a.Type("fieldConstraints", func() {
+	a.Description("Optional restrictions of the values a field can hold, which are checked when a work item is saved")
+	a.Attribute("minimum", d.Number, "The minimum value of an integer, duration or float field", func() {
+		a.Example(0)
+	})
+	a.Attribute("maximum", d.Number, "The maximum value of an integer, duration or float field", func() {
+		a.Example(100)
+	})
+	a.Attribute("minLength", d.Integer, "The minimum number of characters of a string or markup field", func() {
+		a.Example(1)
+	})
+	a.Attribute("maxLength", d.Integer, "The maximum number of characters of a string or markup field", func() {
+		a.Example(255)
+	})
+	a.Attribute("pattern", d.String, "A regular expression that the value of a string field must match", func() {
+		a.Example("^[A-Z]+-[0-9]+$")
+	})
+	a.Attribute("urlSchemes", a.ArrayOf(d.String), "The allowed schemes of an url field", func() {
+		a.Example([]string{"http", "https"})
+	})
+	a.Attribute("maxItems", d.Integer, "The maximum number of elements of a list field", func() {
+		a.Example(10)
+	})
+})
+
+// fieldDefault defines the value of a field for new work items
+var fieldDefault = a.Type("fieldDefault", func() {
+	a.Description("The value a field gets when a work item is created without a value for it")
+	a.Attribute("kind", d.String, `How the default value is determined: a literal value, the creating user,
+	the creation time, the root area or root iteration of the space, or the running iteration of the space`, func() {
+		a.Enum("literal", "current-user", "now", "root-area", "root-iteration", "current-iteration")
+	})
+	a.Attribute("value", d.Any, "The default value of kind 'literal'", func() {
+		a.Example("new")
+	})
+	a.Required("kind")
+})
+
 // fieldDefinition defines the possible values for a field in a work item type
 var fieldDefinition = a.Type("fieldDefinition", func() {
 	a.Description("A fieldDefinition aggregates a fieldType and additional field metadata")
@@ -29,6 +68,8 @@ var fieldDefinition = a.Type("fieldDefinition", func() {
 		a.Example("The iteration field tells to which iteration a work item belongs.")
 		a.MinLength(1)
 	})
+	a.Attribute("constraints", fieldConstraints, "Restrictions of the values the field can hold")
+	a.Attribute("default", fieldDefault, "The value of the field for new work items that are created without a value for it")
 	a.Required("required", "type", "label", "description")
 })
 
@@ -53,6 +94,9 @@ var workItemTypeAttributes = a.Type("WorkItemTypeAttributes", func() {
 		a.MinLength(1)
 	})
 
+	a.Attribute("workflow", workflowDefinition, `The optional workflow of the system.state field, which can be set when updating the work
+	item type. It is kept when omitted and removed when given without states.`)
+
 	// TODO: Maybe this needs to be abandoned at some point
 	a.Attribute("extendedTypeName", d.UUID, "If newly created type extends any existing type (This is never present in any response and is only optional when creating.)")
 
@@ -133,7 +177,10 @@ var _ = a.Resource("workitemtype", func() {
 		a.Routing(
 			a.POST(""),
 		)
-		a.Description("Create work item type.")
+		a.Description(`Create work item type. Only the owner of a space can create work item types
+		in it. A work item type can extend a type of its own space or of the system space, in which case
+		it inherits the fields and the workflow of that type. Inherited fields can be redefined, e.g. with
+		another label, as long as their type and whether they are required stay the same.`)
 		a.Payload(workItemTypeSingle)
 		a.Response(d.Created, "/workitemtypes/.*", func() {
 			a.Media(workItemTypeSingle)
@@ -141,6 +188,29 @@ var _ = a.Resource("workitemtype", func() {
 		a.Response(d.BadRequest, JSONAPIErrors)
 		a.Response(d.InternalServerError, JSONAPIErrors)
 		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:witID"),
+		)
+		a.Description(`Update the work item type with the given ID. Fields can be added as long
+		as they are optional, and labels and descriptions of fields can be changed. Fields cannot be
+		removed. Changing the type of a field requires the convert parameter, in which case the values
+		of that field are converted for all work items of the type.`)
+		a.Params(func() {
+			a.Param("witID", d.UUID, "ID of the work item type")
+			a.Param("convert", d.Boolean, "Convert the values of fields whose type is changed")
+		})
+		a.Payload(workItemTypeSingle)
+		a.Response(d.OK, workItemTypeSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
 	})
 	a.Action("list", func() {
 		a.Routing(
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"fmt"
+	"strings"
 
 	errs "github.com/pkg/errors"
 )
@@ -192,3 +193,45 @@ func IsNotFoundError(err error) (bool, error) {
 	}
 	return true, e
 }
+
+// StateTransitionError means that the state of a work item cannot change
+// from one state to the other, either because the workflow of the work item
+// type does not allow it at all or because a guard of the transition is not
+// satisfied
+type StateTransitionError struct {
+	From string
+	To   string
+	// AllowedStates lists the states the work item can move to from its
+	// current state
+	AllowedStates []string
+	reason        string
+}
+
+// Error implements the error interface
+func (err StateTransitionError) Error() string {
+	if err.reason != "" {
+		return fmt.Sprintf("Cannot change state from '%s' to '%s': %s", err.From, err.To, err.reason)
+	}
+	return fmt.Sprintf("Cannot change state from '%s' to '%s' (allowed: '%s')", err.From, err.To, strings.Join(err.AllowedStates, "', '"))
+}
+
+// Because sets the optional reason on the StateTransitionError
+func (err StateTransitionError) Because(reason string) StateTransitionError {
+	err.reason = reason
+	return err
+}
+
+// NewStateTransitionError returns the custom defined error of type StateTransitionError.
+func NewStateTransitionError(from, to string, allowedStates []string) StateTransitionError {
+	return StateTransitionError{From: from, To: to, AllowedStates: allowedStates}
+}
+
+// IsStateTransitionError returns true if the cause of the given error can be
+// converted to an StateTransitionError, which is returned as the second result.
+func IsStateTransitionError(err error) (bool, error) {
+	e, ok := errs.Cause(err).(StateTransitionError)
+	if !ok {
+		return false, nil
+	}
+	return true, e
+}
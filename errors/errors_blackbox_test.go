@@ -68,6 +68,16 @@ func TestNewForbiddenError(t *testing.T) {
 	assert.Equal(t, msg, err.Error())
 }
 
+func TestNewStateTransitionError(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	err := errors.NewStateTransitionError("new", "closed", []string{"open", "in progress"})
+	assert.Equal(t, "Cannot change state from 'new' to 'closed' (allowed: 'open', 'in progress')", err.Error())
+	assert.Equal(t, []string{"open", "in progress"}, err.AllowedStates)
+	err = errors.NewStateTransitionError("open", "in progress", []string{"in progress"}).Because("field 'system.assignees' is required")
+	assert.Equal(t, "Cannot change state from 'open' to 'in progress': field 'system.assignees' is required", err.Error())
+}
+
 func TestIsXYError(t *testing.T) {
 	resource.Require(t, resource.UnitTest)
 	t.Parallel()
@@ -98,6 +108,9 @@ func TestIsXYError(t *testing.T) {
 		{"IsVersionConflictError - is a VersionConflictError", errors.NewVersionConflictError("some message"), errors.IsVersionConflictError, true},
 		{"IsVersionConflictError - is a wrapped VersionConflictError", errs.Wrap(errs.Wrap(errors.NewVersionConflictError("some message"), "msg1"), "msg2"), errors.IsVersionConflictError, true},
 		{"IsVersionConflictError - is not a VersionConflictError", errors.NewInternalError(errs.New("some message")), errors.IsVersionConflictError, false},
+		{"IsStateTransitionError - is a StateTransitionError", errors.NewStateTransitionError("new", "closed", []string{"open"}), errors.IsStateTransitionError, true},
+		{"IsStateTransitionError - is a wrapped StateTransitionError", errs.Wrap(errs.Wrap(errors.NewStateTransitionError("new", "closed", []string{"open"}), "msg1"), "msg2"), errors.IsStateTransitionError, true},
+		{"IsStateTransitionError - is not a StateTransitionError", errors.NewBadParameterError("param", "actual"), errors.IsStateTransitionError, false},
 	}
 	for _, tc := range testCases {
 		// Note that we need to capture the range variable to ensure that tc
@@ -0,0 +1,91 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/fabric8io/almighty-core/log"
+
+	errs "github.com/pkg/errors"
+)
+
+// Handler handles a published event. An event is published again later to
+// all of its handlers if one of them returns an error, so every handler must
+// be idempotent: handling the same event again must not repeat its effects,
+// e.g. by storing what it did under a unique key which includes the event ID.
+type Handler func(ctx context.Context, e Event) error
+
+type subscriber struct {
+	handler Handler
+	types   map[string]bool
+}
+
+// Bus dispatches the published events to the handlers which subscribed to them
+type Bus struct {
+	lock        sync.RWMutex
+	subscribers []subscriber
+}
+
+// DefaultBus is the bus on which the events committed by the application are published
+var DefaultBus = NewBus()
+
+// NewBus creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers the given handler for the events of the given types,
+// or for all events if no type is given
+func (b *Bus) Subscribe(h Handler, types ...string) {
+	s := subscriber{handler: h}
+	if len(types) > 0 {
+		s.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.subscribers = append(b.subscribers, s)
+}
+
+// Publish hands the given event to the subscribed handlers. A failing or
+// panicking handler is logged and does not keep the others from handling the
+// event. Returns the error of the first handler which failed, if any.
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	b.lock.RLock()
+	subscribers := b.subscribers
+	b.lock.RUnlock()
+	var failure error
+	for _, s := range subscribers {
+		if s.types != nil && !s.types[e.Type] {
+			continue
+		}
+		if err := handle(ctx, s.handler, e); err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"event_id": e.ID,
+				"type":     e.Type,
+				"err":      err,
+			}, "event handler failed: %s", err.Error())
+			if failure == nil {
+				failure = err
+			}
+		}
+	}
+	return failure
+}
+
+func handle(ctx context.Context, h Handler, e Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errs.Errorf("event handler panicked: %s", fmt.Sprint(r))
+		}
+	}()
+	return h(ctx, e)
+}
+
+// Subscribe registers the given handler on the default bus
+func Subscribe(h Handler, types ...string) {
+	DefaultBus.Subscribe(h, types...)
+}
@@ -0,0 +1,13 @@
+// Package event provides the domain events which tell that work items, work
+// item links, comments, iterations or spaces changed.
+//
+// The repositories record an event in the same transaction as the change, in
+// an outbox table. Once the transaction is committed, a relay running in the
+// background publishes the recorded events on the bus, to which notifications,
+// webhooks, search indexing or metrics subscribe. An event is marked as
+// published once all its handlers succeeded; otherwise it is published again
+// after a delay which grows with every failure, so that no event is lost.
+// Handlers may thus see an event more than once, even after they succeeded,
+// and must be idempotent: notifications are unique per user and event, and
+// webhook deliveries are unique per webhook and event.
+package event
@@ -0,0 +1,275 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The types of the domain events
+const (
+	TypeWorkItemCreated  = "workitem.created"
+	TypeWorkItemUpdated  = "workitem.updated"
+	TypeWorkItemMoved    = "workitem.moved"
+	TypeWorkItemDeleted  = "workitem.deleted"
+	TypeLinkCreated      = "link.created"
+	TypeLinkDeleted      = "link.deleted"
+	TypeCommentAdded     = "comment.added"
+	TypeCommentUpdated   = "comment.updated"
+	TypeCommentDeleted   = "comment.deleted"
+	TypeUserMentioned    = "user.mentioned"
+	TypeIterationCreated = "iteration.created"
+	TypeIterationStarted = "iteration.started"
+	TypeIterationClosed  = "iteration.closed"
+	TypeSpaceCreated     = "space.created"
+	TypeSpaceUpdated     = "space.updated"
+	TypeSpaceDeleted     = "space.deleted"
+)
+
+// Payload is the typed content of a domain event
+type Payload interface {
+	// EventType returns the type of the events with this payload
+	EventType() string
+}
+
+// Event is a domain event as stored in the outbox
+type Event struct {
+	ID uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	// Seq orders the events in the order they were recorded
+	Seq       uint64
+	CreatedAt time.Time
+	Type      string
+	SpaceID   uuid.UUID `sql:"type:uuid"`
+	// ActorID is the user who caused the event, or uuid.Nil if unknown
+	ActorID uuid.UUID `sql:"type:uuid"`
+	// Payload holds the JSON of the typed payload of the event
+	Payload     string `sql:"type:jsonb"`
+	PublishedAt *time.Time
+	// Attempts is the number of times the handling of the event failed
+	Attempts int
+	// NextAttemptAt is when the publication of the event is retried after a failure
+	NextAttemptAt *time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (e Event) TableName() string {
+	return "domain_events"
+}
+
+// Decode returns the typed payload of the event
+// returns BadParameterError if the event is of an unknown type
+func (e Event) Decode() (Payload, error) {
+	var p Payload
+	switch e.Type {
+	case TypeWorkItemCreated:
+		p = &WorkItemCreated{}
+	case TypeWorkItemUpdated:
+		p = &WorkItemUpdated{}
+	case TypeWorkItemMoved:
+		p = &WorkItemMoved{}
+	case TypeWorkItemDeleted:
+		p = &WorkItemDeleted{}
+	case TypeLinkCreated:
+		p = &LinkCreated{}
+	case TypeLinkDeleted:
+		p = &LinkDeleted{}
+	case TypeCommentAdded:
+		p = &CommentAdded{}
+	case TypeCommentUpdated:
+		p = &CommentUpdated{}
+	case TypeCommentDeleted:
+		p = &CommentDeleted{}
+	case TypeUserMentioned:
+		p = &UserMentioned{}
+	case TypeIterationCreated:
+		p = &IterationCreated{}
+	case TypeIterationStarted:
+		p = &IterationStarted{}
+	case TypeIterationClosed:
+		p = &IterationClosed{}
+	case TypeSpaceCreated:
+		p = &SpaceCreated{}
+	case TypeSpaceUpdated:
+		p = &SpaceUpdated{}
+	case TypeSpaceDeleted:
+		p = &SpaceDeleted{}
+	default:
+		return nil, errors.NewBadParameterError("type", e.Type).Expected("known event type")
+	}
+	if err := json.Unmarshal([]byte(e.Payload), p); err != nil {
+		return nil, errs.Wrapf(err, "failed to decode the payload of event %s", e.ID)
+	}
+	return p, nil
+}
+
+// FieldChange tells how the value of a field of a work item changed
+type FieldChange struct {
+	Name     string      `json:"name"`
+	OldValue interface{} `json:"old_value"`
+	NewValue interface{} `json:"new_value"`
+}
+
+// WorkItemCreated tells that a work item was created
+type WorkItemCreated struct {
+	WorkItemID string                 `json:"workitem_id"`
+	Number     int                    `json:"number"`
+	TypeID     uuid.UUID              `json:"type_id"`
+	Fields     map[string]interface{} `json:"fields"`
+}
+
+// EventType implements Payload
+func (WorkItemCreated) EventType() string { return TypeWorkItemCreated }
+
+// WorkItemUpdated tells that fields of a work item changed
+type WorkItemUpdated struct {
+	WorkItemID string        `json:"workitem_id"`
+	Version    int           `json:"version"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+// EventType implements Payload
+func (WorkItemUpdated) EventType() string { return TypeWorkItemUpdated }
+
+// WorkItemMoved tells that a work item was moved to another space. The event
+// belongs to the target space.
+type WorkItemMoved struct {
+	WorkItemID    string    `json:"workitem_id"`
+	SourceSpaceID uuid.UUID `json:"source_space_id"`
+	Number        int       `json:"number"`
+}
+
+// EventType implements Payload
+func (WorkItemMoved) EventType() string { return TypeWorkItemMoved }
+
+// WorkItemDeleted tells that a work item was deleted
+type WorkItemDeleted struct {
+	WorkItemID string `json:"workitem_id"`
+}
+
+// EventType implements Payload
+func (WorkItemDeleted) EventType() string { return TypeWorkItemDeleted }
+
+// LinkCreated tells that two work items were linked
+type LinkCreated struct {
+	LinkID     uuid.UUID `json:"link_id"`
+	LinkTypeID uuid.UUID `json:"link_type_id"`
+	SourceID   string    `json:"source_id"`
+	TargetID   string    `json:"target_id"`
+}
+
+// EventType implements Payload
+func (LinkCreated) EventType() string { return TypeLinkCreated }
+
+// LinkDeleted tells that a link between two work items was removed
+type LinkDeleted struct {
+	LinkID     uuid.UUID `json:"link_id"`
+	LinkTypeID uuid.UUID `json:"link_type_id"`
+	SourceID   string    `json:"source_id"`
+	TargetID   string    `json:"target_id"`
+}
+
+// EventType implements Payload
+func (LinkDeleted) EventType() string { return TypeLinkDeleted }
+
+// CommentAdded tells that a work item was commented. ReplyTo is the comment
+// the new comment replies to, if any.
+type CommentAdded struct {
+	CommentID  uuid.UUID  `json:"comment_id"`
+	WorkItemID string     `json:"workitem_id"`
+	ReplyTo    *uuid.UUID `json:"reply_to,omitempty"`
+	Body       string     `json:"body"`
+	Markup     string     `json:"markup"`
+}
+
+// EventType implements Payload
+func (CommentAdded) EventType() string { return TypeCommentAdded }
+
+// CommentUpdated tells that a comment was edited
+type CommentUpdated struct {
+	CommentID  uuid.UUID `json:"comment_id"`
+	WorkItemID string    `json:"workitem_id"`
+	Body       string    `json:"body"`
+	Markup     string    `json:"markup"`
+}
+
+// EventType implements Payload
+func (CommentUpdated) EventType() string { return TypeCommentUpdated }
+
+// CommentDeleted tells that a comment was deleted
+type CommentDeleted struct {
+	CommentID  uuid.UUID `json:"comment_id"`
+	WorkItemID string    `json:"workitem_id"`
+}
+
+// EventType implements Payload
+func (CommentDeleted) EventType() string { return TypeCommentDeleted }
+
+// UserMentioned tells that users were mentioned for the first time in the
+// description of a work item or in a comment. Kind is either "workitems" or
+// "comments", TargetID is the ID of the work item or of the comment.
+type UserMentioned struct {
+	WorkItemID  string      `json:"workitem_id"`
+	Kind        string      `json:"kind"`
+	TargetID    string      `json:"target_id"`
+	IdentityIDs []uuid.UUID `json:"identity_ids"`
+	Body        string      `json:"body"`
+	Markup      string      `json:"markup"`
+}
+
+// EventType implements Payload
+func (UserMentioned) EventType() string { return TypeUserMentioned }
+
+// IterationCreated tells that an iteration was created
+type IterationCreated struct {
+	IterationID uuid.UUID `json:"iteration_id"`
+	Name        string    `json:"name"`
+}
+
+// EventType implements Payload
+func (IterationCreated) EventType() string { return TypeIterationCreated }
+
+// IterationStarted tells that an iteration was started
+type IterationStarted struct {
+	IterationID uuid.UUID `json:"iteration_id"`
+	Name        string    `json:"name"`
+}
+
+// EventType implements Payload
+func (IterationStarted) EventType() string { return TypeIterationStarted }
+
+// IterationClosed tells that an iteration was closed
+type IterationClosed struct {
+	IterationID uuid.UUID `json:"iteration_id"`
+	Name        string    `json:"name"`
+}
+
+// EventType implements Payload
+func (IterationClosed) EventType() string { return TypeIterationClosed }
+
+// SpaceCreated tells that a space was created
+type SpaceCreated struct {
+	Name string `json:"name"`
+}
+
+// EventType implements Payload
+func (SpaceCreated) EventType() string { return TypeSpaceCreated }
+
+// SpaceUpdated tells that the name or the description of a space changed
+type SpaceUpdated struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// EventType implements Payload
+func (SpaceUpdated) EventType() string { return TypeSpaceUpdated }
+
+// SpaceDeleted tells that a space was deleted
+type SpaceDeleted struct{}
+
+// EventType implements Payload
+func (SpaceDeleted) EventType() string { return TypeSpaceDeleted }
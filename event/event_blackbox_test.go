@@ -0,0 +1,231 @@
+package event_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDecode(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("ok", func(t *testing.T) {
+		// given
+		e := event.Event{Type: event.TypeWorkItemDeleted, Payload: `{"workitem_id": "42"}`}
+		// when
+		p, err := e.Decode()
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, &event.WorkItemDeleted{WorkItemID: "42"}, p)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		// given
+		e := event.Event{Type: "foo.bar", Payload: `{}`}
+		// when
+		_, err := e.Decode()
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func TestBus(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("filter by type", func(t *testing.T) {
+		// given
+		bus := event.NewBus()
+		var all, comments []string
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			all = append(all, e.Type)
+			return nil
+		})
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			comments = append(comments, e.Type)
+			return nil
+		}, event.TypeCommentAdded)
+		// when
+		require.Nil(t, bus.Publish(context.Background(), event.Event{Type: event.TypeWorkItemCreated}))
+		require.Nil(t, bus.Publish(context.Background(), event.Event{Type: event.TypeCommentAdded}))
+		// then
+		assert.Equal(t, []string{event.TypeWorkItemCreated, event.TypeCommentAdded}, all)
+		assert.Equal(t, []string{event.TypeCommentAdded}, comments)
+	})
+
+	t.Run("panicking handler", func(t *testing.T) {
+		// given
+		bus := event.NewBus()
+		handled := false
+		bus.Subscribe(func(ctx context.Context, e event.Event) error { panic("boom") })
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			handled = true
+			return nil
+		})
+		// when
+		err := bus.Publish(context.Background(), event.Event{Type: event.TypeSpaceCreated})
+		// then
+		assert.NotNil(t, err)
+		assert.True(t, handled)
+	})
+
+	t.Run("failing handler", func(t *testing.T) {
+		// given
+		bus := event.NewBus()
+		handled := false
+		bus.Subscribe(func(ctx context.Context, e event.Event) error { return errs.New("boom") })
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			handled = true
+			return nil
+		})
+		// when
+		err := bus.Publish(context.Background(), event.Event{Type: event.TypeSpaceCreated})
+		// then
+		require.NotNil(t, err)
+		assert.Equal(t, "boom", err.Error())
+		assert.True(t, handled)
+	})
+}
+
+type TestOutbox struct {
+	gormtestsupport.DBTestSuite
+	clean func()
+}
+
+func TestRunOutbox(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestOutbox{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestOutbox) SetupTest() {
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	// leave no pending events of other tests behind
+	require.Nil(s.T(), event.PublishPending(context.Background(), s.DB, event.NewBus()))
+}
+
+func (s *TestOutbox) TearDownTest() {
+	s.clean()
+}
+
+func (s *TestOutbox) TestPublishPending() {
+	s.T().Run("once after commit", func(t *testing.T) {
+		// given
+		actorID := uuid.NewV4()
+		tx := s.DB.Begin()
+		require.Nil(t, event.Record(context.Background(), tx, space.SystemSpace, actorID, event.SpaceUpdated{Name: "foo"}))
+		require.Nil(t, tx.Commit().Error)
+		bus := event.NewBus()
+		var published []event.Event
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			published = append(published, e)
+			return nil
+		})
+		// when
+		err := event.PublishPending(context.Background(), s.DB, bus)
+		// then
+		require.Nil(t, err)
+		require.Len(t, published, 1)
+		assert.Equal(t, event.TypeSpaceUpdated, published[0].Type)
+		assert.Equal(t, space.SystemSpace, published[0].SpaceID)
+		assert.Equal(t, actorID, published[0].ActorID)
+		p, err := published[0].Decode()
+		require.Nil(t, err)
+		assert.Equal(t, &event.SpaceUpdated{Name: "foo"}, p)
+		// published events are not published again
+		require.Nil(t, event.PublishPending(context.Background(), s.DB, bus))
+		assert.Len(t, published, 1)
+	})
+
+	s.T().Run("not after rollback", func(t *testing.T) {
+		// given
+		tx := s.DB.Begin()
+		require.Nil(t, event.Record(context.Background(), tx, space.SystemSpace, uuid.Nil, event.SpaceDeleted{}))
+		require.Nil(t, tx.Rollback().Error)
+		bus := event.NewBus()
+		published := 0
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			published++
+			return nil
+		})
+		// when
+		err := event.PublishPending(context.Background(), s.DB, bus)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, 0, published)
+	})
+	s.T().Run("retried after a failure", func(t *testing.T) {
+		// given
+		tx := s.DB.Begin()
+		require.Nil(t, event.Record(context.Background(), tx, space.SystemSpace, uuid.Nil, event.SpaceUpdated{Name: "bar"}))
+		require.Nil(t, tx.Commit().Error)
+		failing := event.NewBus()
+		failing.Subscribe(func(ctx context.Context, e event.Event) error { return errs.New("unavailable") })
+		// when
+		err := event.PublishPending(context.Background(), s.DB, failing)
+		// then the event is kept for a later attempt
+		require.Nil(t, err)
+		var pending []event.Event
+		require.Nil(t, s.DB.Where("published_at IS NULL AND type = ?", event.TypeSpaceUpdated).Find(&pending).Error)
+		require.Len(t, pending, 1)
+		assert.Equal(t, 1, pending[0].Attempts)
+		require.NotNil(t, pending[0].NextAttemptAt)
+		assert.True(t, pending[0].NextAttemptAt.After(time.Now()))
+		// and is not published again before it is due
+		bus := event.NewBus()
+		published := 0
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			published++
+			return nil
+		})
+		require.Nil(t, event.PublishPending(context.Background(), s.DB, bus))
+		assert.Equal(t, 0, published)
+		// but once it is due
+		require.Nil(t, s.DB.Exec("UPDATE domain_events SET next_attempt_at = now() WHERE id = ?", pending[0].ID).Error)
+		require.Nil(t, event.PublishPending(context.Background(), s.DB, bus))
+		assert.Equal(t, 1, published)
+	})
+
+	s.T().Run("retried for every handler", func(t *testing.T) {
+		// given a handler which fails once and another one which succeeds
+		tx := s.DB.Begin()
+		require.Nil(t, event.Record(context.Background(), tx, space.SystemSpace, uuid.Nil, event.SpaceUpdated{Name: "baz"}))
+		require.Nil(t, tx.Commit().Error)
+		bus := event.NewBus()
+		failures := 1
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			if failures > 0 {
+				failures--
+				return errs.New("unavailable")
+			}
+			return nil
+		}, event.TypeSpaceUpdated)
+		handled := 0
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			handled++
+			return nil
+		}, event.TypeSpaceUpdated)
+		require.Nil(t, event.PublishPending(context.Background(), s.DB, bus))
+		require.Equal(t, 1, handled)
+		// when the event is due again
+		require.Nil(t, s.DB.Exec("UPDATE domain_events SET next_attempt_at = now() WHERE published_at IS NULL AND type = ?", event.TypeSpaceUpdated).Error)
+		require.Nil(t, event.PublishPending(context.Background(), s.DB, bus))
+		// then the handler which succeeded sees it again, which is why all
+		// handlers must be idempotent
+		assert.Equal(t, 2, handled)
+		var pending []event.Event
+		require.Nil(t, s.DB.Where("published_at IS NULL AND type = ?", event.TypeSpaceUpdated).Find(&pending).Error)
+		assert.Len(t, pending, 0)
+	})
+}
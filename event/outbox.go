@@ -0,0 +1,145 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/login/tokencontext"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// publishBatchSize is the number of events published at once
+const publishBatchSize = 100
+
+// publishRetryDelay is the delay before the first retry of an event whose
+// handling failed. It doubles with every failed attempt up to publishRetryMaxDelay.
+const (
+	publishRetryDelay    = 10 * time.Second
+	publishRetryMaxDelay = time.Hour
+)
+
+// locator is the part of token.Manager needed to find the current user
+type locator interface {
+	Locate(ctx context.Context) (uuid.UUID, error)
+}
+
+// ContextActor returns the ID of the user of the request of the given
+// context, or uuid.Nil if there is none. It is meant for the repositories
+// which are not told who makes the change.
+func ContextActor(ctx context.Context) uuid.UUID {
+	tm, ok := tokencontext.ReadTokenManagerFromContext(ctx).(locator)
+	if !ok {
+		return uuid.Nil
+	}
+	id, err := tm.Locate(ctx)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// Record stores an event with the given payload in the outbox. It must be
+// called with the transaction of the change, so that the event is recorded
+// if and only if the change is committed.
+// returns InternalError
+func Record(ctx context.Context, db *gorm.DB, spaceID uuid.UUID, actorID uuid.UUID, payload Payload) error {
+	defer goa.MeasureSince([]string{"goa", "db", "event", "record"}, time.Now())
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.NewInternalError(errs.Wrapf(err, "failed to encode the payload of a %s event", payload.EventType()))
+	}
+	tx := db.Exec(`INSERT INTO domain_events (id, created_at, type, space_id, actor_id, payload) VALUES (?, now(), ?, ?, ?, ?)`,
+		uuid.NewV4(), payload.EventType(), spaceID, actorID, string(data))
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"type":     payload.EventType(),
+			"space_id": spaceID,
+			"err":      tx.Error,
+		}, "unable to record the event: %s", tx.Error.Error())
+		return errors.NewInternalError(tx.Error)
+	}
+	return nil
+}
+
+// PublishPending publishes the events which are due on the given bus, in
+// the order they were recorded. An event is marked as published once all its
+// handlers succeeded, otherwise its publication is retried after a delay.
+// Concurrent callers skip the events which are being published by another.
+func PublishPending(ctx context.Context, db *gorm.DB, bus *Bus) error {
+	for {
+		n, err := publishBatch(ctx, db, bus)
+		if err != nil {
+			return err
+		}
+		if n < publishBatchSize {
+			return nil
+		}
+	}
+}
+
+func publishBatch(ctx context.Context, db *gorm.DB, bus *Bus) (int, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "event", "publish"}, time.Now())
+	tx := db.Begin()
+	if tx.Error != nil {
+		return 0, errors.NewInternalError(tx.Error)
+	}
+	var events []Event
+	err := tx.Raw(`SELECT * FROM domain_events WHERE published_at IS NULL AND (next_attempt_at IS NULL OR next_attempt_at <= now())
+		ORDER BY seq LIMIT ? FOR UPDATE SKIP LOCKED`, publishBatchSize).Scan(&events).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		return 0, errors.NewInternalError(err)
+	}
+	if len(events) == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+	published := []uuid.UUID{}
+	for _, e := range events {
+		if err := bus.Publish(ctx, e); err != nil {
+			nextAttemptAt := time.Now().Add(retryDelay(e.Attempts))
+			log.Warn(ctx, map[string]interface{}{
+				"event_id":        e.ID,
+				"type":            e.Type,
+				"attempts":        e.Attempts + 1,
+				"next_attempt_at": nextAttemptAt,
+			}, "the publication of the event will be retried")
+			if err := tx.Exec(`UPDATE domain_events SET attempts = attempts + 1, next_attempt_at = ? WHERE id = ?`, nextAttemptAt, e.ID).Error; err != nil {
+				tx.Rollback()
+				return 0, errors.NewInternalError(err)
+			}
+			continue
+		}
+		published = append(published, e.ID)
+	}
+	if len(published) > 0 {
+		if err := tx.Exec(`UPDATE domain_events SET published_at = now() WHERE id IN (?)`, published).Error; err != nil {
+			tx.Rollback()
+			return 0, errors.NewInternalError(err)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return 0, errors.NewInternalError(err)
+	}
+	return len(events), nil
+}
+
+// retryDelay returns the delay before the next attempt to publish an event
+// which failed to be handled the given number of times before
+func retryDelay(attempts int) time.Duration {
+	delay := publishRetryDelay
+	for i := 0; i < attempts && delay < publishRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > publishRetryMaxDelay {
+		return publishRetryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,63 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8io/almighty-core/log"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Relay periodically publishes the committed events which are due, i.e. the
+// new ones and the ones whose handling failed and needs to be retried
+type Relay struct {
+	db   *gorm.DB
+	bus  *Bus
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewRelay creates a relay which publishes on the given bus
+func NewRelay(db *gorm.DB, bus *Bus) *Relay {
+	return &Relay{db: db, bus: bus}
+}
+
+// Start publishes the pending events at the given interval until Stop is called
+func (r *Relay) Start(interval time.Duration) {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.publish()
+			}
+		}
+	}()
+}
+
+// Stop stops the relay and waits for the running publication to complete
+// This should be called only from main
+func (r *Relay) Stop() {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+	r.stop = nil
+}
+
+func (r *Relay) publish() {
+	ctx := context.Background()
+	if err := PublishPending(ctx, r.db, r.bus); err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "unable to publish the pending events: %s", err.Error())
+	}
+}
@@ -69,6 +69,9 @@ var packageAliases map[string]string
 // map of domain structure names and their corresponding aliased package (unknown at the design level)
 var structPackages map[string]string
 
+// map of entity names used in the design and the name of the corresponding domain structure, when they differ
+var structAliases map[string]string
+
 var ignoredStructs []string
 
 func init() {
@@ -81,6 +84,11 @@ func init() {
 		"accountdsl":      "github.com/fabric8io/almighty-core/account",
 		"areadsl":         "github.com/fabric8io/almighty-core/area",
 		"commentdsl":      "github.com/fabric8io/almighty-core/comment",
+		"labeldsl":        "github.com/fabric8io/almighty-core/label",
+		"templatedsl":     "github.com/fabric8io/almighty-core/workitem/template",
+		"subscriptiondsl": "github.com/fabric8io/almighty-core/subscription",
+		"webhookdsl":      "github.com/fabric8io/almighty-core/webhook",
+		"mentiondsl":      "github.com/fabric8io/almighty-core/mention",
 	}
 	// model structures and their corresponding package alias
 	structPackages = map[string]string{
@@ -94,6 +102,15 @@ func init() {
 		"Identity":         "accountdsl",
 		"Area":             "areadsl",
 		"Comment":          "commentdsl",
+		"Label":            "labeldsl",
+		"WorkItemTemplate": "templatedsl",
+		"ChecklistItem":    "workitemdsl",
+		"Subscription":     "subscriptiondsl",
+		"Webhook":          "webhookdsl",
+		"Mention":          "mentiondsl",
+	}
+	structAliases = map[string]string{
+		"WorkItemTemplate": "Template",
 	}
 	// structures to ignore during code generation (mostly because they correspond to model structures which were already taken into account)
 	ignoredStructs = []string{
@@ -147,7 +164,11 @@ func WriteNames(api *design.APIDefinition, outDir string) ([]string, error) {
 											continue
 										}
 										// prepend the package
-										domainTypeName = structPackages[domainTypeName] + "." + domainTypeName
+										structName := domainTypeName
+										if alias, ok := structAliases[domainTypeName]; ok {
+											structName = alias
+										}
+										domainTypeName = structPackages[domainTypeName] + "." + structName
 										entity = &Entity{
 											AppTypeName:    mt.TypeName,
 											DomainTypeName: domainTypeName,
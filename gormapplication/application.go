@@ -11,11 +11,17 @@ import (
 	"github.com/fabric8io/almighty-core/codebase"
 	"github.com/fabric8io/almighty-core/comment"
 	"github.com/fabric8io/almighty-core/iteration"
+	"github.com/fabric8io/almighty-core/label"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/reaction"
 	"github.com/fabric8io/almighty-core/remoteworkitem"
 	"github.com/fabric8io/almighty-core/search"
 	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/subscription"
+	"github.com/fabric8io/almighty-core/webhook"
 	"github.com/fabric8io/almighty-core/workitem"
 	"github.com/fabric8io/almighty-core/workitem/link"
+	"github.com/fabric8io/almighty-core/workitem/template"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 )
@@ -143,6 +149,51 @@ func (g *GormBase) Codebases() codebase.Repository {
 	return codebase.NewCodebaseRepository(g.db)
 }
 
+// WorkItemRevisions returns a work item revision repository
+func (g *GormBase) WorkItemRevisions() workitem.RevisionRepository {
+	return workitem.NewRevisionRepository(g.db)
+}
+
+// Labels returns a label repository
+func (g *GormBase) Labels() label.Repository {
+	return label.NewLabelRepository(g.db)
+}
+
+// WorkItemTemplates returns a work item template repository
+func (g *GormBase) WorkItemTemplates() template.Repository {
+	return template.NewTemplateRepository(g.db)
+}
+
+// ChecklistItems returns a checklist item repository
+func (g *GormBase) ChecklistItems() workitem.ChecklistRepository {
+	return workitem.NewChecklistRepository(g.db)
+}
+
+// Subscriptions returns a subscription repository
+func (g *GormBase) Subscriptions() subscription.Repository {
+	return subscription.NewRepository(g.db)
+}
+
+// Webhooks returns the webhook repository
+func (g *GormBase) Webhooks() webhook.Repository {
+	return webhook.NewRepository(g.db)
+}
+
+// WebhookDeliveries returns the webhook delivery repository
+func (g *GormBase) WebhookDeliveries() webhook.DeliveryRepository {
+	return webhook.NewDeliveryRepository(g.db)
+}
+
+// Mentions returns a mention repository
+func (g *GormBase) Mentions() mention.Repository {
+	return mention.NewRepository(g.db)
+}
+
+// Reactions returns a reaction repository
+func (g *GormBase) Reactions() reaction.Repository {
+	return reaction.NewRepository(g.db)
+}
+
 func (g *GormBase) DB() *gorm.DB {
 	return g.db
 }
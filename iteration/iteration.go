@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
 	"github.com/fabric8io/almighty-core/gormsupport"
 	"github.com/fabric8io/almighty-core/log"
 	"github.com/fabric8io/almighty-core/path"
@@ -62,6 +63,7 @@ type Repository interface {
 	Create(ctx context.Context, u *Iteration) error
 	List(ctx context.Context, spaceID uuid.UUID) ([]Iteration, error)
 	Root(ctx context.Context, spaceID uuid.UUID) (*Iteration, error)
+	Current(ctx context.Context, spaceID uuid.UUID) (*Iteration, error)
 	Load(ctx context.Context, id uuid.UUID) (*Iteration, error)
 	Save(ctx context.Context, i Iteration) (*Iteration, error)
 	CanStart(ctx context.Context, i *Iteration) (bool, error)
@@ -112,7 +114,10 @@ func (m *GormIterationRepository) Create(ctx context.Context, u *Iteration) erro
 		}, "unable to create the iteration")
 		return errs.WithStack(err)
 	}
-
+	created := event.IterationCreated{IterationID: u.ID, Name: u.Name}
+	if err := event.Record(ctx, m.db, u.SpaceID, event.ContextActor(ctx), created); err != nil {
+		return errs.Wrapf(err, "error while creating iteration")
+	}
 	return nil
 }
 
@@ -149,6 +154,26 @@ func (m *GormIterationRepository) Root(ctx context.Context, spaceID uuid.UUID) (
 	return &itr, nil
 }
 
+// Current returns the running iteration of a space. If more than one
+// iteration is running the one that started last is returned.
+// returns NotFoundError if no iteration is running
+func (m *GormIterationRepository) Current(ctx context.Context, spaceID uuid.UUID) (*Iteration, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "iteration", "query"}, time.Now())
+	var itr Iteration
+	tx := m.db.Where("space_id = ? and state = ?", spaceID, IterationStateStart).Order("start_at desc nulls last").First(&itr)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("running iteration of space", spaceID.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": spaceID,
+			"err":      tx.Error,
+		}, "unable to get the current iteration")
+		return nil, errors.NewInternalError(tx.Error)
+	}
+	return &itr, nil
+}
+
 // Load a single Iteration regardless of parent
 func (m *GormIterationRepository) Load(ctx context.Context, id uuid.UUID) (*Iteration, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "iteration", "get"}, time.Now())
@@ -198,6 +223,20 @@ func (m *GormIterationRepository) Save(ctx context.Context, i Iteration) (*Itera
 		}, "unable to save the iterations")
 		return nil, errors.NewInternalError(err)
 	}
+	if i.State != itr.State {
+		var changed event.Payload
+		switch i.State {
+		case IterationStateStart:
+			changed = event.IterationStarted{IterationID: i.ID, Name: i.Name}
+		case IterationStateClose:
+			changed = event.IterationClosed{IterationID: i.ID, Name: i.Name}
+		}
+		if changed != nil {
+			if err := event.Record(ctx, m.db, i.SpaceID, event.ContextActor(ctx), changed); err != nil {
+				return nil, errs.Wrapf(err, "error while saving iteration")
+			}
+		}
+	}
 	return &i, nil
 }
 
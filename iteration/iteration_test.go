@@ -384,3 +384,38 @@ func (test *TestIterationRepository) TestLoadChildren() {
 	require.NotNil(t, err)
 	assert.Equal(t, reflect.TypeOf(errors.NotFoundError{}), reflect.TypeOf(err))
 }
+
+func (test *TestIterationRepository) TestCurrentIteration() {
+	t := test.T()
+	resource.Require(t, resource.Database)
+	// given
+	repo := iteration.NewIterationRepository(test.DB)
+	newSpace := space.Space{
+		Name: "Space Current " + uuid.NewV4().String(),
+	}
+	s, err := space.NewRepository(test.DB).Create(context.Background(), &newSpace)
+	require.Nil(t, err)
+	// when no iteration is running
+	_, err = repo.Current(context.Background(), s.ID)
+	// then
+	require.IsType(t, errors.NotFoundError{}, err)
+	// given two running iterations
+	earlier := time.Now().Add(-48 * time.Hour)
+	later := time.Now().Add(-24 * time.Hour)
+	createInState := func(name string, startAt *time.Time, state string) iteration.Iteration {
+		i := iteration.Iteration{Name: name, SpaceID: s.ID, StartAt: startAt}
+		require.Nil(t, repo.Create(context.Background(), &i))
+		i.State = state
+		_, err := repo.Save(context.Background(), i)
+		require.Nil(t, err)
+		return i
+	}
+	createInState("Sprint 1", &earlier, iteration.IterationStateStart)
+	second := createInState("Sprint 2", &later, iteration.IterationStateStart)
+	createInState("Sprint 0", &later, iteration.IterationStateClose)
+	// when
+	current, err := repo.Current(context.Background(), s.ID)
+	// then the one that started last is returned
+	require.Nil(t, err)
+	assert.Equal(t, second.ID, current.ID)
+}
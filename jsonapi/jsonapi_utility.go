@@ -22,6 +22,7 @@ const (
 	ErrorCodeUnauthorizedError = "unauthorized_error"
 	ErrorCodeForbiddenError    = "forbidden_error"
 	ErrorCodeJWTSecurityError  = "jwt_security_error"
+	ErrorCodeStateTransition   = "state_transition_error"
 )
 
 // ErrorToJSONAPIError returns the JSONAPI representation
@@ -34,6 +35,7 @@ func ErrorToJSONAPIError(err error) (app.JSONAPIError, int) {
 	var title, code string
 	var statusCode int
 	var id *string
+	var meta map[string]interface{}
 	log.Info(nil, map[string]interface{}{"err": cause, "error_message": cause.Error()}, "an error occurred in our api")
 	switch cause.(type) {
 	case errors.NotFoundError:
@@ -48,6 +50,14 @@ func ErrorToJSONAPIError(err error) (app.JSONAPIError, int) {
 		code = ErrorCodeBadParameter
 		title = "Bad parameter error"
 		statusCode = http.StatusBadRequest
+	case errors.StateTransitionError:
+		code = ErrorCodeStateTransition
+		title = "State transition error"
+		statusCode = http.StatusBadRequest
+		// let clients offer the states the work item can move to instead
+		meta = map[string]interface{}{
+			"allowed-states": cause.(errors.StateTransitionError).AllowedStates,
+		}
 	case errors.VersionConflictError:
 		code = ErrorCodeVersionConflict
 		title = "Version conflict error"
@@ -88,6 +98,7 @@ func ErrorToJSONAPIError(err error) (app.JSONAPIError, int) {
 		Status: &statusCodeStr,
 		Title:  &title,
 		Detail: detail,
+		Meta:   meta,
 	}
 	return jerr, statusCode
 }
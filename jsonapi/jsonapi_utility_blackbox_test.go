@@ -69,6 +69,15 @@ func TestErrorToJSONAPIError(t *testing.T) {
 	require.Equal(t, jsonapi.ErrorCodeForbiddenError, *jerr.Code)
 	require.Equal(t, strconv.Itoa(httpStatus), *jerr.Status)
 
+	// test state transition error
+	jerr, httpStatus = jsonapi.ErrorToJSONAPIError(errs.Wrap(errors.NewStateTransitionError("new", "closed", []string{"open"}), "foo"))
+	require.Equal(t, http.StatusBadRequest, httpStatus)
+	require.NotNil(t, jerr.Code)
+	require.NotNil(t, jerr.Status)
+	require.Equal(t, jsonapi.ErrorCodeStateTransition, *jerr.Code)
+	require.Equal(t, strconv.Itoa(httpStatus), *jerr.Status)
+	require.Equal(t, []string{"open"}, jerr.Meta["allowed-states"])
+
 	// test unspecified error
 	jerr, httpStatus = jsonapi.ErrorToJSONAPIError(fmt.Errorf("foobar"))
 	require.Equal(t, http.StatusInternalServerError, httpStatus)
@@ -0,0 +1,3 @@
+// Package label provides all the required functions to manage the definition
+// of labels which can be attached to the work items of a space.
+package label
@@ -0,0 +1,178 @@
+package label
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/gormsupport"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeLabels is the JSON API type of labels
+const APIStringTypeLabels = "labels"
+
+// Label describes a single label of a space. Work items refer to labels by
+// their ID so that a renamed label shows up with its new name everywhere.
+type Label struct {
+	gormsupport.Lifecycle
+	ID          uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	SpaceID     uuid.UUID `sql:"type:uuid"`
+	Name        string
+	Color       string
+	Description string
+	Version     int
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m Label) GetETagData() []interface{} {
+	return []interface{}{m.ID, m.Version}
+}
+
+// GetLastModified returns the last modification time
+func (m Label) GetLastModified() time.Time {
+	return m.UpdatedAt.Truncate(time.Second)
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Label) TableName() string {
+	return "labels"
+}
+
+// Repository describes interactions with Labels
+type Repository interface {
+	Create(ctx context.Context, l *Label) error
+	List(ctx context.Context, spaceID uuid.UUID) ([]Label, error)
+	Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Label, error)
+	Save(ctx context.Context, l Label) (*Label, error)
+	Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error
+	CheckExists(ctx context.Context, spaceID uuid.UUID, ids []string) error
+}
+
+// NewLabelRepository creates a new storage type.
+func NewLabelRepository(db *gorm.DB) Repository {
+	return &GormLabelRepository{db: db}
+}
+
+// GormLabelRepository is the implementation of the storage interface for Labels.
+type GormLabelRepository struct {
+	db *gorm.DB
+}
+
+// Create creates a new record.
+func (m *GormLabelRepository) Create(ctx context.Context, l *Label) error {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "create"}, time.Now())
+	l.ID = uuid.NewV4()
+	err := m.db.Create(l).Error
+	if err != nil {
+		// ( name, spaceID ) needs to be unique
+		if gormsupport.IsUniqueViolation(err, "labels_name_space_id_unique") {
+			return errors.NewBadParameterError("name & space_id", l.Name+" & "+l.SpaceID.String()).Expected("unique")
+		}
+		log.Error(ctx, map[string]interface{}{
+			"space_id": l.SpaceID,
+			"err":      err,
+		}, "error adding label: %s", err.Error())
+		return errors.NewInternalError(err)
+	}
+	return nil
+}
+
+// List returns all labels of the given space ordered by name
+func (m *GormLabelRepository) List(ctx context.Context, spaceID uuid.UUID) ([]Label, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "query"}, time.Now())
+	var objs []Label
+	err := m.db.Where("space_id = ?", spaceID).Order("name").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return objs, nil
+}
+
+// Load returns the label with the given ID in the given space
+func (m *GormLabelRepository) Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Label, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "get"}, time.Now())
+	var obj Label
+	tx := m.db.Where("space_id = ? AND id = ?", spaceID, id).First(&obj)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("label", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error)
+	}
+	return &obj, nil
+}
+
+// Save updates the given label in the db. Version must be the same as the one in the stored version
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (m *GormLabelRepository) Save(ctx context.Context, l Label) (*Label, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "save"}, time.Now())
+	existing, err := m.Load(ctx, l.SpaceID, l.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Version != l.Version {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	l.CreatedAt = existing.CreatedAt
+	l.Version = l.Version + 1
+	tx := m.db.Save(&l)
+	if err := tx.Error; err != nil {
+		if gormsupport.IsUniqueViolation(err, "labels_name_space_id_unique") {
+			return nil, errors.NewBadParameterError("name & space_id", l.Name+" & "+l.SpaceID.String()).Expected("unique")
+		}
+		log.Error(ctx, map[string]interface{}{
+			"label_id": l.ID,
+			"err":      err,
+		}, "unable to save the label")
+		return nil, errors.NewInternalError(err)
+	}
+	return &l, nil
+}
+
+// Delete deletes the label with the given ID. The label needs to be detached
+// from the work items of the space beforehand, so that the work items get a
+// new version.
+func (m *GormLabelRepository) Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "delete"}, time.Now())
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("label", id.String())
+	}
+	tx := m.db.Where("space_id = ?", spaceID).Delete(&Label{ID: id})
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("label", id.String())
+	}
+	return nil
+}
+
+// CheckExists returns a BadParameterError if one of the given label IDs does
+// not identify a label of the given space
+func (m *GormLabelRepository) CheckExists(ctx context.Context, spaceID uuid.UUID, ids []string) error {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "exists"}, time.Now())
+	if len(ids) == 0 {
+		return nil
+	}
+	unique := map[string]struct{}{}
+	for _, id := range ids {
+		if _, err := uuid.FromString(id); err != nil {
+			return errors.NewBadParameterError("label", id).Expected("label ID")
+		}
+		unique[id] = struct{}{}
+	}
+	var count int
+	tx := m.db.Model(&Label{}).Where("space_id = ? AND id IN (?)", spaceID, ids).Count(&count)
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	if count != len(unique) {
+		return errors.NewBadParameterError("label", ids).Expected("labels of the space")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package label_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/label"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestLabelRepository struct {
+	gormtestsupport.DBTestSuite
+	repo  label.Repository
+	space *space.Space
+	clean func()
+}
+
+func TestRunLabelRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestLabelRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (test *TestLabelRepository) SetupTest() {
+	test.clean = cleaner.DeleteCreatedEntities(test.DB)
+	test.repo = label.NewLabelRepository(test.DB)
+	s, err := space.NewRepository(test.DB).Create(context.Background(), &space.Space{
+		Name: "TestLabelRepository " + uuid.NewV4().String(),
+	})
+	require.Nil(test.T(), err)
+	test.space = s
+}
+
+func (test *TestLabelRepository) TearDownTest() {
+	test.clean()
+}
+
+func (test *TestLabelRepository) TestCreate() {
+	test.T().Run("ok", func(t *testing.T) {
+		// given
+		l := label.Label{SpaceID: test.space.ID, Name: "bug", Color: "#ff0000", Description: "Something is broken"}
+		// when
+		err := test.repo.Create(context.Background(), &l)
+		// then
+		require.Nil(t, err)
+		assert.NotEqual(t, uuid.Nil, l.ID)
+		loaded, err := test.repo.Load(context.Background(), test.space.ID, l.ID)
+		require.Nil(t, err)
+		assert.Equal(t, "bug", loaded.Name)
+		assert.Equal(t, "#ff0000", loaded.Color)
+		assert.Equal(t, "Something is broken", loaded.Description)
+	})
+
+	test.T().Run("same name in the space", func(t *testing.T) {
+		// given
+		l := label.Label{SpaceID: test.space.ID, Name: "bug"}
+		// when
+		err := test.repo.Create(context.Background(), &l)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	test.T().Run("same name in another space", func(t *testing.T) {
+		// given
+		l := label.Label{SpaceID: space.SystemSpace, Name: "bug"}
+		// when
+		err := test.repo.Create(context.Background(), &l)
+		// then
+		require.Nil(t, err)
+	})
+}
+
+func (test *TestLabelRepository) TestList() {
+	// given
+	for _, name := range []string{"ux", "backend", "docs"} {
+		require.Nil(test.T(), test.repo.Create(context.Background(), &label.Label{SpaceID: test.space.ID, Name: name}))
+	}
+	// when
+	labels, err := test.repo.List(context.Background(), test.space.ID)
+	// then
+	require.Nil(test.T(), err)
+	require.Len(test.T(), labels, 3)
+	assert.Equal(test.T(), "backend", labels[0].Name)
+	assert.Equal(test.T(), "docs", labels[1].Name)
+	assert.Equal(test.T(), "ux", labels[2].Name)
+}
+
+func (test *TestLabelRepository) TestSave() {
+	// given
+	l := label.Label{SpaceID: test.space.ID, Name: "bug"}
+	require.Nil(test.T(), test.repo.Create(context.Background(), &l))
+
+	test.T().Run("rename", func(t *testing.T) {
+		// given
+		l.Name = "defect"
+		// when
+		saved, err := test.repo.Save(context.Background(), l)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, "defect", saved.Name)
+		assert.Equal(t, l.Version+1, saved.Version)
+	})
+
+	test.T().Run("version conflict", func(t *testing.T) {
+		// given
+		l.Name = "problem"
+		// when
+		_, err := test.repo.Save(context.Background(), l)
+		// then
+		require.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+}
+
+func (test *TestLabelRepository) TestDelete() {
+	// given
+	l := label.Label{SpaceID: test.space.ID, Name: "bug"}
+	require.Nil(test.T(), test.repo.Create(context.Background(), &l))
+	// when
+	err := test.repo.Delete(context.Background(), test.space.ID, l.ID)
+	// then
+	require.Nil(test.T(), err)
+	_, err = test.repo.Load(context.Background(), test.space.ID, l.ID)
+	require.IsType(test.T(), errors.NotFoundError{}, errs.Cause(err))
+	// and the name can be used again
+	require.Nil(test.T(), test.repo.Create(context.Background(), &label.Label{SpaceID: test.space.ID, Name: "bug"}))
+}
+
+func (test *TestLabelRepository) TestCheckExists() {
+	// given
+	l := label.Label{SpaceID: test.space.ID, Name: "bug"}
+	require.Nil(test.T(), test.repo.Create(context.Background(), &l))
+	// then
+	assert.Nil(test.T(), test.repo.CheckExists(context.Background(), test.space.ID, []string{l.ID.String(), l.ID.String()}))
+	assert.IsType(test.T(), errors.BadParameterError{}, errs.Cause(test.repo.CheckExists(context.Background(), space.SystemSpace, []string{l.ID.String()})))
+	assert.IsType(test.T(), errors.BadParameterError{}, errs.Cause(test.repo.CheckExists(context.Background(), test.space.ID, []string{"foo"})))
+}
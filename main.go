@@ -18,16 +18,19 @@ import (
 	"github.com/fabric8io/almighty-core/auth"
 	config "github.com/fabric8io/almighty-core/configuration"
 	"github.com/fabric8io/almighty-core/controller"
+	"github.com/fabric8io/almighty-core/event"
 	"github.com/fabric8io/almighty-core/gormapplication"
 	"github.com/fabric8io/almighty-core/jsonapi"
 	"github.com/fabric8io/almighty-core/log"
 	"github.com/fabric8io/almighty-core/login"
 	"github.com/fabric8io/almighty-core/migration"
 	"github.com/fabric8io/almighty-core/models"
+	"github.com/fabric8io/almighty-core/notification"
 	"github.com/fabric8io/almighty-core/remoteworkitem"
 	"github.com/fabric8io/almighty-core/space"
 	"github.com/fabric8io/almighty-core/space/authz"
 	"github.com/fabric8io/almighty-core/token"
+	"github.com/fabric8io/almighty-core/webhook"
 	"github.com/fabric8io/almighty-core/workitem"
 	"github.com/fabric8io/almighty-core/workitem/link"
 
@@ -193,6 +196,10 @@ func main() {
 	workitemtypeCtrl := controller.NewWorkitemtypeController(service, appDB, configuration)
 	app.MountWorkitemtypeController(service, workitemtypeCtrl)
 
+	// Mount "workitemtype_workflow" controller
+	workitemtypeWorkflowCtrl := controller.NewWorkitemtypeWorkflowController(service, appDB)
+	app.MountWorkitemtypeWorkflowController(service, workitemtypeWorkflowCtrl)
+
 	// Mount "work item link category" controller
 	workItemLinkCategoryCtrl := controller.NewWorkItemLinkCategoryController(service, appDB)
 	app.MountWorkItemLinkCategoryController(service, workItemLinkCategoryCtrl)
@@ -209,6 +216,10 @@ func main() {
 	workItemCommentsCtrl := controller.NewWorkItemCommentsController(service, appDB, configuration)
 	app.MountWorkItemCommentsController(service, workItemCommentsCtrl)
 
+	// Mount "work item revisions" controller
+	workItemRevisionsCtrl := controller.NewWorkItemRevisionsController(service, appDB)
+	app.MountWorkItemRevisionsController(service, workItemRevisionsCtrl)
+
 	// Mount "work item relationships links" controller
 	workItemRelationshipsLinksCtrl := controller.NewWorkItemRelationshipsLinksController(service, appDB, configuration)
 	app.MountWorkItemRelationshipsLinksController(service, workItemRelationshipsLinksCtrl)
@@ -217,6 +228,35 @@ func main() {
 	commentsCtrl := controller.NewCommentsController(service, appDB, configuration)
 	app.MountCommentsController(service, commentsCtrl)
 
+	// Dispatcher to send the domain events to the webhooks
+	dispatcher := webhook.NewDispatcher(db, configuration)
+	event.Subscribe(dispatcher.Handle)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	if configuration.IsNotificationEnabled() {
+		// Notifier to email the users about the changes of what they watch
+		var sender notification.Sender
+		if dir := configuration.GetNotificationFileDir(); dir != "" {
+			sender = notification.NewFileSender(dir, configuration.GetNotificationFrom())
+		} else {
+			sender = notification.NewSMTPSender(
+				configuration.GetNotificationSMTPAddress(),
+				configuration.GetNotificationSMTPUsername(),
+				configuration.GetNotificationSMTPPassword(),
+				configuration.GetNotificationFrom())
+		}
+		notifier := notification.NewNotifier(db, sender)
+		event.Subscribe(notifier.Handle)
+		notifier.Start()
+		defer notifier.Stop()
+	}
+
+	// Relay to publish the domain events which were not published after their commit
+	relay := event.NewRelay(db, event.DefaultBus)
+	relay.Start(configuration.GetEventRelayInterval())
+	defer relay.Stop()
+
 	if !configuration.GetFeatureWorkitemRemote() {
 		// Scheduler to fetch and import remote tracker items
 		scheduler = remoteworkitem.NewScheduler(db)
@@ -267,6 +307,10 @@ func main() {
 	spaceIterationCtrl := controller.NewSpaceIterationsController(service, appDB, configuration)
 	app.MountSpaceIterationsController(service, spaceIterationCtrl)
 
+	// Mount "space_trash" controller
+	spaceTrashCtrl := controller.NewSpaceTrashController(service, appDB)
+	app.MountSpaceTrashController(service, spaceTrashCtrl)
+
 	// Mount "userspace" controller
 	userspaceCtrl := controller.NewUserspaceController(service, db)
 	app.MountUserspaceController(service, userspaceCtrl)
@@ -282,6 +326,30 @@ func main() {
 	spaceAreaCtrl := controller.NewSpaceAreasController(service, appDB, configuration)
 	app.MountSpaceAreasController(service, spaceAreaCtrl)
 
+	// Mount "labels" controller
+	labelCtrl := controller.NewLabelController(service, appDB, configuration)
+	app.MountLabelController(service, labelCtrl)
+
+	// Mount "workitemtemplate" controller
+	workItemTemplateCtrl := controller.NewWorkitemtemplateController(service, appDB, configuration)
+	app.MountWorkitemtemplateController(service, workItemTemplateCtrl)
+
+	// Mount "work_item_checklist" controller
+	workItemChecklistCtrl := controller.NewWorkItemChecklistController(service, appDB, configuration)
+	app.MountWorkItemChecklistController(service, workItemChecklistCtrl)
+
+	// Mount "subscription" controller
+	subscriptionCtrl := controller.NewSubscriptionController(service, appDB, configuration)
+	app.MountSubscriptionController(service, subscriptionCtrl)
+
+	// Mount "mention" controller
+	mentionCtrl := controller.NewMentionController(service, appDB, configuration)
+	app.MountMentionController(service, mentionCtrl)
+
+	// Mount "webhook" controller
+	webhookCtrl := controller.NewWebhookController(service, appDB, configuration)
+	app.MountWebhookController(service, webhookCtrl)
+
 	filterCtrl := controller.NewFilterController(service, configuration)
 	app.MountFilterController(service, filterCtrl)
 
@@ -289,6 +357,10 @@ func main() {
 	namedSpacesCtrl := controller.NewNamedspacesController(service, appDB)
 	app.MountNamedspacesController(service, namedSpacesCtrl)
 
+	// Mount "namedworkitems" controller
+	namedWorkItemsCtrl := controller.NewNamedworkitemsController(service, appDB, configuration)
+	app.MountNamedworkitemsController(service, namedWorkItemsCtrl)
+
 	// Mount "plannerBacklog" controller
 	plannerBacklogCtrl := controller.NewPlannerBacklogController(service, appDB, configuration)
 	app.MountPlannerBacklogController(service, plannerBacklogCtrl)
@@ -0,0 +1,5 @@
+// Package mention provides the functions to manage the mentions of users with
+// @username in the descriptions of work items and in comments. The mentions
+// are resolved against the usernames of the identities and stored, so that
+// the mentioned users can be notified and find what they were mentioned in.
+package mention
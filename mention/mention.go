@@ -0,0 +1,227 @@
+package mention
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/rendering"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeMentions is the JSON API type of mentions
+const APIStringTypeMentions = "mentions"
+
+// The kinds of things users are mentioned in. They are named like the JSON
+// API types of the mentioning entities.
+const (
+	KindWorkItem = "workitems"
+	KindComment  = "comments"
+)
+
+// Mention tells that a user was mentioned in the description of a work item
+// or in a comment
+type Mention struct {
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	CreatedAt  time.Time
+	IdentityID uuid.UUID `sql:"type:uuid"`
+	// WorkItemID is the ID of the mentioning work item, or of the parent of
+	// the mentioning comment
+	WorkItemID string
+	Kind       string
+	// TargetID is the ID of the mentioning work item or comment
+	TargetID string
+	// SpaceID is the space of the work item, it is not stored
+	SpaceID uuid.UUID `sql:"-"`
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m Mention) GetETagData() []interface{} {
+	return []interface{}{m.ID, strconv.FormatInt(m.CreatedAt.Unix(), 10)}
+}
+
+// GetLastModified returns the last modification time
+func (m Mention) GetLastModified() time.Time {
+	return m.CreatedAt.Truncate(time.Second)
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Mention) TableName() string {
+	return "mentions"
+}
+
+// Target identifies a work item or a comment which mentions users
+type Target struct {
+	SpaceID    uuid.UUID
+	WorkItemID string
+	Kind       string
+	ID         string
+}
+
+// Repository describes interactions with mentions
+type Repository interface {
+	Sync(ctx context.Context, target Target, content, markup string, actorID uuid.UUID) error
+	List(ctx context.Context, kind string, targetID string) ([]uuid.UUID, error)
+	ListByIdentity(ctx context.Context, identityID uuid.UUID, start *int, limit *int) ([]Mention, uint64, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormMentionRepository{db: db}
+}
+
+// GormMentionRepository is the implementation of the storage interface for mentions.
+type GormMentionRepository struct {
+	db *gorm.DB
+}
+
+// Sync stores the mentions of users in the given content of the given work
+// item or comment, in place of the previous ones. Unknown usernames are
+// ignored. A UserMentioned event is recorded for the users who were not
+// mentioned by the target before.
+// returns InternalError
+func (m *GormMentionRepository) Sync(ctx context.Context, target Target, content, markup string, actorID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "mention", "sync"}, time.Now())
+	identityIDs, err := m.resolve(ctx, rendering.Mentions(content, markup))
+	if err != nil {
+		return err
+	}
+	db := m.db.Where("kind = ? AND target_id = ?", target.Kind, target.ID)
+	if len(identityIDs) > 0 {
+		db = db.Where("identity_id NOT IN (?)", identityIDs)
+	}
+	if err := db.Delete(Mention{}).Error; err != nil {
+		return errors.NewInternalError(err)
+	}
+	var added []uuid.UUID
+	for _, identityID := range identityIDs {
+		tx := m.db.Exec(`INSERT INTO mentions (id, created_at, identity_id, work_item_id, kind, target_id)
+			VALUES (?, now(), ?, ?, ?, ?)
+			ON CONFLICT (kind, target_id, identity_id) DO NOTHING`,
+			uuid.NewV4(), identityID, target.WorkItemID, target.Kind, target.ID)
+		if tx.Error != nil {
+			log.Error(ctx, map[string]interface{}{
+				"identity_id": identityID,
+				"target_id":   target.ID,
+				"err":         tx.Error,
+			}, "unable to store the mention: %s", tx.Error.Error())
+			return errors.NewInternalError(tx.Error)
+		}
+		if tx.RowsAffected > 0 {
+			added = append(added, identityID)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	mentioned := event.UserMentioned{
+		WorkItemID:  target.WorkItemID,
+		Kind:        target.Kind,
+		TargetID:    target.ID,
+		IdentityIDs: added,
+		Body:        content,
+		Markup:      markup,
+	}
+	return event.Record(ctx, m.db, target.SpaceID, actorID, mentioned)
+}
+
+// resolve returns the IDs of the identities with the given usernames. If
+// several identities share a username, the one of a user is preferred.
+func (m *GormMentionRepository) resolve(ctx context.Context, usernames []string) ([]uuid.UUID, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	rows, err := m.db.Raw(`SELECT DISTINCT ON (username) id FROM identities
+		WHERE username IN (?) AND deleted_at IS NULL
+		ORDER BY username, user_id IS NULL, created_at`, usernames).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	defer rows.Close()
+	var result []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+// List returns the IDs of the identities mentioned by the given work item or
+// comment
+// returns InternalError
+func (m *GormMentionRepository) List(ctx context.Context, kind string, targetID string) ([]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "mention", "list"}, time.Now())
+	var objs []Mention
+	err := m.db.Where("kind = ? AND target_id = ?", kind, targetID).Order("created_at").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	result := make([]uuid.UUID, len(objs))
+	for i, obj := range objs {
+		result[i] = obj.IdentityID
+	}
+	return result, nil
+}
+
+// mentionsOfIdentity selects the mentions of an identity by the work items and
+// the comments which were not deleted
+const mentionsOfIdentity = `FROM mentions m
+	JOIN work_items w ON w.id::text = m.work_item_id AND w.deleted_at IS NULL
+	LEFT JOIN comments c ON m.kind = ? AND c.id::text = m.target_id
+	WHERE m.identity_id = ? AND (m.kind <> ? OR (c.id IS NOT NULL AND c.deleted_at IS NULL))`
+
+// ListByIdentity returns the mentions of the given user, latest first, along
+// with their total count. Mentions by deleted work items or comments are left
+// out.
+// returns BadParameterError or InternalError
+func (m *GormMentionRepository) ListByIdentity(ctx context.Context, identityID uuid.UUID, start *int, limit *int) ([]Mention, uint64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "mention", "query"}, time.Now())
+	offset := 0
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		offset = *start
+	}
+	// postgres treats a NULL limit as no limit
+	var max interface{}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		max = *limit
+	}
+	rows, err := m.db.Raw(`SELECT count(*) OVER (), m.id, m.created_at, m.identity_id, m.work_item_id, m.kind, m.target_id, w.space_id `+
+		mentionsOfIdentity+` ORDER BY m.created_at DESC OFFSET ? LIMIT ?`, KindComment, identityID, KindComment, offset, max).Rows()
+	if err != nil {
+		return nil, 0, errors.NewInternalError(err)
+	}
+	defer rows.Close()
+	result := []Mention{}
+	var count uint64
+	for rows.Next() {
+		var obj Mention
+		if err := rows.Scan(&count, &obj.ID, &obj.CreatedAt, &obj.IdentityID, &obj.WorkItemID, &obj.Kind, &obj.TargetID, &obj.SpaceID); err != nil {
+			return nil, 0, errors.NewInternalError(err)
+		}
+		result = append(result, obj)
+	}
+	if len(result) == 0 && offset > 0 {
+		// the window function gives no count when the offset is beyond the last mention
+		err := m.db.Raw(`SELECT count(*) `+mentionsOfIdentity, KindComment, identityID, KindComment).Row().Scan(&count)
+		if err != nil {
+			return nil, 0, errors.NewInternalError(err)
+		}
+	}
+	return result, count, nil
+}
@@ -0,0 +1,184 @@
+package mention_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/comment"
+	"github.com/fabric8io/almighty-core/event"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/migration"
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+	testsupport "github.com/fabric8io/almighty-core/test"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestMentionRepository struct {
+	gormtestsupport.DBTestSuite
+	repo  mention.Repository
+	clean func()
+	jdoe  account.Identity
+	ann   account.Identity
+}
+
+func TestRunMentionRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestMentionRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestMentionRepository) SetupSuite() {
+	s.DBTestSuite.SetupSuite()
+	ctx := migration.NewMigrationContext(context.Background())
+	s.DBTestSuite.PopulateDBTestSuite(ctx)
+}
+
+func (s *TestMentionRepository) SetupTest() {
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	s.repo = mention.NewRepository(s.DB)
+	// leave no pending events of other tests behind
+	require.Nil(s.T(), event.PublishPending(context.Background(), s.DB, event.NewBus()))
+	var err error
+	s.jdoe, err = testsupport.CreateTestIdentity(s.DB, "TestMentionRepository-"+uuid.NewV4().String(), "test")
+	require.Nil(s.T(), err)
+	s.ann, err = testsupport.CreateTestIdentity(s.DB, "TestMentionRepository-"+uuid.NewV4().String(), "test")
+	require.Nil(s.T(), err)
+}
+
+func (s *TestMentionRepository) TearDownTest() {
+	s.clean()
+}
+
+func (s *TestMentionRepository) createWorkItem(description string) *workitem.WorkItem {
+	fields := map[string]interface{}{
+		workitem.SystemTitle: "Title",
+		workitem.SystemState: workitem.SystemStateNew,
+	}
+	if description != "" {
+		fields[workitem.SystemDescription] = rendering.NewMarkupContent(description, rendering.SystemMarkupMarkdown)
+	}
+	wi, err := workitem.NewWorkItemRepository(s.DB).Create(context.Background(), space.SystemSpace, workitem.SystemBug, fields, s.jdoe.ID)
+	require.Nil(s.T(), err)
+	return wi
+}
+
+// mentioned returns the identities of the users mentioned in the pending
+// events, and publishes them
+func (s *TestMentionRepository) mentioned(t *testing.T) [][]uuid.UUID {
+	var result [][]uuid.UUID
+	bus := event.NewBus()
+	bus.Subscribe(func(ctx context.Context, e event.Event) error {
+		p, err := e.Decode()
+		require.Nil(t, err)
+		result = append(result, p.(*event.UserMentioned).IdentityIDs)
+		return nil
+	}, event.TypeUserMentioned)
+	require.Nil(t, event.PublishPending(context.Background(), s.DB, bus))
+	return result
+}
+
+func (s *TestMentionRepository) TestComment() {
+	// given
+	wi := s.createWorkItem("")
+	s.mentioned(s.T())
+	c := comment.Comment{ParentID: wi.ID, Body: "Thanks @" + s.jdoe.Username + ", and @nobody-" + uuid.NewV4().String(), Markup: rendering.SystemMarkupMarkdown}
+
+	s.T().Run("created", func(t *testing.T) {
+		// when
+		require.Nil(t, comment.NewRepository(s.DB).Create(context.Background(), &c, s.ann.ID))
+		// then the unknown username is ignored
+		ids, err := s.repo.List(context.Background(), mention.KindComment, c.ID.String())
+		require.Nil(t, err)
+		assert.Equal(t, []uuid.UUID{s.jdoe.ID}, ids)
+		assert.Equal(t, [][]uuid.UUID{{s.jdoe.ID}}, s.mentioned(t))
+	})
+
+	s.T().Run("edited", func(t *testing.T) {
+		// given
+		c.Body = "@" + s.ann.Username + " and `@" + s.jdoe.Username + "`"
+		// when
+		require.Nil(t, comment.NewRepository(s.DB).Save(context.Background(), &c, s.ann.ID))
+		// then the mention in code is ignored
+		ids, err := s.repo.List(context.Background(), mention.KindComment, c.ID.String())
+		require.Nil(t, err)
+		assert.Equal(t, []uuid.UUID{s.ann.ID}, ids)
+		assert.Equal(t, [][]uuid.UUID{{s.ann.ID}}, s.mentioned(t))
+	})
+
+	s.T().Run("edited without new mention", func(t *testing.T) {
+		// given
+		c.Body = "Sorry @" + s.ann.Username
+		// when
+		require.Nil(t, comment.NewRepository(s.DB).Save(context.Background(), &c, s.ann.ID))
+		// then
+		assert.Empty(t, s.mentioned(t))
+	})
+
+	s.T().Run("deleted", func(t *testing.T) {
+		// given
+		mentions, count, err := s.repo.ListByIdentity(context.Background(), s.ann.ID, nil, nil)
+		require.Nil(t, err)
+		require.Len(t, mentions, 1)
+		assert.Equal(t, uint64(1), count)
+		assert.Equal(t, mention.KindComment, mentions[0].Kind)
+		assert.Equal(t, wi.ID, mentions[0].WorkItemID)
+		assert.Equal(t, space.SystemSpace, mentions[0].SpaceID)
+		// when
+		require.Nil(t, comment.NewRepository(s.DB).Delete(context.Background(), c.ID, s.ann.ID))
+		// then
+		mentions, count, err = s.repo.ListByIdentity(context.Background(), s.ann.ID, nil, nil)
+		require.Nil(t, err)
+		assert.Empty(t, mentions)
+		assert.Equal(t, uint64(0), count)
+	})
+}
+
+func (s *TestMentionRepository) TestWorkItem() {
+	s.T().Run("created", func(t *testing.T) {
+		// when
+		wi := s.createWorkItem("cc @" + s.jdoe.Username + " @" + s.ann.Username)
+		// then
+		ids, err := s.repo.List(context.Background(), mention.KindWorkItem, wi.ID)
+		require.Nil(t, err)
+		assert.Len(t, ids, 2)
+		mentions, _, err := s.repo.ListByIdentity(context.Background(), s.ann.ID, nil, nil)
+		require.Nil(t, err)
+		require.Len(t, mentions, 1)
+		assert.Equal(t, mention.KindWorkItem, mentions[0].Kind)
+		assert.Equal(t, wi.ID, mentions[0].TargetID)
+	})
+
+	s.T().Run("description removed", func(t *testing.T) {
+		// given
+		wi := s.createWorkItem("cc @" + s.jdoe.Username)
+		delete(wi.Fields, workitem.SystemDescription)
+		// when
+		_, err := workitem.NewWorkItemRepository(s.DB).Save(context.Background(), space.SystemSpace, *wi, s.jdoe.ID)
+		// then
+		require.Nil(t, err)
+		ids, err := s.repo.List(context.Background(), mention.KindWorkItem, wi.ID)
+		require.Nil(t, err)
+		assert.Empty(t, ids)
+	})
+
+	s.T().Run("paging", func(t *testing.T) {
+		// given
+		s.createWorkItem("@" + s.jdoe.Username)
+		offset, limit := 100, 10
+		// when
+		mentions, count, err := s.repo.ListByIdentity(context.Background(), s.jdoe.ID, &offset, &limit)
+		// then
+		require.Nil(t, err)
+		assert.Empty(t, mentions)
+		assert.True(t, count >= 1)
+	})
+}
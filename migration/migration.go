@@ -306,6 +306,54 @@ func GetMigrations() Migrations {
 	// Version 61
 	m = append(m, steps{ExecuteSQLFile("061-replace-index-space-name.sql")})
 
+	// Version 62
+	m = append(m, steps{ExecuteSQLFile("062-work-item-type-revisions.sql")})
+
+	// Version 63
+	m = append(m, steps{ExecuteSQLFile("063-work-item-type-workflow.sql")})
+
+	// Version 64
+	m = append(m, steps{ExecuteSQLFile("064-labels.sql")})
+
+	// Version 65
+	m = append(m, steps{ExecuteSQLFile("065-work-item-numbers.sql")})
+
+	// Version 66
+	m = append(m, steps{ExecuteSQLFile("066-work-item-templates.sql")})
+
+	// Version 67
+	m = append(m, steps{ExecuteSQLFile("067-work-item-checklists.sql")})
+
+	// Version 68
+	m = append(m, steps{ExecuteSQLFile("068-subscriptions.sql")})
+
+	// Version 69
+	m = append(m, steps{ExecuteSQLFile("069-domain-events.sql")})
+
+	// Version 70
+	m = append(m, steps{ExecuteSQLFile("070-webhooks.sql")})
+
+	// Version 71
+	m = append(m, steps{ExecuteSQLFile("071-notifications.sql")})
+
+	// Version 72
+	m = append(m, steps{ExecuteSQLFile("072-mentions.sql")})
+
+	// Version 73
+	m = append(m, steps{ExecuteSQLFile("073-comment-replies.sql")})
+
+	// Version 74
+	m = append(m, steps{ExecuteSQLFile("074-reactions.sql")})
+
+	// Version 75
+	m = append(m, steps{ExecuteSQLFile("075-domain-event-retries.sql")})
+
+	// Version 76
+	m = append(m, steps{ExecuteSQLFile("076-workitem-reference-values.sql")})
+
+	// Version 77
+	m = append(m, steps{ExecuteSQLFile("077-work-item-revision-spaces.sql")})
+
 	// Version N
 	//
 	// In order to add an upgrade, simply append an array of MigrationFunc to the
@@ -702,8 +750,8 @@ func createOrUpdateSystemPlannerItemType(ctx context.Context, witr *workitem.Gor
 		workitem.SystemCreatedAt:    {Type: workitem.SimpleType{Kind: "instant"}, Required: false, Label: "Created at", Description: "The date and time when the work item was created"},
 		workitem.SystemUpdatedAt:    {Type: workitem.SimpleType{Kind: "instant"}, Required: false, Label: "Updated at", Description: "The date and time when the work item was last updated"},
 		workitem.SystemOrder:        {Type: workitem.SimpleType{Kind: "float"}, Required: false, Label: "Execution Order", Description: "Execution Order of the workitem."},
-		workitem.SystemIteration:    {Type: workitem.SimpleType{Kind: "iteration"}, Required: false, Label: "Iteration", Description: "The iteration to which the work item belongs"},
-		workitem.SystemArea:         {Type: workitem.SimpleType{Kind: "area"}, Required: false, Label: "Area", Description: "The area to which the work item belongs"},
+		workitem.SystemIteration:    {Type: workitem.SimpleType{Kind: "iteration"}, Required: false, Label: "Iteration", Description: "The iteration to which the work item belongs", Default: &workitem.FieldDefault{Kind: workitem.DefaultRootIteration}},
+		workitem.SystemArea:         {Type: workitem.SimpleType{Kind: "area"}, Required: false, Label: "Area", Description: "The area to which the work item belongs", Default: &workitem.FieldDefault{Kind: workitem.DefaultRootArea}},
 		workitem.SystemCodebase:     {Type: workitem.SimpleType{Kind: "codebase"}, Required: false, Label: "Codebase", Description: "Contains codebase attributes to which this WI belongs to"},
 		workitem.SystemAssignees: {
 			Type: &workitem.ListType{
@@ -713,6 +761,14 @@ func createOrUpdateSystemPlannerItemType(ctx context.Context, witr *workitem.Gor
 			Label:       "Assignees",
 			Description: "The users that are assigned to the work item",
 		},
+		workitem.SystemLabels: {
+			Type: &workitem.ListType{
+				SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+				ComponentType: workitem.SimpleType{Kind: workitem.KindLabel}},
+			Required:    false,
+			Label:       "Labels",
+			Description: "The labels attached to the work item",
+		},
 		workitem.SystemState: {
 			Type: &workitem.EnumType{
 				SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
@@ -803,6 +859,8 @@ func loadFields(ctx context.Context, wit *workitem.WorkItemType, into workitem.F
 				Description: value.Description,
 				Required:    into[key].Required,
 				Type:        into[key].Type,
+				Constraints: into[key].Constraints,
+				Default:     into[key].Default,
 			}
 		}
 	}
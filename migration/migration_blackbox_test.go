@@ -117,6 +117,22 @@ func TestMigrations(t *testing.T) {
 	t.Run("TestMigration57", testMigration57)
 	t.Run("TestMigration60", testMigration60)
 	t.Run("TestMigration61", testMigration61)
+	t.Run("TestMigration62", testMigration62)
+	t.Run("TestMigration63", testMigration63)
+	t.Run("TestMigration64", testMigration64)
+	t.Run("TestMigration65", testMigration65)
+	t.Run("TestMigration66", testMigration66)
+	t.Run("TestMigration67", testMigration67)
+	t.Run("TestMigration68", testMigration68)
+	t.Run("TestMigration69", testMigration69)
+	t.Run("TestMigration70", testMigration70)
+	t.Run("TestMigration71", testMigration71)
+	t.Run("TestMigration72", testMigration72)
+	t.Run("TestMigration73", testMigration73)
+	t.Run("TestMigration74", testMigration74)
+	t.Run("TestMigration75", testMigration75)
+	t.Run("TestMigration76", testMigration76)
+	t.Run("TestMigration77", testMigration77)
 
 	// Perform the migration
 	if err := migration.Migrate(sqlDB, databaseName); err != nil {
@@ -335,6 +351,131 @@ func testMigration61(t *testing.T) {
 
 }
 
+func testMigration62(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+18)], (initialMigratedVersion + 18))
+
+	assert.True(t, gormDB.HasTable("work_item_type_revisions"))
+	assert.True(t, dialect.HasIndex("work_item_type_revisions", "work_item_type_revisions_work_item_type_id_idx"))
+}
+
+func testMigration63(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+19)], (initialMigratedVersion + 19))
+
+	assert.True(t, dialect.HasColumn("work_item_types", "workflow"))
+	assert.True(t, dialect.HasColumn("work_item_type_revisions", "work_item_type_workflow"))
+}
+
+func testMigration64(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+20)], (initialMigratedVersion + 20))
+
+	assert.True(t, gormDB.HasTable("labels"))
+	assert.True(t, dialect.HasIndex("labels", "labels_name_space_id_unique"))
+}
+
+func testMigration65(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+21)], (initialMigratedVersion + 21))
+
+	assert.True(t, dialect.HasColumn("spaces", "key"))
+	assert.True(t, dialect.HasColumn("spaces", "work_item_counter"))
+	assert.True(t, dialect.HasColumn("work_items", "number"))
+	assert.True(t, dialect.HasIndex("work_items", "work_items_space_id_number_unique"))
+}
+
+func testMigration66(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+22)], (initialMigratedVersion + 22))
+
+	assert.True(t, gormDB.HasTable("work_item_templates"))
+	assert.True(t, dialect.HasIndex("work_item_templates", "work_item_templates_name_space_id_unique"))
+}
+
+func testMigration67(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+23)], (initialMigratedVersion + 23))
+
+	assert.True(t, gormDB.HasTable("work_item_checklist_items"))
+	assert.True(t, dialect.HasIndex("work_item_checklist_items", "ix_work_item_checklist_items_work_item_id"))
+}
+
+func testMigration68(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+24)], (initialMigratedVersion + 24))
+
+	assert.True(t, gormDB.HasTable("subscriptions"))
+	assert.True(t, dialect.HasIndex("subscriptions", "subscriptions_identity_id_kind_target_unique"))
+	assert.True(t, dialect.HasIndex("subscriptions", "ix_subscriptions_kind_target"))
+}
+
+func testMigration69(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+25)], (initialMigratedVersion + 25))
+
+	assert.True(t, gormDB.HasTable("domain_events"))
+	assert.True(t, dialect.HasIndex("domain_events", "ix_domain_events_unpublished"))
+}
+
+func testMigration76(t *testing.T) {
+	// fill DB with work item references stored as numbers
+	assert.Nil(t, runSQLscript(sqlDB, "076-workitem-reference-values.sql"))
+	// then apply the conversion
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+32)], (initialMigratedVersion + 32))
+	// and verify that the references are stored as strings
+	var duplicate, blocks string
+	err := sqlDB.QueryRow("select fields->'duplicate', fields->'blocks' from work_items where id = 12347").Scan(&duplicate, &blocks)
+	require.Nil(t, err)
+	assert.Equal(t, `"12"`, duplicate)
+	assert.Equal(t, `["13", "14"]`, blocks)
+}
+
+func testMigration77(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+33)], (initialMigratedVersion + 33))
+
+	assert.True(t, dialect.HasColumn("work_item_revisions", "space_id"))
+	assert.True(t, dialect.HasIndex("work_item_revisions", "ix_work_item_revisions_space_id"))
+}
+
+func testMigration70(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+26)], (initialMigratedVersion + 26))
+
+	assert.True(t, gormDB.HasTable("webhooks"))
+	assert.True(t, gormDB.HasTable("webhook_deliveries"))
+	assert.True(t, dialect.HasIndex("webhook_deliveries", "webhook_deliveries_webhook_id_event_id_unique"))
+	assert.True(t, dialect.HasIndex("webhook_deliveries", "ix_webhook_deliveries_due"))
+}
+
+func testMigration71(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+27)], (initialMigratedVersion + 27))
+
+	assert.True(t, dialect.HasColumn("users", "notification_mode"))
+	assert.True(t, gormDB.HasTable("notifications"))
+	assert.True(t, dialect.HasIndex("notifications", "notifications_identity_id_event_id_unique"))
+}
+
+func testMigration72(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+28)], (initialMigratedVersion + 28))
+
+	assert.True(t, gormDB.HasTable("mentions"))
+	assert.True(t, dialect.HasIndex("mentions", "mentions_kind_target_id_identity_id_unique"))
+}
+
+func testMigration73(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+29)], (initialMigratedVersion + 29))
+
+	assert.True(t, dialect.HasColumn("comments", "reply_to"))
+	assert.True(t, dialect.HasIndex("comments", "ix_comments_reply_to"))
+}
+
+func testMigration74(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+30)], (initialMigratedVersion + 30))
+
+	assert.True(t, gormDB.HasTable("reactions"))
+	assert.True(t, dialect.HasIndex("reactions", "reactions_kind_target_id_identity_id_content_unique"))
+}
+
+func testMigration75(t *testing.T) {
+	migrateToVersion(sqlDB, migrations[:(initialMigratedVersion+31)], (initialMigratedVersion + 31))
+
+	assert.True(t, dialect.HasColumn("domain_events", "attempts"))
+	assert.True(t, dialect.HasColumn("domain_events", "next_attempt_at"))
+	assert.True(t, dialect.HasIndex("domain_events", "ix_domain_events_unpublished"))
+}
+
 // runSQLscript loads the given filename from the packaged SQL test files and
 // executes it on the given database. Golang text/template module is used
 // to handle all the optional arguments passed to the sql test files
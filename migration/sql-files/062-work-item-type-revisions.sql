@@ -0,0 +1,22 @@
+-- create a revision table for work item types. Each row holds the definition
+-- of a work item type as it was before the update made at revision_time.
+CREATE TABLE work_item_type_revisions (
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    revision_time timestamp with time zone default current_timestamp,
+    modifier_id uuid NOT NULL,
+    work_item_type_id uuid NOT NULL,
+    work_item_type_version int NOT NULL,
+    work_item_type_name text NOT NULL,
+    work_item_type_description text,
+    work_item_type_icon text,
+    work_item_type_fields jsonb
+);
+
+CREATE INDEX work_item_type_revisions_work_item_type_id_idx ON work_item_type_revisions USING BTREE (work_item_type_id, revision_time);
+
+ALTER TABLE work_item_type_revisions
+    ADD CONSTRAINT work_item_type_revisions_modifier_id_fk FOREIGN KEY (modifier_id) REFERENCES identities(id);
+
+-- delete work item type revisions when the work item type is deleted from the database.
+ALTER TABLE work_item_type_revisions
+    ADD CONSTRAINT work_item_type_revisions_work_item_type_id_fk FOREIGN KEY (work_item_type_id) REFERENCES work_item_types(id) ON DELETE CASCADE;
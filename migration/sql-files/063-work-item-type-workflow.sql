@@ -0,0 +1,3 @@
+-- the optional workflow of the system.state field of a work item type
+ALTER TABLE work_item_types ADD COLUMN workflow jsonb;
+ALTER TABLE work_item_type_revisions ADD COLUMN work_item_type_workflow jsonb;
@@ -0,0 +1,18 @@
+-- labels are defined per space and attached to work items through the
+-- "system.labels" field which holds the IDs of the labels.
+CREATE TABLE labels (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    name text NOT NULL CHECK (name <> ''),
+    color text,
+    description text,
+    version integer DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX ix_labels_space_id ON labels USING btree (space_id);
+
+-- the name of a label must be unique within its space
+CREATE UNIQUE INDEX labels_name_space_id_unique ON labels (space_id, name) WHERE deleted_at IS NULL;
@@ -0,0 +1,19 @@
+-- every space can have a short key, e.g. PLAT, with which its work items can
+-- be addressed by their number within the space, e.g. PLAT-123.
+ALTER TABLE spaces ADD COLUMN key text CHECK (key ~ '^[A-Z][A-Z0-9]{1,9}$');
+CREATE UNIQUE INDEX spaces_key_unique ON spaces (key) WHERE deleted_at IS NULL;
+
+-- the last number given to a work item of the space
+ALTER TABLE spaces ADD COLUMN work_item_counter integer DEFAULT 0 NOT NULL;
+
+-- number the existing work items of each space in the order of their creation
+ALTER TABLE work_items ADD COLUMN number integer;
+UPDATE work_items SET number = numbered.number FROM (
+    SELECT id, row_number() OVER (PARTITION BY space_id ORDER BY id) AS number FROM work_items
+) AS numbered WHERE work_items.id = numbered.id;
+UPDATE spaces SET work_item_counter = counters.counter FROM (
+    SELECT space_id, max(number) AS counter FROM work_items GROUP BY space_id
+) AS counters WHERE spaces.id = counters.space_id;
+
+-- numbers are never reused, not even those of deleted work items
+CREATE UNIQUE INDEX work_items_space_id_number_unique ON work_items (space_id, number);
@@ -0,0 +1,25 @@
+-- work item templates are defined per space. The assignees and labels hold
+-- JSON arrays of IDs, the children hold a JSON array of the work items to
+-- create below the instantiated work item.
+CREATE TABLE work_item_templates (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    name text NOT NULL CHECK (name <> ''),
+    type uuid NOT NULL REFERENCES work_item_types (id) ON DELETE CASCADE,
+    title text NOT NULL CHECK (title <> ''),
+    description text,
+    markup text,
+    assignees jsonb,
+    area uuid REFERENCES areas (id) ON DELETE SET NULL,
+    labels jsonb,
+    children jsonb,
+    version integer DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX ix_work_item_templates_space_id ON work_item_templates USING btree (space_id);
+
+-- the name of a template must be unique within its space
+CREATE UNIQUE INDEX work_item_templates_name_space_id_unique ON work_item_templates (space_id, name) WHERE deleted_at IS NULL;
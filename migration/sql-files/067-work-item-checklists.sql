@@ -0,0 +1,17 @@
+-- checklist items are ordered entries of a work item which can be done and
+-- assigned to a user individually
+CREATE TABLE work_item_checklist_items (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    work_item_id bigint NOT NULL REFERENCES work_items (id) ON DELETE CASCADE,
+    text text NOT NULL CHECK (text <> ''),
+    done boolean DEFAULT false NOT NULL,
+    assignee uuid REFERENCES identities (id) ON DELETE SET NULL,
+    position integer DEFAULT 0 NOT NULL,
+    version integer DEFAULT 0 NOT NULL
+);
+
+-- the index also serves the filter on work items with open checklist items
+CREATE INDEX ix_work_item_checklist_items_work_item_id ON work_item_checklist_items USING btree (work_item_id, done) WHERE deleted_at IS NULL;
@@ -0,0 +1,18 @@
+-- subscriptions tell which users watch a work item, an area, an iteration, a
+-- space or a filter. The target holds the ID of the watched entity, or the
+-- query for a filter. Unsubscribing soft deletes the row, so that automatic
+-- subscriptions do not bring it back.
+CREATE TABLE subscriptions (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    identity_id uuid NOT NULL REFERENCES identities (id) ON DELETE CASCADE,
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    kind text NOT NULL CHECK (kind IN ('workitems', 'areas', 'iterations', 'spaces', 'filters')),
+    target text NOT NULL CHECK (target <> ''),
+    reason text NOT NULL
+);
+
+CREATE UNIQUE INDEX subscriptions_identity_id_kind_target_unique ON subscriptions USING btree (identity_id, kind, target);
+CREATE INDEX ix_subscriptions_kind_target ON subscriptions USING btree (kind, target) WHERE deleted_at IS NULL;
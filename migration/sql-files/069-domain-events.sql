@@ -0,0 +1,16 @@
+-- domain_events is the outbox of the domain events. An event is recorded in
+-- the transaction of the change it tells about and is marked as published
+-- once it was handed to the subscribers. The events outlive the entities they
+-- refer to, hence there are no foreign keys.
+CREATE TABLE domain_events (
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    seq bigserial NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    type text NOT NULL,
+    space_id uuid,
+    actor_id uuid,
+    payload jsonb NOT NULL,
+    published_at timestamp with time zone
+);
+
+CREATE INDEX ix_domain_events_unpublished ON domain_events USING btree (seq) WHERE published_at IS NULL;
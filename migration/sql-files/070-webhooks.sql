@@ -0,0 +1,36 @@
+-- webhooks tell to which URLs the events of a space are sent. events holds the
+-- JSON array of the filters of the events to send.
+CREATE TABLE webhooks (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    url text NOT NULL CHECK (url <> ''),
+    secret text NOT NULL CHECK (secret <> ''),
+    events jsonb NOT NULL,
+    version integer DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX ix_webhooks_space_id ON webhooks USING btree (space_id) WHERE deleted_at IS NULL;
+
+-- webhook_deliveries is the queue and the log of the events sent to the
+-- webhooks. An event is delivered at most once per webhook.
+CREATE TABLE webhook_deliveries (
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    webhook_id uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id uuid NOT NULL,
+    event_type text NOT NULL,
+    body jsonb NOT NULL,
+    state text NOT NULL CHECK (state IN ('pending', 'succeeded', 'failed')),
+    attempts integer NOT NULL DEFAULT 0,
+    status_code integer NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    next_attempt_at timestamp with time zone
+);
+
+CREATE UNIQUE INDEX webhook_deliveries_webhook_id_event_id_unique ON webhook_deliveries USING btree (webhook_id, event_id);
+CREATE INDEX ix_webhook_deliveries_webhook_id_created_at ON webhook_deliveries USING btree (webhook_id, created_at);
+CREATE INDEX ix_webhook_deliveries_due ON webhook_deliveries USING btree (next_attempt_at) WHERE state = 'pending';
@@ -0,0 +1,23 @@
+-- notification_mode tells whether a user gets an email for each change of what
+-- the user watches, an hourly or a daily digest, or no email at all.
+ALTER TABLE users ADD COLUMN notification_mode text NOT NULL DEFAULT 'immediate'
+    CHECK (notification_mode IN ('immediate', 'hourly', 'daily', 'none'));
+
+-- notifications are the changes a user is told about by email. A notification
+-- is sent on its own or along with the others in a digest, sent_at is set
+-- once it was sent.
+CREATE TABLE notifications (
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    identity_id uuid NOT NULL REFERENCES identities (id) ON DELETE CASCADE,
+    event_id uuid NOT NULL,
+    reason text NOT NULL,
+    work_item_id text NOT NULL,
+    subject text NOT NULL,
+    html text NOT NULL,
+    sent_at timestamp with time zone
+);
+
+-- an event is notified at most once to a user
+CREATE UNIQUE INDEX notifications_identity_id_event_id_unique ON notifications USING btree (identity_id, event_id);
+CREATE INDEX ix_notifications_unsent ON notifications USING btree (identity_id, created_at) WHERE sent_at IS NULL;
@@ -0,0 +1,15 @@
+-- mentions are the users mentioned with @username in the description of a
+-- work item or in a comment. work_item_id is the mentioning work item, or the
+-- parent of the mentioning comment.
+CREATE TABLE mentions (
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    identity_id uuid NOT NULL REFERENCES identities (id) ON DELETE CASCADE,
+    work_item_id text NOT NULL,
+    kind text NOT NULL CHECK (kind IN ('workitems', 'comments')),
+    target_id text NOT NULL
+);
+
+-- a user is mentioned at most once by a work item or a comment
+CREATE UNIQUE INDEX mentions_kind_target_id_identity_id_unique ON mentions USING btree (kind, target_id, identity_id);
+CREATE INDEX ix_mentions_identity_id ON mentions USING btree (identity_id, created_at);
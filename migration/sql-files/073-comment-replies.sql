@@ -0,0 +1,4 @@
+-- reply_to is the comment a comment replies to, if any. Replies belong to the
+-- same work item as the comment they reply to.
+ALTER TABLE comments ADD COLUMN reply_to uuid REFERENCES comments (id) ON DELETE CASCADE;
+CREATE INDEX ix_comments_reply_to ON comments USING btree (reply_to);
@@ -0,0 +1,14 @@
+-- reactions are the emoji reactions of users to work items and comments.
+-- target_id is the ID of the work item or comment reacted to.
+CREATE TABLE reactions (
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    identity_id uuid NOT NULL REFERENCES identities (id) ON DELETE CASCADE,
+    kind text NOT NULL CHECK (kind IN ('workitems', 'comments')),
+    target_id text NOT NULL,
+    content text NOT NULL CHECK (content IN ('+1', '-1', 'laugh', 'confused', 'heart', 'hooray', 'rocket', 'eyes'))
+);
+
+-- a user reacts at most once with the same content to a work item or a comment
+CREATE UNIQUE INDEX reactions_kind_target_id_identity_id_content_unique ON reactions USING btree (kind, target_id, identity_id, content);
+CREATE INDEX ix_reactions_kind_target_id_content ON reactions USING btree (kind, target_id, content);
@@ -0,0 +1,8 @@
+-- an event stays unpublished until every subscriber has handled it. The
+-- publication of an event whose handling failed is retried after a delay
+-- which grows with the number of failed attempts.
+ALTER TABLE domain_events ADD COLUMN attempts integer NOT NULL DEFAULT 0;
+ALTER TABLE domain_events ADD COLUMN next_attempt_at timestamp with time zone;
+
+DROP INDEX ix_domain_events_unpublished;
+CREATE INDEX ix_domain_events_unpublished ON domain_events USING btree (seq, next_attempt_at) WHERE published_at IS NULL;
@@ -0,0 +1,29 @@
+-- work item references used to be stored as numbers and are now stored as the
+-- string ID of the referenced work item, like the references to users,
+-- iterations and areas. Convert the stored values of the fields of kind
+-- "workitem" and of the lists of such references, so that the JSON
+-- containment filters keep matching them.
+DO $$
+DECLARE
+    f record;
+BEGIN
+    FOR f IN
+        SELECT wit.id AS type_id, field.key AS name, field.value->'Type'->>'Kind' AS kind
+        FROM work_item_types wit, jsonb_each(wit.fields) field
+        WHERE field.value->'Type'->>'Kind' = 'workitem'
+            OR (field.value->'Type'->>'Kind' = 'list' AND field.value->'Type'->'ComponentType'->>'Kind' = 'workitem')
+    LOOP
+        IF f.kind = 'workitem' THEN
+            UPDATE work_items
+            SET fields = jsonb_set(fields, ARRAY[f.name], to_jsonb(fields->>f.name))
+            WHERE type = f.type_id AND jsonb_typeof(fields->f.name) = 'number';
+        ELSE
+            UPDATE work_items
+            SET fields = jsonb_set(fields, ARRAY[f.name], (
+                SELECT jsonb_agg(CASE WHEN jsonb_typeof(e.value) = 'number' THEN to_jsonb(e.value::text) ELSE e.value END ORDER BY e.ordinality)
+                FROM jsonb_array_elements(fields->f.name) WITH ORDINALITY e))
+            WHERE type = f.type_id AND jsonb_typeof(fields->f.name) = 'array'
+                AND EXISTS (SELECT 1 FROM jsonb_array_elements(fields->f.name) e WHERE jsonb_typeof(e.value) = 'number');
+        END IF;
+    END LOOP;
+END $$;
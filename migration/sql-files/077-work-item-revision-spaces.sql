@@ -0,0 +1,6 @@
+-- space_id is the space the work item belonged to at the time of the
+-- revision, since work items can be moved to another space. The space of the
+-- existing revisions is not known and is assumed to be the current one.
+ALTER TABLE work_item_revisions ADD COLUMN space_id uuid REFERENCES spaces (id) ON DELETE CASCADE;
+UPDATE work_item_revisions r SET space_id = w.space_id FROM work_items w WHERE w.id = r.work_item_id;
+CREATE INDEX ix_work_item_revisions_space_id ON work_item_revisions USING btree (space_id);
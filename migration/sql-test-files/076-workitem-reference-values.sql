@@ -0,0 +1,3 @@
+insert into spaces (id, name) values ('11111111-2222-cccc-0000-000000000000', 'test references');
+insert into work_item_types (id, name, space_id, fields) values ('11111111-4444-cccc-0000-000000000000', 'Test references','11111111-2222-cccc-0000-000000000000', '{"duplicate": {"Required": false, "Label": "Duplicate", "Description": "", "Type": {"Kind": "workitem"}}, "blocks": {"Required": false, "Label": "Blocks", "Description": "", "Type": {"Kind": "list", "ComponentType": {"Kind": "workitem"}}}}'::json);
+insert into work_items (id, space_id, type, fields) values (12347, '11111111-2222-cccc-0000-000000000000', '11111111-4444-cccc-0000-000000000000', '{"system.title":"Title", "duplicate": 12, "blocks": [13, "14"]}'::json);
@@ -0,0 +1,11 @@
+// Package notification provides the email notifications of the changes of
+// what users watch: work items they are assigned to, state changes and new
+// comments of the work items, areas, iterations, spaces and filters they
+// subscribed to.
+//
+// The notifier subscribes to the domain events and stores a notification for
+// each user to tell. Depending on the notification mode of the user, the
+// notification is sent right away in the background, or along with the others
+// in an hourly or daily digest. The emails are sent through a Sender, e.g.
+// over SMTP.
+package notification
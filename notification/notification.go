@@ -0,0 +1,144 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The reasons why a user is notified
+const (
+	ReasonAssigned     = "assigned"
+	ReasonStateChanged = "state_changed"
+	ReasonComment      = "comment"
+	ReasonMentioned    = "mentioned"
+)
+
+// Notification tells a user about a change of a work item
+type Notification struct {
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	CreatedAt  time.Time
+	IdentityID uuid.UUID `sql:"type:uuid"`
+	EventID    uuid.UUID `sql:"type:uuid"`
+	Reason     string
+	WorkItemID string
+	Subject    string
+	// HTML tells about the change, it is the body of an immediate email or a
+	// section of a digest
+	HTML   string
+	SentAt *time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Notification) TableName() string {
+	return "notifications"
+}
+
+// Recipient is a user to notify
+type Recipient struct {
+	IdentityID uuid.UUID
+	Username   string
+	FullName   string
+	Email      string
+	Mode       string `gorm:"column:notification_mode"`
+}
+
+// Name returns the name to show for the recipient
+func (r Recipient) Name() string {
+	if r.FullName != "" {
+		return r.FullName
+	}
+	return r.Username
+}
+
+// Repository describes interactions with notifications
+type Repository interface {
+	Create(ctx context.Context, n *Notification) (bool, error)
+	ListUnsent(ctx context.Context, modes ...string) ([]Notification, error)
+	MarkSent(ctx context.Context, ids ...uuid.UUID) error
+	Recipients(ctx context.Context, identityIDs ...uuid.UUID) ([]Recipient, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormNotificationRepository{db: db}
+}
+
+// GormNotificationRepository is the implementation of the storage interface for notifications.
+type GormNotificationRepository struct {
+	db *gorm.DB
+}
+
+// Create stores the given notification unless the user was notified of the
+// same event already. It returns true if the notification was stored.
+// returns InternalError
+func (m *GormNotificationRepository) Create(ctx context.Context, n *Notification) (bool, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification", "create"}, time.Now())
+	n.ID = uuid.NewV4()
+	tx := m.db.Exec(`INSERT INTO notifications (id, created_at, identity_id, event_id, reason, work_item_id, subject, html)
+		VALUES (?, now(), ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (identity_id, event_id) DO NOTHING`,
+		n.ID, n.IdentityID, n.EventID, n.Reason, n.WorkItemID, n.Subject, n.HTML)
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"identity_id": n.IdentityID,
+			"event_id":    n.EventID,
+			"err":         tx.Error,
+		}, "unable to store the notification: %s", tx.Error.Error())
+		return false, errors.NewInternalError(tx.Error)
+	}
+	return tx.RowsAffected > 0, nil
+}
+
+// ListUnsent returns the notifications which were not sent yet to the users
+// with one of the given notification modes, by user and oldest first
+func (m *GormNotificationRepository) ListUnsent(ctx context.Context, modes ...string) ([]Notification, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification", "unsent"}, time.Now())
+	var objs []Notification
+	err := m.db.Raw(`SELECT n.* FROM notifications n
+		JOIN identities i ON i.id = n.identity_id
+		JOIN users u ON u.id = i.user_id
+		WHERE n.sent_at IS NULL AND u.notification_mode IN (?)
+		ORDER BY n.identity_id, n.created_at`, modes).Scan(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return objs, nil
+}
+
+// MarkSent records that the notifications with the given IDs were sent
+func (m *GormNotificationRepository) MarkSent(ctx context.Context, ids ...uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "notification", "sent"}, time.Now())
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := m.db.Exec("UPDATE notifications SET sent_at = now() WHERE id IN (?)", ids).Error; err != nil {
+		return errors.NewInternalError(err)
+	}
+	return nil
+}
+
+// Recipients returns the names, email addresses and notification modes of
+// the users with the given identities. Identities without a user are left
+// out.
+func (m *GormNotificationRepository) Recipients(ctx context.Context, identityIDs ...uuid.UUID) ([]Recipient, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification", "recipients"}, time.Now())
+	if len(identityIDs) == 0 {
+		return nil, nil
+	}
+	var objs []Recipient
+	err := m.db.Raw(`SELECT i.id AS identity_id, i.username, u.full_name, u.email, u.notification_mode
+		FROM identities i JOIN users u ON u.id = i.user_id
+		WHERE i.id IN (?) AND i.deleted_at IS NULL AND u.deleted_at IS NULL`, identityIDs).Scan(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return objs, nil
+}
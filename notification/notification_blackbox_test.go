@@ -0,0 +1,279 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/comment"
+	"github.com/fabric8io/almighty-core/event"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/migration"
+	"github.com/fabric8io/almighty-core/notification"
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestNotifier struct {
+	gormtestsupport.DBTestSuite
+	clean  func()
+	sender *notification.MemorySender
+	n      *notification.Notifier
+}
+
+func TestRunNotifier(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestNotifier{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestNotifier) SetupSuite() {
+	s.DBTestSuite.SetupSuite()
+	ctx := migration.NewMigrationContext(context.Background())
+	s.DBTestSuite.PopulateDBTestSuite(ctx)
+}
+
+func (s *TestNotifier) SetupTest() {
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	s.sender = notification.NewMemorySender()
+	s.n = notification.NewNotifier(s.DB, s.sender)
+}
+
+func (s *TestNotifier) TearDownTest() {
+	s.clean()
+}
+
+// createIdentity creates a user with the given notification mode and its identity
+func (s *TestNotifier) createIdentity(mode string) (account.Identity, account.User) {
+	name := "TestNotifier-" + uuid.NewV4().String()
+	user := account.User{Email: name + "@example.com", FullName: name, NotificationMode: mode}
+	require.Nil(s.T(), account.NewUserRepository(s.DB).Create(context.Background(), &user))
+	identity := account.Identity{Username: name, ProviderType: account.KeycloakIDP, UserID: account.NullUUID{UUID: user.ID, Valid: true}}
+	require.Nil(s.T(), account.NewIdentityRepository(s.DB).Create(context.Background(), &identity))
+	return identity, user
+}
+
+// createWorkItem creates a work item, which is watched by its creator and
+// its assignee
+func (s *TestNotifier) createWorkItem(creator, assignee account.Identity) *workitem.WorkItem {
+	wi, err := workitem.NewWorkItemRepository(s.DB).Create(context.Background(), space.SystemSpace, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle:     "Title",
+			workitem.SystemState:     workitem.SystemStateNew,
+			workitem.SystemAssignees: []string{assignee.ID.String()},
+		}, creator.ID)
+	require.Nil(s.T(), err)
+	return wi
+}
+
+func newEvent(t *testing.T, actorID uuid.UUID, payload event.Payload) event.Event {
+	b, err := json.Marshal(payload)
+	require.Nil(t, err)
+	return event.Event{
+		ID:        uuid.NewV4(),
+		CreatedAt: time.Now(),
+		Type:      payload.EventType(),
+		SpaceID:   space.SystemSpace,
+		ActorID:   actorID,
+		Payload:   string(b),
+	}
+}
+
+func (s *TestNotifier) TestHandle() {
+	s.T().Run("assigned", func(t *testing.T) {
+		// given
+		creator, _ := s.createIdentity(account.NotificationModeImmediate)
+		assignee, assigneeUser := s.createIdentity(account.NotificationModeImmediate)
+		wi := s.createWorkItem(creator, assignee)
+		e := newEvent(t, creator.ID, event.WorkItemCreated{
+			WorkItemID: wi.ID,
+			Number:     wi.Number,
+			Fields:     map[string]interface{}{workitem.SystemAssignees: []interface{}{assignee.ID.String()}},
+		})
+		require.Nil(t, s.n.Handle(context.Background(), e))
+		// the notification is queued rather than sent by the handler
+		require.Empty(t, s.sender.Messages())
+		// when
+		require.Nil(t, s.n.SendImmediate(context.Background()))
+		// then
+		messages := s.sender.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, assigneeUser.Email, messages[0].To)
+		assert.True(t, strings.HasPrefix(messages[0].Subject, "You were assigned"), messages[0].Subject)
+		assert.Contains(t, messages[0].HTML, creator.Username)
+	})
+
+	s.T().Run("once per event", func(t *testing.T) {
+		// given
+		s.sender = notification.NewMemorySender()
+		s.n = notification.NewNotifier(s.DB, s.sender)
+		creator, _ := s.createIdentity(account.NotificationModeImmediate)
+		assignee, _ := s.createIdentity(account.NotificationModeImmediate)
+		wi := s.createWorkItem(creator, assignee)
+		e := newEvent(t, creator.ID, event.WorkItemUpdated{
+			WorkItemID: wi.ID,
+			Changes: []event.FieldChange{
+				{Name: workitem.SystemAssignees, OldValue: []interface{}{}, NewValue: []interface{}{assignee.ID.String()}},
+				{Name: workitem.SystemState, OldValue: workitem.SystemStateNew, NewValue: workitem.SystemStateOpen},
+			},
+		})
+		// when the event is published twice
+		require.Nil(t, s.n.Handle(context.Background(), e))
+		require.Nil(t, s.n.Handle(context.Background(), e))
+		require.Nil(t, s.n.SendImmediate(context.Background()))
+		// then the assignee is told about the assignment only
+		messages := s.sender.Messages()
+		require.Len(t, messages, 1)
+		assert.True(t, strings.HasPrefix(messages[0].Subject, "You were assigned"), messages[0].Subject)
+	})
+
+	s.T().Run("comment", func(t *testing.T) {
+		// given
+		s.sender = notification.NewMemorySender()
+		s.n = notification.NewNotifier(s.DB, s.sender)
+		creator, _ := s.createIdentity(account.NotificationModeImmediate)
+		assignee, assigneeUser := s.createIdentity(account.NotificationModeImmediate)
+		wi := s.createWorkItem(creator, assignee)
+		e := newEvent(t, creator.ID, event.CommentAdded{
+			CommentID:  uuid.NewV4(),
+			WorkItemID: wi.ID,
+			Body:       "> **Done**",
+			Markup:     rendering.SystemMarkupMarkdown,
+		})
+		// when
+		require.Nil(t, s.n.Handle(context.Background(), e))
+		require.Nil(t, s.n.SendImmediate(context.Background()))
+		// then the creator, who commented, is not notified
+		messages := s.sender.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, assigneeUser.Email, messages[0].To)
+		assert.Contains(t, messages[0].HTML, "<blockquote>")
+		assert.Contains(t, messages[0].HTML, "<strong>Done</strong>")
+	})
+
+	s.T().Run("plain text comment", func(t *testing.T) {
+		// given
+		s.sender = notification.NewMemorySender()
+		s.n = notification.NewNotifier(s.DB, s.sender)
+		creator, _ := s.createIdentity(account.NotificationModeImmediate)
+		assignee, _ := s.createIdentity(account.NotificationModeImmediate)
+		wi := s.createWorkItem(creator, assignee)
+		e := newEvent(t, creator.ID, event.CommentAdded{
+			CommentID:  uuid.NewV4(),
+			WorkItemID: wi.ID,
+			Body:       "<b>Done</b>",
+			Markup:     rendering.SystemMarkupPlainText,
+		})
+		// when
+		require.Nil(t, s.n.Handle(context.Background(), e))
+		require.Nil(t, s.n.SendImmediate(context.Background()))
+		// then
+		messages := s.sender.Messages()
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0].HTML, "&lt;b&gt;Done&lt;/b&gt;")
+	})
+
+	s.T().Run("mentioned", func(t *testing.T) {
+		// given
+		s.sender = notification.NewMemorySender()
+		s.n = notification.NewNotifier(s.DB, s.sender)
+		creator, _ := s.createIdentity(account.NotificationModeImmediate)
+		assignee, assigneeUser := s.createIdentity(account.NotificationModeImmediate)
+		wi := s.createWorkItem(creator, assignee)
+		c := comment.Comment{ParentID: wi.ID, Body: "Ping @" + assignee.Username, Markup: rendering.SystemMarkupMarkdown}
+		require.Nil(t, comment.NewRepository(s.DB).Create(context.Background(), &c, creator.ID))
+		commented := newEvent(t, creator.ID, event.CommentAdded{CommentID: c.ID, WorkItemID: wi.ID, Body: c.Body, Markup: c.Markup})
+		mentioned := newEvent(t, creator.ID, event.UserMentioned{
+			WorkItemID:  wi.ID,
+			Kind:        mention.KindComment,
+			TargetID:    c.ID.String(),
+			IdentityIDs: []uuid.UUID{assignee.ID},
+			Body:        c.Body,
+			Markup:      c.Markup,
+		})
+		// when
+		require.Nil(t, s.n.Handle(context.Background(), commented))
+		require.Nil(t, s.n.Handle(context.Background(), mentioned))
+		require.Nil(t, s.n.SendImmediate(context.Background()))
+		// then the assignee, who watches the work item, is told about the mention only
+		messages := s.sender.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, assigneeUser.Email, messages[0].To)
+		assert.True(t, strings.HasPrefix(messages[0].Subject, "You were mentioned"), messages[0].Subject)
+		assert.Contains(t, messages[0].HTML, "@"+assignee.Username)
+	})
+
+	s.T().Run("none", func(t *testing.T) {
+		// given
+		s.sender = notification.NewMemorySender()
+		s.n = notification.NewNotifier(s.DB, s.sender)
+		creator, _ := s.createIdentity(account.NotificationModeImmediate)
+		assignee, _ := s.createIdentity(account.NotificationModeNone)
+		wi := s.createWorkItem(creator, assignee)
+		e := newEvent(t, creator.ID, event.WorkItemCreated{
+			WorkItemID: wi.ID,
+			Fields:     map[string]interface{}{workitem.SystemAssignees: []interface{}{assignee.ID.String()}},
+		})
+		// when
+		require.Nil(t, s.n.Handle(context.Background(), e))
+		require.Nil(t, s.n.SendImmediate(context.Background()))
+		// then
+		assert.Empty(t, s.sender.Messages())
+	})
+}
+
+func (s *TestNotifier) TestSendDigests() {
+	// given
+	creator, _ := s.createIdentity(account.NotificationModeImmediate)
+	assignee, assigneeUser := s.createIdentity(account.NotificationModeHourly)
+	wi := s.createWorkItem(creator, assignee)
+	s.n.Handle(context.Background(), newEvent(s.T(), creator.ID, event.WorkItemCreated{
+		WorkItemID: wi.ID,
+		Fields:     map[string]interface{}{workitem.SystemAssignees: []interface{}{assignee.ID.String()}},
+	}))
+	s.n.Handle(context.Background(), newEvent(s.T(), creator.ID, event.WorkItemUpdated{
+		WorkItemID: wi.ID,
+		Changes:    []event.FieldChange{{Name: workitem.SystemState, OldValue: workitem.SystemStateNew, NewValue: workitem.SystemStateOpen}},
+	}))
+	require.Empty(s.T(), s.sender.Messages())
+
+	s.T().Run("daily", func(t *testing.T) {
+		// when
+		err := s.n.SendDigests(context.Background(), account.NotificationModeDaily)
+		// then the hourly notifications are left
+		require.Nil(t, err)
+		assert.Empty(t, s.sender.Messages())
+	})
+
+	s.T().Run("hourly", func(t *testing.T) {
+		// when
+		err := s.n.SendDigests(context.Background(), account.NotificationModeHourly)
+		// then
+		require.Nil(t, err)
+		messages := s.sender.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, assigneeUser.Email, messages[0].To)
+		assert.Contains(t, messages[0].Subject, "2 changes")
+		assert.Contains(t, messages[0].HTML, "assigned you")
+		assert.Contains(t, messages[0].HTML, workitem.SystemStateOpen)
+	})
+
+	s.T().Run("sent once", func(t *testing.T) {
+		// when
+		err := s.n.SendDigests(context.Background(), account.NotificationModeHourly)
+		// then
+		require.Nil(t, err)
+		assert.Len(t, s.sender.Messages(), 1)
+	})
+}
@@ -0,0 +1,425 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/criteria"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/query"
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/subscription"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Notifier notifies the users of the changes of what they watch
+type Notifier struct {
+	db     *gorm.DB
+	sender Sender
+	wake   chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewNotifier creates a notifier which sends the emails with the given sender
+func NewNotifier(db *gorm.DB, sender Sender) *Notifier {
+	return &Notifier{db: db, sender: sender, wake: make(chan struct{}, 1)}
+}
+
+// Handle notifies the users concerned by the given event: the new assignees
+// of a work item, the watchers of a work item whose state changed or which
+// was commented, and the users mentioned in a description or a comment. The
+// user who made the change is not notified. It is meant to be subscribed to
+// the event bus. Handling an event again creates no further notifications,
+// since a notification is unique per user and event.
+func (n *Notifier) Handle(ctx context.Context, e event.Event) error {
+	p, err := e.Decode()
+	if err != nil {
+		// events of unknown types can never be handled
+		return nil
+	}
+	switch p := p.(type) {
+	case *event.WorkItemCreated:
+		return n.notify(ctx, e, p.WorkItemID, identities(p.Fields[workitem.SystemAssignees]), change{Reason: ReasonAssigned})
+	case *event.WorkItemUpdated:
+		for _, c := range p.Changes {
+			if c.Name == workitem.SystemAssignees {
+				if err := n.notify(ctx, e, p.WorkItemID, added(c.OldValue, c.NewValue), change{Reason: ReasonAssigned}); err != nil {
+					return err
+				}
+			}
+		}
+		for _, c := range p.Changes {
+			if c.Name == workitem.SystemState {
+				err := n.notifyWatchers(ctx, e, p.WorkItemID, change{
+					Reason:   ReasonStateChanged,
+					OldState: fmt.Sprint(c.OldValue),
+					NewState: fmt.Sprint(c.NewValue),
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	case *event.CommentAdded:
+		// the users mentioned in the comment are told about the mention instead
+		mentioned, err := mention.NewRepository(n.db).List(ctx, mention.KindComment, p.CommentID.String())
+		if err != nil {
+			return errs.Wrapf(err, "failed to list the users mentioned in comment %s", p.CommentID)
+		}
+		return n.notifyWatchers(ctx, e, p.WorkItemID, change{
+			Reason:  ReasonComment,
+			Comment: renderComment(p.Body, p.Markup),
+		}, mentioned...)
+	case *event.UserMentioned:
+		return n.notify(ctx, e, p.WorkItemID, p.IdentityIDs, change{
+			Reason:  ReasonMentioned,
+			Comment: renderComment(p.Body, p.Markup),
+		})
+	}
+	return nil
+}
+
+// renderComment renders the given comment body as HTML. Plain text is
+// escaped, while the HTML rendered from Markdown is already sanitized.
+func renderComment(body, markup string) template.HTML {
+	if markup == rendering.SystemMarkupPlainText {
+		body = html.EscapeString(body)
+	}
+	return template.HTML(rendering.RenderMarkupToHTML(body, markup))
+}
+
+// identities returns the IDs in the given value of a list field
+func identities(value interface{}) []uuid.UUID {
+	values, _ := value.([]interface{})
+	var result []uuid.UUID
+	for _, v := range values {
+		if id, err := uuid.FromString(fmt.Sprint(v)); err == nil {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// added returns the IDs which are in the new value of a list field but not
+// in the old one
+func added(oldValue, newValue interface{}) []uuid.UUID {
+	old := map[uuid.UUID]bool{}
+	for _, id := range identities(oldValue) {
+		old[id] = true
+	}
+	var result []uuid.UUID
+	for _, id := range identities(newValue) {
+		if !old[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// notifyWatchers notifies the users who watch the given work item, its area,
+// its iteration, its space or a filter which matches it, except the given
+// ones
+func (n *Notifier) notifyWatchers(ctx context.Context, e event.Event, workItemID string, c change, except ...uuid.UUID) error {
+	wi, err := n.loadWorkItem(ctx, workItemID)
+	if err != nil || wi == nil {
+		return err
+	}
+	targets := []subscription.Target{
+		{Kind: subscription.KindWorkItem, ID: wi.ID},
+		{Kind: subscription.KindSpace, ID: wi.SpaceID.String()},
+	}
+	if area, ok := wi.Fields[workitem.SystemArea].(string); ok {
+		targets = append(targets, subscription.Target{Kind: subscription.KindArea, ID: area})
+	}
+	if iteration, ok := wi.Fields[workitem.SystemIteration].(string); ok {
+		targets = append(targets, subscription.Target{Kind: subscription.KindIteration, ID: iteration})
+	}
+	subscriptions := subscription.NewRepository(n.db)
+	watchers, err := subscriptions.ListWatchers(ctx, targets...)
+	if err != nil {
+		return errs.Wrapf(err, "failed to list the watchers of work item %s", workItemID)
+	}
+	filters, err := subscriptions.ListFilters(ctx, wi.SpaceID)
+	if err != nil {
+		return errs.Wrapf(err, "failed to list the filters of space %s", wi.SpaceID)
+	}
+	for _, f := range filters {
+		matches, err := n.matches(ctx, *wi, f.Target)
+		if err != nil {
+			return errs.Wrapf(err, "failed to match work item %s against filter %s", workItemID, f.ID)
+		}
+		if matches {
+			watchers = append(watchers, f)
+		}
+	}
+	excluded := map[uuid.UUID]bool{}
+	for _, id := range except {
+		excluded[id] = true
+	}
+	var ids []uuid.UUID
+	for _, w := range watchers {
+		if !excluded[w.IdentityID] {
+			ids = append(ids, w.IdentityID)
+		}
+	}
+	return n.notify(ctx, e, workItemID, ids, c)
+}
+
+// matches returns true if the given work item matches the given query. A
+// query which cannot be parsed matches no work item.
+func (n *Notifier) matches(ctx context.Context, wi workitem.WorkItem, q string) (bool, error) {
+	exp, err := query.Parse(&q)
+	if err != nil {
+		return false, nil
+	}
+	exp = criteria.And(exp, criteria.Equals(criteria.Field("ID"), criteria.Literal(wi.ID)))
+	count, err := workitem.NewWorkItemRepository(n.db).Count(ctx, wi.SpaceID, exp)
+	if err != nil {
+		return false, errs.WithStack(err)
+	}
+	return count > 0, nil
+}
+
+// loadWorkItem returns the work item with the given ID, or nil if it does not
+// exist anymore, in which case there is nobody to notify
+func (n *Notifier) loadWorkItem(ctx context.Context, workItemID string) (*workitem.WorkItem, error) {
+	wi, err := workitem.NewWorkItemRepository(n.db).LoadByID(ctx, workItemID)
+	if err != nil {
+		if _, notFound := errs.Cause(err).(errors.NotFoundError); notFound {
+			return nil, nil
+		}
+		return nil, errs.Wrapf(err, "failed to load work item %s", workItemID)
+	}
+	return wi, nil
+}
+
+// notify tells the given users about the given change of the given work item
+func (n *Notifier) notify(ctx context.Context, e event.Event, workItemID string, identityIDs []uuid.UUID, c change) error {
+	if len(identityIDs) == 0 {
+		return nil
+	}
+	wi, err := n.loadWorkItem(ctx, workItemID)
+	if err != nil || wi == nil {
+		return err
+	}
+	repo := NewRepository(n.db)
+	c.Number = wi.Number
+	c.Title = fmt.Sprint(wi.Fields[workitem.SystemTitle])
+	c.Actor = "Someone"
+	actors, err := repo.Recipients(ctx, e.ActorID)
+	if err != nil {
+		return errs.Wrapf(err, "failed to load the author of event %s", e.ID)
+	}
+	if len(actors) > 0 {
+		c.Actor = actors[0].Name()
+	}
+	subject, err := renderSubject(c)
+	if err != nil {
+		return errs.Wrap(err, "failed to render the notification subject")
+	}
+	html, err := render("change", c)
+	if err != nil {
+		return errs.Wrap(err, "failed to render the notification")
+	}
+	recipients, err := repo.Recipients(ctx, identityIDs...)
+	if err != nil {
+		return errs.Wrapf(err, "failed to load the recipients of event %s", e.ID)
+	}
+	queued := false
+	for _, r := range recipients {
+		if uuid.Equal(r.IdentityID, e.ActorID) || r.Email == "" || r.Mode == account.NotificationModeNone {
+			continue
+		}
+		notification := Notification{
+			IdentityID: r.IdentityID,
+			EventID:    e.ID,
+			Reason:     c.Reason,
+			WorkItemID: workItemID,
+			Subject:    subject,
+			HTML:       html,
+		}
+		// creating the notification again when the event is published again
+		// has no effect
+		created, err := repo.Create(ctx, &notification)
+		if err != nil {
+			return errs.Wrapf(err, "failed to create the notification of event %s", e.ID)
+		}
+		if created && r.Mode == account.NotificationModeImmediate {
+			queued = true
+		}
+	}
+	if queued {
+		select {
+		case n.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// SendImmediate sends the notifications which were not sent yet to the users
+// with immediate notifications, one email per notification. A notification
+// which fails to be sent is sent again along with the next ones, or with the
+// next hourly digest.
+func (n *Notifier) SendImmediate(ctx context.Context) error {
+	repo := NewRepository(n.db)
+	notifications, err := repo.ListUnsent(ctx, account.NotificationModeImmediate)
+	if err != nil {
+		return err
+	}
+	recipients := map[uuid.UUID]*Recipient{}
+	for _, notification := range notifications {
+		r, ok := recipients[notification.IdentityID]
+		if !ok {
+			found, err := repo.Recipients(ctx, notification.IdentityID)
+			if err == nil && len(found) > 0 {
+				r = &found[0]
+			}
+			recipients[notification.IdentityID] = r
+		}
+		if r == nil {
+			continue
+		}
+		n.send(ctx, *r, notification.Subject, template.HTML(notification.HTML), notification.ID)
+	}
+	return nil
+}
+
+// send sends a single email to the given recipient and marks the given
+// notifications as sent
+func (n *Notifier) send(ctx context.Context, r Recipient, subject string, body template.HTML, ids ...uuid.UUID) {
+	html, err := render("immediate", body)
+	if err == nil {
+		err = n.sender.Send(ctx, Message{To: r.Email, Subject: subject, HTML: html})
+	}
+	if err == nil {
+		err = NewRepository(n.db).MarkSent(ctx, ids...)
+	}
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"identity_id": r.IdentityID,
+			"err":         err,
+		}, "unable to send the notification: %s", err.Error())
+	}
+}
+
+// SendDigests sends a digest of the notifications which were not sent yet to
+// each user with the given notification mode. The hourly digests include the
+// notifications of the users with immediate notifications which failed to be
+// sent.
+func (n *Notifier) SendDigests(ctx context.Context, mode string) error {
+	modes := []string{mode}
+	period := "last day"
+	if mode == account.NotificationModeHourly {
+		modes = append(modes, account.NotificationModeImmediate)
+		period = "last hour"
+	}
+	repo := NewRepository(n.db)
+	notifications, err := repo.ListUnsent(ctx, modes...)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(notifications); {
+		end := start + 1
+		for end < len(notifications) && uuid.Equal(notifications[end].IdentityID, notifications[start].IdentityID) {
+			end++
+		}
+		n.sendDigest(ctx, period, notifications[start:end])
+		start = end
+	}
+	return nil
+}
+
+func (n *Notifier) sendDigest(ctx context.Context, period string, notifications []Notification) {
+	recipients, err := NewRepository(n.db).Recipients(ctx, notifications[0].IdentityID)
+	if err != nil || len(recipients) == 0 {
+		return
+	}
+	r := recipients[0]
+	d := digest{Period: period}
+	ids := make([]uuid.UUID, len(notifications))
+	for i, notification := range notifications {
+		d.Changes = append(d.Changes, template.HTML(notification.HTML))
+		ids[i] = notification.ID
+	}
+	html, err := render("digest", d)
+	if err == nil {
+		subject := "Your digest: " + strconv.Itoa(len(notifications)) + " changes in the " + period
+		if len(notifications) == 1 {
+			subject = notifications[0].Subject
+		}
+		err = n.sender.Send(ctx, Message{To: r.Email, Subject: subject, HTML: html})
+	}
+	if err == nil {
+		err = NewRepository(n.db).MarkSent(ctx, ids...)
+	}
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"identity_id": r.IdentityID,
+			"err":         err,
+		}, "unable to send the digest: %s", err.Error())
+	}
+}
+
+// Start sends the immediate notifications as soon as they are queued, and the
+// hourly and the daily digests, until Stop is called
+func (n *Notifier) Start() {
+	n.stop = make(chan struct{})
+	n.done = make(chan struct{})
+	go func() {
+		defer close(n.done)
+		hourly := time.NewTicker(time.Hour)
+		defer hourly.Stop()
+		daily := time.NewTicker(24 * time.Hour)
+		defer daily.Stop()
+		for {
+			mode := account.NotificationModeHourly
+			select {
+			case <-n.stop:
+				return
+			case <-n.wake:
+				ctx := context.Background()
+				if err := n.SendImmediate(ctx); err != nil {
+					log.Error(ctx, map[string]interface{}{
+						"err": err,
+					}, "unable to send the notifications: %s", err.Error())
+				}
+				continue
+			case <-hourly.C:
+			case <-daily.C:
+				mode = account.NotificationModeDaily
+			}
+			ctx := context.Background()
+			if err := n.SendDigests(ctx, mode); err != nil {
+				log.Error(ctx, map[string]interface{}{
+					"mode": mode,
+					"err":  err,
+				}, "unable to send the digests: %s", err.Error())
+			}
+		}
+	}()
+}
+
+// Stop stops sending the notifications and the digests
+// This should be called only from main
+func (n *Notifier) Stop() {
+	if n.stop == nil {
+		return
+	}
+	close(n.stop)
+	<-n.done
+	n.stop = nil
+}
@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Message is an email to send
+type Message struct {
+	To      string
+	Subject string
+	// HTML is the body of the email
+	HTML string
+}
+
+// Sender sends emails
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// encode returns the given message as sent over the wire
+func encode(from string, m Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", m.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(m.HTML)
+	return b.Bytes()
+}
+
+// SMTPSender sends emails through an SMTP server
+type SMTPSender struct {
+	address string
+	auth    smtp.Auth
+	from    string
+}
+
+// NewSMTPSender creates a sender which sends emails from the given address
+// through the SMTP server at the given address, e.g. "localhost:25". Plain
+// authentication is used if a username is given.
+func NewSMTPSender(address, username, password, from string) *SMTPSender {
+	s := &SMTPSender{address: address, from: from}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(address)
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s
+}
+
+// Send implements Sender
+func (s *SMTPSender) Send(ctx context.Context, m Message) error {
+	err := smtp.SendMail(s.address, s.auth, s.from, []string{m.To}, encode(s.from, m))
+	return errs.Wrapf(err, "failed to send email to %s", m.To)
+}
+
+// FileSender writes the emails to files instead of sending them, which is
+// handy during development
+type FileSender struct {
+	dir  string
+	from string
+}
+
+// NewFileSender creates a sender which writes the emails to .eml files in
+// the given directory
+func NewFileSender(dir, from string) *FileSender {
+	return &FileSender{dir: dir, from: from}
+}
+
+// Send implements Sender
+func (s *FileSender) Send(ctx context.Context, m Message) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return errs.Wrapf(err, "failed to create directory %s", s.dir)
+	}
+	name := filepath.Join(s.dir, fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), uuid.NewV4()))
+	err := ioutil.WriteFile(name, encode(s.from, m), 0644)
+	return errs.Wrapf(err, "failed to write email to %s", name)
+}
+
+// MemorySender keeps the emails in memory instead of sending them, it is
+// meant for tests
+type MemorySender struct {
+	lock     sync.Mutex
+	messages []Message
+}
+
+// NewMemorySender creates a sender which keeps the emails in memory
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+// Send implements Sender
+func (s *MemorySender) Send(ctx context.Context, m Message) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.messages = append(s.messages, m)
+	return nil
+}
+
+// Messages returns the emails sent so far
+func (s *MemorySender) Messages() []Message {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Message{}, s.messages...)
+}
@@ -0,0 +1,79 @@
+package notification
+
+import (
+	"bytes"
+	"html/template"
+	text "text/template"
+
+	errs "github.com/pkg/errors"
+)
+
+// change holds what is told about a change of a work item
+type change struct {
+	Reason   string
+	Actor    string
+	Number   int
+	Title    string
+	OldState string
+	NewState string
+	// Comment is the rendered body of a new comment, or of the comment or
+	// description which mentions the user
+	Comment template.HTML
+}
+
+// subjects is a text template, so that the titles are not HTML escaped
+var subjects = text.Must(text.New("subject").Parse(`
+	{{- if eq .Reason "assigned"}}You were assigned to #{{.Number}} {{.Title}}
+	{{- else if eq .Reason "state_changed"}}#{{.Number}} {{.Title}} is now {{.NewState}}
+	{{- else if eq .Reason "comment"}}New comment on #{{.Number}} {{.Title}}
+	{{- else if eq .Reason "mentioned"}}You were mentioned in #{{.Number}} {{.Title}}
+	{{- end}}`))
+
+var templates = template.Must(template.New("notification").Parse(`
+{{define "change"}}<div class="change">
+<p>{{.Actor}}
+{{- if eq .Reason "assigned"}} assigned you to
+{{- else if eq .Reason "state_changed"}} moved
+{{- else if eq .Reason "comment"}} commented on
+{{- else if eq .Reason "mentioned"}} mentioned you in
+{{- end}} <strong>#{{.Number}} {{.Title}}</strong>
+{{- if eq .Reason "state_changed"}} from <em>{{.OldState}}</em> to <em>{{.NewState}}</em>{{end}}.</p>
+{{- if .Comment}}
+<blockquote>{{.Comment}}</blockquote>
+{{- end}}
+</div>
+{{end}}
+
+{{define "immediate"}}<html><body>
+{{.}}
+</body></html>
+{{end}}
+
+{{define "digest"}}<html><body>
+<p>Here is what changed in the {{.Period}} in what you watch:</p>
+{{range .Changes}}{{.}}{{end}}
+</body></html>
+{{end}}
+`))
+
+// digest holds what is told in a digest email
+type digest struct {
+	Period  string
+	Changes []template.HTML
+}
+
+func renderSubject(c change) (string, error) {
+	var b bytes.Buffer
+	if err := subjects.Execute(&b, c); err != nil {
+		return "", errs.Wrap(err, "failed to render the subject")
+	}
+	return b.String(), nil
+}
+
+func render(name string, data interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := templates.ExecuteTemplate(&b, name, data); err != nil {
+		return "", errs.Wrapf(err, "failed to render the %s template", name)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,38 @@
+// Package query implements the filter language used by the work item list
+// endpoints. A query is parsed into a criteria.Expression tree which can then
+// be compiled for the database.
+//
+// The grammar looks like this:
+//
+//	expression := or
+//	or         := and ( "OR" and )*
+//	and        := not ( "AND" not )*
+//	not        := "NOT" not | primary
+//	primary    := "(" expression ")" | comparison
+//	comparison := field op value
+//	            | field [ "NOT" ] "IN" "(" value ( "," value )* ")"
+//	            | field "IS" [ "NOT" ] "NULL"
+//	            | field "CONTAINS" string
+//	            | field "BETWEEN" value "AND" value
+//	op         := "=" | "==" | "!=" | "<" | "<=" | ">" | ">="
+//	value      := string | number | "true" | "false" | "null"
+//
+// Keywords are case insensitive. Fields are paths like system.state, strings
+// are delimited with either double or single quotes, for example:
+//
+//	system.state IN ("new", "open") AND NOT (system.title = 'foo' OR system.assignees IS NULL)
+//
+// CONTAINS is a case insensitive substring match. Strings which are ordered
+// with <, <=, >, >= or BETWEEN are compared as instants if they are a date in
+// RFC 3339 format or of the form 2006-01-02, for example:
+//
+//	system.updated_at >= "2017-06-01" AND storypoints BETWEEN 3 AND 8
+//
+// system.checklist.open is the number of checklist items of a work item which
+// are not done, for example:
+//
+//	system.checklist.open > 0
+//
+// For backwards compatibility a query that starts with "{" is treated as a
+// JSON object of field/value pairs which all have to match.
+package query
@@ -0,0 +1,182 @@
+package query
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of query"
+	case tokenIdent:
+		return "identifier"
+	case tokenString:
+		return "string"
+	case tokenNumber:
+		return "number"
+	case tokenOperator:
+		return "operator"
+	case tokenLeftParen:
+		return "'('"
+	case tokenRightParen:
+		return "')'"
+	case tokenComma:
+		return "','"
+	}
+	return "unknown token"
+}
+
+// token is a lexical element of a query. pos is the byte offset of the token
+// in the query string.
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// isKeyword tells if the token is the given keyword, ignoring case
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.value, keyword)
+}
+
+// lexer splits a query string into tokens
+type lexer struct {
+	input string
+	pos   int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: input}
+}
+
+// next returns the next token of the input or a ParseError when the input
+// contains something that is not a valid token.
+func (l *lexer) next() (token, error) {
+	l.skipWhitespace()
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+	start := l.pos
+	c := l.input[l.pos]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{kind: tokenLeftParen, value: "(", pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokenRightParen, value: ")", pos: start}, nil
+	case c == ',':
+		l.pos++
+		return token{kind: tokenComma, value: ",", pos: start}, nil
+	case c == '"' || c == '\'':
+		return l.lexString(c)
+	case c == '-' || isDigit(c):
+		return l.lexNumber()
+	case isIdentStart(c):
+		for l.pos < len(l.input) && isIdentPart(l.input[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokenIdent, value: l.input[start:l.pos], pos: start}, nil
+	case c == '=' || c == '!' || c == '<' || c == '>':
+		return l.lexOperator()
+	}
+	r, _ := utf8.DecodeRuneInString(l.input[l.pos:])
+	return token{}, newParseError(start, "unexpected character %q", r)
+}
+
+func (l *lexer) skipWhitespace() {
+	for l.pos < len(l.input) {
+		switch l.input[l.pos] {
+		case ' ', '\t', '\n', '\r':
+			l.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) lexString(quote byte) (token, error) {
+	start := l.pos
+	l.pos++ // opening quote
+	var value []byte
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		switch {
+		case c == '\\':
+			if l.pos+1 >= len(l.input) {
+				return token{}, newParseError(l.pos, "unterminated escape sequence")
+			}
+			value = append(value, l.input[l.pos+1])
+			l.pos += 2
+		case c == quote:
+			l.pos++
+			return token{kind: tokenString, value: string(value), pos: start}, nil
+		default:
+			value = append(value, c)
+			l.pos++
+		}
+	}
+	return token{}, newParseError(start, "unterminated string")
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	start := l.pos
+	if l.input[l.pos] == '-' {
+		l.pos++
+	}
+	digits := l.pos
+	for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+		l.pos++
+	}
+	if l.pos == digits {
+		return token{}, newParseError(start, "expected a number after '-'")
+	}
+	if l.pos < len(l.input) && l.input[l.pos] == '.' {
+		l.pos++
+		fraction := l.pos
+		for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+			l.pos++
+		}
+		if l.pos == fraction {
+			return token{}, newParseError(l.pos, "expected a digit after '.'")
+		}
+	}
+	return token{kind: tokenNumber, value: l.input[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) lexOperator() (token, error) {
+	start := l.pos
+	for _, op := range []string{"==", "!=", "<=", ">=", "=", "<", ">"} {
+		if strings.HasPrefix(l.input[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokenOperator, value: op, pos: start}, nil
+		}
+	}
+	return token{}, newParseError(start, "unexpected character %q", l.input[l.pos])
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.'
+}
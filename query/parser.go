@@ -0,0 +1,406 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	. "github.com/fabric8io/almighty-core/criteria"
+	legacy "github.com/fabric8io/almighty-core/query/simple"
+)
+
+// ParseError is returned when a query cannot be parsed. Position is the
+// 1-based character (not byte) offset in the query where the problem was
+// found.
+type ParseError struct {
+	Position int
+	Message  string
+}
+
+// Error implements the error interface
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+// newParseError returns a ParseError at the given byte offset, which Parse
+// turns into a character offset
+func newParseError(offset int, format string, args ...interface{}) ParseError {
+	return ParseError{Position: offset, Message: fmt.Sprintf(format, args...)}
+}
+
+// Parse parses the given query into an expression tree. See the package
+// documentation for the syntax. Returns the expression "true" if the query is
+// nil or empty.
+func Parse(exp *string) (Expression, error) {
+	if exp == nil || len(strings.TrimSpace(*exp)) == 0 {
+		return Literal(true), nil
+	}
+	if strings.HasPrefix(strings.TrimSpace(*exp), "{") {
+		return legacy.Parse(exp)
+	}
+	result, err := parse(*exp)
+	if pe, ok := err.(ParseError); ok {
+		pe.Position = utf8.RuneCountInString((*exp)[:pe.Position]) + 1
+		return nil, pe
+	}
+	return result, err
+}
+
+// parse parses the given query, the positions of the errors are byte offsets
+func parse(exp string) (Expression, error) {
+	p := parser{lexer: newLexer(exp)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.current.kind != tokenEOF {
+		return nil, p.unexpected("AND, OR or end of query")
+	}
+	return result, nil
+}
+
+// parser is a recursive descent parser with one token of lookahead
+type parser struct {
+	lexer   *lexer
+	current token
+}
+
+func (p *parser) advance() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.current = t
+	return nil
+}
+
+// unexpected reports the current token as not matching what was expected
+func (p *parser) unexpected(expected string) error {
+	if p.current.kind == tokenEOF {
+		return newParseError(p.current.pos, "expected %s but found end of query", expected)
+	}
+	return newParseError(p.current.pos, "expected %s but found %q", expected, p.current.value)
+}
+
+func (p *parser) expect(kind tokenKind) error {
+	if p.current.kind != kind {
+		return p.unexpected(kind.String())
+	}
+	return p.advance()
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.current.isKeyword("or") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.current.isKeyword("and") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = And(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expression, error) {
+	if p.current.isKeyword("not") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Negate(operand), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	if p.current.kind == tokenLeftParen {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		result, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRightParen); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expression, error) {
+	if p.current.kind != tokenIdent || isReserved(p.current.value) {
+		return nil, p.unexpected("field name or '('")
+	}
+	field := p.current.value
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.current.isKeyword("is"):
+		return p.parseIsNull(field)
+	case p.current.isKeyword("in"):
+		return p.parseIn(field)
+	case p.current.isKeyword("contains"):
+		return p.parseContains(field)
+	case p.current.isKeyword("between"):
+		return p.parseBetween(field)
+	case p.current.isKeyword("not"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if !p.current.isKeyword("in") {
+			return nil, p.unexpected("IN")
+		}
+		in, err := p.parseIn(field)
+		if err != nil {
+			return nil, err
+		}
+		return Negate(in), nil
+	case p.current.kind == tokenOperator:
+		op := p.current
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		valuePos := p.current.pos
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return comparison(field, op, value, valuePos)
+	}
+	return nil, p.unexpected("comparison operator, IN, IS, CONTAINS or BETWEEN")
+}
+
+// comparison builds the expression for "field op value"
+func comparison(field string, op token, value interface{}, valuePos int) (Expression, error) {
+	if value == nil {
+		switch op.value {
+		case "=", "==":
+			return IsNull(field), nil
+		case "!=":
+			return Negate(IsNull(field)), nil
+		}
+		return nil, newParseError(valuePos, "null can only be compared with = or !=")
+	}
+	switch op.value {
+	case "=", "==":
+		return Equals(Field(field), Literal(value)), nil
+	case "!=":
+		return Not(Field(field), Literal(value)), nil
+	case "<":
+		return LessThan(Field(field), Literal(orderedValue(value))), nil
+	case "<=":
+		return LessOrEqual(Field(field), Literal(orderedValue(value))), nil
+	case ">":
+		return GreaterThan(Field(field), Literal(orderedValue(value))), nil
+	case ">=":
+		return GreaterOrEqual(Field(field), Literal(orderedValue(value))), nil
+	}
+	return nil, newParseError(op.pos, "operator %q is not supported", op.value)
+}
+
+// dateLayouts are the formats of strings which are compared as instants
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+
+// orderedValue converts strings which look like a date to a time.Time so
+// that fields of kind instant can be compared with them. All other values
+// are returned unchanged.
+func orderedValue(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return value
+}
+
+// parseContains parses "CONTAINS string" for the given field; the current
+// token is the CONTAINS keyword.
+func (p *parser) parseContains(field string) (Expression, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.current.kind != tokenString {
+		return nil, p.unexpected(tokenString.String())
+	}
+	value := p.current.value
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return Contains(Field(field), Literal(value)), nil
+}
+
+// parseBetween parses "BETWEEN value AND value" for the given field; the
+// current token is the BETWEEN keyword.
+func (p *parser) parseBetween(field string) (Expression, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	lowerPos := p.current.pos
+	lower, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if !p.current.isKeyword("and") {
+		return nil, p.unexpected("AND")
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	upperPos := p.current.pos
+	upper, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if lower == nil {
+		return nil, newParseError(lowerPos, "null is not allowed as a bound of BETWEEN")
+	}
+	if upper == nil {
+		return nil, newParseError(upperPos, "null is not allowed as a bound of BETWEEN")
+	}
+	return Between(Field(field), Literal(orderedValue(lower)), Literal(orderedValue(upper))), nil
+}
+
+// parseIn parses "IN ( value, ... )" for the given field; the current token
+// is the IN keyword.
+func (p *parser) parseIn(field string) (Expression, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokenLeftParen); err != nil {
+		return nil, err
+	}
+	values := []Expression{}
+	for {
+		valuePos := p.current.pos
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, newParseError(valuePos, "null is not allowed in an IN list, use IS NULL instead")
+		}
+		values = append(values, Literal(value))
+		if p.current.kind != tokenComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(tokenRightParen); err != nil {
+		return nil, err
+	}
+	return In(Field(field), values...), nil
+}
+
+// parseIsNull parses "IS [NOT] NULL" for the given field; the current token
+// is the IS keyword.
+func (p *parser) parseIsNull(field string) (Expression, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	negated := false
+	if p.current.isKeyword("not") {
+		negated = true
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if !p.current.isKeyword("null") {
+		return nil, p.unexpected("NULL")
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if negated {
+		return Negate(IsNull(field)), nil
+	}
+	return IsNull(field), nil
+}
+
+// parseValue parses a literal value. A nil value represents null.
+func (p *parser) parseValue() (interface{}, error) {
+	t := p.current
+	var value interface{}
+	switch {
+	case t.kind == tokenString:
+		value = t.value
+	case t.kind == tokenNumber:
+		if strings.Contains(t.value, ".") {
+			f, err := strconv.ParseFloat(t.value, 64)
+			if err != nil {
+				return nil, newParseError(t.pos, "invalid number %q", t.value)
+			}
+			value = f
+		} else {
+			i, err := strconv.Atoi(t.value)
+			if err != nil {
+				return nil, newParseError(t.pos, "invalid number %q", t.value)
+			}
+			value = i
+		}
+	case t.isKeyword("true"):
+		value = true
+	case t.isKeyword("false"):
+		value = false
+	case t.isKeyword("null"):
+		value = nil
+	default:
+		return nil, p.unexpected("value")
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+var reservedWords = []string{"and", "or", "not", "in", "is", "null", "true", "false", "contains", "between"}
+
+// isReserved tells if the given identifier is a keyword of the query language
+func isReserved(ident string) bool {
+	for _, w := range reservedWords {
+		if strings.EqualFold(ident, w) {
+			return true
+		}
+	}
+	return false
+}
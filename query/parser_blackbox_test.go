@@ -0,0 +1,141 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/fabric8io/almighty-core/criteria"
+	"github.com/fabric8io/almighty-core/query"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, q string) Expression {
+	result, err := query.Parse(&q)
+	require.Nil(t, err, "could not parse %s", q)
+	return result
+}
+
+func TestParseEmpty(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, Literal(true), parse(t, ""))
+	assert.Equal(t, Literal(true), parse(t, "  "))
+	result, err := query.Parse(nil)
+	require.Nil(t, err)
+	assert.Equal(t, Literal(true), result)
+}
+
+func TestParseComparison(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, Equals(Field("system.state"), Literal("open")), parse(t, `system.state = "open"`))
+	assert.Equal(t, Equals(Field("system.state"), Literal("open")), parse(t, `system.state=='open'`))
+	assert.Equal(t, Not(Field("system.state"), Literal("open")), parse(t, `system.state != "open"`))
+	assert.Equal(t, Equals(Field("Version"), Literal(3)), parse(t, `Version = 3`))
+	assert.Equal(t, Equals(Field("effort"), Literal(-1.5)), parse(t, `effort = -1.5`))
+	assert.Equal(t, Equals(Field("flag"), Literal(true)), parse(t, `flag = TRUE`))
+	assert.Equal(t, Equals(Field("title"), Literal(`say "hi"`)), parse(t, `title = "say \"hi\""`))
+}
+
+func TestParseNull(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, IsNull("system.assignees"), parse(t, `system.assignees IS NULL`))
+	assert.Equal(t, IsNull("system.assignees"), parse(t, `system.assignees = null`))
+	assert.Equal(t, Negate(IsNull("system.assignees")), parse(t, `system.assignees is not null`))
+	assert.Equal(t, Negate(IsNull("system.assignees")), parse(t, `system.assignees != null`))
+}
+
+func TestParseIn(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expected := In(Field("system.state"), Literal("new"), Literal("open"), Literal("closed"))
+	assert.Equal(t, expected, parse(t, `system.state IN ("new", "open", "closed")`))
+	assert.Equal(t, Negate(In(Field("system.state"), Literal("new"))), parse(t, `system.state not in ("new")`))
+}
+
+func TestParseOrdering(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, GreaterThan(Field("storypoints"), Literal(5)), parse(t, `storypoints > 5`))
+	assert.Equal(t, GreaterOrEqual(Field("storypoints"), Literal(5)), parse(t, `storypoints >= 5`))
+	assert.Equal(t, LessThan(Field("effort"), Literal(2.5)), parse(t, `effort < 2.5`))
+	assert.Equal(t, LessOrEqual(Field("system.title"), Literal("m")), parse(t, `system.title <= "m"`))
+	date := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, GreaterOrEqual(Field("system.updated_at"), Literal(date)), parse(t, `system.updated_at >= "2017-06-01"`))
+	assert.Equal(t, LessThan(Field("system.updated_at"), Literal(date.Add(90*time.Minute))), parse(t, `system.updated_at < "2017-06-01T01:30:00Z"`))
+	// dates are only interpreted for ordering
+	assert.Equal(t, Equals(Field("system.title"), Literal("2017-06-01")), parse(t, `system.title = "2017-06-01"`))
+}
+
+func TestParseBetween(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, Between(Field("storypoints"), Literal(3), Literal(8)), parse(t, `storypoints BETWEEN 3 AND 8`))
+	from := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2017, 6, 8, 0, 0, 0, 0, time.UTC)
+	expected := And(Between(Field("system.created_at"), Literal(from), Literal(to)), Equals(Field("a"), Literal(1)))
+	assert.Equal(t, expected, parse(t, `system.created_at between "2017-06-01" and "2017-06-08" and a = 1`))
+}
+
+func TestParseContains(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, Contains(Field("system.title"), Literal("login")), parse(t, `system.title CONTAINS "login"`))
+	assert.Equal(t, Negate(Contains(Field("system.title"), Literal("login"))), parse(t, `NOT system.title contains 'login'`))
+}
+
+func TestParsePrecedence(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	a := func() Expression { return Equals(Field("a"), Literal(1)) }
+	b := func() Expression { return Equals(Field("b"), Literal(2)) }
+	c := func() Expression { return Equals(Field("c"), Literal(3)) }
+	assert.Equal(t, Or(a(), And(b(), c())), parse(t, `a = 1 OR b = 2 AND c = 3`))
+	assert.Equal(t, And(Or(a(), b()), c()), parse(t, `(a = 1 OR b = 2) AND c = 3`))
+	assert.Equal(t, And(Negate(a()), b()), parse(t, `NOT a = 1 AND b = 2`))
+	assert.Equal(t, Negate(And(a(), b())), parse(t, `not (a = 1 and b = 2)`))
+	assert.Equal(t, And(And(a(), b()), c()), parse(t, `a = 1 AND b = 2 AND c = 3`))
+}
+
+func TestParseLegacyJSON(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, Equals(Field("system.title"), Literal("run integration test")), parse(t, `{"system.title":"run integration test"}`))
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	testData := []struct {
+		query    string
+		position int
+	}{
+		{`system.state = `, 16},
+		{`system.state "open"`, 14},
+		{`(a = 1`, 7},
+		{`a = 1 b = 2`, 7},
+		{`a = "open`, 5},
+		{`a = 1 AND`, 10},
+		{`a IN ()`, 7},
+		{`a IN (1, null)`, 10},
+		{`a IS 5`, 6},
+		{`a < null`, 5},
+		{`a # 1`, 3},
+		{`a CONTAINS 5`, 12},
+		{`a BETWEEN 1 OR 5`, 13},
+		{`a BETWEEN null AND 5`, 11},
+		{`AND = 1`, 1},
+		{`system.title = "café" AND`, 26},
+	}
+	for _, d := range testData {
+		q := d.query
+		_, err := query.Parse(&q)
+		require.NotNil(t, err, "expected an error for %s", q)
+		parseErr, ok := err.(query.ParseError)
+		require.True(t, ok, "expected a ParseError for %s but got %T", q, err)
+		assert.Equal(t, d.position, parseErr.Position, "wrong position for %s: %s", q, err.Error())
+	}
+}
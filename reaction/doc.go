@@ -0,0 +1,5 @@
+// Package reaction provides the functions to manage the emoji reactions of
+// users to work items and comments, e.g. a +1 to vote for a feature request
+// without adding a comment. A user reacts at most once with the same emoji to
+// the same work item or comment, reacting again takes the reaction back.
+package reaction
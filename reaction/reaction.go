@@ -0,0 +1,170 @@
+package reaction
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeReactions is the JSON API type of reactions
+const APIStringTypeReactions = "reactions"
+
+// The kinds of things users react to. They are named like the JSON API types
+// of the entities.
+const (
+	KindWorkItem = "workitems"
+	KindComment  = "comments"
+)
+
+// The emojis users can react with
+const (
+	ContentPlusOne  = "+1"
+	ContentMinusOne = "-1"
+	ContentLaugh    = "laugh"
+	ContentConfused = "confused"
+	ContentHeart    = "heart"
+	ContentHooray   = "hooray"
+	ContentRocket   = "rocket"
+	ContentEyes     = "eyes"
+)
+
+// Contents holds all emojis users can react with
+var Contents = []string{
+	ContentPlusOne,
+	ContentMinusOne,
+	ContentLaugh,
+	ContentConfused,
+	ContentHeart,
+	ContentHooray,
+	ContentRocket,
+	ContentEyes,
+}
+
+// Reaction tells that a user reacted with an emoji to a work item or a comment
+type Reaction struct {
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	CreatedAt  time.Time
+	IdentityID uuid.UUID `sql:"type:uuid"`
+	Kind       string
+	// TargetID is the ID of the work item or comment reacted to
+	TargetID string
+	Content  string
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Reaction) TableName() string {
+	return "reactions"
+}
+
+// Counts holds the number of reactions to a work item or a comment by emoji,
+// emojis nobody reacted with are left out
+type Counts map[string]int
+
+// Repository describes interactions with reactions
+type Repository interface {
+	Toggle(ctx context.Context, kind string, targetID string, content string, identityID uuid.UUID) (bool, error)
+	Count(ctx context.Context, kind string, targetID string) (Counts, error)
+	ListContents(ctx context.Context, kind string, targetID string, identityID uuid.UUID) ([]string, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormReactionRepository{db: db}
+}
+
+// GormReactionRepository is the implementation of the storage interface for reactions.
+type GormReactionRepository struct {
+	db *gorm.DB
+}
+
+// Toggle adds the reaction of the given user with the given emoji to the given
+// work item or comment, or takes it back if the user already reacted so. It
+// returns whether the user has the reaction afterwards.
+// returns BadParameterError or InternalError
+func (m *GormReactionRepository) Toggle(ctx context.Context, kind string, targetID string, content string, identityID uuid.UUID) (bool, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "reaction", "toggle"}, time.Now())
+	if kind != KindWorkItem && kind != KindComment {
+		return false, errors.NewBadParameterError("kind", kind).Expected(KindWorkItem + " or " + KindComment)
+	}
+	if !isContent(content) {
+		return false, errors.NewBadParameterError("content", content).Expected(strings.Join(Contents, ", "))
+	}
+	tx := m.db.Where("kind = ? AND target_id = ? AND identity_id = ? AND content = ?", kind, targetID, identityID, content).Delete(Reaction{})
+	if tx.Error != nil {
+		return false, errors.NewInternalError(tx.Error)
+	}
+	if tx.RowsAffected > 0 {
+		return false, nil
+	}
+	tx = m.db.Exec(`INSERT INTO reactions (id, created_at, identity_id, kind, target_id, content)
+		VALUES (?, now(), ?, ?, ?, ?)
+		ON CONFLICT (kind, target_id, identity_id, content) DO NOTHING`,
+		uuid.NewV4(), identityID, kind, targetID, content)
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"identity_id": identityID,
+			"target_id":   targetID,
+			"content":     content,
+			"err":         tx.Error,
+		}, "unable to store the reaction: %s", tx.Error.Error())
+		return false, errors.NewInternalError(tx.Error)
+	}
+	return true, nil
+}
+
+func isContent(content string) bool {
+	for _, c := range Contents {
+		if c == content {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of reactions to the given work item or comment by
+// emoji
+// returns InternalError
+func (m *GormReactionRepository) Count(ctx context.Context, kind string, targetID string) (Counts, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "reaction", "count"}, time.Now())
+	rows, err := m.db.Model(&Reaction{}).Select("content, count(*)").
+		Where("kind = ? AND target_id = ?", kind, targetID).Group("content").Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	defer rows.Close()
+	result := Counts{}
+	for rows.Next() {
+		var content string
+		var count int
+		if err := rows.Scan(&content, &count); err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		result[content] = count
+	}
+	return result, nil
+}
+
+// ListContents returns the emojis the given user reacted with to the given
+// work item or comment
+// returns InternalError
+func (m *GormReactionRepository) ListContents(ctx context.Context, kind string, targetID string, identityID uuid.UUID) ([]string, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "reaction", "list"}, time.Now())
+	var objs []Reaction
+	err := m.db.Where("kind = ? AND target_id = ? AND identity_id = ?", kind, targetID, identityID).Order("created_at").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	result := make([]string, len(objs))
+	for i, obj := range objs {
+		result[i] = obj.Content
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package reaction_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/migration"
+	"github.com/fabric8io/almighty-core/reaction"
+	"github.com/fabric8io/almighty-core/resource"
+	testsupport "github.com/fabric8io/almighty-core/test"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestReactionRepository struct {
+	gormtestsupport.DBTestSuite
+	repo  reaction.Repository
+	clean func()
+	jdoe  account.Identity
+	ann   account.Identity
+}
+
+func TestRunReactionRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestReactionRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestReactionRepository) SetupSuite() {
+	s.DBTestSuite.SetupSuite()
+	ctx := migration.NewMigrationContext(context.Background())
+	s.DBTestSuite.PopulateDBTestSuite(ctx)
+}
+
+func (s *TestReactionRepository) SetupTest() {
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	s.repo = reaction.NewRepository(s.DB)
+	var err error
+	s.jdoe, err = testsupport.CreateTestIdentity(s.DB, "TestReactionRepository-"+uuid.NewV4().String(), "test")
+	require.Nil(s.T(), err)
+	s.ann, err = testsupport.CreateTestIdentity(s.DB, "TestReactionRepository-"+uuid.NewV4().String(), "test")
+	require.Nil(s.T(), err)
+}
+
+func (s *TestReactionRepository) TearDownTest() {
+	s.clean()
+}
+
+func (s *TestReactionRepository) TestToggle() {
+	ctx := context.Background()
+
+	s.T().Run("add and take back", func(t *testing.T) {
+		// given
+		targetID := uuid.NewV4().String()
+		// when
+		reacted, err := s.repo.Toggle(ctx, reaction.KindComment, targetID, reaction.ContentHeart, s.jdoe.ID)
+		// then
+		require.Nil(t, err)
+		assert.True(t, reacted)
+		contents, err := s.repo.ListContents(ctx, reaction.KindComment, targetID, s.jdoe.ID)
+		require.Nil(t, err)
+		assert.Equal(t, []string{reaction.ContentHeart}, contents)
+		// when
+		reacted, err = s.repo.Toggle(ctx, reaction.KindComment, targetID, reaction.ContentHeart, s.jdoe.ID)
+		// then
+		require.Nil(t, err)
+		assert.False(t, reacted)
+		contents, err = s.repo.ListContents(ctx, reaction.KindComment, targetID, s.jdoe.ID)
+		require.Nil(t, err)
+		assert.Empty(t, contents)
+	})
+
+	s.T().Run("unknown content", func(t *testing.T) {
+		// when
+		_, err := s.repo.Toggle(ctx, reaction.KindWorkItem, "1", "thumbsup", s.jdoe.ID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("unknown kind", func(t *testing.T) {
+		// when
+		_, err := s.repo.Toggle(ctx, "spaces", uuid.NewV4().String(), reaction.ContentPlusOne, s.jdoe.ID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *TestReactionRepository) TestCount() {
+	// given
+	ctx := context.Background()
+	targetID := uuid.NewV4().String()
+	toggle := func(content string, identityID uuid.UUID) {
+		_, err := s.repo.Toggle(ctx, reaction.KindWorkItem, targetID, content, identityID)
+		require.Nil(s.T(), err)
+	}
+	toggle(reaction.ContentPlusOne, s.jdoe.ID)
+	toggle(reaction.ContentPlusOne, s.ann.ID)
+	toggle(reaction.ContentEyes, s.ann.ID)
+	toggle(reaction.ContentRocket, s.ann.ID)
+	toggle(reaction.ContentRocket, s.ann.ID)
+	// when
+	counts, err := s.repo.Count(ctx, reaction.KindWorkItem, targetID)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), reaction.Counts{reaction.ContentPlusOne: 2, reaction.ContentEyes: 1}, counts)
+	// the reactions to comments are counted separately
+	counts, err = s.repo.Count(ctx, reaction.KindComment, targetID)
+	require.Nil(s.T(), err)
+	assert.Empty(s.T(), counts)
+}
@@ -1,7 +1,12 @@
 package rendering
 
 import (
+	"bytes"
+	"fmt"
+	"html"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/microcosm-cc/bluemonday"
 )
@@ -21,13 +26,130 @@ func RenderMarkupToHTML(content, markup string) string {
 	case SystemMarkupPlainText:
 		return content
 	case SystemMarkupMarkdown:
-		unsafe := MarkdownCommonHighlighter([]byte(content))
-		p := bluemonday.UGCPolicy()
-		p.AllowAttrs("class").Matching(regexp.MustCompile("^language-[a-zA-Z0-9]+$|prettyprint")).OnElements("code")
-		p.AllowAttrs("class").OnElements("span")
-		html := string(p.SanitizeBytes(unsafe))
-		return html
+		return renderMarkdown(content)
 	default:
 		return ""
 	}
 }
+
+// renderMarkdown converts the given Markdown content in sanitized HTML
+func renderMarkdown(content string) string {
+	unsafe := MarkdownCommonHighlighter([]byte(content))
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile("^language-[a-zA-Z0-9]+$|prettyprint")).OnElements("code")
+	p.AllowAttrs("class").OnElements("span")
+	return string(p.SanitizeBytes(unsafe))
+}
+
+// workItemKeyRegex matches the human-friendly IDs of work items, e.g. PLAT-123
+var workItemKeyRegex = regexp.MustCompile(`\b([A-Z][A-Z0-9]{1,9})-([1-9][0-9]*)\b`)
+
+// htmlTagRegex matches the start and end tags of HTML elements
+var htmlTagRegex = regexp.MustCompile(`<(/?)([a-zA-Z0-9]+)[^>]*>`)
+
+// LinkWorkItemKeys turns the human-friendly work item IDs like PLAT-123 in the
+// given HTML into links. The given function returns the URL to link a work
+// item ID to, or false if the ID must be left as it is, e.g. because there is
+// no space with the key. IDs within links and code are left as they are.
+func LinkWorkItemKeys(content string, linkFor func(spaceKey string, number int) (string, bool)) string {
+	return replaceInText(content, func(text string) string {
+		return linkWorkItemKeysInText(text, linkFor)
+	})
+}
+
+// replaceInText applies the given function to the text of the given HTML,
+// except to the text within links and code
+func replaceInText(content string, replace func(text string) string) string {
+	var result bytes.Buffer
+	// the number of open elements in which the text must be left as it is
+	skip := 0
+	pos := 0
+	for _, tag := range htmlTagRegex.FindAllStringSubmatchIndex(content, -1) {
+		if skip == 0 {
+			result.WriteString(replace(content[pos:tag[0]]))
+		} else {
+			result.WriteString(content[pos:tag[0]])
+		}
+		result.WriteString(content[tag[0]:tag[1]])
+		switch strings.ToLower(content[tag[4]:tag[5]]) {
+		case "a", "code", "pre":
+			if tag[3] > tag[2] {
+				if skip > 0 {
+					skip--
+				}
+			} else {
+				skip++
+			}
+		}
+		pos = tag[1]
+	}
+	if skip == 0 {
+		result.WriteString(replace(content[pos:]))
+	} else {
+		result.WriteString(content[pos:])
+	}
+	return result.String()
+}
+
+// linkWorkItemKeysInText links the human-friendly work item IDs in the given
+// text, which does not contain any HTML tags
+func linkWorkItemKeysInText(text string, linkFor func(spaceKey string, number int) (string, bool)) string {
+	return workItemKeyRegex.ReplaceAllStringFunc(text, func(wiKey string) string {
+		match := workItemKeyRegex.FindStringSubmatch(wiKey)
+		number, err := strconv.Atoi(match[2])
+		if err != nil {
+			return wiKey
+		}
+		url, ok := linkFor(match[1], number)
+		if !ok {
+			return wiKey
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), wiKey)
+	})
+}
+
+// mentionRegex matches the mentions of users, e.g. @jdoe, but not email
+// addresses. The first group is the character before the mention, the second
+// one is the username.
+var mentionRegex = regexp.MustCompile(`(^|[^\w.@/-])@([a-zA-Z0-9](?:[\w.-]*[a-zA-Z0-9])?)`)
+
+// LinkMentions turns the mentions of users like @jdoe in the given HTML into
+// links. The given function returns the URL to link a username to, or false
+// if the mention must be left as it is, e.g. because no such user was
+// mentioned. Mentions within links and code are left as they are.
+func LinkMentions(content string, linkFor func(username string) (string, bool)) string {
+	return replaceInText(content, func(text string) string {
+		return mentionRegex.ReplaceAllStringFunc(text, func(mention string) string {
+			match := mentionRegex.FindStringSubmatch(mention)
+			url, ok := linkFor(match[2])
+			if !ok {
+				return mention
+			}
+			return fmt.Sprintf(`%s<a class="mention" href="%s">@%s</a>`, match[1], html.EscapeString(url), match[2])
+		})
+	})
+}
+
+// Mentions returns the usernames mentioned in the given content, in the
+// order of their first mention. Mentions within links and code are ignored,
+// so are the mentions in content of an unsupported markup.
+func Mentions(content, markup string) []string {
+	var usernames []string
+	seen := map[string]bool{}
+	collect := func(text string) string {
+		for _, match := range mentionRegex.FindAllStringSubmatch(text, -1) {
+			if !seen[match[2]] {
+				seen[match[2]] = true
+				usernames = append(usernames, match[2])
+			}
+		}
+		return text
+	}
+	switch markup {
+	case SystemMarkupPlainText:
+		collect(content)
+	case SystemMarkupMarkdown:
+		replaceInText(renderMarkdown(content), collect)
+	}
+	return usernames
+}
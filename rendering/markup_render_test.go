@@ -1,6 +1,7 @@
 package rendering_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -41,3 +42,41 @@ func TestIsMarkupSupported(t *testing.T) {
 	assert.False(t, rendering.IsMarkupSupported(""))
 	assert.False(t, rendering.IsMarkupSupported("foo"))
 }
+
+func TestLinkWorkItemKeys(t *testing.T) {
+	linkFor := func(spaceKey string, number int) (string, bool) {
+		if spaceKey != "PLAT" {
+			return "", false
+		}
+		return fmt.Sprintf("/api/namedworkitems/%s-%d", spaceKey, number), true
+	}
+	content := rendering.RenderMarkupToHTML("Duplicate of PLAT-12, see `PLAT-13` and UTF-8", rendering.SystemMarkupMarkdown)
+	result := rendering.LinkWorkItemKeys(content, linkFor)
+	assert.Equal(t, "<p>Duplicate of <a href=\"/api/namedworkitems/PLAT-12\">PLAT-12</a>, see <code>PLAT-13</code> and UTF-8</p>\n", result)
+}
+
+func TestLinkMentions(t *testing.T) {
+	linkFor := func(username string) (string, bool) {
+		if username == "foo" || username == "nobody" {
+			return "", false
+		}
+		return "/api/users/" + username, true
+	}
+	content := rendering.RenderMarkupToHTML("Thanks @jdoe, see `@foo` and mail jdoe@example.com or @Ann_B, not @nobody.", rendering.SystemMarkupMarkdown)
+	result := rendering.LinkMentions(content, linkFor)
+	assert.Equal(t, "<p>Thanks <a class=\"mention\" href=\"/api/users/jdoe\">@jdoe</a>, see <code>@foo</code> and mail jdoe@example.com or <a class=\"mention\" href=\"/api/users/Ann_B\">@Ann_B</a>, not @nobody.</p>\n", result)
+}
+
+func TestMentions(t *testing.T) {
+	t.Run("markdown", func(t *testing.T) {
+		result := rendering.Mentions("@jdoe and @ann, not `@foo`\n\n```\n@bar\n```\n\nagain @jdoe", rendering.SystemMarkupMarkdown)
+		assert.Equal(t, []string{"jdoe", "ann"}, result)
+	})
+	t.Run("plain text", func(t *testing.T) {
+		result := rendering.Mentions("ping @jdoe (cc @ann.smith)", rendering.SystemMarkupPlainText)
+		assert.Equal(t, []string{"jdoe", "ann.smith"}, result)
+	})
+	t.Run("none", func(t *testing.T) {
+		assert.Empty(t, rendering.Mentions("mail jdoe@example.com", rendering.SystemMarkupPlainText))
+	})
+}
@@ -47,9 +47,17 @@ func generateSearchQuery(q string) (string, error) {
 type searchKeyword struct {
 	workItemTypes []uuid.UUID
 	id            []string
+	keys          []workItemKey
 	words         []string
 }
 
+// workItemKey is a human-friendly work item ID like PLAT-123, split into the
+// key of the space and the number of the work item
+type workItemKey struct {
+	spaceKey string
+	number   int
+}
+
 // KnownURL has a regex string format URL and compiled regex for the same
 type KnownURL struct {
 	URLRegex          string         // regex for URL, Exposed to make the code testable
@@ -213,7 +221,12 @@ func parseSearchString(rawSearchString string) (searchKeyword, error) {
 		// IF part is for search with id:1234
 		// TODO: need to find out the way to use ID fields.
 		if strings.HasPrefix(part, "id:") {
-			res.id = append(res.id, strings.TrimPrefix(part, "id:")+":*A")
+			id := strings.TrimPrefix(part, "id:")
+			if spaceKey, number, ok := workitem.ParseWorkItemKey(id); ok {
+				res.keys = append(res.keys, workItemKey{spaceKey: spaceKey, number: number})
+				continue
+			}
+			res.id = append(res.id, id+":*A")
 		} else if strings.HasPrefix(part, "type:") {
 			typeIDStr := strings.TrimPrefix(part, "type:")
 			if len(typeIDStr) == 0 {
@@ -252,8 +265,21 @@ func generateSQLSearchInfo(keywords searchKeyword) (sqlParameter string) {
 
 // extracted this function from List() in order to close the rows object with "defer" for more readability
 // workaround for https://github.com/lib/pq/issues/81
-func (r *GormSearchRepository) search(ctx context.Context, sqlSearchQueryParameter string, workItemTypes []uuid.UUID, start *int, limit *int, spaceID *string) ([]workitem.WorkItemStorage, uint64, error) {
-	db := r.db.Model(workitem.WorkItemStorage{}).Where("tsv @@ query")
+func (r *GormSearchRepository) search(ctx context.Context, sqlSearchQueryParameter string, workItemTypes []uuid.UUID, keys []workItemKey, start *int, limit *int, spaceID *string) ([]workitem.WorkItemStorage, uint64, error) {
+	db := r.db.Model(workitem.WorkItemStorage{})
+	if sqlSearchQueryParameter != "" || len(keys) == 0 {
+		db = db.Where("tsv @@ query")
+	}
+	if len(keys) > 0 {
+		// any of the work items with the given keys
+		conditions := make([]string, len(keys))
+		args := make([]interface{}, 0, 2*len(keys))
+		for i, key := range keys {
+			conditions[i] = fmt.Sprintf("(%[1]s.space_id IN (SELECT id FROM spaces WHERE key = ?) AND %[1]s.number = ?)", workitem.WorkItemStorage{}.TableName())
+			args = append(args, key.spaceKey, key.number)
+		}
+		db = db.Where(strings.Join(conditions, " OR "), args...)
+	}
 	if start != nil {
 		if *start < 0 {
 			return nil, 0, errors.NewBadParameterError("start", *start)
@@ -337,7 +363,7 @@ func (r *GormSearchRepository) SearchFullText(ctx context.Context, rawSearchStri
 
 	sqlSearchQueryParameter := generateSQLSearchInfo(parsedSearchDict)
 	var rows []workitem.WorkItemStorage
-	rows, count, err := r.search(ctx, sqlSearchQueryParameter, parsedSearchDict.workItemTypes, start, limit, spaceID)
+	rows, count, err := r.search(ctx, sqlSearchQueryParameter, parsedSearchDict.workItemTypes, parsedSearchDict.keys, start, limit, spaceID)
 	if err != nil {
 		return nil, 0, errs.WithStack(err)
 	}
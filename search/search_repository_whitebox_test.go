@@ -347,6 +347,19 @@ func TestParseSearchString(t *testing.T) {
 	assert.True(t, assert.ObjectsAreEqualValues(expectedSearchRes, op))
 }
 
+func TestParseSearchStringWithWorkItemKeys(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	input := "id:PLAT-123 id:42 id:plat-7 login"
+	op, _ := parseSearchString(input)
+	expectedSearchRes := searchKeyword{
+		id:    []string{"42:*A", "plat-7:*A"},
+		keys:  []workItemKey{{spaceKey: "PLAT", number: 123}},
+		words: []string{"login:*"},
+	}
+	assert.True(t, assert.ObjectsAreEqualValues(expectedSearchRes, op))
+}
+
 type searchTestData struct {
 	query    string
 	expected searchKeyword
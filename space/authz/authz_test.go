@@ -13,13 +13,19 @@ import (
 	"github.com/fabric8io/almighty-core/codebase"
 	"github.com/fabric8io/almighty-core/comment"
 	"github.com/fabric8io/almighty-core/iteration"
+	"github.com/fabric8io/almighty-core/label"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/reaction"
 	"github.com/fabric8io/almighty-core/resource"
 	"github.com/fabric8io/almighty-core/space"
 	"github.com/fabric8io/almighty-core/space/authz"
+	"github.com/fabric8io/almighty-core/subscription"
 	testsupport "github.com/fabric8io/almighty-core/test"
 	almtoken "github.com/fabric8io/almighty-core/token"
+	"github.com/fabric8io/almighty-core/webhook"
 	"github.com/fabric8io/almighty-core/workitem"
 	"github.com/fabric8io/almighty-core/workitem/link"
+	"github.com/fabric8io/almighty-core/workitem/template"
 	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -179,6 +185,51 @@ func (a *app) Codebases() codebase.Repository {
 	return nil
 }
 
+// WorkItemRevisions returns a work item revision repository
+func (a *app) WorkItemRevisions() workitem.RevisionRepository {
+	return nil
+}
+
+// Labels returns a label repository
+func (a *app) Labels() label.Repository {
+	return nil
+}
+
+// WorkItemTemplates returns a work item template repository
+func (a *app) WorkItemTemplates() template.Repository {
+	return nil
+}
+
+// ChecklistItems returns a checklist item repository
+func (a *app) ChecklistItems() workitem.ChecklistRepository {
+	return nil
+}
+
+// Subscriptions returns a subscription repository
+func (a *app) Subscriptions() subscription.Repository {
+	return nil
+}
+
+// Webhooks webhooks
+func (a *app) Webhooks() webhook.Repository {
+	return nil
+}
+
+// WebhookDeliveries webhook deliveries
+func (a *app) WebhookDeliveries() webhook.DeliveryRepository {
+	return nil
+}
+
+// Mentions returns a mention repository
+func (a *app) Mentions() mention.Repository {
+	return nil
+}
+
+// Reactions returns a reaction repository
+func (a *app) Reactions() reaction.Repository {
+	return nil
+}
+
 func (r *resourceRepo) Create(ctx context.Context, s *space.Resource) (*space.Resource, error) {
 	return nil, nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/fabric8io/almighty-core/convert"
 	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
 	"github.com/fabric8io/almighty-core/gormsupport"
 	"github.com/fabric8io/almighty-core/log"
 
@@ -28,6 +29,9 @@ type Space struct {
 	Name        string
 	Description string
 	OwnerId     uuid.UUID `sql:"type:uuid"` // Belongs To Identity
+	// Key is the optional short key of the space, e.g. PLAT, with which its
+	// work items can be addressed by their number, e.g. PLAT-123
+	Key *string
 }
 
 // Ensure Fields implements the Equaler interface
@@ -56,6 +60,9 @@ func (p Space) Equal(u convert.Equaler) bool {
 	if !uuid.Equal(p.OwnerId, other.OwnerId) {
 		return false
 	}
+	if (p.Key == nil) != (other.Key == nil) || (p.Key != nil && *p.Key != *other.Key) {
+		return false
+	}
 	return true
 }
 
@@ -77,6 +84,7 @@ type Repository interface {
 	Delete(ctx context.Context, ID uuid.UUID) error
 	LoadByOwner(ctx context.Context, userID *uuid.UUID, start *int, length *int) ([]Space, uint64, error)
 	LoadByOwnerAndName(ctx context.Context, userID *uuid.UUID, spaceName *string) (*Space, error)
+	LoadByKey(ctx context.Context, key string) (*Space, error)
 	List(ctx context.Context, start *int, length *int) ([]Space, uint64, error)
 	Search(ctx context.Context, q *string, start *int, length *int) ([]Space, uint64, error)
 }
@@ -132,7 +140,9 @@ func (r *GormRepository) Delete(ctx context.Context, ID uuid.UUID) error {
 		}, "none row was affected by the deletion operation")
 		return errors.NewNotFoundError("space", ID.String())
 	}
-
+	if err := event.Record(ctx, r.db, ID, event.ContextActor(ctx), event.SpaceDeleted{}); err != nil {
+		return errs.Wrapf(err, "error while deleting space")
+	}
 	return nil
 }
 
@@ -158,11 +168,21 @@ func (r *GormRepository) Save(ctx context.Context, p *Space) (*Space, error) {
 		if gormsupport.IsUniqueViolation(tx.Error, "spaces_name_idx") {
 			return nil, errors.NewBadParameterError("Name", p.Name).Expected("unique")
 		}
+		if gormsupport.IsCheckViolation(tx.Error, "spaces_key_check") {
+			return nil, errors.NewBadParameterError("Key", *p.Key).Expected("2 to 10 upper case letters or digits, starting with a letter")
+		}
+		if gormsupport.IsUniqueViolation(tx.Error, "spaces_key_unique") {
+			return nil, errors.NewBadParameterError("Key", *p.Key).Expected("unique")
+		}
 		return nil, errors.NewInternalError(err)
 	}
 	if tx.RowsAffected == 0 {
 		return nil, errors.NewVersionConflictError("version conflict")
 	}
+	updated := event.SpaceUpdated{Name: p.Name, Description: p.Description}
+	if err := event.Record(ctx, r.db, p.ID, event.ContextActor(ctx), updated); err != nil {
+		return nil, errs.Wrapf(err, "error while saving space")
+	}
 
 	log.Info(ctx, map[string]interface{}{
 		"space_id": p.ID,
@@ -186,8 +206,17 @@ func (r *GormRepository) Create(ctx context.Context, space *Space) (*Space, erro
 		if gormsupport.IsUniqueViolation(tx.Error, "spaces_name_idx") {
 			return nil, errors.NewBadParameterError("Name", space.Name).Expected("unique")
 		}
+		if gormsupport.IsCheckViolation(tx.Error, "spaces_key_check") {
+			return nil, errors.NewBadParameterError("Key", *space.Key).Expected("2 to 10 upper case letters or digits, starting with a letter")
+		}
+		if gormsupport.IsUniqueViolation(tx.Error, "spaces_key_unique") {
+			return nil, errors.NewBadParameterError("Key", *space.Key).Expected("unique")
+		}
 		return nil, errors.NewInternalError(err)
 	}
+	if err := event.Record(ctx, r.db, space.ID, space.OwnerId, event.SpaceCreated{Name: space.Name}); err != nil {
+		return nil, errs.Wrapf(err, "error while creating space")
+	}
 
 	log.Info(ctx, map[string]interface{}{
 		"space_id": space.ID,
@@ -315,3 +344,20 @@ func (r *GormRepository) LoadByOwnerAndName(ctx context.Context, userID *uuid.UU
 	}
 	return &res, nil
 }
+
+// LoadByKey returns the space with the given key
+// returns NotFoundError or InternalError
+func (r *GormRepository) LoadByKey(ctx context.Context, key string) (*Space, error) {
+	res := Space{}
+	tx := r.db.Where("key = ?", key).First(&res)
+	if tx.RecordNotFound() {
+		log.Error(ctx, map[string]interface{}{
+			"space_key": key,
+		}, "Could not find space with key")
+		return nil, errors.NewNotFoundError("space", key)
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error)
+	}
+	return &res, nil
+}
@@ -0,0 +1,6 @@
+// Package subscription provides the functions to manage which users watch
+// work items, areas, iterations, spaces or filters. Users subscribe
+// themselves, or are subscribed automatically when they create, are assigned
+// to or comment on a work item. The subscriptions tell whom to notify about
+// changes.
+package subscription
@@ -0,0 +1,231 @@
+package subscription
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/gormsupport"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/query"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeSubscriptions is the JSON API type of subscriptions
+const APIStringTypeSubscriptions = "subscriptions"
+
+// The kinds of things a user can watch. They are named like the JSON API
+// types of the watched entities.
+const (
+	KindWorkItem  = "workitems"
+	KindArea      = "areas"
+	KindIteration = "iterations"
+	KindSpace     = "spaces"
+	KindFilter    = "filters"
+)
+
+// The reasons why a user watches something
+const (
+	ReasonManual    = "manual"
+	ReasonCreator   = "creator"
+	ReasonAssignee  = "assignee"
+	ReasonCommenter = "commenter"
+)
+
+// Subscription tells that a user watches a work item, an area, an iteration,
+// a space or the work items of a space which match a filter
+type Subscription struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	IdentityID uuid.UUID `sql:"type:uuid"`
+	SpaceID    uuid.UUID `sql:"type:uuid"`
+	Kind       string
+	// Target is the ID of the watched entity, or the query of a filter
+	Target string
+	Reason string
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m Subscription) GetETagData() []interface{} {
+	return []interface{}{m.ID, m.Reason}
+}
+
+// GetLastModified returns the last modification time
+func (m Subscription) GetLastModified() time.Time {
+	return m.UpdatedAt.Truncate(time.Second)
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Subscription) TableName() string {
+	return "subscriptions"
+}
+
+// Target identifies something which can be watched
+type Target struct {
+	Kind string
+	ID   string
+}
+
+// Repository describes interactions with subscriptions
+type Repository interface {
+	Create(ctx context.Context, s *Subscription) error
+	List(ctx context.Context, identityID uuid.UUID) ([]Subscription, error)
+	Load(ctx context.Context, identityID uuid.UUID, id uuid.UUID) (*Subscription, error)
+	Delete(ctx context.Context, identityID uuid.UUID, id uuid.UUID) error
+	WatchWorkItem(ctx context.Context, workItemID string, identityIDs []uuid.UUID, reason string) error
+	ListWatchers(ctx context.Context, targets ...Target) ([]Subscription, error)
+	ListFilters(ctx context.Context, spaceID uuid.UUID) ([]Subscription, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormSubscriptionRepository{db: db}
+}
+
+// GormSubscriptionRepository is the implementation of the storage interface for subscriptions.
+type GormSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+// Create subscribes the user to the given target. Subscribing again to the
+// same target returns the existing subscription, resubscribing after an
+// unsubscription restores it.
+// returns BadParameterError or InternalError
+func (m *GormSubscriptionRepository) Create(ctx context.Context, s *Subscription) error {
+	defer goa.MeasureSince([]string{"goa", "db", "subscription", "create"}, time.Now())
+	switch s.Kind {
+	case KindWorkItem, KindArea, KindIteration, KindSpace:
+	case KindFilter:
+		if _, err := query.Parse(&s.Target); err != nil {
+			return errors.NewBadParameterError("filter", s.Target).Expected("valid query")
+		}
+	default:
+		return errors.NewBadParameterError("kind", s.Kind).Expected(KindWorkItem + ", " + KindArea + ", " + KindIteration + ", " + KindSpace + " or " + KindFilter)
+	}
+	if s.Target == "" {
+		return errors.NewBadParameterError("target", s.Target).Expected("not empty")
+	}
+	if s.Reason == "" {
+		s.Reason = ReasonManual
+	}
+	row := m.db.Raw(`INSERT INTO subscriptions (created_at, updated_at, id, identity_id, space_id, kind, target, reason)
+		VALUES (now(), now(), ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (identity_id, kind, target) DO UPDATE SET deleted_at = NULL, updated_at = now(),
+			reason = CASE WHEN subscriptions.deleted_at IS NULL THEN subscriptions.reason ELSE EXCLUDED.reason END
+		RETURNING id, created_at, updated_at, reason`,
+		uuid.NewV4(), s.IdentityID, s.SpaceID, s.Kind, s.Target, s.Reason).Row()
+	if err := row.Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt, &s.Reason); err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"identity_id": s.IdentityID,
+			"kind":        s.Kind,
+			"target":      s.Target,
+			"err":         err,
+		}, "error adding subscription: %s", err.Error())
+		return errors.NewInternalError(err)
+	}
+	return nil
+}
+
+// List returns the subscriptions of the given user, latest first
+func (m *GormSubscriptionRepository) List(ctx context.Context, identityID uuid.UUID) ([]Subscription, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "subscription", "query"}, time.Now())
+	var objs []Subscription
+	err := m.db.Where("identity_id = ?", identityID).Order("created_at desc").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return objs, nil
+}
+
+// Load returns the subscription of the given user with the given ID
+func (m *GormSubscriptionRepository) Load(ctx context.Context, identityID uuid.UUID, id uuid.UUID) (*Subscription, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "subscription", "get"}, time.Now())
+	var obj Subscription
+	tx := m.db.Where("identity_id = ? AND id = ?", identityID, id).First(&obj)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("subscription", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error)
+	}
+	return &obj, nil
+}
+
+// Delete unsubscribes the given user. The subscription is kept as deleted, so
+// that the user is not subscribed automatically to the same target again.
+func (m *GormSubscriptionRepository) Delete(ctx context.Context, identityID uuid.UUID, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "subscription", "delete"}, time.Now())
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("subscription", id.String())
+	}
+	tx := m.db.Where("identity_id = ?", identityID).Delete(&Subscription{ID: id})
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("subscription", id.String())
+	}
+	return nil
+}
+
+// WatchWorkItem subscribes the given users to the work item with the given
+// ID for the given reason. Users who watch the work item already, or who
+// unsubscribed from it, are left as they are, as are unknown users.
+func (m *GormSubscriptionRepository) WatchWorkItem(ctx context.Context, workItemID string, identityIDs []uuid.UUID, reason string) error {
+	defer goa.MeasureSince([]string{"goa", "db", "subscription", "watch"}, time.Now())
+	id, err := strconv.ParseUint(workItemID, 10, 64)
+	if err != nil || len(identityIDs) == 0 {
+		// not the ID of a work item, or nobody to subscribe
+		return nil
+	}
+	tx := m.db.Exec(`INSERT INTO subscriptions (created_at, updated_at, identity_id, space_id, kind, target, reason)
+		SELECT now(), now(), i.id, w.space_id, ?, w.id::text, ? FROM work_items w, identities i
+		WHERE w.id = ? AND w.deleted_at IS NULL AND i.id IN (?)
+		ON CONFLICT (identity_id, kind, target) DO NOTHING`,
+		KindWorkItem, reason, id, identityIDs)
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": workItemID,
+			"err":   tx.Error,
+		}, "unable to subscribe to the work item: %s", tx.Error.Error())
+		return errors.NewInternalError(tx.Error)
+	}
+	return nil
+}
+
+// ListWatchers returns the subscriptions to any of the given targets
+func (m *GormSubscriptionRepository) ListWatchers(ctx context.Context, targets ...Target) ([]Subscription, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "subscription", "watchers"}, time.Now())
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	conditions := make([]string, len(targets))
+	args := make([]interface{}, 0, 2*len(targets))
+	for i, t := range targets {
+		conditions[i] = "(kind = ? AND target = ?)"
+		args = append(args, t.Kind, t.ID)
+	}
+	var objs []Subscription
+	err := m.db.Where(strings.Join(conditions, " OR "), args...).Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return objs, nil
+}
+
+// ListFilters returns the subscriptions to filters of the given space
+func (m *GormSubscriptionRepository) ListFilters(ctx context.Context, spaceID uuid.UUID) ([]Subscription, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "subscription", "filters"}, time.Now())
+	var objs []Subscription
+	err := m.db.Where("space_id = ? AND kind = ?", spaceID, KindFilter).Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return objs, nil
+}
@@ -0,0 +1,199 @@
+package subscription_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/comment"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/migration"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/subscription"
+	testsupport "github.com/fabric8io/almighty-core/test"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSubscriptionRepository struct {
+	gormtestsupport.DBTestSuite
+	repo     subscription.Repository
+	clean    func()
+	creator  account.Identity
+	assignee account.Identity
+}
+
+func TestRunSubscriptionRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestSubscriptionRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (test *TestSubscriptionRepository) SetupSuite() {
+	test.DBTestSuite.SetupSuite()
+	ctx := migration.NewMigrationContext(context.Background())
+	test.DBTestSuite.PopulateDBTestSuite(ctx)
+}
+
+func (test *TestSubscriptionRepository) SetupTest() {
+	test.clean = cleaner.DeleteCreatedEntities(test.DB)
+	test.repo = subscription.NewRepository(test.DB)
+	var err error
+	test.creator, err = testsupport.CreateTestIdentity(test.DB, "TestSubscriptionRepository-"+uuid.NewV4().String(), "test")
+	require.Nil(test.T(), err)
+	test.assignee, err = testsupport.CreateTestIdentity(test.DB, "TestSubscriptionRepository-"+uuid.NewV4().String(), "test")
+	require.Nil(test.T(), err)
+}
+
+func (test *TestSubscriptionRepository) TearDownTest() {
+	test.clean()
+}
+
+func (test *TestSubscriptionRepository) createWorkItem() *workitem.WorkItem {
+	wi, err := workitem.NewWorkItemRepository(test.DB).Create(context.Background(), space.SystemSpace, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle:     "Title",
+			workitem.SystemState:     workitem.SystemStateNew,
+			workitem.SystemAssignees: []string{test.assignee.ID.String()},
+		}, test.creator.ID)
+	require.Nil(test.T(), err)
+	return wi
+}
+
+func (test *TestSubscriptionRepository) TestCreate() {
+	test.T().Run("ok", func(t *testing.T) {
+		// given
+		s := subscription.Subscription{IdentityID: test.creator.ID, SpaceID: space.SystemSpace, Kind: subscription.KindSpace, Target: space.SystemSpace.String()}
+		// when
+		err := test.repo.Create(context.Background(), &s)
+		// then
+		require.Nil(t, err)
+		assert.NotEqual(t, uuid.Nil, s.ID)
+		assert.Equal(t, subscription.ReasonManual, s.Reason)
+		loaded, err := test.repo.Load(context.Background(), test.creator.ID, s.ID)
+		require.Nil(t, err)
+		assert.Equal(t, subscription.KindSpace, loaded.Kind)
+	})
+
+	test.T().Run("twice", func(t *testing.T) {
+		// given
+		first := subscription.Subscription{IdentityID: test.creator.ID, SpaceID: space.SystemSpace, Kind: subscription.KindFilter, Target: `system.state = "open"`}
+		require.Nil(t, test.repo.Create(context.Background(), &first))
+		second := subscription.Subscription{IdentityID: test.creator.ID, SpaceID: space.SystemSpace, Kind: subscription.KindFilter, Target: `system.state = "open"`}
+		// when
+		err := test.repo.Create(context.Background(), &second)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, first.ID, second.ID)
+	})
+
+	test.T().Run("invalid filter", func(t *testing.T) {
+		// given
+		s := subscription.Subscription{IdentityID: test.creator.ID, SpaceID: space.SystemSpace, Kind: subscription.KindFilter, Target: "system.state = "}
+		// when
+		err := test.repo.Create(context.Background(), &s)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	test.T().Run("unknown kind", func(t *testing.T) {
+		// given
+		s := subscription.Subscription{IdentityID: test.creator.ID, SpaceID: space.SystemSpace, Kind: "foo", Target: "bar"}
+		// when
+		err := test.repo.Create(context.Background(), &s)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (test *TestSubscriptionRepository) TestAutomaticSubscriptions() {
+	test.T().Run("creator and assignees", func(t *testing.T) {
+		// when
+		wi := test.createWorkItem()
+		// then
+		watchers, err := test.repo.ListWatchers(context.Background(), subscription.Target{Kind: subscription.KindWorkItem, ID: wi.ID})
+		require.Nil(t, err)
+		reasons := map[uuid.UUID]string{}
+		for _, w := range watchers {
+			reasons[w.IdentityID] = w.Reason
+		}
+		assert.Equal(t, map[uuid.UUID]string{
+			test.creator.ID:  subscription.ReasonCreator,
+			test.assignee.ID: subscription.ReasonAssignee,
+		}, reasons)
+	})
+
+	test.T().Run("commenter", func(t *testing.T) {
+		// given
+		wi := test.createWorkItem()
+		commenter, err := testsupport.CreateTestIdentity(test.DB, "TestSubscriptionRepository-"+uuid.NewV4().String(), "test")
+		require.Nil(t, err)
+		// when
+		err = comment.NewRepository(test.DB).Create(context.Background(), &comment.Comment{ParentID: wi.ID, Body: "hello", CreatedBy: commenter.ID}, commenter.ID)
+		// then
+		require.Nil(t, err)
+		subscriptions, err := test.repo.List(context.Background(), commenter.ID)
+		require.Nil(t, err)
+		require.Len(t, subscriptions, 1)
+		assert.Equal(t, wi.ID, subscriptions[0].Target)
+		assert.Equal(t, subscription.ReasonCommenter, subscriptions[0].Reason)
+	})
+
+	test.T().Run("not again after unsubscribing", func(t *testing.T) {
+		// given
+		wi := test.createWorkItem()
+		subscriptions, err := test.repo.List(context.Background(), test.assignee.ID)
+		require.Nil(t, err)
+		for _, s := range subscriptions {
+			require.Nil(t, test.repo.Delete(context.Background(), test.assignee.ID, s.ID))
+		}
+		// when the work item is saved with the same assignee
+		_, err = workitem.NewWorkItemRepository(test.DB).Save(context.Background(), space.SystemSpace, *wi, test.creator.ID)
+		// then
+		require.Nil(t, err)
+		subscriptions, err = test.repo.List(context.Background(), test.assignee.ID)
+		require.Nil(t, err)
+		assert.Empty(t, subscriptions)
+	})
+}
+
+func (test *TestSubscriptionRepository) TestDelete() {
+	// given
+	s := subscription.Subscription{IdentityID: test.creator.ID, SpaceID: space.SystemSpace, Kind: subscription.KindSpace, Target: space.SystemSpace.String()}
+	require.Nil(test.T(), test.repo.Create(context.Background(), &s))
+
+	test.T().Run("other user", func(t *testing.T) {
+		// when
+		err := test.repo.Delete(context.Background(), test.assignee.ID, s.ID)
+		// then
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	test.T().Run("ok", func(t *testing.T) {
+		// when
+		err := test.repo.Delete(context.Background(), test.creator.ID, s.ID)
+		// then
+		require.Nil(t, err)
+		_, err = test.repo.Load(context.Background(), test.creator.ID, s.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	test.T().Run("resubscribe", func(t *testing.T) {
+		// given
+		again := subscription.Subscription{IdentityID: test.creator.ID, SpaceID: space.SystemSpace, Kind: subscription.KindSpace, Target: space.SystemSpace.String()}
+		// when
+		err := test.repo.Create(context.Background(), &again)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, s.ID, again.ID)
+		_, err = test.repo.Load(context.Background(), test.creator.ID, s.ID)
+		require.Nil(t, err)
+	})
+}
@@ -8,9 +8,15 @@ import (
 	"github.com/fabric8io/almighty-core/codebase"
 	"github.com/fabric8io/almighty-core/comment"
 	"github.com/fabric8io/almighty-core/iteration"
+	"github.com/fabric8io/almighty-core/label"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/reaction"
 	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/subscription"
+	"github.com/fabric8io/almighty-core/webhook"
 	"github.com/fabric8io/almighty-core/workitem"
 	"github.com/fabric8io/almighty-core/workitem/link"
+	"github.com/fabric8io/almighty-core/workitem/template"
 )
 
 func NewMockDB() *MockDB {
@@ -81,6 +87,51 @@ func (db *MockDB) Codebases() codebase.Repository {
 	return nil
 }
 
+// WorkItemRevisions returns a work item revision repository
+func (db *MockDB) WorkItemRevisions() workitem.RevisionRepository {
+	return nil
+}
+
+// Labels returns a label repository
+func (db *MockDB) Labels() label.Repository {
+	return nil
+}
+
+// WorkItemTemplates returns a work item template repository
+func (db *MockDB) WorkItemTemplates() template.Repository {
+	return nil
+}
+
+// ChecklistItems returns a checklist item repository
+func (db *MockDB) ChecklistItems() workitem.ChecklistRepository {
+	return nil
+}
+
+// Subscriptions returns a subscription repository
+func (db *MockDB) Subscriptions() subscription.Repository {
+	return nil
+}
+
+// Webhooks webhooks
+func (db *MockDB) Webhooks() webhook.Repository {
+	return nil
+}
+
+// WebhookDeliveries webhook deliveries
+func (db *MockDB) WebhookDeliveries() webhook.DeliveryRepository {
+	return nil
+}
+
+// Mentions returns a mention repository
+func (db *MockDB) Mentions() mention.Repository {
+	return nil
+}
+
+// Reactions returns a reaction repository
+func (db *MockDB) Reactions() reaction.Repository {
+	return nil
+}
+
 func (db *MockDB) Commit() error {
 	return nil
 }
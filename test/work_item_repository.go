@@ -3,6 +3,7 @@ package test
 
 import (
 	"sync"
+	"time"
 
 	"context"
 	"github.com/fabric8io/almighty-core/criteria"
@@ -137,6 +138,111 @@ type WorkItemRepository struct {
 		result1 map[string]workitem.WICountsPerIteration
 		result2 error
 	}
+	LoadAsOfStub        func(ctx context.Context, spaceID uuid.UUID, ID string, asOf time.Time) (*workitem.WorkItem, error)
+	loadAsOfMutex       sync.RWMutex
+	loadAsOfArgsForCall []struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+		ID      string
+		asOf    time.Time
+	}
+	loadAsOfReturns struct {
+		result1 *workitem.WorkItem
+		result2 error
+	}
+	ListAsOfStub        func(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, asOf time.Time, start *int, length *int) ([]workitem.WorkItem, uint64, error)
+	listAsOfMutex       sync.RWMutex
+	listAsOfArgsForCall []struct {
+		ctx          context.Context
+		spaceID      uuid.UUID
+		criteria     criteria.Expression
+		parentExists *bool
+		asOf         time.Time
+		start        *int
+		length       *int
+	}
+	listAsOfReturns struct {
+		result1 []workitem.WorkItem
+		result2 uint64
+		result3 error
+	}
+	ListSortedStub        func(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, sort string, start *int, length *int) ([]workitem.WorkItem, uint64, error)
+	listSortedMutex       sync.RWMutex
+	listSortedArgsForCall []struct {
+		ctx          context.Context
+		spaceID      uuid.UUID
+		criteria     criteria.Expression
+		parentExists *bool
+		sort         string
+		start        *int
+		length       *int
+	}
+	listSortedReturns struct {
+		result1 []workitem.WorkItem
+		result2 uint64
+		result3 error
+	}
+	RestoreStub        func(ctx context.Context, spaceID uuid.UUID, ID string, restorerID uuid.UUID) (*workitem.WorkItem, time.Time, error)
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		ID         string
+		restorerID uuid.UUID
+	}
+	restoreReturns struct {
+		result1 *workitem.WorkItem
+		result2 time.Time
+		result3 error
+	}
+	ListDeletedStub        func(ctx context.Context, spaceID uuid.UUID, since *time.Time, start *int, length *int) ([]workitem.WorkItem, uint64, error)
+	listDeletedMutex       sync.RWMutex
+	listDeletedArgsForCall []struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+		since   *time.Time
+		start   *int
+		length  *int
+	}
+	listDeletedReturns struct {
+		result1 []workitem.WorkItem
+		result2 uint64
+		result3 error
+	}
+	GetCountsPerLabelStub        func(ctx context.Context, spaceID uuid.UUID) (map[string]int, error)
+	getCountsPerLabelMutex       sync.RWMutex
+	getCountsPerLabelArgsForCall []struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+	}
+	getCountsPerLabelReturns struct {
+		result1 map[string]int
+		result2 error
+	}
+	DetachLabelStub        func(ctx context.Context, spaceID uuid.UUID, labelID uuid.UUID, modifierID uuid.UUID) error
+	detachLabelMutex       sync.RWMutex
+	detachLabelArgsForCall []struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		labelID    uuid.UUID
+		modifierID uuid.UUID
+	}
+	detachLabelReturns struct {
+		result1 error
+	}
+	MoveStub        func(ctx context.Context, spaceID uuid.UUID, wi workitem.WorkItem, targetSpaceID uuid.UUID, modifierID uuid.UUID) (*workitem.WorkItem, error)
+	moveMutex       sync.RWMutex
+	moveArgsForCall []struct {
+		ctx           context.Context
+		spaceID       uuid.UUID
+		wi            workitem.WorkItem
+		targetSpaceID uuid.UUID
+		modifierID    uuid.UUID
+	}
+	moveReturns struct {
+		result1 *workitem.WorkItem
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -507,6 +613,303 @@ func (fake *WorkItemRepository) GetCountsForIterationReturns(result1 map[string]
 	}{result1, result2}
 }
 
+func (fake *WorkItemRepository) LoadAsOf(ctx context.Context, spaceID uuid.UUID, ID string, asOf time.Time) (*workitem.WorkItem, error) {
+	fake.loadAsOfMutex.Lock()
+	fake.loadAsOfArgsForCall = append(fake.loadAsOfArgsForCall, struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+		ID      string
+		asOf    time.Time
+	}{ctx, spaceID, ID, asOf})
+	fake.recordInvocation("LoadAsOf", []interface{}{ctx, spaceID, ID, asOf})
+	fake.loadAsOfMutex.Unlock()
+	if fake.LoadAsOfStub != nil {
+		return fake.LoadAsOfStub(ctx, spaceID, ID, asOf)
+	}
+	return fake.loadAsOfReturns.result1, fake.loadAsOfReturns.result2
+}
+
+func (fake *WorkItemRepository) LoadAsOfCallCount() int {
+	fake.loadAsOfMutex.RLock()
+	defer fake.loadAsOfMutex.RUnlock()
+	return len(fake.loadAsOfArgsForCall)
+}
+
+func (fake *WorkItemRepository) LoadAsOfArgsForCall(i int) (context.Context, uuid.UUID, string, time.Time) {
+	fake.loadAsOfMutex.RLock()
+	defer fake.loadAsOfMutex.RUnlock()
+	return fake.loadAsOfArgsForCall[i].ctx, fake.loadAsOfArgsForCall[i].spaceID, fake.loadAsOfArgsForCall[i].ID, fake.loadAsOfArgsForCall[i].asOf
+}
+
+func (fake *WorkItemRepository) LoadAsOfReturns(result1 *workitem.WorkItem, result2 error) {
+	fake.LoadAsOfStub = nil
+	fake.loadAsOfReturns = struct {
+		result1 *workitem.WorkItem
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *WorkItemRepository) ListAsOf(ctx context.Context, spaceID uuid.UUID, c criteria.Expression, parentExists *bool, asOf time.Time, start *int, length *int) ([]workitem.WorkItem, uint64, error) {
+	fake.listAsOfMutex.Lock()
+	fake.listAsOfArgsForCall = append(fake.listAsOfArgsForCall, struct {
+		ctx          context.Context
+		spaceID      uuid.UUID
+		criteria     criteria.Expression
+		parentExists *bool
+		asOf         time.Time
+		start        *int
+		length       *int
+	}{ctx, spaceID, c, parentExists, asOf, start, length})
+	fake.recordInvocation("ListAsOf", []interface{}{ctx, spaceID, c, parentExists, asOf, start, length})
+	fake.listAsOfMutex.Unlock()
+	if fake.ListAsOfStub != nil {
+		return fake.ListAsOfStub(ctx, spaceID, c, parentExists, asOf, start, length)
+	}
+	return fake.listAsOfReturns.result1, fake.listAsOfReturns.result2, fake.listAsOfReturns.result3
+}
+
+func (fake *WorkItemRepository) ListAsOfCallCount() int {
+	fake.listAsOfMutex.RLock()
+	defer fake.listAsOfMutex.RUnlock()
+	return len(fake.listAsOfArgsForCall)
+}
+
+func (fake *WorkItemRepository) ListAsOfArgsForCall(i int) (context.Context, uuid.UUID, criteria.Expression, *bool, time.Time, *int, *int) {
+	fake.listAsOfMutex.RLock()
+	defer fake.listAsOfMutex.RUnlock()
+	return fake.listAsOfArgsForCall[i].ctx, fake.listAsOfArgsForCall[i].spaceID, fake.listAsOfArgsForCall[i].criteria, fake.listAsOfArgsForCall[i].parentExists, fake.listAsOfArgsForCall[i].asOf, fake.listAsOfArgsForCall[i].start, fake.listAsOfArgsForCall[i].length
+}
+
+func (fake *WorkItemRepository) ListAsOfReturns(result1 []workitem.WorkItem, result2 uint64, result3 error) {
+	fake.ListAsOfStub = nil
+	fake.listAsOfReturns = struct {
+		result1 []workitem.WorkItem
+		result2 uint64
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *WorkItemRepository) ListSorted(ctx context.Context, spaceID uuid.UUID, c criteria.Expression, parentExists *bool, sort string, start *int, length *int) ([]workitem.WorkItem, uint64, error) {
+	fake.listSortedMutex.Lock()
+	fake.listSortedArgsForCall = append(fake.listSortedArgsForCall, struct {
+		ctx          context.Context
+		spaceID      uuid.UUID
+		criteria     criteria.Expression
+		parentExists *bool
+		sort         string
+		start        *int
+		length       *int
+	}{ctx, spaceID, c, parentExists, sort, start, length})
+	fake.recordInvocation("ListSorted", []interface{}{ctx, spaceID, c, parentExists, sort, start, length})
+	fake.listSortedMutex.Unlock()
+	if fake.ListSortedStub != nil {
+		return fake.ListSortedStub(ctx, spaceID, c, parentExists, sort, start, length)
+	}
+	return fake.listSortedReturns.result1, fake.listSortedReturns.result2, fake.listSortedReturns.result3
+}
+
+func (fake *WorkItemRepository) ListSortedCallCount() int {
+	fake.listSortedMutex.RLock()
+	defer fake.listSortedMutex.RUnlock()
+	return len(fake.listSortedArgsForCall)
+}
+
+func (fake *WorkItemRepository) ListSortedArgsForCall(i int) (context.Context, uuid.UUID, criteria.Expression, *bool, string, *int, *int) {
+	fake.listSortedMutex.RLock()
+	defer fake.listSortedMutex.RUnlock()
+	return fake.listSortedArgsForCall[i].ctx, fake.listSortedArgsForCall[i].spaceID, fake.listSortedArgsForCall[i].criteria, fake.listSortedArgsForCall[i].parentExists, fake.listSortedArgsForCall[i].sort, fake.listSortedArgsForCall[i].start, fake.listSortedArgsForCall[i].length
+}
+
+func (fake *WorkItemRepository) ListSortedReturns(result1 []workitem.WorkItem, result2 uint64, result3 error) {
+	fake.ListSortedStub = nil
+	fake.listSortedReturns = struct {
+		result1 []workitem.WorkItem
+		result2 uint64
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *WorkItemRepository) Restore(ctx context.Context, spaceID uuid.UUID, ID string, restorerID uuid.UUID) (*workitem.WorkItem, time.Time, error) {
+	fake.restoreMutex.Lock()
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		ID         string
+		restorerID uuid.UUID
+	}{ctx, spaceID, ID, restorerID})
+	fake.recordInvocation("Restore", []interface{}{ctx, spaceID, ID, restorerID})
+	fake.restoreMutex.Unlock()
+	if fake.RestoreStub != nil {
+		return fake.RestoreStub(ctx, spaceID, ID, restorerID)
+	}
+	return fake.restoreReturns.result1, fake.restoreReturns.result2, fake.restoreReturns.result3
+}
+
+func (fake *WorkItemRepository) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *WorkItemRepository) RestoreArgsForCall(i int) (context.Context, uuid.UUID, string, uuid.UUID) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].ctx, fake.restoreArgsForCall[i].spaceID, fake.restoreArgsForCall[i].ID, fake.restoreArgsForCall[i].restorerID
+}
+
+func (fake *WorkItemRepository) RestoreReturns(result1 *workitem.WorkItem, result2 time.Time, result3 error) {
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 *workitem.WorkItem
+		result2 time.Time
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *WorkItemRepository) ListDeleted(ctx context.Context, spaceID uuid.UUID, since *time.Time, start *int, length *int) ([]workitem.WorkItem, uint64, error) {
+	fake.listDeletedMutex.Lock()
+	fake.listDeletedArgsForCall = append(fake.listDeletedArgsForCall, struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+		since   *time.Time
+		start   *int
+		length  *int
+	}{ctx, spaceID, since, start, length})
+	fake.recordInvocation("ListDeleted", []interface{}{ctx, spaceID, since, start, length})
+	fake.listDeletedMutex.Unlock()
+	if fake.ListDeletedStub != nil {
+		return fake.ListDeletedStub(ctx, spaceID, since, start, length)
+	}
+	return fake.listDeletedReturns.result1, fake.listDeletedReturns.result2, fake.listDeletedReturns.result3
+}
+
+func (fake *WorkItemRepository) ListDeletedCallCount() int {
+	fake.listDeletedMutex.RLock()
+	defer fake.listDeletedMutex.RUnlock()
+	return len(fake.listDeletedArgsForCall)
+}
+
+func (fake *WorkItemRepository) ListDeletedArgsForCall(i int) (context.Context, uuid.UUID, *time.Time, *int, *int) {
+	fake.listDeletedMutex.RLock()
+	defer fake.listDeletedMutex.RUnlock()
+	return fake.listDeletedArgsForCall[i].ctx, fake.listDeletedArgsForCall[i].spaceID, fake.listDeletedArgsForCall[i].since, fake.listDeletedArgsForCall[i].start, fake.listDeletedArgsForCall[i].length
+}
+
+func (fake *WorkItemRepository) ListDeletedReturns(result1 []workitem.WorkItem, result2 uint64, result3 error) {
+	fake.ListDeletedStub = nil
+	fake.listDeletedReturns = struct {
+		result1 []workitem.WorkItem
+		result2 uint64
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *WorkItemRepository) GetCountsPerLabel(ctx context.Context, spaceID uuid.UUID) (map[string]int, error) {
+	fake.getCountsPerLabelMutex.Lock()
+	fake.getCountsPerLabelArgsForCall = append(fake.getCountsPerLabelArgsForCall, struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+	}{ctx, spaceID})
+	fake.recordInvocation("GetCountsPerLabel", []interface{}{ctx, spaceID})
+	fake.getCountsPerLabelMutex.Unlock()
+	if fake.GetCountsPerLabelStub != nil {
+		return fake.GetCountsPerLabelStub(ctx, spaceID)
+	}
+	return fake.getCountsPerLabelReturns.result1, fake.getCountsPerLabelReturns.result2
+}
+
+func (fake *WorkItemRepository) GetCountsPerLabelCallCount() int {
+	fake.getCountsPerLabelMutex.RLock()
+	defer fake.getCountsPerLabelMutex.RUnlock()
+	return len(fake.getCountsPerLabelArgsForCall)
+}
+
+func (fake *WorkItemRepository) GetCountsPerLabelArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.getCountsPerLabelMutex.RLock()
+	defer fake.getCountsPerLabelMutex.RUnlock()
+	return fake.getCountsPerLabelArgsForCall[i].ctx, fake.getCountsPerLabelArgsForCall[i].spaceID
+}
+
+func (fake *WorkItemRepository) GetCountsPerLabelReturns(result1 map[string]int, result2 error) {
+	fake.GetCountsPerLabelStub = nil
+	fake.getCountsPerLabelReturns = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *WorkItemRepository) DetachLabel(ctx context.Context, spaceID uuid.UUID, labelID uuid.UUID, modifierID uuid.UUID) error {
+	fake.detachLabelMutex.Lock()
+	fake.detachLabelArgsForCall = append(fake.detachLabelArgsForCall, struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		labelID    uuid.UUID
+		modifierID uuid.UUID
+	}{ctx, spaceID, labelID, modifierID})
+	fake.recordInvocation("DetachLabel", []interface{}{ctx, spaceID, labelID, modifierID})
+	fake.detachLabelMutex.Unlock()
+	if fake.DetachLabelStub != nil {
+		return fake.DetachLabelStub(ctx, spaceID, labelID, modifierID)
+	}
+	return fake.detachLabelReturns.result1
+}
+
+func (fake *WorkItemRepository) DetachLabelCallCount() int {
+	fake.detachLabelMutex.RLock()
+	defer fake.detachLabelMutex.RUnlock()
+	return len(fake.detachLabelArgsForCall)
+}
+
+func (fake *WorkItemRepository) DetachLabelArgsForCall(i int) (context.Context, uuid.UUID, uuid.UUID, uuid.UUID) {
+	fake.detachLabelMutex.RLock()
+	defer fake.detachLabelMutex.RUnlock()
+	return fake.detachLabelArgsForCall[i].ctx, fake.detachLabelArgsForCall[i].spaceID, fake.detachLabelArgsForCall[i].labelID, fake.detachLabelArgsForCall[i].modifierID
+}
+
+func (fake *WorkItemRepository) DetachLabelReturns(result1 error) {
+	fake.DetachLabelStub = nil
+	fake.detachLabelReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *WorkItemRepository) Move(ctx context.Context, spaceID uuid.UUID, wi workitem.WorkItem, targetSpaceID uuid.UUID, modifierID uuid.UUID) (*workitem.WorkItem, error) {
+	fake.moveMutex.Lock()
+	fake.moveArgsForCall = append(fake.moveArgsForCall, struct {
+		ctx           context.Context
+		spaceID       uuid.UUID
+		wi            workitem.WorkItem
+		targetSpaceID uuid.UUID
+		modifierID    uuid.UUID
+	}{ctx, spaceID, wi, targetSpaceID, modifierID})
+	fake.recordInvocation("Move", []interface{}{ctx, spaceID, wi, targetSpaceID, modifierID})
+	fake.moveMutex.Unlock()
+	if fake.MoveStub != nil {
+		return fake.MoveStub(ctx, spaceID, wi, targetSpaceID, modifierID)
+	}
+	return fake.moveReturns.result1, fake.moveReturns.result2
+}
+
+func (fake *WorkItemRepository) MoveCallCount() int {
+	fake.moveMutex.RLock()
+	defer fake.moveMutex.RUnlock()
+	return len(fake.moveArgsForCall)
+}
+
+func (fake *WorkItemRepository) MoveArgsForCall(i int) (context.Context, uuid.UUID, workitem.WorkItem, uuid.UUID, uuid.UUID) {
+	fake.moveMutex.RLock()
+	defer fake.moveMutex.RUnlock()
+	return fake.moveArgsForCall[i].ctx, fake.moveArgsForCall[i].spaceID, fake.moveArgsForCall[i].wi, fake.moveArgsForCall[i].targetSpaceID, fake.moveArgsForCall[i].modifierID
+}
+
+func (fake *WorkItemRepository) MoveReturns(result1 *workitem.WorkItem, result2 error) {
+	fake.MoveStub = nil
+	fake.moveReturns = struct {
+		result1 *workitem.WorkItem
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *WorkItemRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -528,6 +931,22 @@ func (fake *WorkItemRepository) Invocations() map[string][][]interface{} {
 	defer fake.getCountsPerIterationMutex.RUnlock()
 	fake.getCountsForIterationMutex.RLock()
 	defer fake.getCountsForIterationMutex.RUnlock()
+	fake.loadAsOfMutex.RLock()
+	defer fake.loadAsOfMutex.RUnlock()
+	fake.listAsOfMutex.RLock()
+	defer fake.listAsOfMutex.RUnlock()
+	fake.listSortedMutex.RLock()
+	defer fake.listSortedMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	fake.listDeletedMutex.RLock()
+	defer fake.listDeletedMutex.RUnlock()
+	fake.getCountsPerLabelMutex.RLock()
+	defer fake.getCountsPerLabelMutex.RUnlock()
+	fake.detachLabelMutex.RLock()
+	defer fake.detachLabelMutex.RUnlock()
+	fake.moveMutex.RLock()
+	defer fake.moveMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeDeliveries is the JSON API type of webhook deliveries
+const APIStringTypeDeliveries = "webhookdeliveries"
+
+// The states of a delivery
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Delivery is the sending of an event to a webhook
+type Delivery struct {
+	ID        uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	WebhookID uuid.UUID `sql:"type:uuid"`
+	EventID   uuid.UUID `sql:"type:uuid"`
+	EventType string
+	// Body is the JSON document which is sent
+	Body     string `sql:"type:jsonb"`
+	State    string
+	Attempts int
+	// StatusCode is the HTTP status code of the response to the last attempt,
+	// or 0 if there was no response
+	StatusCode int
+	// Error tells why the last attempt failed
+	Error string
+	// NextAttemptAt is when the delivery is attempted next, it is nil once
+	// the delivery succeeded or failed
+	NextAttemptAt *time.Time
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m Delivery) GetETagData() []interface{} {
+	return []interface{}{m.ID, m.State, m.Attempts}
+}
+
+// GetLastModified returns the last modification time
+func (m Delivery) GetLastModified() time.Time {
+	return m.UpdatedAt.Truncate(time.Second)
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// DeliveryRepository describes interactions with webhook deliveries
+type DeliveryRepository interface {
+	Enqueue(ctx context.Context, d *Delivery) error
+	List(ctx context.Context, webhookID uuid.UUID, start *int, limit *int) ([]Delivery, uint64, error)
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error)
+	Save(ctx context.Context, d Delivery) error
+}
+
+// NewDeliveryRepository creates a new storage type.
+func NewDeliveryRepository(db *gorm.DB) DeliveryRepository {
+	return &GormDeliveryRepository{db: db}
+}
+
+// GormDeliveryRepository is the implementation of the storage interface for webhook deliveries.
+type GormDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// Enqueue queues the given delivery to be attempted right away. An event is
+// queued at most once per webhook, even if it is published more than once.
+// returns InternalError
+func (m *GormDeliveryRepository) Enqueue(ctx context.Context, d *Delivery) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhookdelivery", "enqueue"}, time.Now())
+	d.ID = uuid.NewV4()
+	d.State = DeliveryPending
+	tx := m.db.Exec(`INSERT INTO webhook_deliveries (id, created_at, updated_at, webhook_id, event_id, event_type, body, state, attempts, status_code, error, next_attempt_at)
+		VALUES (?, now(), now(), ?, ?, ?, ?, ?, 0, 0, '', now())
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`,
+		d.ID, d.WebhookID, d.EventID, d.EventType, d.Body, d.State)
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"webhook_id": d.WebhookID,
+			"event_id":   d.EventID,
+			"err":        tx.Error,
+		}, "unable to queue the webhook delivery: %s", tx.Error.Error())
+		return errors.NewInternalError(tx.Error)
+	}
+	return nil
+}
+
+// List returns the deliveries of the given webhook, latest first, along with
+// their total count
+func (m *GormDeliveryRepository) List(ctx context.Context, webhookID uuid.UUID, start *int, limit *int) ([]Delivery, uint64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhookdelivery", "query"}, time.Now())
+	db := m.db.Model(&Delivery{}).Where("webhook_id = ?", webhookID)
+	var count uint64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(err)
+	}
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		db = db.Offset(*start)
+	}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		db = db.Limit(*limit)
+	}
+	var objs []Delivery
+	err := db.Order("created_at desc").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, 0, errors.NewInternalError(err)
+	}
+	return objs, count, nil
+}
+
+// Claim returns the pending deliveries which are due, picking the most
+// overdue first, and postpones their next attempt by the given lease so that they are
+// not claimed again while they are being attempted. Deliveries claimed
+// concurrently by another dispatcher are skipped.
+// returns InternalError
+func (m *GormDeliveryRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhookdelivery", "claim"}, time.Now())
+	var objs []Delivery
+	err := m.db.Raw(`UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id IN (
+			SELECT id FROM webhook_deliveries WHERE state = ? AND next_attempt_at <= now()
+			ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED)
+		RETURNING *`, time.Now().Add(lease), DeliveryPending, limit).Scan(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "unable to claim the due webhook deliveries: %s", err.Error())
+		return nil, errors.NewInternalError(err)
+	}
+	return objs, nil
+}
+
+// Save stores the outcome of an attempt of the given delivery
+// returns InternalError
+func (m *GormDeliveryRepository) Save(ctx context.Context, d Delivery) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhookdelivery", "save"}, time.Now())
+	if err := m.db.Save(&d).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"delivery_id": d.ID,
+			"err":         err,
+		}, "unable to save the webhook delivery")
+		return errors.NewInternalError(err)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fabric8io/almighty-core/event"
+	"github.com/fabric8io/almighty-core/log"
+
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The headers sent along with each delivery
+const (
+	HeaderEvent     = "X-Almighty-Event"
+	HeaderDelivery  = "X-Almighty-Delivery"
+	HeaderSignature = "X-Hub-Signature-256"
+)
+
+// deliveryBatchSize is the number of deliveries attempted at once
+const deliveryBatchSize = 20
+
+// DispatcherConfiguration the configuration for the Dispatcher
+type DispatcherConfiguration interface {
+	GetWebhookDeliveryInterval() time.Duration
+	GetWebhookDeliveryTimeout() time.Duration
+	GetWebhookDeliveryBackoff() time.Duration
+	GetWebhookDeliveryMaxAttempts() int
+}
+
+// Dispatcher queues the deliveries of the domain events to the webhooks and
+// sends them
+type Dispatcher struct {
+	db     *gorm.DB
+	config DispatcherConfiguration
+	client *http.Client
+	wake   chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewDispatcher creates a dispatcher
+func NewDispatcher(db *gorm.DB, config DispatcherConfiguration) *Dispatcher {
+	return &Dispatcher{
+		db:     db,
+		config: config,
+		client: &http.Client{Timeout: config.GetWebhookDeliveryTimeout()},
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Handle queues a delivery of the given event for each webhook of its space
+// which is registered for it. It is meant to be subscribed to the event bus.
+// Handling an event again queues no further deliveries, since a delivery is
+// unique per webhook and event. Returns the last error met while queuing the
+// deliveries, if any.
+func (d *Dispatcher) Handle(ctx context.Context, e event.Event) error {
+	if uuid.Equal(e.SpaceID, uuid.Nil) {
+		return nil
+	}
+	webhooks, err := NewRepository(d.db).List(ctx, e.SpaceID)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"event_id": e.ID,
+			"err":      err,
+		}, "unable to list the webhooks of the space: %s", err.Error())
+		return err
+	}
+	var body []byte
+	var failure error
+	queued := false
+	for _, w := range webhooks {
+		if !w.Matches(e) {
+			continue
+		}
+		if body == nil {
+			if body, err = Body(e); err != nil {
+				log.Error(ctx, map[string]interface{}{
+					"event_id": e.ID,
+					"err":      err,
+				}, "unable to encode the event: %s", err.Error())
+				return err
+			}
+		}
+		delivery := Delivery{WebhookID: w.ID, EventID: e.ID, EventType: e.Type, Body: string(body)}
+		if err := NewDeliveryRepository(d.db).Enqueue(ctx, &delivery); err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"event_id":   e.ID,
+				"webhook_id": w.ID,
+				"err":        err,
+			}, "unable to queue the delivery of the event: %s", err.Error())
+			failure = err
+			continue
+		}
+		queued = true
+	}
+	if queued {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+	// the deliveries which were queued are not queued twice when the event
+	// is published again
+	return failure
+}
+
+// eventResource is the JSON API document sent to the webhooks
+type eventResource struct {
+	Data eventData `json:"data"`
+}
+
+type eventData struct {
+	Type          string                  `json:"type"`
+	ID            uuid.UUID               `json:"id"`
+	Attributes    eventAttributes         `json:"attributes"`
+	Relationships map[string]relationship `json:"relationships"`
+}
+
+type eventAttributes struct {
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created-at"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+type relationship struct {
+	Data relationshipData `json:"data"`
+}
+
+type relationshipData struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// Body returns the JSON API document which is sent to the webhooks for the
+// given event
+func Body(e event.Event) ([]byte, error) {
+	relationships := map[string]relationship{
+		"space": {Data: relationshipData{Type: "spaces", ID: e.SpaceID}},
+	}
+	if !uuid.Equal(e.ActorID, uuid.Nil) {
+		relationships["actor"] = relationship{Data: relationshipData{Type: "identities", ID: e.ActorID}}
+	}
+	doc := eventResource{
+		Data: eventData{
+			Type: "events",
+			ID:   e.ID,
+			Attributes: eventAttributes{
+				Type:      e.Type,
+				CreatedAt: e.CreatedAt,
+				Payload:   json.RawMessage(e.Payload),
+			},
+			Relationships: relationships,
+		},
+	}
+	body, err := json.Marshal(doc)
+	return body, errs.WithStack(err)
+}
+
+// Start sends the due deliveries at the configured interval, or as soon as
+// deliveries are queued, until Stop is called
+func (d *Dispatcher) Start() {
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(d.config.GetWebhookDeliveryInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+			case <-d.wake:
+			}
+			ctx := context.Background()
+			if err := d.DeliverDue(ctx); err != nil {
+				log.Error(ctx, map[string]interface{}{
+					"err": err,
+				}, "unable to send the webhook deliveries: %s", err.Error())
+			}
+		}
+	}()
+}
+
+// Stop stops the dispatcher and waits for the running deliveries to complete
+// This should be called only from main
+func (d *Dispatcher) Stop() {
+	if d.stop == nil {
+		return
+	}
+	close(d.stop)
+	<-d.done
+	d.stop = nil
+}
+
+// DeliverDue attempts the deliveries which are due
+func (d *Dispatcher) DeliverDue(ctx context.Context) error {
+	for {
+		n, err := d.deliverBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if n < deliveryBatchSize {
+			return nil
+		}
+	}
+}
+
+// deliverBatch claims a batch of due deliveries, attempts them outside of
+// any transaction so that no database connection is held during the HTTP
+// requests, and then records their outcome
+func (d *Dispatcher) deliverBatch(ctx context.Context) (int, error) {
+	due, err := NewDeliveryRepository(d.db).Claim(ctx, deliveryBatchSize, d.config.GetWebhookDeliveryTimeout()*deliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range due {
+		delivery := &due[i]
+		w := Webhook{}
+		if err := d.db.Where("id = ?", delivery.WebhookID).First(&w).Error; err != nil {
+			// the webhook was deleted in the meantime
+			delivery.State = DeliveryFailed
+			delivery.Error = "webhook deleted"
+			delivery.NextAttemptAt = nil
+		} else {
+			d.attempt(ctx, w, delivery)
+		}
+	}
+	tx := d.db.Begin()
+	if tx.Error != nil {
+		return 0, errs.WithStack(tx.Error)
+	}
+	deliveries := NewDeliveryRepository(tx)
+	for _, delivery := range due {
+		if err := deliveries.Save(ctx, delivery); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return 0, errs.WithStack(err)
+	}
+	return len(due), nil
+}
+
+// attempt sends the given delivery to the given webhook and records the
+// outcome. Failed attempts are retried with exponential backoff until the
+// maximum number of attempts is reached.
+func (d *Dispatcher) attempt(ctx context.Context, w Webhook, delivery *Delivery) {
+	delivery.Attempts++
+	delivery.StatusCode = 0
+	delivery.Error = ""
+	body := []byte(delivery.Body)
+	req, err := http.NewRequest("POST", w.URL, bytes.NewReader(body))
+	if err == nil {
+		req.Header.Set("Content-Type", "application/vnd.api+json")
+		req.Header.Set(HeaderEvent, delivery.EventType)
+		req.Header.Set(HeaderDelivery, delivery.ID.String())
+		req.Header.Set(HeaderSignature, Sign(w.Secret, body))
+		var resp *http.Response
+		resp, err = d.client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			delivery.StatusCode = resp.StatusCode
+			if resp.StatusCode < 200 || resp.StatusCode > 299 {
+				err = fmt.Errorf("unexpected status %s", resp.Status)
+			}
+		}
+	}
+	if err == nil {
+		delivery.State = DeliverySucceeded
+		delivery.NextAttemptAt = nil
+		return
+	}
+	delivery.Error = err.Error()
+	log.Warn(ctx, map[string]interface{}{
+		"webhook_id":  w.ID,
+		"delivery_id": delivery.ID,
+		"attempts":    delivery.Attempts,
+		"err":         err,
+	}, "webhook delivery failed")
+	if delivery.Attempts >= d.config.GetWebhookDeliveryMaxAttempts() {
+		delivery.State = DeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+	next := time.Now().Add(d.config.GetWebhookDeliveryBackoff() << uint(delivery.Attempts-1))
+	delivery.NextAttemptAt = &next
+}
@@ -0,0 +1,10 @@
+// Package webhook provides the webhooks through which the changes of the work
+// items, comments and iterations of a space are sent to other services, e.g.
+// CI or chatops bots.
+//
+// The dispatcher subscribes to the domain events, queues a delivery for each
+// webhook whose filter matches an event and sends the queued deliveries in
+// the background. Each delivery is signed with the secret of its webhook and
+// is retried with exponential backoff until it succeeds or the maximum number
+// of attempts is reached. The deliveries are kept as a log of what was sent.
+package webhook
@@ -0,0 +1,288 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
+	"github.com/fabric8io/almighty-core/gormsupport"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeWebhooks is the JSON API type of webhooks
+const APIStringTypeWebhooks = "webhooks"
+
+// EventWorkItemStateChanged is the filter of the work item updates which
+// change the state of the work item. All other filters are event types.
+const EventWorkItemStateChanged = "workitem.state_changed"
+
+// Filters are the events a webhook can be registered for
+var Filters = []string{
+	event.TypeWorkItemCreated,
+	event.TypeWorkItemUpdated,
+	EventWorkItemStateChanged,
+	event.TypeWorkItemMoved,
+	event.TypeWorkItemDeleted,
+	event.TypeLinkCreated,
+	event.TypeLinkDeleted,
+	event.TypeCommentAdded,
+	event.TypeCommentUpdated,
+	event.TypeCommentDeleted,
+	event.TypeUserMentioned,
+	event.TypeIterationCreated,
+	event.TypeIterationStarted,
+	event.TypeIterationClosed,
+	event.TypeSpaceUpdated,
+	event.TypeSpaceDeleted,
+}
+
+// Webhook tells to which URL the events of a space are sent
+type Webhook struct {
+	gormsupport.Lifecycle
+	ID      uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	SpaceID uuid.UUID `sql:"type:uuid"`
+	URL     string
+	// Secret is the key with which the deliveries are signed
+	Secret string
+	// Events holds the filters of the events to send
+	Events  Events `sql:"type:jsonb"`
+	Version int
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m Webhook) GetETagData() []interface{} {
+	return []interface{}{m.ID, m.Version}
+}
+
+// GetLastModified returns the last modification time
+func (m Webhook) GetLastModified() time.Time {
+	return m.UpdatedAt.Truncate(time.Second)
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Matches returns true if the given event passes one of the filters of the
+// webhook
+func (m Webhook) Matches(e event.Event) bool {
+	for _, filter := range m.Events {
+		if filter == e.Type {
+			return true
+		}
+		if filter == EventWorkItemStateChanged && e.Type == event.TypeWorkItemUpdated && changesState(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// changesState returns true if the given work item update changes the state
+// of the work item
+func changesState(e event.Event) bool {
+	p, err := e.Decode()
+	if err != nil {
+		return false
+	}
+	for _, change := range p.(*event.WorkItemUpdated).Changes {
+		if change.Name == workitem.SystemState {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the signature of the given body with the given secret as sent
+// in the X-Hub-Signature-256 header, i.e. "sha256=" followed by the hex encoded
+// HMAC-SHA256
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret returns a random secret for a webhook
+func NewSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", errs.Wrap(err, "failed to generate the secret")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Events holds the filters of the events a webhook is registered for
+type Events []string
+
+// Value implements the driver.Valuer interface
+func (e Events) Value() (driver.Value, error) {
+	if e == nil {
+		return nil, nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements the sql.Scanner interface
+func (e *Events) Scan(src interface{}) error {
+	return scanJSON(src, e)
+}
+
+func scanJSON(src interface{}, target interface{}) error {
+	if src == nil {
+		return nil
+	}
+	s, ok := src.([]byte)
+	if !ok {
+		return errs.New("Scan source was not string")
+	}
+	return json.Unmarshal(s, target)
+}
+
+// validate checks the URL and the filters of the given webhook
+// returns BadParameterError
+func validate(w Webhook) error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.NewBadParameterError("url", w.URL).Expected("absolute http or https URL")
+	}
+	if w.Secret == "" {
+		return errors.NewBadParameterError("secret", w.Secret).Expected("not empty")
+	}
+	if len(w.Events) == 0 {
+		return errors.NewBadParameterError("events", w.Events).Expected("not empty")
+	}
+	for _, e := range w.Events {
+		known := false
+		for _, f := range Filters {
+			if e == f {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errors.NewBadParameterError("events", e).Expected(strings.Join(Filters, ", "))
+		}
+	}
+	return nil
+}
+
+// Repository describes interactions with webhooks
+type Repository interface {
+	Create(ctx context.Context, w *Webhook) error
+	List(ctx context.Context, spaceID uuid.UUID) ([]Webhook, error)
+	Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Webhook, error)
+	Save(ctx context.Context, w Webhook) (*Webhook, error)
+	Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormWebhookRepository{db: db}
+}
+
+// GormWebhookRepository is the implementation of the storage interface for webhooks.
+type GormWebhookRepository struct {
+	db *gorm.DB
+}
+
+// Create creates a new record.
+// returns BadParameterError or InternalError
+func (m *GormWebhookRepository) Create(ctx context.Context, w *Webhook) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "create"}, time.Now())
+	if err := validate(*w); err != nil {
+		return err
+	}
+	w.ID = uuid.NewV4()
+	if err := m.db.Create(w).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": w.SpaceID,
+			"err":      err,
+		}, "error adding webhook: %s", err.Error())
+		return errors.NewInternalError(err)
+	}
+	return nil
+}
+
+// List returns the webhooks of the given space, oldest first
+func (m *GormWebhookRepository) List(ctx context.Context, spaceID uuid.UUID) ([]Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "query"}, time.Now())
+	var objs []Webhook
+	err := m.db.Where("space_id = ?", spaceID).Order("created_at").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return objs, nil
+}
+
+// Load returns the webhook with the given ID in the given space
+func (m *GormWebhookRepository) Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "get"}, time.Now())
+	var obj Webhook
+	tx := m.db.Where("space_id = ? AND id = ?", spaceID, id).First(&obj)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("webhook", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error)
+	}
+	return &obj, nil
+}
+
+// Save updates the given webhook in the db. Version must be the same as the one in the stored version
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (m *GormWebhookRepository) Save(ctx context.Context, w Webhook) (*Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "save"}, time.Now())
+	existing, err := m.Load(ctx, w.SpaceID, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Version != w.Version {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	if err := validate(w); err != nil {
+		return nil, err
+	}
+	w.CreatedAt = existing.CreatedAt
+	w.Version = w.Version + 1
+	if err := m.db.Save(&w).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"webhook_id": w.ID,
+			"err":        err,
+		}, "unable to save the webhook")
+		return nil, errors.NewInternalError(err)
+	}
+	return &w, nil
+}
+
+// Delete deletes the webhook with the given ID. Its pending deliveries are
+// not sent anymore.
+func (m *GormWebhookRepository) Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "delete"}, time.Now())
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("webhook", id.String())
+	}
+	tx := m.db.Where("space_id = ?", spaceID).Delete(&Webhook{ID: id})
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("webhook", id.String())
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package webhook_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/migration"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/webhook"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMatches(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	w := webhook.Webhook{Events: webhook.Events{event.TypeCommentAdded, webhook.EventWorkItemStateChanged}}
+
+	t.Run("event type", func(t *testing.T) {
+		assert.True(t, w.Matches(event.Event{Type: event.TypeCommentAdded}))
+		assert.False(t, w.Matches(event.Event{Type: event.TypeWorkItemCreated}))
+	})
+
+	t.Run("state changed", func(t *testing.T) {
+		e := event.Event{Type: event.TypeWorkItemUpdated, Payload: `{"workitem_id": "1", "changes": [{"name": "system.state", "old_value": "new", "new_value": "open"}]}`}
+		assert.True(t, w.Matches(e))
+	})
+
+	t.Run("state unchanged", func(t *testing.T) {
+		e := event.Event{Type: event.TypeWorkItemUpdated, Payload: `{"workitem_id": "1", "changes": [{"name": "system.title", "old_value": "a", "new_value": "b"}]}`}
+		assert.False(t, w.Matches(e))
+	})
+}
+
+type dispatcherConfig struct {
+	maxAttempts int
+}
+
+func (c dispatcherConfig) GetWebhookDeliveryInterval() time.Duration { return time.Hour }
+func (c dispatcherConfig) GetWebhookDeliveryTimeout() time.Duration  { return 5 * time.Second }
+func (c dispatcherConfig) GetWebhookDeliveryBackoff() time.Duration  { return 0 }
+func (c dispatcherConfig) GetWebhookDeliveryMaxAttempts() int        { return c.maxAttempts }
+
+// receiver is a webhook endpoint which answers with the given status codes in
+// turn and records the requests it gets
+type receiver struct {
+	lock     sync.Mutex
+	statuses []int
+	headers  []http.Header
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	body, _ := ioutil.ReadAll(req.Body)
+	r.headers = append(r.headers, req.Header)
+	r.bodies = append(r.bodies, body)
+	status := r.statuses[0]
+	if len(r.statuses) > 1 {
+		r.statuses = r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+type TestWebhookRepository struct {
+	gormtestsupport.DBTestSuite
+	repo  webhook.Repository
+	clean func()
+}
+
+func TestRunWebhookRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWebhookRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestWebhookRepository) SetupSuite() {
+	s.DBTestSuite.SetupSuite()
+	ctx := migration.NewMigrationContext(context.Background())
+	s.DBTestSuite.PopulateDBTestSuite(ctx)
+}
+
+func (s *TestWebhookRepository) SetupTest() {
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	s.repo = webhook.NewRepository(s.DB)
+}
+
+func (s *TestWebhookRepository) TearDownTest() {
+	s.clean()
+}
+
+func (s *TestWebhookRepository) createWebhook(url string, events ...string) webhook.Webhook {
+	w := webhook.Webhook{SpaceID: space.SystemSpace, URL: url, Secret: "s3cr3t", Events: events}
+	require.Nil(s.T(), s.repo.Create(context.Background(), &w))
+	return w
+}
+
+func (s *TestWebhookRepository) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		w := s.createWebhook("https://ci.example.com/hook", event.TypeWorkItemCreated)
+		// then
+		loaded, err := s.repo.Load(context.Background(), space.SystemSpace, w.ID)
+		require.Nil(t, err)
+		assert.Equal(t, webhook.Events{event.TypeWorkItemCreated}, loaded.Events)
+	})
+
+	s.T().Run("invalid URL", func(t *testing.T) {
+		// given
+		w := webhook.Webhook{SpaceID: space.SystemSpace, URL: "ci.example.com", Secret: "s3cr3t", Events: webhook.Events{event.TypeWorkItemCreated}}
+		// when
+		err := s.repo.Create(context.Background(), &w)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("unknown event", func(t *testing.T) {
+		// given
+		w := webhook.Webhook{SpaceID: space.SystemSpace, URL: "https://ci.example.com/hook", Secret: "s3cr3t", Events: webhook.Events{"foo"}}
+		// when
+		err := s.repo.Create(context.Background(), &w)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *TestWebhookRepository) TestDeliver() {
+	newEvent := func() event.Event {
+		return event.Event{
+			ID:        uuid.NewV4(),
+			CreatedAt: time.Now(),
+			Type:      event.TypeCommentAdded,
+			SpaceID:   space.SystemSpace,
+			Payload:   `{"workitem_id": "1"}`,
+		}
+	}
+	deliveries := func(t *testing.T, w webhook.Webhook) []webhook.Delivery {
+		result, _, err := webhook.NewDeliveryRepository(s.DB).List(context.Background(), w.ID, nil, nil)
+		require.Nil(t, err)
+		return result
+	}
+
+	s.T().Run("signed", func(t *testing.T) {
+		// given
+		r := &receiver{statuses: []int{http.StatusOK}}
+		server := httptest.NewServer(r)
+		defer server.Close()
+		w := s.createWebhook(server.URL, event.TypeCommentAdded)
+		d := webhook.NewDispatcher(s.DB, dispatcherConfig{maxAttempts: 3})
+		e := newEvent()
+		// when
+		require.Nil(t, d.Handle(context.Background(), e))
+		err := d.DeliverDue(context.Background())
+		// then
+		require.Nil(t, err)
+		require.Len(t, r.bodies, 1)
+		assert.Equal(t, webhook.Sign("s3cr3t", r.bodies[0]), r.headers[0].Get(webhook.HeaderSignature))
+		assert.Equal(t, event.TypeCommentAdded, r.headers[0].Get(webhook.HeaderEvent))
+		result := deliveries(t, w)
+		require.Len(t, result, 1)
+		assert.Equal(t, webhook.DeliverySucceeded, result[0].State)
+		assert.Equal(t, http.StatusOK, result[0].StatusCode)
+		assert.Equal(t, result[0].ID.String(), r.headers[0].Get(webhook.HeaderDelivery))
+	})
+
+	s.T().Run("once per event", func(t *testing.T) {
+		// given
+		r := &receiver{statuses: []int{http.StatusOK}}
+		server := httptest.NewServer(r)
+		defer server.Close()
+		w := s.createWebhook(server.URL, event.TypeCommentAdded)
+		d := webhook.NewDispatcher(s.DB, dispatcherConfig{maxAttempts: 3})
+		e := newEvent()
+		// when the event is published twice
+		require.Nil(t, d.Handle(context.Background(), e))
+		require.Nil(t, d.Handle(context.Background(), e))
+		err := d.DeliverDue(context.Background())
+		// then
+		require.Nil(t, err)
+		assert.Len(t, r.bodies, 1)
+		assert.Len(t, deliveries(t, w), 1)
+	})
+
+	s.T().Run("filtered", func(t *testing.T) {
+		// given
+		r := &receiver{statuses: []int{http.StatusOK}}
+		server := httptest.NewServer(r)
+		defer server.Close()
+		w := s.createWebhook(server.URL, event.TypeIterationClosed)
+		d := webhook.NewDispatcher(s.DB, dispatcherConfig{maxAttempts: 3})
+		// when
+		require.Nil(t, d.Handle(context.Background(), newEvent()))
+		err := d.DeliverDue(context.Background())
+		// then
+		require.Nil(t, err)
+		assert.Empty(t, r.bodies)
+		assert.Empty(t, deliveries(t, w))
+	})
+
+	s.T().Run("retried", func(t *testing.T) {
+		// given
+		r := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusOK}}
+		server := httptest.NewServer(r)
+		defer server.Close()
+		w := s.createWebhook(server.URL, event.TypeCommentAdded)
+		d := webhook.NewDispatcher(s.DB, dispatcherConfig{maxAttempts: 3})
+		require.Nil(t, d.Handle(context.Background(), newEvent()))
+		// when
+		require.Nil(t, d.DeliverDue(context.Background()))
+		// then
+		result := deliveries(t, w)
+		require.Len(t, result, 1)
+		assert.Equal(t, webhook.DeliveryPending, result[0].State)
+		assert.Equal(t, http.StatusInternalServerError, result[0].StatusCode)
+		assert.Equal(t, 1, result[0].Attempts)
+		require.NotNil(t, result[0].NextAttemptAt)
+		// when the delivery is due again
+		require.Nil(t, d.DeliverDue(context.Background()))
+		// then
+		result = deliveries(t, w)
+		assert.Equal(t, webhook.DeliverySucceeded, result[0].State)
+		assert.Equal(t, 2, result[0].Attempts)
+		assert.Nil(t, result[0].NextAttemptAt)
+	})
+
+	s.T().Run("given up", func(t *testing.T) {
+		// given
+		r := &receiver{statuses: []int{http.StatusServiceUnavailable}}
+		server := httptest.NewServer(r)
+		defer server.Close()
+		w := s.createWebhook(server.URL, event.TypeCommentAdded)
+		d := webhook.NewDispatcher(s.DB, dispatcherConfig{maxAttempts: 2})
+		require.Nil(t, d.Handle(context.Background(), newEvent()))
+		// when
+		require.Nil(t, d.DeliverDue(context.Background()))
+		require.Nil(t, d.DeliverDue(context.Background()))
+		require.Nil(t, d.DeliverDue(context.Background()))
+		// then
+		result := deliveries(t, w)
+		require.Len(t, result, 1)
+		assert.Equal(t, webhook.DeliveryFailed, result[0].State)
+		assert.Equal(t, 2, result[0].Attempts)
+		assert.Len(t, r.bodies, 2)
+	})
+}
@@ -0,0 +1,301 @@
+package workitem
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/gormsupport"
+	"github.com/fabric8io/almighty-core/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeChecklistItems is the JSON API type of checklist items
+const APIStringTypeChecklistItems = "checklistitems"
+
+// RevisionFieldChecklist is the name under which the revisions of a work
+// item hold its checklist, so that changes of the checklist show up in the
+// history of the work item like changes of its fields. The work item itself
+// does not have such a field.
+const RevisionFieldChecklist = "system.checklist"
+
+// ChecklistItem is an entry of the checklist of a work item
+type ChecklistItem struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	WorkItemID uint64
+	Text       string
+	Done       bool
+	Assignee   *uuid.UUID `sql:"type:uuid"`
+	// Position defines the order of the items of a checklist
+	Position int
+	Version  int
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m ChecklistItem) GetETagData() []interface{} {
+	return []interface{}{m.ID, m.Version}
+}
+
+// GetLastModified returns the last modification time
+func (m ChecklistItem) GetLastModified() time.Time {
+	return m.UpdatedAt.Truncate(time.Second)
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m ChecklistItem) TableName() string {
+	return "work_item_checklist_items"
+}
+
+// ChecklistProgress tells how many items of a checklist are done
+type ChecklistProgress struct {
+	Done  int
+	Total int
+}
+
+// ChecklistRepository describes interactions with the checklists of work
+// items. Every change of a checklist increments the version of the work item
+// and stores a revision of it.
+type ChecklistRepository interface {
+	List(ctx context.Context, spaceID uuid.UUID, workitemID string) ([]ChecklistItem, error)
+	Create(ctx context.Context, spaceID uuid.UUID, workitemID string, item *ChecklistItem, modifierID uuid.UUID) error
+	Save(ctx context.Context, spaceID uuid.UUID, workitemID string, item ChecklistItem, modifierID uuid.UUID) (*ChecklistItem, error)
+	Delete(ctx context.Context, spaceID uuid.UUID, workitemID string, id uuid.UUID, modifierID uuid.UUID) error
+	Reorder(ctx context.Context, spaceID uuid.UUID, workitemID string, ids []uuid.UUID, modifierID uuid.UUID) ([]ChecklistItem, error)
+	Progress(ctx context.Context, workitemID string) (*ChecklistProgress, error)
+}
+
+// NewChecklistRepository creates a new storage type.
+func NewChecklistRepository(db *gorm.DB) *GormChecklistRepository {
+	return &GormChecklistRepository{db: db, wir: NewWorkItemRepository(db)}
+}
+
+// GormChecklistRepository is the implementation of the storage interface for
+// checklist items.
+type GormChecklistRepository struct {
+	db  *gorm.DB
+	wir *GormWorkItemRepository
+}
+
+// List returns the items of the checklist of the given work item in their order
+func (r *GormChecklistRepository) List(ctx context.Context, spaceID uuid.UUID, workitemID string) ([]ChecklistItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "checklist", "query"}, time.Now())
+	wiStorage, _, err := r.wir.loadWorkItemStorage(ctx, spaceID, workitemID, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.list(wiStorage.ID)
+}
+
+func (r *GormChecklistRepository) list(workItemID uint64) ([]ChecklistItem, error) {
+	var items []ChecklistItem
+	err := r.db.Where("work_item_id = ?", workItemID).Order("position, created_at").Find(&items).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return items, nil
+}
+
+// Create appends the given item to the checklist of the given work item
+// returns NotFoundError, BadParameterError or InternalError
+func (r *GormChecklistRepository) Create(ctx context.Context, spaceID uuid.UUID, workitemID string, item *ChecklistItem, modifierID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "checklist", "create"}, time.Now())
+	if item.Text == "" {
+		return errors.NewBadParameterError("text", item.Text).Expected("not empty")
+	}
+	wiStorage, _, err := r.wir.loadWorkItemStorage(ctx, spaceID, workitemID, true)
+	if err != nil {
+		return err
+	}
+	var positions []int
+	if err := r.db.Model(&ChecklistItem{}).Where("work_item_id = ?", wiStorage.ID).Pluck("coalesce(max(position), 0)", &positions).Error; err != nil {
+		return errors.NewInternalError(err)
+	}
+	item.ID = uuid.NewV4()
+	item.WorkItemID = wiStorage.ID
+	item.Position = 1
+	if len(positions) > 0 {
+		item.Position = positions[0] + 1
+	}
+	item.Version = 0
+	if err := r.db.Create(item).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": workitemID,
+			"err":   err,
+		}, "unable to add the checklist item: %s", err.Error())
+		return errors.NewInternalError(err)
+	}
+	return r.touch(ctx, wiStorage, modifierID)
+}
+
+// Save updates the given checklist item. Version must be the same as the one in the stored version
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (r *GormChecklistRepository) Save(ctx context.Context, spaceID uuid.UUID, workitemID string, item ChecklistItem, modifierID uuid.UUID) (*ChecklistItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "checklist", "save"}, time.Now())
+	if item.Text == "" {
+		return nil, errors.NewBadParameterError("text", item.Text).Expected("not empty")
+	}
+	wiStorage, _, err := r.wir.loadWorkItemStorage(ctx, spaceID, workitemID, true)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := r.load(wiStorage.ID, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Version != item.Version {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	item.WorkItemID = existing.WorkItemID
+	item.Position = existing.Position
+	item.CreatedAt = existing.CreatedAt
+	item.Version = item.Version + 1
+	if err := r.db.Save(&item).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"checklist_item_id": item.ID,
+			"err":               err,
+		}, "unable to save the checklist item")
+		return nil, errors.NewInternalError(err)
+	}
+	if err := r.touch(ctx, wiStorage, modifierID); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Delete removes the given item from the checklist of the given work item
+// returns NotFoundError or InternalError
+func (r *GormChecklistRepository) Delete(ctx context.Context, spaceID uuid.UUID, workitemID string, id uuid.UUID, modifierID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "checklist", "delete"}, time.Now())
+	wiStorage, _, err := r.wir.loadWorkItemStorage(ctx, spaceID, workitemID, true)
+	if err != nil {
+		return err
+	}
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("checklist item", id.String())
+	}
+	tx := r.db.Where("work_item_id = ?", wiStorage.ID).Delete(&ChecklistItem{ID: id})
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("checklist item", id.String())
+	}
+	return r.touch(ctx, wiStorage, modifierID)
+}
+
+// Reorder puts the items of the checklist of the given work item in the
+// given order. The given IDs must be the ones of all items of the checklist.
+// returns NotFoundError, BadParameterError or InternalError
+func (r *GormChecklistRepository) Reorder(ctx context.Context, spaceID uuid.UUID, workitemID string, ids []uuid.UUID, modifierID uuid.UUID) ([]ChecklistItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "checklist", "reorder"}, time.Now())
+	wiStorage, _, err := r.wir.loadWorkItemStorage(ctx, spaceID, workitemID, true)
+	if err != nil {
+		return nil, err
+	}
+	items, err := r.list(wiStorage.ID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]ChecklistItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	if len(ids) != len(items) {
+		return nil, errors.NewBadParameterError("items", ids).Expected("the IDs of all items of the checklist")
+	}
+	result := make([]ChecklistItem, len(ids))
+	for i, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			return nil, errors.NewBadParameterError("items", ids).Expected("the IDs of all items of the checklist")
+		}
+		delete(byID, id)
+		if item.Position != i+1 {
+			item.Position = i + 1
+			item.Version = item.Version + 1
+			if err := r.db.Save(&item).Error; err != nil {
+				return nil, errors.NewInternalError(err)
+			}
+		}
+		result[i] = item
+	}
+	if err := r.touch(ctx, wiStorage, modifierID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Progress returns how many items of the checklist of the work item with the
+// given ID are done
+func (r *GormChecklistRepository) Progress(ctx context.Context, workitemID string) (*ChecklistProgress, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "checklist", "progress"}, time.Now())
+	var progress ChecklistProgress
+	row := r.db.Model(&ChecklistItem{}).Where("work_item_id = ?", workitemID).
+		Select("count(*) FILTER (WHERE done), count(*)").Row()
+	if err := row.Scan(&progress.Done, &progress.Total); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	return &progress, nil
+}
+
+func (r *GormChecklistRepository) load(workItemID uint64, id uuid.UUID) (*ChecklistItem, error) {
+	var item ChecklistItem
+	tx := r.db.Where("work_item_id = ? AND id = ?", workItemID, id).First(&item)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("checklist item", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error)
+	}
+	return &item, nil
+}
+
+// touch increments the version of the given work item and stores a revision
+// of it, which holds the changed checklist
+func (r *GormChecklistRepository) touch(ctx context.Context, wiStorage *WorkItemStorage, modifierID uuid.UUID) error {
+	version := wiStorage.Version
+	wiStorage.Version = version + 1
+	tx := r.db.Model(wiStorage).Where("version = ?", version).Update("version", wiStorage.Version)
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewVersionConflictError("version conflict")
+	}
+	if err := r.wir.wirr.Create(ctx, modifierID, RevisionTypeUpdate, *wiStorage); err != nil {
+		return errs.Wrapf(err, "error while storing the revision of work item %d", wiStorage.ID)
+	}
+	return nil
+}
+
+// checklistSnapshot returns the checklist of the work item with the given ID
+// as stored in the revisions of the work item, or nil if the work item has no
+// checklist items
+func checklistSnapshot(db *gorm.DB, workItemID uint64) ([]interface{}, error) {
+	var items []ChecklistItem
+	err := db.Where("work_item_id = ?", workItemID).Order("position, created_at").Find(&items).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		entry := map[string]interface{}{
+			"text": item.Text,
+			"done": item.Done,
+		}
+		if item.Assignee != nil {
+			entry["assignee"] = item.Assignee.String()
+		}
+		result[i] = entry
+	}
+	return result, nil
+}
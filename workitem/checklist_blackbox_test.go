@@ -0,0 +1,215 @@
+package workitem_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/criteria"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/migration"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+	testsupport "github.com/fabric8io/almighty-core/test"
+	"github.com/fabric8io/almighty-core/workitem"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRunChecklistRepositoryBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &checklistRepositoryBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+type checklistRepositoryBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo     workitem.ChecklistRepository
+	wiRepo   workitem.WorkItemRepository
+	clean    func()
+	identity account.Identity
+	workItem *workitem.WorkItem
+}
+
+func (s *checklistRepositoryBlackBoxTest) SetupSuite() {
+	s.DBTestSuite.SetupSuite()
+	ctx := migration.NewMigrationContext(context.Background())
+	s.DBTestSuite.PopulateDBTestSuite(ctx)
+}
+
+func (s *checklistRepositoryBlackBoxTest) SetupTest() {
+	s.repo = workitem.NewChecklistRepository(s.DB)
+	s.wiRepo = workitem.NewWorkItemRepository(s.DB)
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	identity, err := testsupport.CreateTestIdentity(s.DB, "TestChecklist-"+uuid.NewV4().String(), "test")
+	require.Nil(s.T(), err)
+	s.identity = identity
+	s.workItem, err = s.wiRepo.Create(context.Background(), space.SystemSpace, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "Title",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, s.identity.ID)
+	require.Nil(s.T(), err)
+}
+
+func (s *checklistRepositoryBlackBoxTest) TearDownTest() {
+	s.clean()
+}
+
+func (s *checklistRepositoryBlackBoxTest) createItems(texts ...string) []workitem.ChecklistItem {
+	items := make([]workitem.ChecklistItem, len(texts))
+	for i, text := range texts {
+		items[i] = workitem.ChecklistItem{Text: text}
+		require.Nil(s.T(), s.repo.Create(context.Background(), space.SystemSpace, s.workItem.ID, &items[i], s.identity.ID))
+	}
+	return items
+}
+
+func (s *checklistRepositoryBlackBoxTest) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		items := s.createItems("first", "second")
+		// then
+		assert.Equal(t, 1, items[0].Position)
+		assert.Equal(t, 2, items[1].Position)
+		list, err := s.repo.List(context.Background(), space.SystemSpace, s.workItem.ID)
+		require.Nil(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, "first", list[0].Text)
+		assert.Equal(t, "second", list[1].Text)
+		wi, err := s.wiRepo.LoadByID(context.Background(), s.workItem.ID)
+		require.Nil(t, err)
+		assert.Equal(t, s.workItem.Version+2, wi.Version)
+	})
+
+	s.T().Run("empty text", func(t *testing.T) {
+		// when
+		err := s.repo.Create(context.Background(), space.SystemSpace, s.workItem.ID, &workitem.ChecklistItem{}, s.identity.ID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("unknown work item", func(t *testing.T) {
+		// when
+		err := s.repo.Create(context.Background(), space.SystemSpace, "0", &workitem.ChecklistItem{Text: "first"}, s.identity.ID)
+		// then
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *checklistRepositoryBlackBoxTest) TestSave() {
+	// given
+	items := s.createItems("first", "second")
+
+	s.T().Run("done", func(t *testing.T) {
+		// given
+		item := items[0]
+		item.Done = true
+		// when
+		saved, err := s.repo.Save(context.Background(), space.SystemSpace, s.workItem.ID, item, s.identity.ID)
+		// then
+		require.Nil(t, err)
+		assert.True(t, saved.Done)
+		assert.Equal(t, 1, saved.Version)
+		progress, err := s.repo.Progress(context.Background(), s.workItem.ID)
+		require.Nil(t, err)
+		assert.Equal(t, workitem.ChecklistProgress{Done: 1, Total: 2}, *progress)
+		// the change shows up in the history of the work item
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(context.Background(), s.workItem.ID)
+		require.Nil(t, err)
+		last := revisions[len(revisions)-1]
+		assert.Equal(t, workitem.RevisionTypeUpdate, last.Type)
+		require.NotNil(t, last.WorkItemFields[workitem.RevisionFieldChecklist])
+		checklist := last.WorkItemFields[workitem.RevisionFieldChecklist].([]interface{})
+		require.Len(t, checklist, 2)
+		assert.Equal(t, true, checklist[0].(map[string]interface{})["done"])
+	})
+
+	s.T().Run("version conflict", func(t *testing.T) {
+		// when the version of the item before the save is used
+		_, err := s.repo.Save(context.Background(), space.SystemSpace, s.workItem.ID, items[0], s.identity.ID)
+		// then
+		require.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+}
+
+func (s *checklistRepositoryBlackBoxTest) TestReorder() {
+	// given
+	items := s.createItems("first", "second", "third")
+
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		reordered, err := s.repo.Reorder(context.Background(), space.SystemSpace, s.workItem.ID, []uuid.UUID{items[2].ID, items[0].ID, items[1].ID}, s.identity.ID)
+		// then
+		require.Nil(t, err)
+		require.Len(t, reordered, 3)
+		list, err := s.repo.List(context.Background(), space.SystemSpace, s.workItem.ID)
+		require.Nil(t, err)
+		assert.Equal(t, []string{"third", "first", "second"}, []string{list[0].Text, list[1].Text, list[2].Text})
+	})
+
+	s.T().Run("missing item", func(t *testing.T) {
+		// when
+		_, err := s.repo.Reorder(context.Background(), space.SystemSpace, s.workItem.ID, []uuid.UUID{items[2].ID, items[0].ID}, s.identity.ID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("unknown item", func(t *testing.T) {
+		// when
+		_, err := s.repo.Reorder(context.Background(), space.SystemSpace, s.workItem.ID, []uuid.UUID{items[2].ID, items[0].ID, uuid.NewV4()}, s.identity.ID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *checklistRepositoryBlackBoxTest) TestDelete() {
+	// given
+	items := s.createItems("first")
+
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		err := s.repo.Delete(context.Background(), space.SystemSpace, s.workItem.ID, items[0].ID, s.identity.ID)
+		// then
+		require.Nil(t, err)
+		progress, err := s.repo.Progress(context.Background(), s.workItem.ID)
+		require.Nil(t, err)
+		assert.Equal(t, 0, progress.Total)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// when
+		err := s.repo.Delete(context.Background(), space.SystemSpace, s.workItem.ID, uuid.NewV4(), s.identity.ID)
+		// then
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *checklistRepositoryBlackBoxTest) TestListOpenChecklist() {
+	// given a second work item whose checklist is done
+	other, err := s.wiRepo.Create(context.Background(), space.SystemSpace, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "Other",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, s.identity.ID)
+	require.Nil(s.T(), err)
+	done := workitem.ChecklistItem{Text: "done", Done: true}
+	require.Nil(s.T(), s.repo.Create(context.Background(), space.SystemSpace, other.ID, &done, s.identity.ID))
+	s.createItems("open")
+	// when
+	open := criteria.GreaterThan(criteria.Field(workitem.SystemChecklistOpen), criteria.Literal(0))
+	result, _, err := s.wiRepo.List(context.Background(), space.SystemSpace, open, nil, nil, nil)
+	// then
+	require.Nil(s.T(), err)
+	ids := make([]string, len(result))
+	for i, wi := range result {
+		ids[i] = wi.ID
+	}
+	assert.Contains(s.T(), ids, s.workItem.ID)
+	assert.NotContains(s.T(), ids, other.ID)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fabric8io/almighty-core/criteria"
 	uuid "github.com/satori/go.uuid"
@@ -20,7 +21,10 @@ func Compile(where criteria.Expression) (whereClause string, parameters []interf
 
 	compiler := newExpressionCompiler()
 	compiled := where.Accept(&compiler)
-
+	if compiled == nil {
+		// errors have been accumulated by the compiler
+		return "", compiler.parameters, compiler.err
+	}
 	return compiled.(string), compiler.parameters, compiler.err
 }
 
@@ -43,13 +47,25 @@ func bubbleUpJSONContext(exp criteria.Expression) bool {
 	return true
 }
 
+// columns maps the field names which are stored in a column of the work item
+// table, or computed from other tables, to that column or computation,
+// everything else lives in the Fields json
+var columns = map[string]string{
+	"ID":            "ID",
+	"Type":          "Type",
+	"Version":       "Version",
+	SystemCreatedAt: "created_at",
+	SystemUpdatedAt: "updated_at",
+	SystemChecklistOpen: `(SELECT count(*) FROM work_item_checklist_items c
+		WHERE c.work_item_id = work_items.id AND NOT c.done AND c.deleted_at IS NULL)`,
+	SystemVotes: `(SELECT count(*) FROM reactions r
+		WHERE r.kind = 'workitems' AND r.target_id = work_items.id::text AND r.content = '+1')`,
+}
+
 // does the field name reference a json field or a column?
 func isJSONField(fieldName string) bool {
-	switch fieldName {
-	case "ID", "Type", "Version":
-		return false
-	}
-	return true
+	_, isColumn := columns[fieldName]
+	return !isColumn
 }
 
 func newExpressionCompiler() expressionCompiler {
@@ -67,17 +83,26 @@ type expressionCompiler struct {
 // the convention is to return nil when the expression cannot be compiled and to append an error to the err field
 
 func (c *expressionCompiler) Field(f *criteria.FieldExpression) interface{} {
-	if !isJSONField(f.FieldName) {
-		return f.FieldName
+	if column, ok := columns[f.FieldName]; ok {
+		return column
 	}
-	if strings.Contains(f.FieldName, "'") {
-		// beware of injection, it's a reasonable restriction for field names, make sure it's not allowed when creating wi types
-		c.err = append(c.err, fmt.Errorf("single quote not allowed in field name"))
+	if !c.checkFieldName(f.FieldName) {
 		return nil
 	}
 	return "Fields@>'{\"" + f.FieldName + "\""
 }
 
+// checkFieldName records an error if the json field name cannot be safely
+// embedded in the query
+func (c *expressionCompiler) checkFieldName(fieldName string) bool {
+	if strings.Contains(fieldName, "'") {
+		// beware of injection, it's a reasonable restriction for field names, make sure it's not allowed when creating wi types
+		c.err = append(c.err, fmt.Errorf("single quote not allowed in field name"))
+		return false
+	}
+	return true
+}
+
 func (c *expressionCompiler) And(a *criteria.AndExpression) interface{} {
 	return c.binary(a, "and")
 }
@@ -97,6 +122,9 @@ func (c *expressionCompiler) Or(a *criteria.OrExpression) interface{} {
 }
 
 func (c *expressionCompiler) Equals(e *criteria.EqualsExpression) interface{} {
+	if isTimeComparison(e.Left(), e.Right()) {
+		return c.compare(e.Left(), e.Right(), "=")
+	}
 	if isInJSONContext(e.Left()) {
 		return c.binary(e, ":")
 	}
@@ -104,13 +132,19 @@ func (c *expressionCompiler) Equals(e *criteria.EqualsExpression) interface{} {
 }
 
 func (c *expressionCompiler) IsNull(e *criteria.IsNullExpression) interface{} {
-	if isJSONField(e.FieldName) {
-		return "(Fields->>'" + e.FieldName + "' IS NULL)"
+	if column, ok := columns[e.FieldName]; ok {
+		return "(" + column + " IS NULL)"
 	}
-	return "(" + e.FieldName + " IS NULL)"
+	if !c.checkFieldName(e.FieldName) {
+		return nil
+	}
+	return "(Fields->>'" + e.FieldName + "' IS NULL)"
 }
 
 func (c *expressionCompiler) Not(e *criteria.NotExpression) interface{} {
+	if isTimeComparison(e.Left(), e.Right()) {
+		return c.compare(e.Left(), e.Right(), "!=")
+	}
 	if isInJSONContext(e.Left()) {
 		condition := c.binary(e, ":")
 		if condition != nil {
@@ -121,6 +155,190 @@ func (c *expressionCompiler) Not(e *criteria.NotExpression) interface{} {
 	return c.binary(e, "!=")
 }
 
+func (c *expressionCompiler) Negate(e *criteria.NegateExpression) interface{} {
+	condition := e.Operand().Accept(c)
+	if condition != nil {
+		return "NOT " + condition.(string)
+	}
+	return nil
+}
+
+func (c *expressionCompiler) GreaterThan(e *criteria.GreaterThanExpression) interface{} {
+	return c.compare(e.Left(), e.Right(), ">")
+}
+
+func (c *expressionCompiler) GreaterOrEqual(e *criteria.GreaterOrEqualExpression) interface{} {
+	return c.compare(e.Left(), e.Right(), ">=")
+}
+
+func (c *expressionCompiler) LessThan(e *criteria.LessThanExpression) interface{} {
+	return c.compare(e.Left(), e.Right(), "<")
+}
+
+func (c *expressionCompiler) LessOrEqual(e *criteria.LessOrEqualExpression) interface{} {
+	return c.compare(e.Left(), e.Right(), "<=")
+}
+
+// compare compiles a comparison where json fields are extracted as text and
+// cast to the type of the value they are compared with
+func (c *expressionCompiler) compare(left, right criteria.Expression, op string) interface{} {
+	json := isJSONFieldExpression(left) || isJSONFieldExpression(right)
+	l := c.term(left, right, json)
+	r := c.term(right, left, json)
+	if l != nil && r != nil {
+		return "(" + l.(string) + " " + op + " " + r.(string) + ")"
+	}
+	return nil
+}
+
+func (c *expressionCompiler) Contains(e *criteria.ContainsExpression) interface{} {
+	literal, ok := e.Right().(*criteria.LiteralExpression)
+	if !ok {
+		c.err = append(c.err, fmt.Errorf("contains is only supported with a literal value"))
+		return nil
+	}
+	value, ok := literal.Value.(string)
+	if !ok {
+		c.err = append(c.err, fmt.Errorf("contains is only supported with a string value, but got %v: %T", literal.Value, literal.Value))
+		return nil
+	}
+	var left interface{}
+	if f, ok := e.Left().(*criteria.FieldExpression); ok && isJSONField(f.FieldName) {
+		if c.checkFieldName(f.FieldName) {
+			left = "Fields->>'" + f.FieldName + "'"
+		}
+	} else {
+		left = e.Left().Accept(c)
+	}
+	if left == nil {
+		return nil
+	}
+	c.parameters = append(c.parameters, "%"+escapeLike(value)+"%")
+	return "(" + left.(string) + " ILIKE ?)"
+}
+
+func (c *expressionCompiler) In(e *criteria.InExpression) interface{} {
+	if len(e.Values()) == 0 {
+		c.err = append(c.err, fmt.Errorf("in requires at least one value"))
+		return nil
+	}
+	json := isJSONFieldExpression(e.Left())
+	left := c.term(e.Left(), e.Values()[0], json)
+	values := []string{}
+	for _, v := range e.Values() {
+		value := c.term(v, e.Left(), json)
+		if value == nil {
+			return nil
+		}
+		values = append(values, value.(string))
+	}
+	if left == nil {
+		return nil
+	}
+	return "(" + left.(string) + " IN (" + strings.Join(values, ", ") + "))"
+}
+
+func (c *expressionCompiler) Between(e *criteria.BetweenExpression) interface{} {
+	json := isJSONFieldExpression(e.Operand())
+	operand := c.term(e.Operand(), e.Lower(), json)
+	lower := c.term(e.Lower(), e.Operand(), json)
+	upper := c.term(e.Upper(), e.Operand(), json)
+	if operand != nil && lower != nil && upper != nil {
+		return "(" + operand.(string) + " BETWEEN " + lower.(string) + " AND " + upper.(string) + ")"
+	}
+	return nil
+}
+
+// term compiles one side of a comparison. other is the opposite side of the
+// comparison: json fields are cast to the type of the value they are
+// compared with and literals compared with json fields are converted to
+// the representation used in the json.
+func (c *expressionCompiler) term(exp criteria.Expression, other criteria.Expression, json bool) interface{} {
+	switch t := exp.(type) {
+	case *criteria.FieldExpression:
+		if !isJSONField(t.FieldName) {
+			return t.Accept(c)
+		}
+		if !c.checkFieldName(t.FieldName) {
+			return nil
+		}
+		cast := ""
+		if literal, ok := other.(*criteria.LiteralExpression); ok {
+			var err error
+			cast, err = jsonCast(literal.Value)
+			if err != nil {
+				c.err = append(c.err, err)
+				return nil
+			}
+		}
+		if cast == "" {
+			return "Fields->>'" + t.FieldName + "'"
+		}
+		return "(Fields->>'" + t.FieldName + "')::" + cast
+	case *criteria.LiteralExpression:
+		value := t.Value
+		if json {
+			value = jsonValue(value)
+		}
+		c.parameters = append(c.parameters, value)
+		return "?"
+	}
+	return exp.Accept(c)
+}
+
+// jsonCast returns the type a json field needs to be cast to in order to be
+// compared with the given value. Instants are stored as nanoseconds since the
+// epoch. Integers are compared as numeric so that they can be compared with
+// float fields, too.
+func jsonCast(value interface{}) (string, error) {
+	switch value.(type) {
+	case time.Time:
+		return "bigint", nil
+	case int, int32, int64, uint, uint32, uint64:
+		return "numeric", nil
+	case float32, float64:
+		return "float8", nil
+	case bool:
+		return "boolean", nil
+	case string, uuid.UUID:
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown value type of %v: %T", value, value)
+}
+
+// jsonValue converts a value to the representation used in the Fields json
+func jsonValue(value interface{}) interface{} {
+	switch t := value.(type) {
+	case time.Time:
+		return t.UnixNano()
+	case uuid.UUID:
+		return t.String()
+	}
+	return value
+}
+
+// isJSONFieldExpression tells if the expression is a reference to a json field
+func isJSONFieldExpression(exp criteria.Expression) bool {
+	f, ok := exp.(*criteria.FieldExpression)
+	return ok && isJSONField(f.FieldName)
+}
+
+// isTimeComparison tells if a json field is compared with a time. Instants
+// and dates are stored as numbers and cannot be matched by json containment.
+func isTimeComparison(left, right criteria.Expression) bool {
+	literal, ok := right.(*criteria.LiteralExpression)
+	if !ok {
+		return false
+	}
+	_, isTime := literal.Value.(time.Time)
+	return isTime && isJSONFieldExpression(left)
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
 func (c *expressionCompiler) Parameter(v *criteria.ParameterExpression) interface{} {
 	c.err = append(c.err, fmt.Errorf("Parameter expression not supported"))
 	return nil
@@ -162,7 +380,7 @@ func (c *expressionCompiler) Literal(v *criteria.LiteralExpression) interface{}
 func (c *expressionCompiler) wrapStrings(value []string) string {
 	wrapped := []string{}
 	for i := 0; i < len(value); i++ {
-		wrapped = append(wrapped, "\""+value[i]+"\"")
+		wrapped = append(wrapped, quoteJSONString(value[i]))
 	}
 	return strings.Join(wrapped, ",")
 }
@@ -181,7 +399,7 @@ func (c *expressionCompiler) convertToString(value interface{}) (string, error)
 	case uint64:
 		result = strconv.FormatUint(t, 10)
 	case string:
-		result = "\"" + t + "\""
+		result = quoteJSONString(t)
 	case bool:
 		result = strconv.FormatBool(t)
 	case uuid.UUID:
@@ -191,3 +409,11 @@ func (c *expressionCompiler) convertToString(value interface{}) (string, error)
 	}
 	return result, nil
 }
+
+// quoteJSONString turns the given value into a JSON string literal that can
+// be embedded in a single-quoted SQL string. Query values come straight from
+// the API, so both the JSON and the SQL delimiters must be escaped.
+func quoteJSONString(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "'", "''")
+	return "\"" + replacer.Replace(value) + "\""
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"runtime/debug"
 	"testing"
+	"time"
 
 	. "github.com/fabric8io/almighty-core/criteria"
 	"github.com/fabric8io/almighty-core/resource"
@@ -38,6 +39,79 @@ func TestIsNull(t *testing.T) {
 	expect(t, IsNull("Version"), "(Version IS NULL)", []interface{}{})
 }
 
+func TestNegate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expect(t, Negate(Equals(Field("foo"), Literal("abcd"))), "NOT (Fields@>'{\"foo\" : \"abcd\"}')", []interface{}{})
+	expect(t, Negate(IsNull("system.assignees")), "NOT (Fields->>'system.assignees' IS NULL)", []interface{}{})
+	expect(t, Negate(Or(Equals(Field("Type"), Literal("abcd")), Equals(Field("foo"), Literal(5)))), "NOT ((Type = ?) or (Fields@>'{\"foo\" : 5}'))", []interface{}{"abcd"})
+}
+
+func TestStringEscaping(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expect(t, Equals(Field("foo"), Literal(`it's a "quote"`)), `(Fields@>'{"foo" : "it''s a \"quote\""}')`, []interface{}{})
+}
+
+func TestComparison(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expect(t, GreaterThan(Field("storypoints"), Literal(5)), "((Fields->>'storypoints')::numeric > ?)", []interface{}{5})
+	expect(t, GreaterOrEqual(Field("effort"), Literal(1.5)), "((Fields->>'effort')::float8 >= ?)", []interface{}{1.5})
+	expect(t, LessThan(Field("Version"), Literal(3)), "(Version < ?)", []interface{}{3})
+	expect(t, LessOrEqual(Field("system.title"), Literal("m")), "(Fields->>'system.title' <= ?)", []interface{}{"m"})
+	now := time.Now()
+	expect(t, GreaterThan(Field("system.updated_at"), Literal(now)), "(updated_at > ?)", []interface{}{now})
+	expect(t, LessThan(Field("duedate"), Literal(now)), "((Fields->>'duedate')::bigint < ?)", []interface{}{now.UnixNano()})
+	expect(t, GreaterThan(Literal(now), Field("duedate")), "(? > (Fields->>'duedate')::bigint)", []interface{}{now.UnixNano()})
+}
+
+func TestDateAndBoolean(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	day := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	expect(t, Equals(Field("duedate"), Literal(day)), "((Fields->>'duedate')::bigint = ?)", []interface{}{day.UnixNano()})
+	expect(t, Not(Field("duedate"), Literal(day)), "((Fields->>'duedate')::bigint != ?)", []interface{}{day.UnixNano()})
+	expect(t, Equals(Field("blocked"), Literal(true)), "(Fields@>'{\"blocked\" : true}')", []interface{}{})
+	expect(t, LessThan(Field("blocked"), Literal(true)), "((Fields->>'blocked')::boolean < ?)", []interface{}{true})
+}
+
+func TestBetween(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expect(t, Between(Field("storypoints"), Literal(1), Literal(8)), "((Fields->>'storypoints')::numeric BETWEEN ? AND ?)", []interface{}{1, 8})
+	from := time.Now().Add(-7 * 24 * time.Hour)
+	to := time.Now()
+	expect(t, Between(Field("system.created_at"), Literal(from), Literal(to)), "(created_at BETWEEN ? AND ?)", []interface{}{from, to})
+}
+
+func TestIn(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expect(t, In(Field("system.state"), Literal("new"), Literal("open")), "(Fields->>'system.state' IN (?, ?))", []interface{}{"new", "open"})
+	expect(t, In(Field("Type"), Literal("abcd")), "(Type IN (?))", []interface{}{"abcd"})
+	expect(t, Negate(In(Field("storypoints"), Literal(1), Literal(2))), "NOT ((Fields->>'storypoints')::numeric IN (?, ?))", []interface{}{1, 2})
+	_, _, err := Compile(In(Field("system.state")))
+	assert.NotEmpty(t, err)
+}
+
+func TestContains(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expect(t, Contains(Field("system.title"), Literal("Login")), "(Fields->>'system.title' ILIKE ?)", []interface{}{"%Login%"})
+	expect(t, Contains(Field("system.title"), Literal("100%_done")), "(Fields->>'system.title' ILIKE ?)", []interface{}{`%100\%\_done%`})
+	_, _, err := Compile(Contains(Field("system.title"), Literal(5)))
+	assert.NotEmpty(t, err)
+}
+
+func TestColumnFields(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	now := time.Now()
+	expect(t, Equals(Field("system.created_at"), Literal(now)), "(created_at = ?)", []interface{}{now})
+	expect(t, IsNull("system.updated_at"), "(updated_at IS NULL)", []interface{}{})
+}
+
 func expect(t *testing.T, expr Expression, expectedClause string, expectedParameters []interface{}) {
 	clause, parameters, err := Compile(expr)
 	if len(err) > 0 {
@@ -0,0 +1,165 @@
+package workitem
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fabric8io/almighty-core/convert"
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/rendering"
+)
+
+// FieldConstraints restricts the values a field can hold beyond what its
+// type allows. All constraints are optional and each one only applies to
+// certain kinds of fields:
+//
+//	Minimum, Maximum     integer, duration, float
+//	MinLength, MaxLength string, markup
+//	Pattern              string
+//	URLSchemes           url
+//	MaxItems             list
+type FieldConstraints struct {
+	Minimum    *float64 `json:",omitempty"`
+	Maximum    *float64 `json:",omitempty"`
+	MinLength  *int     `json:",omitempty"`
+	MaxLength  *int     `json:",omitempty"`
+	Pattern    *string  `json:",omitempty"`
+	URLSchemes []string `json:",omitempty"`
+	MaxItems   *int     `json:",omitempty"`
+}
+
+// Ensure FieldConstraints implements the Equaler interface
+var _ convert.Equaler = FieldConstraints{}
+var _ convert.Equaler = (*FieldConstraints)(nil)
+
+// Equal returns true if two FieldConstraints objects are equal; otherwise false is returned.
+func (c FieldConstraints) Equal(u convert.Equaler) bool {
+	other, ok := u.(FieldConstraints)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(c, other)
+}
+
+// CheckApplicable returns an error if the constraints cannot be used for a
+// field of the given type, e.g. because a pattern is given for an integer
+// field or the pattern is not a valid regular expression.
+func (c FieldConstraints) CheckApplicable(name string, fieldType FieldType) error {
+	kind := fieldType.GetKind()
+	if (c.Minimum != nil || c.Maximum != nil) && kind != KindInteger && kind != KindDuration && kind != KindFloat {
+		return errors.NewBadParameterError(name+".constraints", kind).Expected("minimum and maximum only for integer, duration and float fields")
+	}
+	if c.Minimum != nil && c.Maximum != nil && *c.Minimum > *c.Maximum {
+		return errors.NewBadParameterError(name+".constraints.minimum", *c.Minimum).Expected(fmt.Sprintf("not greater than maximum %v", *c.Maximum))
+	}
+	if (c.MinLength != nil || c.MaxLength != nil) && kind != KindString && kind != KindMarkup {
+		return errors.NewBadParameterError(name+".constraints", kind).Expected("minLength and maxLength only for string and markup fields")
+	}
+	if c.MinLength != nil && *c.MinLength < 0 {
+		return errors.NewBadParameterError(name+".constraints.minLength", *c.MinLength).Expected("not negative")
+	}
+	if c.MaxLength != nil && *c.MaxLength < 0 {
+		return errors.NewBadParameterError(name+".constraints.maxLength", *c.MaxLength).Expected("not negative")
+	}
+	if c.MinLength != nil && c.MaxLength != nil && *c.MinLength > *c.MaxLength {
+		return errors.NewBadParameterError(name+".constraints.minLength", *c.MinLength).Expected(fmt.Sprintf("not greater than maxLength %d", *c.MaxLength))
+	}
+	if c.Pattern != nil {
+		if kind != KindString {
+			return errors.NewBadParameterError(name+".constraints", kind).Expected("pattern only for string fields")
+		}
+		if _, err := regexp.Compile(*c.Pattern); err != nil {
+			return errors.NewBadParameterError(name+".constraints.pattern", *c.Pattern).Expected("valid regular expression")
+		}
+	}
+	if len(c.URLSchemes) > 0 && kind != KindURL {
+		return errors.NewBadParameterError(name+".constraints", kind).Expected("urlSchemes only for url fields")
+	}
+	if c.MaxItems != nil {
+		if kind != KindList {
+			return errors.NewBadParameterError(name+".constraints", kind).Expected("maxItems only for list fields")
+		}
+		if *c.MaxItems < 0 {
+			return errors.NewBadParameterError(name+".constraints.maxItems", *c.MaxItems).Expected("not negative")
+		}
+	}
+	return nil
+}
+
+// Validate checks a field value, as converted for use in the persistence
+// layer, against the constraints. Values for which a constraint does not
+// apply are accepted.
+func (c FieldConstraints) Validate(name string, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case int:
+		return c.validateNumber(name, value, float64(v))
+	case float64:
+		return c.validateNumber(name, value, v)
+	case string:
+		if err := c.validateLength(name, value, v); err != nil {
+			return err
+		}
+		if c.Pattern != nil {
+			matched, err := regexp.MatchString(*c.Pattern, v)
+			if err != nil || !matched {
+				return errors.NewBadParameterError(name, value).Expected("value matching " + *c.Pattern)
+			}
+		}
+		if len(c.URLSchemes) > 0 {
+			return c.validateURLScheme(name, value, v)
+		}
+	case rendering.MarkupContent:
+		return c.validateLength(name, value, v.Content)
+	case map[string]interface{}:
+		// markup content as stored
+		if content, ok := v[rendering.ContentKey].(string); ok {
+			return c.validateLength(name, value, content)
+		}
+	case []interface{}:
+		if c.MaxItems != nil && len(v) > *c.MaxItems {
+			return errors.NewBadParameterError(name, len(v)).Expected(fmt.Sprintf("at most %d items", *c.MaxItems))
+		}
+	}
+	return nil
+}
+
+func (c FieldConstraints) validateNumber(name string, value interface{}, number float64) error {
+	if c.Minimum != nil && number < *c.Minimum {
+		return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("at least %v", *c.Minimum))
+	}
+	if c.Maximum != nil && number > *c.Maximum {
+		return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("at most %v", *c.Maximum))
+	}
+	return nil
+}
+
+func (c FieldConstraints) validateLength(name string, value interface{}, text string) error {
+	length := utf8.RuneCountInString(text)
+	if c.MinLength != nil && length < *c.MinLength {
+		return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("at least %d characters", *c.MinLength))
+	}
+	if c.MaxLength != nil && length > *c.MaxLength {
+		// do not echo the whole text in the error message
+		return errors.NewBadParameterError(name, length).Expected(fmt.Sprintf("at most %d characters", *c.MaxLength))
+	}
+	return nil
+}
+
+func (c FieldConstraints) validateURLScheme(name string, value interface{}, text string) error {
+	u, err := url.Parse(text)
+	if err == nil {
+		for _, scheme := range c.URLSchemes {
+			if strings.EqualFold(u.Scheme, scheme) {
+				return nil
+			}
+		}
+	}
+	return errors.NewBadParameterError(name, value).Expected("url with scheme " + strings.Join(c.URLSchemes, ", "))
+}
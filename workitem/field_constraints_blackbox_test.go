@@ -0,0 +1,106 @@
+package workitem_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/resource"
+	. "github.com/fabric8io/almighty-core/workitem"
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldConstraintsValidate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	zero := 0.0
+	ten := 10.0
+	one := 1
+	five := 5
+	pattern := "^[A-Z]+-[0-9]+$"
+
+	testData := []struct {
+		name     string
+		def      FieldDefinition
+		value    interface{}
+		expected interface{}
+		valid    bool
+	}{
+		{"integer in range", FieldDefinition{Type: SimpleType{Kind: KindInteger}, Constraints: &FieldConstraints{Minimum: &zero, Maximum: &ten}}, 3, 3, true},
+		{"negative integer", FieldDefinition{Type: SimpleType{Kind: KindInteger}, Constraints: &FieldConstraints{Minimum: &zero}}, -1, nil, false},
+		{"float above maximum", FieldDefinition{Type: SimpleType{Kind: KindFloat}, Constraints: &FieldConstraints{Maximum: &ten}}, 10.5, nil, false},
+		{"string in length", FieldDefinition{Type: SimpleType{Kind: KindString}, Constraints: &FieldConstraints{MinLength: &one, MaxLength: &five}}, "abc", "abc", true},
+		{"string too long", FieldDefinition{Type: SimpleType{Kind: KindString}, Constraints: &FieldConstraints{MaxLength: &five}}, "abcdef", nil, false},
+		{"string length in characters", FieldDefinition{Type: SimpleType{Kind: KindString}, Constraints: &FieldConstraints{MaxLength: &five}}, "äöüßé", "äöüßé", true},
+		{"string matching pattern", FieldDefinition{Type: SimpleType{Kind: KindString}, Constraints: &FieldConstraints{Pattern: &pattern}}, "PLAT-123", "PLAT-123", true},
+		{"string not matching pattern", FieldDefinition{Type: SimpleType{Kind: KindString}, Constraints: &FieldConstraints{Pattern: &pattern}}, "plat 123", nil, false},
+		{"markup too long", FieldDefinition{Type: SimpleType{Kind: KindMarkup}, Constraints: &FieldConstraints{MaxLength: &five}}, rendering.NewMarkupContentFromLegacy("abcdef"), nil, false},
+		{"url with allowed scheme", FieldDefinition{Type: SimpleType{Kind: KindURL}, Constraints: &FieldConstraints{URLSchemes: []string{"https"}}}, "https://example.com", "https://example.com", true},
+		{"url with other scheme", FieldDefinition{Type: SimpleType{Kind: KindURL}, Constraints: &FieldConstraints{URLSchemes: []string{"https"}}}, "ftp://example.com/file", nil, false},
+		{"list with too many items", FieldDefinition{Type: ListType{SimpleType: SimpleType{Kind: KindList}, ComponentType: SimpleType{Kind: KindString}}, Constraints: &FieldConstraints{MaxItems: &one}}, []interface{}{"a", "b"}, nil, false},
+		{"no value", FieldDefinition{Type: SimpleType{Kind: KindInteger}, Constraints: &FieldConstraints{Minimum: &ten}}, nil, nil, true},
+	}
+	for _, d := range testData {
+		result, err := d.def.ConvertToModel("foo", d.value)
+		if d.valid {
+			require.Nil(t, err, d.name)
+			assert.Equal(t, d.expected, result, d.name)
+		} else {
+			require.NotNil(t, err, d.name)
+			assert.IsType(t, errors.BadParameterError{}, errs.Cause(err), d.name)
+			assert.Nil(t, result, d.name)
+		}
+	}
+}
+
+func TestFieldConstraintsCheckApplicable(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	zero := 0.0
+	ten := 10.0
+	five := 5
+	pattern := "^[A-Z"
+
+	assert.Nil(t, FieldConstraints{Minimum: &zero, Maximum: &ten}.CheckApplicable("foo", SimpleType{Kind: KindDuration}))
+	assert.Nil(t, FieldConstraints{MaxLength: &five}.CheckApplicable("foo", SimpleType{Kind: KindMarkup}))
+	assert.Nil(t, FieldConstraints{MaxItems: &five}.CheckApplicable("foo", ListType{SimpleType: SimpleType{Kind: KindList}, ComponentType: SimpleType{Kind: KindUser}}))
+
+	assert.NotNil(t, FieldConstraints{Minimum: &ten, Maximum: &zero}.CheckApplicable("foo", SimpleType{Kind: KindInteger}))
+	assert.NotNil(t, FieldConstraints{Minimum: &zero}.CheckApplicable("foo", SimpleType{Kind: KindString}))
+	assert.NotNil(t, FieldConstraints{MaxLength: &five}.CheckApplicable("foo", SimpleType{Kind: KindInteger}))
+	assert.NotNil(t, FieldConstraints{Pattern: &pattern}.CheckApplicable("foo", SimpleType{Kind: KindString}))
+	assert.NotNil(t, FieldConstraints{URLSchemes: []string{"https"}}.CheckApplicable("foo", SimpleType{Kind: KindString}))
+	assert.NotNil(t, FieldConstraints{MaxItems: &five}.CheckApplicable("foo", SimpleType{Kind: KindString}))
+}
+
+func TestFieldConstraintsMarshalling(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	zero := 0.0
+	max := 255
+	def := FieldDefinition{
+		Label: "Story Points",
+		Type:  SimpleType{Kind: KindFloat},
+		Constraints: &FieldConstraints{
+			Minimum:   &zero,
+			MaxLength: &max,
+		},
+	}
+	bytes, err := json.Marshal(def)
+	require.Nil(t, err)
+	assert.False(t, strings.Contains(string(bytes), "Pattern"))
+	unmarshalled := FieldDefinition{}
+	require.Nil(t, json.Unmarshal(bytes, &unmarshalled))
+	assert.True(t, def.Equal(unmarshalled))
+	// definitions without constraints stay without constraints
+	def.Constraints = nil
+	bytes, err = json.Marshal(def)
+	require.Nil(t, err)
+	unmarshalled = FieldDefinition{}
+	require.Nil(t, json.Unmarshal(bytes, &unmarshalled))
+	assert.Nil(t, unmarshalled.Constraints)
+}
@@ -0,0 +1,112 @@
+package workitem
+
+import (
+	"reflect"
+
+	"github.com/fabric8io/almighty-core/convert"
+	"github.com/fabric8io/almighty-core/errors"
+)
+
+// DefaultKind tells how the default value of a field is determined
+type DefaultKind string
+
+// constants for the possible kinds of default values
+const (
+	// DefaultLiteral uses a fixed value
+	DefaultLiteral DefaultKind = "literal"
+	// DefaultCurrentUser uses the user who creates the work item
+	DefaultCurrentUser DefaultKind = "current-user"
+	// DefaultNow uses the time or the day at which the work item is created
+	DefaultNow DefaultKind = "now"
+	// DefaultRootArea uses the root area of the space
+	DefaultRootArea DefaultKind = "root-area"
+	// DefaultRootIteration uses the root iteration of the space
+	DefaultRootIteration DefaultKind = "root-iteration"
+	// DefaultCurrentIteration uses the running iteration of the space, or
+	// the root iteration if no iteration is running
+	DefaultCurrentIteration DefaultKind = "current-iteration"
+)
+
+// FieldDefault describes the value a field gets when a work item is created
+// without a value for it
+type FieldDefault struct {
+	Kind DefaultKind
+	// Value holds the value of a literal default in the representation of
+	// the REST API layer
+	Value interface{} `json:",omitempty"`
+}
+
+// Ensure FieldDefault implements the Equaler interface
+var _ convert.Equaler = FieldDefault{}
+var _ convert.Equaler = (*FieldDefault)(nil)
+
+// Equal returns true if two FieldDefault objects are equal; otherwise false is returned.
+func (d FieldDefault) Equal(u convert.Equaler) bool {
+	other, ok := u.(FieldDefault)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(d, other)
+}
+
+// equalDefaults returns true if both defaults are nil or equal
+func equalDefaults(a, b *FieldDefault) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// CheckApplicable returns an error if the default cannot be used for a field
+// of the given type, e.g. because "now" is given for a string field or the
+// literal value cannot be converted to the type of the field.
+func (d FieldDefault) CheckApplicable(name string, fieldType FieldType) error {
+	kind := fieldType.GetKind()
+	if listType, ok := fieldType.(ListType); ok {
+		kind = listType.ComponentType.GetKind()
+	}
+	if listType, ok := fieldType.(*ListType); ok {
+		kind = listType.ComponentType.GetKind()
+	}
+	switch d.Kind {
+	case DefaultLiteral:
+		if d.Value == nil {
+			return errors.NewBadParameterError(name+".default.value", nil).Expected("not nil")
+		}
+		if _, err := d.literal(fieldType); err != nil {
+			return errors.NewBadParameterError(name+".default.value", d.Value).Expected("value of kind " + string(fieldType.GetKind()))
+		}
+		return nil
+	case DefaultCurrentUser:
+		if kind == KindUser {
+			return nil
+		}
+	case DefaultNow:
+		if kind == KindInstant || kind == KindDate {
+			return nil
+		}
+	case DefaultRootArea:
+		if kind == KindArea {
+			return nil
+		}
+	case DefaultRootIteration, DefaultCurrentIteration:
+		if kind == KindIteration {
+			return nil
+		}
+	default:
+		return errors.NewBadParameterError(name+".default.kind", d.Kind).Expected("one of literal, current-user, now, root-area, root-iteration or current-iteration")
+	}
+	return errors.NewBadParameterError(name+".default.kind", d.Kind).Expected("default applicable for fields of kind " + string(fieldType.GetKind()))
+}
+
+// literal returns the literal default value in the representation of the
+// REST API layer for the given field type
+func (d FieldDefault) literal(fieldType FieldType) (interface{}, error) {
+	// values read from JSON need to be brought into the exact Go type that the
+	// field type expects, e.g. float64 into int for integer fields
+	value, err := convertValueToFieldType(d.Value, fieldType)
+	if err != nil {
+		return nil, err
+	}
+	return fieldType.ConvertFromModel(value)
+}
@@ -0,0 +1,50 @@
+package workitem_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fabric8io/almighty-core/resource"
+	. "github.com/fabric8io/almighty-core/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldDefaultCheckApplicable(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	users := ListType{SimpleType: SimpleType{Kind: KindList}, ComponentType: SimpleType{Kind: KindUser}}
+	states := EnumType{SimpleType: SimpleType{Kind: KindEnum}, BaseType: SimpleType{Kind: KindString}, Values: []interface{}{"new", "open"}}
+
+	assert.Nil(t, FieldDefault{Kind: DefaultLiteral, Value: 3.0}.CheckApplicable("foo", SimpleType{Kind: KindInteger}))
+	assert.Nil(t, FieldDefault{Kind: DefaultLiteral, Value: "new"}.CheckApplicable("foo", states))
+	assert.Nil(t, FieldDefault{Kind: DefaultLiteral, Value: "2017-06-01T00:00:00Z"}.CheckApplicable("foo", SimpleType{Kind: KindInstant}))
+	assert.Nil(t, FieldDefault{Kind: DefaultCurrentUser}.CheckApplicable("foo", users))
+	assert.Nil(t, FieldDefault{Kind: DefaultNow}.CheckApplicable("foo", SimpleType{Kind: KindInstant}))
+	assert.Nil(t, FieldDefault{Kind: DefaultRootArea}.CheckApplicable("foo", SimpleType{Kind: KindArea}))
+	assert.Nil(t, FieldDefault{Kind: DefaultCurrentIteration}.CheckApplicable("foo", SimpleType{Kind: KindIteration}))
+
+	assert.NotNil(t, FieldDefault{Kind: DefaultLiteral}.CheckApplicable("foo", SimpleType{Kind: KindString}))
+	assert.NotNil(t, FieldDefault{Kind: DefaultLiteral, Value: "many"}.CheckApplicable("foo", SimpleType{Kind: KindInteger}))
+	assert.NotNil(t, FieldDefault{Kind: DefaultLiteral, Value: "closed"}.CheckApplicable("foo", states))
+	assert.NotNil(t, FieldDefault{Kind: DefaultNow}.CheckApplicable("foo", SimpleType{Kind: KindString}))
+	assert.NotNil(t, FieldDefault{Kind: DefaultCurrentUser}.CheckApplicable("foo", SimpleType{Kind: KindArea}))
+	assert.NotNil(t, FieldDefault{Kind: DefaultRootIteration}.CheckApplicable("foo", SimpleType{Kind: KindArea}))
+	assert.NotNil(t, FieldDefault{Kind: "tomorrow"}.CheckApplicable("foo", SimpleType{Kind: KindInstant}))
+}
+
+func TestFieldDefaultMarshalling(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	def := FieldDefinition{
+		Label:   "State",
+		Type:    SimpleType{Kind: KindString},
+		Default: &FieldDefault{Kind: DefaultLiteral, Value: "new"},
+	}
+	bytes, err := json.Marshal(def)
+	require.Nil(t, err)
+	unmarshalled := FieldDefinition{}
+	require.Nil(t, json.Unmarshal(bytes, &unmarshalled))
+	assert.True(t, def.Equal(unmarshalled))
+	assert.Equal(t, def.Default, unmarshalled.Default)
+}
@@ -28,6 +28,9 @@ const (
 	KindMarkup            Kind = "markup"
 	KindArea              Kind = "area"
 	KindCodebase          Kind = "codebase"
+	KindBoolean           Kind = "boolean"
+	KindDate              Kind = "date"
+	KindLabel             Kind = "label"
 )
 
 // Kind is the kind of field type
@@ -55,6 +58,11 @@ type FieldDefinition struct {
 	Label       string
 	Description string
 	Type        FieldType
+	// Constraints optionally restrict the values of the field beyond its type
+	Constraints *FieldConstraints `json:",omitempty"`
+	// Default optionally defines the value of the field for new work items
+	// that are created without a value for it
+	Default *FieldDefault `json:",omitempty"`
 }
 
 // Ensure FieldDefinition implements the Equaler interface
@@ -76,15 +84,36 @@ func (f FieldDefinition) Equal(u convert.Equaler) bool {
 	if f.Description != other.Description {
 		return false
 	}
+	if !equalConstraints(f.Constraints, other.Constraints) {
+		return false
+	}
+	if !equalDefaults(f.Default, other.Default) {
+		return false
+	}
 	return f.Type.Equal(other.Type)
 }
 
+// equalConstraints returns true if both constraints are nil or equal
+func equalConstraints(a, b *FieldConstraints) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
 // ConvertToModel converts a field value for use in the persistence layer
 func (f FieldDefinition) ConvertToModel(name string, value interface{}) (interface{}, error) {
 	if f.Required && (value == nil || (f.Type.GetKind() == KindString && strings.TrimSpace(value.(string)) == "")) {
 		return nil, fmt.Errorf("Value %s is required", name)
 	}
-	return f.Type.ConvertToModel(value)
+	result, err := f.Type.ConvertToModel(value)
+	if err != nil || f.Constraints == nil {
+		return result, err
+	}
+	if err := f.Constraints.Validate(name, result); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return result, nil
 }
 
 // ConvertFromModel converts a field value for use in the REST API layer
@@ -100,6 +129,8 @@ type rawFieldDef struct {
 	Label       string
 	Description string
 	Type        *json.RawMessage
+	Constraints *FieldConstraints
+	Default     *FieldDefault
 }
 
 // Ensure rawFieldDef implements the Equaler interface
@@ -121,6 +152,12 @@ func (f rawFieldDef) Equal(u convert.Equaler) bool {
 	if f.Description != other.Description {
 		return false
 	}
+	if !equalConstraints(f.Constraints, other.Constraints) {
+		return false
+	}
+	if !equalDefaults(f.Default, other.Default) {
+		return false
+	}
 	if f.Type == nil && other.Type == nil {
 		return true
 	}
@@ -152,21 +189,21 @@ func (f *FieldDefinition) UnmarshalJSON(bytes []byte) error {
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints, Default: temp.Default}
 	case KindEnum:
 		theType := EnumType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints, Default: temp.Default}
 	default:
 		theType := SimpleType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints, Default: temp.Default}
 	}
 	return nil
 }
@@ -182,7 +219,7 @@ func ConvertAnyToKind(any interface{}) (*Kind, error) {
 func ConvertStringToKind(k string) (*Kind, error) {
 	kind := Kind(k)
 	switch kind {
-	case KindString, KindInteger, KindFloat, KindInstant, KindDuration, KindURL, KindWorkitemReference, KindUser, KindEnum, KindList, KindIteration, KindMarkup, KindArea, KindCodebase:
+	case KindString, KindInteger, KindFloat, KindInstant, KindDuration, KindURL, KindWorkitemReference, KindUser, KindEnum, KindList, KindIteration, KindMarkup, KindArea, KindCodebase, KindBoolean, KindDate, KindLabel:
 		return &kind, nil
 	}
 	return nil, fmt.Errorf("kind '%s' is not a simple type", k)
@@ -190,10 +227,40 @@ func ConvertStringToKind(k string) (*Kind, error) {
 
 // compatibleFields returns true if the existing and new field are compatible;
 // otherwise false is returned. It does so by comparing all members of the field
-// definition except for the label and description.
+// definition except for the label and description. Adding values to an enum
+// field keeps it compatible, since its existing values remain valid.
 func compatibleFields(existing FieldDefinition, new FieldDefinition) bool {
 	if existing.Required != new.Required {
 		return false
 	}
-	return reflect.DeepEqual(existing.Type, new.Type)
+	if reflect.DeepEqual(existing.Type, new.Type) {
+		return true
+	}
+	existingEnum, ok := toEnumType(existing.Type)
+	if !ok {
+		return false
+	}
+	newEnum, ok := toEnumType(new.Type)
+	if !ok || !reflect.DeepEqual(existingEnum.BaseType, newEnum.BaseType) {
+		return false
+	}
+	for _, value := range existingEnum.Values {
+		if !contains(newEnum.Values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// toEnumType returns the given field type as an enum type, if it is one
+func toEnumType(fieldType FieldType) (EnumType, bool) {
+	switch t := fieldType.(type) {
+	case EnumType:
+		return t, true
+	case *EnumType:
+		if t != nil {
+			return *t, true
+		}
+	}
+	return EnumType{}, false
 }
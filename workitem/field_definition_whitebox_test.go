@@ -66,4 +66,22 @@ func TestCompatibleFields(t *testing.T) {
 		// then
 		assert.False(t, compatibleFields(a, d), "fields %+v and %+v are not detected as being incompatible", a, d)
 	})
+	t.Run("enum values", func(t *testing.T) {
+		t.Parallel()
+		// given
+		enum := func(values ...interface{}) FieldDefinition {
+			return FieldDefinition{
+				Label: "e",
+				Type: EnumType{
+					SimpleType: SimpleType{Kind: KindEnum},
+					BaseType:   SimpleType{Kind: KindString},
+					Values:     values,
+				},
+			}
+		}
+		e := enum("low", "high")
+		// then
+		assert.True(t, compatibleFields(e, enum("low", "medium", "high")), "adding an enum value is not detected as being compatible")
+		assert.False(t, compatibleFields(e, enum("low")), "removing an enum value is not detected as being incompatible")
+	})
 }
@@ -8,8 +8,10 @@ import (
 	"context"
 
 	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
 	"github.com/fabric8io/almighty-core/gormsupport"
 	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/space"
 	"github.com/fabric8io/almighty-core/workitem"
 
 	"github.com/goadesign/goa"
@@ -35,9 +37,13 @@ type WorkItemLinkRepository interface {
 	ListByWorkItemID(ctx context.Context, wiIDStr string) ([]WorkItemLink, error)
 	DeleteRelatedLinks(ctx context.Context, wiIDStr string, suppressorID uuid.UUID) error
 	Delete(ctx context.Context, ID uuid.UUID, suppressorID uuid.UUID) error
+	Restore(ctx context.Context, ID uuid.UUID, restorerID uuid.UUID) (*WorkItemLink, error)
+	RestoreRelatedLinks(ctx context.Context, wiIDStr string, deletedSince time.Time, restorerID uuid.UUID) error
+	ListDeleted(ctx context.Context, spaceID uuid.UUID, since *time.Time, start *int, limit *int) ([]WorkItemLink, uint64, error)
 	Save(ctx context.Context, linkCat WorkItemLink, modifierID uuid.UUID) (*WorkItemLink, error)
 	ListWorkItemChildren(ctx context.Context, parent string, start *int, limit *int) ([]workitem.WorkItem, uint64, error)
 	WorkItemHasChildren(ctx context.Context, parent string) (bool, error)
+	ValidateLinksInSpace(ctx context.Context, wiIDStr string, spaceID uuid.UUID) error
 }
 
 // NewWorkItemLinkRepository creates a work item link repository based on gorm
@@ -177,9 +183,29 @@ func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targe
 	if err := r.revisionRepo.Create(ctx, creatorID, RevisionTypeCreate, *link); err != nil {
 		return nil, errs.Wrapf(err, "error while creating work item")
 	}
+	created := event.LinkCreated{
+		LinkID:     link.ID,
+		LinkTypeID: link.LinkTypeID,
+		SourceID:   strconv.FormatUint(link.SourceID, 10),
+		TargetID:   strconv.FormatUint(link.TargetID, 10),
+	}
+	if err := event.Record(ctx, r.db, r.spaceOf(link.SourceID), creatorID, created); err != nil {
+		return nil, errs.Wrapf(err, "error while creating work item link")
+	}
 	return link, nil
 }
 
+// spaceOf returns the space of the work item with the given ID, even if the
+// work item was deleted, or uuid.Nil if there is no such work item
+func (r *GormWorkItemLinkRepository) spaceOf(wiID uint64) uuid.UUID {
+	var spaceID uuid.UUID
+	row := r.db.Raw("SELECT space_id FROM work_items WHERE id = ?", wiID).Row()
+	if err := row.Scan(&spaceID); err != nil {
+		return uuid.Nil
+	}
+	return spaceID
+}
+
 // Load returns the work item link for the given ID.
 // Returns NotFoundError, ConversionError or InternalError
 func (r *GormWorkItemLinkRepository) Load(ctx context.Context, ID uuid.UUID) (*WorkItemLink, error) {
@@ -216,6 +242,31 @@ func (r *GormWorkItemLinkRepository) ListByWorkItemID(ctx context.Context, wiIDS
 	return modelLinks, nil
 }
 
+// ValidateLinksInSpace returns a BadParameterError if a link of the given work
+// item is of a link type which is neither defined by the given space nor by
+// the system space, or if the work items no longer match the source and
+// target types of the link type. It is used when a work item is moved to
+// another space.
+func (r *GormWorkItemLinkRepository) ValidateLinksInSpace(ctx context.Context, wiIDStr string, spaceID uuid.UUID) error {
+	links, err := r.ListByWorkItemID(ctx, wiIDStr)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	for _, l := range links {
+		linkType, err := r.workItemLinkTypeRepo.Load(ctx, l.LinkTypeID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if !uuid.Equal(linkType.SpaceID, spaceID) && !uuid.Equal(linkType.SpaceID, space.SystemSpace) {
+			return errors.NewBadParameterError("link type", linkType.ID).Expected("link type of the target space or of the system space")
+		}
+		if err := r.ValidateCorrectSourceAndTargetType(ctx, l.SourceID, l.TargetID, l.LinkTypeID); err != nil {
+			return errs.WithStack(err)
+		}
+	}
+	return nil
+}
+
 // List returns all work item links if wiID is nil; otherwise the work item links are returned
 // that have wiID as source or target.
 // TODO: Handle pagination
@@ -283,6 +334,146 @@ func (r *GormWorkItemLinkRepository) deleteLink(ctx context.Context, lnk WorkIte
 	if err := r.revisionRepo.Create(ctx, suppressorID, RevisionTypeDelete, lnk); err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	deleted := event.LinkDeleted{
+		LinkID:     lnk.ID,
+		LinkTypeID: lnk.LinkTypeID,
+		SourceID:   strconv.FormatUint(lnk.SourceID, 10),
+		TargetID:   strconv.FormatUint(lnk.TargetID, 10),
+	}
+	if err := event.Record(ctx, r.db, r.spaceOf(lnk.SourceID), suppressorID, deleted); err != nil {
+		return errs.Wrapf(err, "error while deleting work item link")
+	}
+	return nil
+}
+
+// Restore brings back the soft-deleted work item link with the given id
+// returns NotFoundError, BadParameterError or InternalError
+func (r *GormWorkItemLinkRepository) Restore(ctx context.Context, linkID uuid.UUID, restorerID uuid.UUID) (*WorkItemLink, error) {
+	lnk := WorkItemLink{}
+	tx := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", linkID).First(&lnk)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("deleted work item link", linkID.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error)
+	}
+	if err := r.restoreLink(ctx, &lnk, restorerID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return &lnk, nil
+}
+
+// RestoreRelatedLinks brings back the links in which the source or target
+// equals the given work item ID and which were deleted at or after the given
+// time, i.e. along with the work item. Links which can no longer be restored,
+// for example because the work item at the other end is still deleted, are
+// skipped.
+func (r *GormWorkItemLinkRepository) RestoreRelatedLinks(ctx context.Context, wiIDStr string, deletedSince time.Time, restorerID uuid.UUID) error {
+	log.Info(ctx, map[string]interface{}{
+		"workitem_id": wiIDStr,
+	}, "Restoring the links related to work item")
+
+	wiID, err := strconv.ParseUint(wiIDStr, 10, 64)
+	if err != nil {
+		// treat as not found: clients don't know it must be a uint64
+		return errors.NewNotFoundError("work item link", wiIDStr)
+	}
+	var workitemLinks = []WorkItemLink{}
+	tx := r.db.Unscoped().Where("? in (source_id, target_id) AND deleted_at >= ?", wiID, deletedSince).Order("deleted_at").Find(&workitemLinks)
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	// restore one by one to trigger the creation of a new work item link revision
+	for i := range workitemLinks {
+		err := r.restoreLink(ctx, &workitemLinks[i], restorerID)
+		if _, ok := errs.Cause(err).(errors.BadParameterError); ok {
+			log.Info(ctx, map[string]interface{}{
+				"wil_id": workitemLinks[i].ID,
+				"err":    err,
+			}, "skipping the restoration of the work item link")
+			continue
+		}
+		if err != nil {
+			return errs.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// ListDeleted returns the deleted links whose source work item belongs to the
+// given space, most recently deleted first. If since is given, only the links
+// deleted after that time are returned.
+func (r *GormWorkItemLinkRepository) ListDeleted(ctx context.Context, spaceID uuid.UUID, since *time.Time, start *int, limit *int) ([]WorkItemLink, uint64, error) {
+	db := r.db.Unscoped().Model(&WorkItemLink{}).Where("deleted_at IS NOT NULL AND source_id IN (SELECT id FROM work_items WHERE space_id = ?)", spaceID)
+	if since != nil {
+		db = db.Where("deleted_at > ?", *since)
+	}
+	var count uint64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to count deleted work item links"))
+	}
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		db = db.Offset(*start)
+	}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		db = db.Limit(*limit)
+	}
+	result := []WorkItemLink{}
+	if err := db.Order("deleted_at desc").Find(&result).Error; err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to list deleted work item links"))
+	}
+	return result, count, nil
+}
+
+// restoreLink brings back the given soft-deleted link after making sure that
+// both of its work items exist and that it does not break the topology of its
+// link type.
+func (r *GormWorkItemLinkRepository) restoreLink(ctx context.Context, lnk *WorkItemLink, restorerID uuid.UUID) error {
+	var count int
+	tx := r.db.Model(&workitem.WorkItemStorage{}).Where("id IN (?, ?)", lnk.SourceID, lnk.TargetID).Count(&count)
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	if count != 2 {
+		return errors.NewBadParameterError("work item link", lnk.ID).Expected("existing source and target work items")
+	}
+	tx = r.db.Model(&WorkItemLink{}).Where("source_id = ? AND target_id = ? AND link_type_id = ?", lnk.SourceID, lnk.TargetID, lnk.LinkTypeID).Count(&count)
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	if count > 0 {
+		return errors.NewBadParameterError("work item link", lnk.ID).Expected("unique")
+	}
+	linkType, err := r.workItemLinkTypeRepo.Load(ctx, lnk.LinkTypeID)
+	if err != nil {
+		return errs.Wrap(err, "failed to load link type")
+	}
+	if err := r.ValidateTopology(ctx, lnk.TargetID, linkType); err != nil {
+		return errs.WithStack(err)
+	}
+	lnk.DeletedAt = nil
+	lnk.Version = lnk.Version + 1
+	tx = r.db.Unscoped().Model(lnk).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    lnk.Version,
+	})
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wil_id": lnk.ID,
+			"err":    tx.Error,
+		}, "unable to restore work item link")
+		return errors.NewInternalError(tx.Error)
+	}
+	// save a revision of the restored work item link
+	if err := r.revisionRepo.Create(ctx, restorerID, RevisionTypeRestore, *lnk); err != nil {
+		return errs.Wrapf(err, "error while restoring work item link")
+	}
 	return nil
 }
 
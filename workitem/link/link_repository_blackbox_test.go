@@ -4,8 +4,10 @@ import (
 	"context"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/errors"
 	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
 	"github.com/fabric8io/almighty-core/gormtestsupport"
 	"github.com/fabric8io/almighty-core/migration"
@@ -14,7 +16,9 @@ import (
 	testsupport "github.com/fabric8io/almighty-core/test"
 	"github.com/fabric8io/almighty-core/workitem"
 	"github.com/fabric8io/almighty-core/workitem/link"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -222,3 +226,93 @@ func (s *linkRepoBlackBoxTest) TestCountChildWorkitems() {
 	require.Len(s.T(), res, 1)
 	require.Equal(s.T(), 3, int(count))
 }
+
+func (s *linkRepoBlackBoxTest) TestRestoreLinks() {
+	// given
+	workitemRepository := workitem.NewWorkItemRepository(s.DB)
+	createWorkItem := func(title string) (string, uint64) {
+		wi, err := workitemRepository.Create(
+			s.ctx, s.testSpace, workitem.SystemBug,
+			map[string]interface{}{
+				workitem.SystemTitle: title,
+				workitem.SystemState: workitem.SystemStateNew,
+			}, s.testIdentity.ID)
+		require.Nil(s.T(), err)
+		id, err := strconv.ParseUint(wi.ID, 10, 64)
+		require.Nil(s.T(), err)
+		return wi.ID, id
+	}
+	_, parent1ID := createWorkItem("Parent 1")
+	_, parent2ID := createWorkItem("Parent 2")
+	childStrID, childID := createWorkItem("Child")
+	linkCategory, err := link.NewWorkItemLinkCategoryRepository(s.DB).Create(s.ctx, &link.WorkItemLinkCategory{
+		Name: "test" + uuid.NewV4().String(),
+	})
+	require.Nil(s.T(), err)
+	linkType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, &link.WorkItemLinkType{
+		Name:           "TestRestoreTreeLinkType",
+		SourceTypeID:   workitem.SystemBug,
+		TargetTypeID:   workitem.SystemBug,
+		ForwardName:    "foo",
+		ReverseName:    "foo",
+		Topology:       "tree",
+		LinkCategoryID: linkCategory.ID,
+		SpaceID:        s.testSpace,
+	})
+	require.Nil(s.T(), err)
+	l, err := s.repo.Create(s.ctx, parent1ID, childID, linkType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+
+	s.T().Run("single link", func(t *testing.T) {
+		// given
+		require.Nil(t, s.repo.Delete(s.ctx, l.ID, s.testIdentity.ID))
+		// when
+		restored, err := s.repo.Restore(s.ctx, l.ID, s.testIdentity.ID)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, l.Version+1, restored.Version)
+		_, err = s.repo.Load(s.ctx, l.ID)
+		require.Nil(t, err)
+	})
+
+	s.T().Run("related links", func(t *testing.T) {
+		// given
+		deletedSince := time.Now()
+		require.Nil(t, workitemRepository.Delete(s.ctx, s.testSpace, childStrID, s.testIdentity.ID))
+		require.Nil(t, s.repo.DeleteRelatedLinks(s.ctx, childStrID, s.testIdentity.ID))
+		deleted, count, err := s.repo.ListDeleted(s.ctx, s.testSpace, &deletedSince, nil, nil)
+		require.Nil(t, err)
+		assert.Equal(t, uint64(1), count)
+		require.Len(t, deleted, 1)
+		assert.Equal(t, l.ID, deleted[0].ID)
+		// the link cannot be restored while the child is deleted
+		_, err = s.repo.Restore(s.ctx, l.ID, s.testIdentity.ID)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		_, _, err = workitemRepository.Restore(s.ctx, s.testSpace, childStrID, s.testIdentity.ID)
+		require.Nil(t, err)
+		// when
+		err = s.repo.RestoreRelatedLinks(s.ctx, childStrID, deletedSince, s.testIdentity.ID)
+		// then
+		require.Nil(t, err)
+		links, err := s.repo.ListByWorkItemID(s.ctx, childStrID)
+		require.Nil(t, err)
+		require.Len(t, links, 1)
+		assert.Equal(t, l.ID, links[0].ID)
+	})
+
+	s.T().Run("related links breaking the topology are skipped", func(t *testing.T) {
+		// given
+		deletedSince := time.Now()
+		require.Nil(t, s.repo.DeleteRelatedLinks(s.ctx, childStrID, s.testIdentity.ID))
+		l2, err := s.repo.Create(s.ctx, parent2ID, childID, linkType.ID, s.testIdentity.ID)
+		require.Nil(t, err)
+		// when
+		err = s.repo.RestoreRelatedLinks(s.ctx, childStrID, deletedSince, s.testIdentity.ID)
+		// then
+		require.Nil(t, err)
+		links, err := s.repo.ListByWorkItemID(s.ctx, childStrID)
+		require.Nil(t, err)
+		require.Len(t, links, 1)
+		assert.Equal(t, l2.ID, links[0].ID)
+	})
+}
@@ -18,6 +18,8 @@ const (
 	_                  // ignore 3rd value
 	// RevisionTypeUpdate a work item link update
 	RevisionTypeUpdate // 4
+	// RevisionTypeRestore a work item link restoration after its deletion
+	RevisionTypeRestore // 5
 )
 
 // Revision represents a version of a work item link
@@ -10,6 +10,7 @@ import (
 	"github.com/fabric8io/almighty-core/rendering"
 	"github.com/asaskevich/govalidator"
 	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 // SimpleType is an unstructured FieldType
@@ -66,16 +67,41 @@ func (fieldType SimpleType) ConvertToModel(value interface{}) (interface{}, erro
 		return value, nil
 	case KindInstant:
 		// instant == milliseconds
-		if !valueType.Implements(timeType) {
+		if valueType != timeType {
 			return nil, errs.Errorf("value %v should be %s, but is %s", value, "time.Time", valueType.Name())
 		}
 		return value.(time.Time).UnixNano(), nil
 	case KindWorkitemReference:
+		// references are stored as the string ID of the work item, like the
+		// references to users, iterations and areas
 		if valueType.Kind() != reflect.String {
 			return nil, errs.Errorf("value %v should be %s, but is %s", value, "string", valueType.Name())
 		}
-		idValue, err := strconv.Atoi(value.(string))
-		return idValue, errs.WithStack(err)
+		if _, err := strconv.ParseUint(value.(string), 10, 64); err != nil {
+			return nil, errs.Errorf("value %v should be %s", value, "a work item ID")
+		}
+		return value, nil
+	case KindLabel:
+		if valueType.Kind() != reflect.String {
+			return nil, errs.Errorf("value %v should be %s, but is %s", value, "string", valueType.Name())
+		}
+		if _, err := uuid.FromString(value.(string)); err != nil {
+			return nil, errs.Errorf("value %v should be %s", value, "a label ID")
+		}
+		return value, nil
+	case KindBoolean:
+		if valueType.Kind() != reflect.Bool {
+			return nil, errs.Errorf("value %v should be %s, but is %s", value, "bool", valueType.Name())
+		}
+		return value, nil
+	case KindDate:
+		date, err := toDate(value)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		// dates are stored like instants, as the nanoseconds of midnight UTC,
+		// so that they can be compared with the same queries
+		return date.UnixNano(), nil
 	case KindList:
 		if (valueType.Kind() != reflect.Array) && (valueType.Kind() != reflect.Slice) {
 			return nil, errs.Errorf("value %v should be %s, but is %s,", value, "array/slice", valueType.Kind())
@@ -114,15 +140,31 @@ func (fieldType SimpleType) ConvertFromModel(value interface{}) (interface{}, er
 	}
 	valueType := reflect.TypeOf(value)
 	switch fieldType.GetKind() {
-	case KindString, KindURL, KindUser, KindInteger, KindFloat, KindDuration, KindIteration, KindArea:
+	case KindString, KindURL, KindUser, KindInteger, KindFloat, KindDuration, KindIteration, KindArea, KindLabel, KindBoolean:
 		return value, nil
 	case KindInstant:
-		return time.Unix(0, value.(int64)), nil
+		nanos, err := toNanos(value)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		return time.Unix(0, nanos), nil
+	case KindDate:
+		nanos, err := toNanos(value)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		return time.Unix(0, nanos).UTC().Format(DateFormat), nil
 	case KindWorkitemReference:
-		if valueType.Kind() != reflect.String {
-			return nil, errs.Errorf("value %v should be %s, but is %s", value, "string", valueType.Name())
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64:
+			// references stored as numbers by earlier versions
+			return strconv.FormatUint(uint64(v), 10), nil
+		case int:
+			return strconv.Itoa(v), nil
 		}
-		return strconv.FormatUint(value.(uint64), 10), nil
+		return nil, errs.Errorf("value %v should be %s, but is %s", value, "string", valueType.Name())
 	case KindMarkup:
 		if valueType.Kind() != reflect.Map {
 			return nil, errs.Errorf("value %v should be %s, but is %s", value, reflect.Map, valueType.Name())
@@ -142,3 +184,33 @@ func (fieldType SimpleType) ConvertFromModel(value interface{}) (interface{}, er
 		return nil, errs.Errorf("unexpected field type: %s", fieldType.GetKind())
 	}
 }
+
+// DateFormat is the representation of values of fields of kind date in the
+// REST API layer
+const DateFormat = "2006-01-02"
+
+// toDate returns midnight UTC of the calendar day given as a string in
+// DateFormat or as the day of a time.Time in its own location
+func toDate(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case string:
+		return time.Parse(DateFormat, v)
+	case time.Time:
+		return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, errs.Errorf("value %v should be %s, but is %T", value, "a date", value)
+}
+
+// toNanos returns the nanoseconds of a stored instant or date. Values read
+// from the json of the database are float64.
+func toNanos(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case float64:
+		return int64(v), nil
+	}
+	return 0, errs.Errorf("value %v should be %s, but is %T", value, "int64", value)
+}
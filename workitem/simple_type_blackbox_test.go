@@ -2,11 +2,14 @@ package workitem_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/fabric8io/almighty-core/convert"
 	"github.com/fabric8io/almighty-core/resource"
 	. "github.com/fabric8io/almighty-core/workitem"
+	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSimpleTypeEqual(t *testing.T) {
@@ -38,3 +41,107 @@ func TestConvertToModel(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, res)
 }
+
+func TestConvertBoolean(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	boolean := SimpleType{Kind: KindBoolean}
+
+	stored, err := boolean.ConvertToModel(true)
+	require.Nil(t, err)
+	assert.Equal(t, true, stored)
+	value, err := boolean.ConvertFromModel(stored)
+	require.Nil(t, err)
+	assert.Equal(t, true, value)
+
+	_, err = boolean.ConvertToModel("true")
+	assert.NotNil(t, err)
+}
+
+func TestConvertInstant(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	instant := SimpleType{Kind: KindInstant}
+	now := time.Date(2017, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	stored, err := instant.ConvertToModel(now)
+	require.Nil(t, err)
+	assert.Equal(t, now.UnixNano(), stored)
+	// values read from the database are float64
+	value, err := instant.ConvertFromModel(float64(stored.(int64)))
+	require.Nil(t, err)
+	assert.True(t, now.Equal(value.(time.Time)))
+
+	_, err = instant.ConvertToModel(&now)
+	assert.NotNil(t, err)
+	_, err = instant.ConvertToModel("2017-06-01T12:30:00Z")
+	assert.NotNil(t, err)
+}
+
+func TestConvertDate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	date := SimpleType{Kind: KindDate}
+
+	stored, err := date.ConvertToModel("2017-06-01")
+	require.Nil(t, err)
+	assert.Equal(t, time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC).UnixNano(), stored)
+	// values read from the database are float64
+	value, err := date.ConvertFromModel(float64(stored.(int64)))
+	require.Nil(t, err)
+	assert.Equal(t, "2017-06-01", value)
+
+	// the day of a time is taken in its own location
+	tokyo := time.FixedZone("JST", 9*60*60)
+	stored, err = date.ConvertToModel(time.Date(2017, 6, 1, 2, 0, 0, 0, tokyo))
+	require.Nil(t, err)
+	value, err = date.ConvertFromModel(stored)
+	require.Nil(t, err)
+	assert.Equal(t, "2017-06-01", value)
+
+	_, err = date.ConvertToModel("01.06.2017")
+	assert.NotNil(t, err)
+}
+
+func TestConvertReferences(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("work item", func(t *testing.T) {
+		workItem := SimpleType{Kind: KindWorkitemReference}
+		stored, err := workItem.ConvertToModel("42")
+		require.Nil(t, err)
+		assert.Equal(t, "42", stored)
+		value, err := workItem.ConvertFromModel(stored)
+		require.Nil(t, err)
+		assert.Equal(t, "42", value)
+		// references stored as numbers
+		value, err = workItem.ConvertFromModel(float64(42))
+		require.Nil(t, err)
+		assert.Equal(t, "42", value)
+
+		_, err = workItem.ConvertToModel("forty-two")
+		assert.NotNil(t, err)
+		_, err = workItem.ConvertToModel(42)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("label", func(t *testing.T) {
+		label := SimpleType{Kind: KindLabel}
+		id := uuid.NewV4().String()
+		stored, err := label.ConvertToModel(id)
+		require.Nil(t, err)
+		assert.Equal(t, id, stored)
+		_, err = label.ConvertToModel("bug")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("list of work items", func(t *testing.T) {
+		workItems := ListType{SimpleType: SimpleType{Kind: KindList}, ComponentType: SimpleType{Kind: KindWorkitemReference}}
+		stored, err := workItems.ConvertToModel([]interface{}{"1", "2"})
+		require.Nil(t, err)
+		assert.Equal(t, []interface{}{"1", "2"}, stored)
+		_, err = workItems.ConvertToModel([]interface{}{"1", "two"})
+		assert.NotNil(t, err)
+	})
+}
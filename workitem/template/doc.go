@@ -0,0 +1,3 @@
+// Package template provides all the required functions to manage the
+// templates from which the work items of a space can be created.
+package template
@@ -0,0 +1,238 @@
+package template
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/gormsupport"
+	"github.com/fabric8io/almighty-core/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeTemplates is the JSON API type of work item templates
+const APIStringTypeTemplates = "workitemtemplates"
+
+const (
+	// PlaceholderTitle is replaced with the title given when a work item is
+	// created from a template
+	PlaceholderTitle = "{title}"
+	// PlaceholderDate is replaced with the current date when a work item is
+	// created from a template
+	PlaceholderDate = "{date}"
+)
+
+// Template describes a named template from which work items of a space can
+// be created. Creating a work item from a template creates its children
+// as well and links them to the work item with parent/child links.
+type Template struct {
+	gormsupport.Lifecycle
+	ID      uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	SpaceID uuid.UUID `sql:"type:uuid"`
+	Name    string
+	// Type is the ID of the type of the work item to create
+	Type uuid.UUID `sql:"type:uuid"`
+	// Title is the pattern of the title of the work item to create, see
+	// ExpandTitle
+	Title       string
+	Description string
+	Markup      string
+	Assignees   IDs        `sql:"type:jsonb"`
+	Area        *uuid.UUID `sql:"type:uuid"`
+	Labels      IDs        `sql:"type:jsonb"`
+	Children    Children   `sql:"type:jsonb"`
+	Version     int
+}
+
+// Child describes a work item to create below the work item created from a
+// template
+type Child struct {
+	Type        uuid.UUID `json:"type"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Markup      string    `json:"markup,omitempty"`
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m Template) GetETagData() []interface{} {
+	return []interface{}{m.ID, m.Version}
+}
+
+// GetLastModified returns the last modification time
+func (m Template) GetLastModified() time.Time {
+	return m.UpdatedAt.Truncate(time.Second)
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Template) TableName() string {
+	return "work_item_templates"
+}
+
+// ExpandTitle replaces the placeholders in the given title pattern: the
+// {title} placeholder with the given title and the {date} placeholder with
+// the date of the given time, e.g. "Release {title} ({date})".
+func ExpandTitle(pattern string, title string, now time.Time) string {
+	return strings.NewReplacer(
+		PlaceholderTitle, title,
+		PlaceholderDate, now.Format("2006-01-02"),
+	).Replace(pattern)
+}
+
+// IDs holds the IDs of the assignees or labels of a template
+type IDs []string
+
+// Value implements the driver.Valuer interface
+func (ids IDs) Value() (driver.Value, error) {
+	if ids == nil {
+		return nil, nil
+	}
+	return json.Marshal(ids)
+}
+
+// Scan implements the sql.Scanner interface
+func (ids *IDs) Scan(src interface{}) error {
+	return scanJSON(src, ids)
+}
+
+// Children holds the work items to create below the work item created from
+// a template
+type Children []Child
+
+// Value implements the driver.Valuer interface
+func (c Children) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface
+func (c *Children) Scan(src interface{}) error {
+	return scanJSON(src, c)
+}
+
+func scanJSON(src interface{}, target interface{}) error {
+	if src == nil {
+		return nil
+	}
+	s, ok := src.([]byte)
+	if !ok {
+		return errs.New("Scan source was not string")
+	}
+	return json.Unmarshal(s, target)
+}
+
+// Repository describes interactions with work item templates
+type Repository interface {
+	Create(ctx context.Context, t *Template) error
+	List(ctx context.Context, spaceID uuid.UUID) ([]Template, error)
+	Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Template, error)
+	Save(ctx context.Context, t Template) (*Template, error)
+	Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error
+}
+
+// NewTemplateRepository creates a new storage type.
+func NewTemplateRepository(db *gorm.DB) Repository {
+	return &GormTemplateRepository{db: db}
+}
+
+// GormTemplateRepository is the implementation of the storage interface for
+// work item templates.
+type GormTemplateRepository struct {
+	db *gorm.DB
+}
+
+// Create creates a new record.
+func (m *GormTemplateRepository) Create(ctx context.Context, t *Template) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "create"}, time.Now())
+	t.ID = uuid.NewV4()
+	err := m.db.Create(t).Error
+	if err != nil {
+		// ( name, spaceID ) needs to be unique
+		if gormsupport.IsUniqueViolation(err, "work_item_templates_name_space_id_unique") {
+			return errors.NewBadParameterError("name & space_id", t.Name+" & "+t.SpaceID.String()).Expected("unique")
+		}
+		log.Error(ctx, map[string]interface{}{
+			"space_id": t.SpaceID,
+			"err":      err,
+		}, "error adding work item template: %s", err.Error())
+		return errors.NewInternalError(err)
+	}
+	return nil
+}
+
+// List returns all templates of the given space ordered by name
+func (m *GormTemplateRepository) List(ctx context.Context, spaceID uuid.UUID) ([]Template, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "query"}, time.Now())
+	var objs []Template
+	err := m.db.Where("space_id = ?", spaceID).Order("name").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(err)
+	}
+	return objs, nil
+}
+
+// Load returns the template with the given ID in the given space
+func (m *GormTemplateRepository) Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Template, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "get"}, time.Now())
+	var obj Template
+	tx := m.db.Where("space_id = ? AND id = ?", spaceID, id).First(&obj)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("work item template", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error)
+	}
+	return &obj, nil
+}
+
+// Save updates the given template in the db. Version must be the same as the one in the stored version
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (m *GormTemplateRepository) Save(ctx context.Context, t Template) (*Template, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "save"}, time.Now())
+	existing, err := m.Load(ctx, t.SpaceID, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Version != t.Version {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	t.CreatedAt = existing.CreatedAt
+	t.Version = t.Version + 1
+	tx := m.db.Save(&t)
+	if err := tx.Error; err != nil {
+		if gormsupport.IsUniqueViolation(err, "work_item_templates_name_space_id_unique") {
+			return nil, errors.NewBadParameterError("name & space_id", t.Name+" & "+t.SpaceID.String()).Expected("unique")
+		}
+		log.Error(ctx, map[string]interface{}{
+			"template_id": t.ID,
+			"err":         err,
+		}, "unable to save the work item template")
+		return nil, errors.NewInternalError(err)
+	}
+	return &t, nil
+}
+
+// Delete deletes the template with the given ID. The work items created
+// from the template are left as they are.
+func (m *GormTemplateRepository) Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "delete"}, time.Now())
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("work item template", id.String())
+	}
+	tx := m.db.Where("space_id = ?", spaceID).Delete(&Template{ID: id})
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("work item template", id.String())
+	}
+	return nil
+}
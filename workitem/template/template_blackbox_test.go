@@ -0,0 +1,127 @@
+package template_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
+	"github.com/fabric8io/almighty-core/gormtestsupport"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/workitem"
+	"github.com/fabric8io/almighty-core/workitem/template"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestExpandTitle(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	now := time.Date(2017, time.June, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, "Release 1.2 (2017-06-01)", template.ExpandTitle("Release {title} ({date})", "1.2", now))
+	assert.Equal(t, "Release ", template.ExpandTitle("Release {title}", "", now))
+	assert.Equal(t, "No placeholders", template.ExpandTitle("No placeholders", "1.2", now))
+}
+
+type TestTemplateRepository struct {
+	gormtestsupport.DBTestSuite
+	repo  template.Repository
+	space *space.Space
+	clean func()
+}
+
+func TestRunTemplateRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestTemplateRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../../config.yaml")})
+}
+
+func (test *TestTemplateRepository) SetupTest() {
+	test.clean = cleaner.DeleteCreatedEntities(test.DB)
+	test.repo = template.NewTemplateRepository(test.DB)
+	s, err := space.NewRepository(test.DB).Create(context.Background(), &space.Space{
+		Name: "TestTemplateRepository " + uuid.NewV4().String(),
+	})
+	require.Nil(test.T(), err)
+	test.space = s
+}
+
+func (test *TestTemplateRepository) TearDownTest() {
+	test.clean()
+}
+
+func (test *TestTemplateRepository) TestCreate() {
+	test.T().Run("ok", func(t *testing.T) {
+		// given
+		tpl := template.Template{
+			SpaceID: test.space.ID,
+			Name:    "release",
+			Type:    workitem.SystemFeature,
+			Title:   "Release {title}",
+			Labels:  template.IDs{},
+			Children: template.Children{
+				{Type: workitem.SystemTask, Title: "Tag {title}"},
+			},
+		}
+		// when
+		err := test.repo.Create(context.Background(), &tpl)
+		// then
+		require.Nil(t, err)
+		assert.NotEqual(t, uuid.Nil, tpl.ID)
+		loaded, err := test.repo.Load(context.Background(), test.space.ID, tpl.ID)
+		require.Nil(t, err)
+		assert.Equal(t, "release", loaded.Name)
+		assert.Equal(t, "Release {title}", loaded.Title)
+		assert.Nil(t, loaded.Area)
+		require.Len(t, loaded.Children, 1)
+		assert.Equal(t, workitem.SystemTask, loaded.Children[0].Type)
+		assert.Equal(t, "Tag {title}", loaded.Children[0].Title)
+	})
+
+	test.T().Run("same name in the space", func(t *testing.T) {
+		// given
+		tpl := template.Template{SpaceID: test.space.ID, Name: "release", Type: workitem.SystemFeature, Title: "Other"}
+		// when
+		err := test.repo.Create(context.Background(), &tpl)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (test *TestTemplateRepository) TestSaveAndDelete() {
+	// given
+	tpl := template.Template{SpaceID: test.space.ID, Name: "release", Type: workitem.SystemFeature, Title: "Release {title}"}
+	require.Nil(test.T(), test.repo.Create(context.Background(), &tpl))
+
+	test.T().Run("save", func(t *testing.T) {
+		// given
+		tpl.Title = "Release {title} ({date})"
+		// when
+		saved, err := test.repo.Save(context.Background(), tpl)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, 1, saved.Version)
+		assert.Equal(t, "Release {title} ({date})", saved.Title)
+	})
+
+	test.T().Run("version conflict", func(t *testing.T) {
+		// when the version of the template before the save is used
+		_, err := test.repo.Save(context.Background(), tpl)
+		// then
+		require.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+
+	test.T().Run("delete", func(t *testing.T) {
+		// when
+		err := test.repo.Delete(context.Background(), test.space.ID, tpl.ID)
+		// then
+		require.Nil(t, err)
+		_, err = test.repo.Load(context.Background(), test.space.ID, tpl.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
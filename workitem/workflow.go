@@ -0,0 +1,234 @@
+package workitem
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fabric8io/almighty-core/convert"
+	"github.com/fabric8io/almighty-core/errors"
+)
+
+// Workflow defines the states of the work items of a type and how work
+// items can move from one state to another. A work item type without a
+// workflow lets work items move freely between all values of the
+// system.state field.
+type Workflow struct {
+	// States lists the states in the order in which they should be shown,
+	// e.g. as the columns of a board
+	States []string `json:",omitempty"`
+	// Transitions lists the allowed changes of the state
+	Transitions []WorkflowTransition `json:",omitempty"`
+}
+
+// WorkflowTransition allows work items to move from one state to another
+type WorkflowTransition struct {
+	From string
+	To   string
+	// RequiredFields guards the transition: all of the fields must have a
+	// value for the work item to move to the new state
+	RequiredFields []string `json:",omitempty"`
+}
+
+// Ensure Workflow implements the Equaler interface
+var _ convert.Equaler = Workflow{}
+var _ convert.Equaler = (*Workflow)(nil)
+
+// Equal returns true if two Workflow objects are equal; otherwise false is returned.
+func (w Workflow) Equal(u convert.Equaler) bool {
+	other, ok := u.(Workflow)
+	if !ok {
+		return false
+	}
+	if w.IsEmpty() && other.IsEmpty() {
+		return true
+	}
+	return reflect.DeepEqual(w, other)
+}
+
+// IsEmpty returns true if no workflow is defined
+func (w Workflow) IsEmpty() bool {
+	return len(w.States) == 0
+}
+
+// Value implements the driver.Valuer interface
+func (w Workflow) Value() (driver.Value, error) {
+	if w.IsEmpty() {
+		return nil, nil
+	}
+	return toBytes(w)
+}
+
+// Scan implements the sql.Scanner interface
+func (w *Workflow) Scan(src interface{}) error {
+	if src == nil {
+		*w = Workflow{}
+		return nil
+	}
+	return fromBytes(src, w)
+}
+
+// hasState returns true if the workflow defines the given state
+func (w Workflow) hasState(state string) bool {
+	for _, s := range w.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStates returns the states a work item can move to from the given
+// state, in the order in which the states are defined. Work items in a state
+// that is not part of the workflow, e.g. because the workflow was introduced
+// after they were created, can move to any state of the workflow.
+func (w Workflow) NextStates(from string) []string {
+	result := []string{}
+	if !w.hasState(from) {
+		return append(result, w.States...)
+	}
+	for _, s := range w.States {
+		if _, ok := w.transition(from, s); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func (w Workflow) transition(from, to string) (*WorkflowTransition, bool) {
+	for i, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return &w.Transitions[i], true
+		}
+	}
+	return nil, false
+}
+
+// CheckTransition returns a StateTransitionError if a work item with the
+// given field values must not move from one state to the other.
+func (w Workflow) CheckTransition(from, to string, fields Fields) error {
+	if w.IsEmpty() || from == to {
+		return nil
+	}
+	if !w.hasState(to) {
+		return errors.NewStateTransitionError(from, to, w.NextStates(from))
+	}
+	if !w.hasState(from) {
+		// work items in unknown states can move to any state of the workflow
+		return nil
+	}
+	t, ok := w.transition(from, to)
+	if !ok {
+		return errors.NewStateTransitionError(from, to, w.NextStates(from))
+	}
+	for _, name := range t.RequiredFields {
+		if isEmptyValue(fields[name]) {
+			return errors.NewStateTransitionError(from, to, w.NextStates(from)).Because(fmt.Sprintf("field '%s' is required", name))
+		}
+	}
+	return nil
+}
+
+// CheckInitialState returns a BadParameterError if work items must not be
+// created in the given state, i.e. if the state is not part of the workflow.
+func (w Workflow) CheckInitialState(state interface{}) error {
+	if w.IsEmpty() || state == nil {
+		return nil
+	}
+	if !w.hasState(fmt.Sprint(state)) {
+		return errors.NewBadParameterError(SystemState, state).Expected(fmt.Sprintf("a state of the workflow: %s", strings.Join(w.States, ", ")))
+	}
+	return nil
+}
+
+// isEmptyValue returns true for missing values, empty strings and empty lists
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// Validate checks that the workflow fits the given field definitions of its
+// work item type: the states must be values of the system.state field, and
+// the transitions must only refer to these states and to existing fields.
+func (w Workflow) Validate(fields FieldDefinitions) error {
+	if w.IsEmpty() {
+		if len(w.Transitions) > 0 {
+			return errors.NewBadParameterError("workflow.states", w.States).Expected("not empty")
+		}
+		return nil
+	}
+	stateField, ok := fields[SystemState]
+	if !ok {
+		return errors.NewBadParameterError("workflow", w.States).Expected(fmt.Sprintf("a work item type with a %s field", SystemState))
+	}
+	values := map[string]bool{}
+	if enumType, ok := stateField.Type.(EnumType); ok {
+		for _, v := range enumType.Values {
+			values[fmt.Sprint(v)] = true
+		}
+	} else if enumType, ok := stateField.Type.(*EnumType); ok {
+		for _, v := range enumType.Values {
+			values[fmt.Sprint(v)] = true
+		}
+	}
+	seen := map[string]bool{}
+	for _, s := range w.States {
+		if len(values) > 0 && !values[s] {
+			return errors.NewBadParameterError("workflow.states", s).Expected(fmt.Sprintf("a value of the %s field", SystemState))
+		}
+		if seen[s] {
+			return errors.NewBadParameterError("workflow.states", s).Expected("unique states")
+		}
+		seen[s] = true
+	}
+	for _, t := range w.Transitions {
+		if !seen[t.From] {
+			return errors.NewBadParameterError("workflow.transitions.from", t.From).Expected("a state of the workflow")
+		}
+		if !seen[t.To] {
+			return errors.NewBadParameterError("workflow.transitions.to", t.To).Expected("a state of the workflow")
+		}
+		for _, name := range t.RequiredFields {
+			if _, ok := fields[name]; !ok {
+				return errors.NewBadParameterError("workflow.transitions.requiredFields", name).Expected("a field of the work item type")
+			}
+		}
+	}
+	return nil
+}
+
+// EffectiveWorkflow returns the workflow of the work item type. For types
+// without a workflow, a workflow that allows all changes between the values
+// of the system.state field is returned.
+func (wit WorkItemType) EffectiveWorkflow() Workflow {
+	if !wit.Workflow.IsEmpty() {
+		return wit.Workflow
+	}
+	result := Workflow{}
+	var values []interface{}
+	switch t := wit.Fields[SystemState].Type.(type) {
+	case EnumType:
+		values = t.Values
+	case *EnumType:
+		values = t.Values
+	}
+	for _, v := range values {
+		result.States = append(result.States, fmt.Sprint(v))
+	}
+	for _, from := range result.States {
+		for _, to := range result.States {
+			if from != to {
+				result.Transitions = append(result.Transitions, WorkflowTransition{From: from, To: to})
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/resource"
+	. "github.com/fabric8io/almighty-core/workitem"
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStateType = EnumType{
+	SimpleType: SimpleType{Kind: KindEnum},
+	BaseType:   SimpleType{Kind: KindString},
+	Values:     []interface{}{"new", "open", "closed"},
+}
+
+var testWorkflow = Workflow{
+	States: []string{"new", "open", "closed"},
+	Transitions: []WorkflowTransition{
+		{From: "new", To: "open"},
+		{From: "open", To: "closed", RequiredFields: []string{SystemAssignees}},
+		{From: "closed", To: "open"},
+	},
+}
+
+func TestWorkflowNextStates(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, []string{"open"}, testWorkflow.NextStates("new"))
+	assert.Equal(t, []string{"closed"}, testWorkflow.NextStates("open"))
+	// work items in unknown states can move to any state
+	assert.Equal(t, []string{"new", "open", "closed"}, testWorkflow.NextStates("resolved"))
+}
+
+func TestWorkflowCheckTransition(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assigned := Fields{SystemAssignees: []interface{}{"jdoe"}}
+
+	assert.Nil(t, testWorkflow.CheckTransition("new", "open", Fields{}))
+	assert.Nil(t, testWorkflow.CheckTransition("new", "new", Fields{}))
+	assert.Nil(t, testWorkflow.CheckTransition("open", "closed", assigned))
+	assert.Nil(t, testWorkflow.CheckTransition("resolved", "closed", Fields{}))
+	assert.Nil(t, Workflow{}.CheckTransition("new", "closed", Fields{}))
+
+	t.Run("illegal transition", func(t *testing.T) {
+		err := testWorkflow.CheckTransition("new", "closed", assigned)
+		require.NotNil(t, err)
+		require.IsType(t, errors.StateTransitionError{}, errs.Cause(err))
+		assert.Equal(t, []string{"open"}, errs.Cause(err).(errors.StateTransitionError).AllowedStates)
+	})
+	t.Run("unknown state", func(t *testing.T) {
+		err := testWorkflow.CheckTransition("new", "resolved", Fields{})
+		require.NotNil(t, err)
+		require.IsType(t, errors.StateTransitionError{}, errs.Cause(err))
+	})
+	t.Run("missing required field", func(t *testing.T) {
+		err := testWorkflow.CheckTransition("open", "closed", Fields{SystemAssignees: []interface{}{}})
+		require.NotNil(t, err)
+		require.IsType(t, errors.StateTransitionError{}, errs.Cause(err))
+		assert.Contains(t, err.Error(), SystemAssignees)
+	})
+}
+
+func TestWorkflowCheckInitialState(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	assert.Nil(t, testWorkflow.CheckInitialState("new"))
+	assert.Nil(t, testWorkflow.CheckInitialState(nil))
+	assert.Nil(t, Workflow{}.CheckInitialState("resolved"))
+	err := testWorkflow.CheckInitialState("resolved")
+	require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	fields := FieldDefinitions{
+		SystemState:     {Type: testStateType},
+		SystemAssignees: {Type: ListType{SimpleType: SimpleType{Kind: KindList}, ComponentType: SimpleType{Kind: KindUser}}},
+	}
+	assert.Nil(t, testWorkflow.Validate(fields))
+	assert.Nil(t, Workflow{}.Validate(fields))
+
+	assert.NotNil(t, testWorkflow.Validate(FieldDefinitions{}))
+	assert.NotNil(t, Workflow{States: []string{"new", "resolved"}}.Validate(fields))
+	assert.NotNil(t, Workflow{States: []string{"new", "new"}}.Validate(fields))
+	assert.NotNil(t, Workflow{Transitions: []WorkflowTransition{{From: "new", To: "open"}}}.Validate(fields))
+	assert.NotNil(t, Workflow{
+		States:      []string{"new", "open"},
+		Transitions: []WorkflowTransition{{From: "new", To: "closed"}},
+	}.Validate(fields))
+	assert.NotNil(t, Workflow{
+		States:      []string{"new", "open"},
+		Transitions: []WorkflowTransition{{From: "new", To: "open", RequiredFields: []string{"foo"}}},
+	}.Validate(fields))
+}
+
+func TestEffectiveWorkflow(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	wit := WorkItemType{Fields: FieldDefinitions{SystemState: {Type: testStateType}}}
+
+	permissive := wit.EffectiveWorkflow()
+	assert.Equal(t, []string{"new", "open", "closed"}, permissive.States)
+	assert.Len(t, permissive.Transitions, 6)
+	assert.Nil(t, permissive.CheckTransition("closed", "new", Fields{}))
+
+	wit.Workflow = testWorkflow
+	assert.Equal(t, testWorkflow, wit.EffectiveWorkflow())
+}
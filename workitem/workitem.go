@@ -18,6 +18,8 @@ type WorkItem struct {
 	Version int
 	// ID of the space to which this work item belongs
 	SpaceID uuid.UUID
+	// the number of the work item within its space
+	Number int
 	// The field values, according to the field type
 	Fields map[string]interface{}
 }
@@ -1,17 +1,28 @@
 package workitem
 
 import (
+	"database/sql"
 	"strconv"
+	"strings"
+	"time"
 
 	"context"
 
 	"fmt"
 
+	"github.com/fabric8io/almighty-core/area"
 	"github.com/fabric8io/almighty-core/criteria"
 	"github.com/fabric8io/almighty-core/errors"
+	"github.com/fabric8io/almighty-core/event"
+	"github.com/fabric8io/almighty-core/iteration"
 
+	"github.com/fabric8io/almighty-core/label"
 	"github.com/fabric8io/almighty-core/log"
+	"github.com/fabric8io/almighty-core/mention"
+	"github.com/fabric8io/almighty-core/path"
 	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/space"
+	"github.com/fabric8io/almighty-core/subscription"
 
 	"github.com/jinzhu/gorm"
 	errs "github.com/pkg/errors"
@@ -36,11 +47,19 @@ type WorkItemRepository interface {
 	Save(ctx context.Context, spaceID uuid.UUID, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error)
 	Reorder(ctx context.Context, direction DirectionType, targetID *string, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error)
 	Delete(ctx context.Context, spaceID uuid.UUID, ID string, suppressorID uuid.UUID) error
+	Restore(ctx context.Context, spaceID uuid.UUID, ID string, restorerID uuid.UUID) (*WorkItem, time.Time, error)
+	ListDeleted(ctx context.Context, spaceID uuid.UUID, since *time.Time, start *int, length *int) ([]WorkItem, uint64, error)
 	Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, error)
 	List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, start *int, length *int) ([]WorkItem, uint64, error)
+	ListSorted(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, sort string, start *int, length *int) ([]WorkItem, uint64, error)
+	LoadAsOf(ctx context.Context, spaceID uuid.UUID, ID string, asOf time.Time) (*WorkItem, error)
+	ListAsOf(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, asOf time.Time, start *int, length *int) ([]WorkItem, uint64, error)
 	Fetch(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (*WorkItem, error)
 	GetCountsPerIteration(ctx context.Context, spaceID uuid.UUID) (map[string]WICountsPerIteration, error)
 	GetCountsForIteration(ctx context.Context, iterationID uuid.UUID) (map[string]WICountsPerIteration, error)
+	GetCountsPerLabel(ctx context.Context, spaceID uuid.UUID) (map[string]int, error)
+	DetachLabel(ctx context.Context, spaceID uuid.UUID, labelID uuid.UUID, modifierID uuid.UUID) error
+	Move(ctx context.Context, spaceID uuid.UUID, wi WorkItem, targetSpaceID uuid.UUID, modifierID uuid.UUID) (*WorkItem, error)
 	Count(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (int, error)
 }
 
@@ -61,12 +80,38 @@ type GormWorkItemRepository struct {
 // WorkItemRepository implementation
 // ************************************************
 
-// LoadFromDB returns the work item with the given ID in model representation.
-func (r *GormWorkItemRepository) LoadFromDB(ctx context.Context, workitemID string) (*WorkItemStorage, error) {
+// resolveID returns the global numeric ID of the work item with the given ID,
+// which is either the global ID or the key of the space followed by the
+// number of the work item in the space, e.g. PLAT-123
+// returns NotFoundError or InternalError
+func (r *GormWorkItemRepository) resolveID(ctx context.Context, workitemID string) (uint64, error) {
+	if key, number, ok := ParseWorkItemKey(workitemID); ok {
+		var ids []uint64
+		db := r.db.Model(&WorkItemStorage{}).
+			Joins("JOIN spaces ON spaces.id = work_items.space_id").
+			Where("spaces.key = ? AND spaces.deleted_at IS NULL AND work_items.number = ?", key, number).
+			Pluck("work_items.id", &ids)
+		if db.Error != nil {
+			return 0, errors.NewInternalError(db.Error)
+		}
+		if len(ids) == 0 {
+			return 0, errors.NewNotFoundError("work item", workitemID)
+		}
+		return ids[0], nil
+	}
 	id, err := strconv.ParseUint(workitemID, 10, 64)
 	if err != nil || id == 0 {
 		// treating this as a not found error: the fact that we're using number internal is implementation detail
-		return nil, errors.NewNotFoundError("work item", workitemID)
+		return 0, errors.NewNotFoundError("work item", workitemID)
+	}
+	return id, nil
+}
+
+// LoadFromDB returns the work item with the given ID in model representation.
+func (r *GormWorkItemRepository) LoadFromDB(ctx context.Context, workitemID string) (*WorkItemStorage, error) {
+	id, err := r.resolveID(ctx, workitemID)
+	if err != nil {
+		return nil, err
 	}
 	log.Info(nil, map[string]interface{}{
 		"wi_id": workitemID,
@@ -111,10 +156,9 @@ func (r *GormWorkItemRepository) Load(ctx context.Context, spaceID uuid.UUID, wo
 }
 
 func (r *GormWorkItemRepository) loadWorkItemStorage(ctx context.Context, spaceID uuid.UUID, workitemID string, selectForUpdate bool) (*WorkItemStorage, *WorkItemType, error) {
-	id, err := strconv.ParseUint(workitemID, 10, 64)
-	if err != nil || id == 0 {
-		// treating this as a not found error: the fact that we're using number internal is implementation detail
-		return nil, nil, errors.NewNotFoundError("work item", workitemID)
+	id, err := r.resolveID(ctx, workitemID)
+	if err != nil {
+		return nil, nil, err
 	}
 	log.Info(nil, map[string]interface{}{
 		"wi_id":    workitemID,
@@ -202,10 +246,9 @@ func (r *GormWorkItemRepository) LoadHighestOrder() (float64, error) {
 // returns NotFoundError or InternalError
 func (r *GormWorkItemRepository) Delete(ctx context.Context, spaceID uuid.UUID, workitemID string, suppressorID uuid.UUID) error {
 	var workItem = WorkItemStorage{}
-	id, err := strconv.ParseUint(workitemID, 10, 64)
-	if err != nil || id == 0 {
-		// treat as not found: clients don't know it must be a number
-		return errors.NewNotFoundError("work item", workitemID)
+	id, err := r.resolveID(ctx, workitemID)
+	if err != nil {
+		return err
 	}
 	workItem.ID = id
 	workItem.SpaceID = spaceID
@@ -224,10 +267,102 @@ func (r *GormWorkItemRepository) Delete(ctx context.Context, spaceID uuid.UUID,
 	if err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	err = event.Record(ctx, r.db, spaceID, suppressorID, event.WorkItemDeleted{WorkItemID: strconv.FormatUint(id, 10)})
+	if err != nil {
+		return errs.Wrapf(err, "error while deleting work item")
+	}
 	log.Debug(ctx, map[string]interface{}{"wi_id": workitemID, "space_id": spaceID}, "Work item deleted successfully!")
 	return nil
 }
 
+// Restore brings back the soft-deleted work item with the given id. The
+// returned time is when the work item had been deleted, so that the links and
+// comments deleted along with it can be restored as well.
+// returns NotFoundError or InternalError
+func (r *GormWorkItemRepository) Restore(ctx context.Context, spaceID uuid.UUID, workitemID string, restorerID uuid.UUID) (*WorkItem, time.Time, error) {
+	id, err := strconv.ParseUint(workitemID, 10, 64)
+	if err != nil || id == 0 {
+		// treat as not found: clients don't know it must be a number
+		return nil, time.Time{}, errors.NewNotFoundError("deleted work item", workitemID)
+	}
+	wiStorage := WorkItemStorage{}
+	tx := r.db.Unscoped().Where("id = ? AND space_id = ? AND deleted_at IS NOT NULL", id, spaceID).First(&wiStorage)
+	if tx.RecordNotFound() {
+		return nil, time.Time{}, errors.NewNotFoundError("deleted work item", workitemID)
+	}
+	if tx.Error != nil {
+		return nil, time.Time{}, errors.NewInternalError(tx.Error)
+	}
+	deletedAt := *wiStorage.DeletedAt
+	wiStorage.DeletedAt = nil
+	wiStorage.Version = wiStorage.Version + 1
+	tx = r.db.Unscoped().Model(&wiStorage).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    wiStorage.Version,
+	})
+	if tx.Error != nil {
+		return nil, time.Time{}, errors.NewInternalError(tx.Error)
+	}
+	// store a revision of the restored work item
+	if err = r.wirr.Create(context.Background(), restorerID, RevisionTypeRestore, wiStorage); err != nil {
+		return nil, time.Time{}, errs.Wrapf(err, "error while restoring work item")
+	}
+	wiType, err := r.witr.LoadTypeFromDB(ctx, wiStorage.Type)
+	if err != nil {
+		return nil, time.Time{}, errors.NewInternalError(err)
+	}
+	wi, err := ConvertWorkItemStorageToModel(wiType, &wiStorage)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	log.Debug(ctx, map[string]interface{}{"wi_id": workitemID, "space_id": spaceID}, "Work item restored successfully!")
+	return wi, deletedAt, nil
+}
+
+// ListDeleted returns the work items of the given space which were deleted,
+// most recently deleted first. If since is given, only the work items deleted
+// after that time are returned.
+func (r *GormWorkItemRepository) ListDeleted(ctx context.Context, spaceID uuid.UUID, since *time.Time, start *int, limit *int) ([]WorkItem, uint64, error) {
+	db := r.db.Unscoped().Model(&WorkItemStorage{}).Where("space_id = ? AND deleted_at IS NOT NULL", spaceID)
+	if since != nil {
+		db = db.Where("deleted_at > ?", *since)
+	}
+	var count uint64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to count deleted work items"))
+	}
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		db = db.Offset(*start)
+	}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		db = db.Limit(*limit)
+	}
+	result := []WorkItemStorage{}
+	if err := db.Order("deleted_at desc").Find(&result).Error; err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to list deleted work items"))
+	}
+	res := make([]WorkItem, len(result))
+	for index, value := range result {
+		wiType, err := r.witr.LoadTypeFromDB(ctx, value.Type)
+		if err != nil {
+			return nil, 0, errors.NewInternalError(err)
+		}
+		modelWI, err := ConvertWorkItemStorageToModel(wiType, &value)
+		if err != nil {
+			return nil, 0, errors.NewInternalError(err)
+		}
+		modelWI.Fields[SystemDeletedAt] = *value.DeletedAt
+		res[index] = *modelWI
+	}
+	return res, count, nil
+}
+
 // Calculates the order of the reorder workitem
 func (r *GormWorkItemRepository) CalculateOrder(above, below *float64) float64 {
 	return (*above + *below) / 2
@@ -400,7 +535,7 @@ func (r *GormWorkItemRepository) Reorder(ctx context.Context, direction Directio
 		var err error
 		res.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, errors.NewBadParameterError(fieldName, fieldValue)
+			return nil, fieldValueError(fieldName, fieldValue, err)
 		}
 	}
 	tx = tx.Where("Version = ?", wi.Version).Save(&res)
@@ -430,6 +565,8 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	}
 	wiStorage.Version = wiStorage.Version + 1
 	wiStorage.Type = updatedWorkItem.Type
+	previousState := wiStorage.Fields[SystemState]
+	previousFields := wiStorage.Fields
 	wiStorage.Fields = Fields{}
 	wiStorage.ExecutionOrder = updatedWorkItem.Fields[SystemOrder].(float64)
 	for fieldName, fieldDef := range wiType.Fields {
@@ -440,7 +577,16 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 		var err error
 		wiStorage.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, errors.NewBadParameterError(fieldName, fieldValue)
+			return nil, fieldValueError(fieldName, fieldValue, err)
+		}
+		if err := r.checkReferences(ctx, spaceID, fieldName, fieldDef.Type, wiStorage.Fields[fieldName]); err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
+	// check the state change against the workflow of the type
+	if previousState != nil && wiStorage.Fields[SystemState] != nil {
+		if err := wiType.Workflow.CheckTransition(fmt.Sprint(previousState), fmt.Sprint(wiStorage.Fields[SystemState]), wiStorage.Fields); err != nil {
+			return nil, errs.WithStack(err)
 		}
 	}
 	tx := r.db.Where("Version = ?", updatedWorkItem.Version).Save(&wiStorage)
@@ -461,6 +607,24 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	if err != nil {
 		return nil, errs.Wrapf(err, "error while saving work item")
 	}
+	if err := r.subscribe(ctx, *wiStorage); err != nil {
+		return nil, errs.Wrapf(err, "error while saving work item")
+	}
+	changes := Revision{WorkItemFields: wiStorage.Fields}.Changes(&Revision{WorkItemFields: previousFields}, wiType)
+	updated := event.WorkItemUpdated{
+		WorkItemID: updatedWorkItem.ID,
+		Version:    wiStorage.Version,
+		Changes:    make([]event.FieldChange, len(changes)),
+	}
+	for i, change := range changes {
+		updated.Changes[i] = event.FieldChange{Name: change.Name, OldValue: change.OldValue, NewValue: change.NewValue}
+	}
+	if err := event.Record(ctx, r.db, spaceID, modifierID, updated); err != nil {
+		return nil, errs.Wrapf(err, "error while saving work item")
+	}
+	if err := r.mention(ctx, *wiStorage, modifierID); err != nil {
+		return nil, errs.Wrapf(err, "error while saving work item")
+	}
 	log.Info(ctx, map[string]interface{}{
 		"wi_id":    updatedWorkItem.ID,
 		"space_id": spaceID,
@@ -468,6 +632,85 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	return ConvertWorkItemStorageToModel(wiType, wiStorage)
 }
 
+// Move moves the given work item from the given space to the target space,
+// keeping its ID and thus its revisions, comments and links. It gets the next
+// number of the target space though. The work item is saved with the given
+// type and field values, in which the iterations, areas, labels and work
+// items referred to are replaced with the ones of the target space:
+// iterations and areas with the ones of the same name or else with the root
+// iteration and area, labels with the ones of the same name. References which
+// cannot be replaced are dropped. If the type is not available in the target
+// space, it is replaced with the type of the target space with the same name.
+// returns NotFoundError, VersionConflictError, BadParameterError, ConversionError or InternalError
+func (r *GormWorkItemRepository) Move(ctx context.Context, spaceID uuid.UUID, wi WorkItem, targetSpaceID uuid.UUID, modifierID uuid.UUID) (*WorkItem, error) {
+	wiStorage, _, err := r.loadWorkItemStorage(ctx, spaceID, wi.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if wiStorage.Version != wi.Version {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	wiType, err := r.witr.LoadForSpace(ctx, wi.Type, targetSpaceID)
+	if err != nil {
+		return nil, err
+	}
+	number, err := r.nextNumber(ctx, targetSpaceID)
+	if err != nil {
+		return nil, err
+	}
+	wiStorage.Version = wiStorage.Version + 1
+	wiStorage.Type = wiType.ID
+	wiStorage.SpaceID = targetSpaceID
+	wiStorage.Number = number
+	wiStorage.Fields = Fields{}
+	for fieldName, fieldDef := range wiType.Fields {
+		if fieldName == SystemCreatedAt || fieldName == SystemUpdatedAt || fieldName == SystemOrder {
+			continue
+		}
+		fieldValue := wi.Fields[fieldName]
+		var err error
+		wiStorage.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
+		if err != nil {
+			return nil, fieldValueError(fieldName, fieldValue, err)
+		}
+		wiStorage.Fields[fieldName], err = r.moveReferences(ctx, targetSpaceID, fieldDef.Type, wiStorage.Fields[fieldName])
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to move the references of field %s", fieldName)
+		}
+		if err := r.checkReferences(ctx, targetSpaceID, fieldName, fieldDef.Type, wiStorage.Fields[fieldName]); err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
+	tx := r.db.Where("Version = ?", wi.Version).Save(&wiStorage)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id":           wi.ID,
+			"space_id":        spaceID,
+			"target_space_id": targetSpaceID,
+			"err":             err,
+		}, "unable to move the work item")
+		return nil, errors.NewInternalError(err)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	// store a revision of the moved work item
+	err = r.wirr.Create(context.Background(), modifierID, RevisionTypeUpdate, *wiStorage)
+	if err != nil {
+		return nil, errs.Wrapf(err, "error while moving work item")
+	}
+	err = event.Record(ctx, r.db, targetSpaceID, modifierID, event.WorkItemMoved{WorkItemID: wi.ID, SourceSpaceID: spaceID, Number: number})
+	if err != nil {
+		return nil, errs.Wrapf(err, "error while moving work item")
+	}
+	log.Info(ctx, map[string]interface{}{
+		"wi_id":           wi.ID,
+		"space_id":        spaceID,
+		"target_space_id": targetSpaceID,
+	}, "Moved work item")
+	return ConvertWorkItemStorageToModel(wiType, wiStorage)
+}
+
 // Create creates a new work item in the repository
 // returns BadParameterError, ConversionError or InternalError
 func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, error) {
@@ -475,6 +718,9 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 	if err != nil {
 		return nil, errors.NewBadParameterError("typeID", typeID)
 	}
+	if !uuid.Equal(wiType.SpaceID, spaceID) && !uuid.Equal(wiType.SpaceID, space.SystemSpace) {
+		return nil, errors.NewBadParameterError("typeID", typeID).Expected("work item type of the space or of the system space")
+	}
 
 	// The order of workitems are spaced by a factor of 1000.
 	pos, err := r.LoadHighestOrder()
@@ -482,11 +728,16 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 		return nil, errors.NewInternalError(err)
 	}
 	pos = pos + orderValue
+	number, err := r.nextNumber(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
 	wi := WorkItemStorage{
 		Type:           typeID,
 		Fields:         Fields{},
 		ExecutionOrder: pos,
 		SpaceID:        spaceID,
+		Number:         number,
 	}
 	fields[SystemCreator] = creatorID.String()
 	for fieldName, fieldDef := range wiType.Fields {
@@ -494,10 +745,18 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 			continue
 		}
 		fieldValue := fields[fieldName]
-		var err error
+		if fieldValue == nil && fieldDef.Default != nil {
+			fieldValue, err = r.defaultValue(ctx, spaceID, fieldDef, creatorID)
+			if err != nil {
+				return nil, errs.Wrapf(err, "failed to compute the default value of field %s", fieldName)
+			}
+		}
 		wi.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, errors.NewBadParameterError(fieldName, fieldValue)
+			return nil, fieldValueError(fieldName, fieldValue, err)
+		}
+		if err := r.checkReferences(ctx, spaceID, fieldName, fieldDef.Type, wi.Fields[fieldName]); err != nil {
+			return nil, errs.WithStack(err)
 		}
 		if fieldName == SystemDescription && wi.Fields[fieldName] != nil {
 			description := rendering.NewMarkupContentFromMap(wi.Fields[fieldName].(map[string]interface{}))
@@ -506,6 +765,9 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 			}
 		}
 	}
+	if err := wiType.Workflow.CheckInitialState(wi.Fields[SystemState]); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	tx := r.db
 	if err = tx.Create(&wi).Error; err != nil {
 		return nil, errs.Wrapf(err, "failed to create work item")
@@ -520,10 +782,126 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 	if err != nil {
 		return nil, errs.Wrapf(err, "error while creating work item")
 	}
+	if err := r.subscribe(ctx, wi); err != nil {
+		return nil, errs.Wrapf(err, "error while creating work item")
+	}
+	err = event.Record(ctx, r.db, spaceID, creatorID, event.WorkItemCreated{WorkItemID: witem.ID, Number: wi.Number, TypeID: typeID, Fields: wi.Fields})
+	if err != nil {
+		return nil, errs.Wrapf(err, "error while creating work item")
+	}
+	if err := r.mention(ctx, wi, creatorID); err != nil {
+		return nil, errs.Wrapf(err, "error while creating work item")
+	}
 	log.Debug(ctx, map[string]interface{}{"pkg": "workitem", "wi_id": wi.ID}, "Work item created successfully!")
 	return witem, nil
 }
 
+// subscribe subscribes the creator and the assignees of the given work item
+// to it, unless they unsubscribed from it before
+func (r *GormWorkItemRepository) subscribe(ctx context.Context, wi WorkItemStorage) error {
+	repo := subscription.NewRepository(r.db)
+	id := strconv.FormatUint(wi.ID, 10)
+	if creator, err := uuid.FromString(fmt.Sprint(wi.Fields[SystemCreator])); err == nil {
+		if err := repo.WatchWorkItem(ctx, id, []uuid.UUID{creator}, subscription.ReasonCreator); err != nil {
+			return err
+		}
+	}
+	var assignees []uuid.UUID
+	if values, ok := wi.Fields[SystemAssignees].([]interface{}); ok {
+		for _, value := range values {
+			if assignee, err := uuid.FromString(fmt.Sprint(value)); err == nil {
+				assignees = append(assignees, assignee)
+			}
+		}
+	}
+	return repo.WatchWorkItem(ctx, id, assignees, subscription.ReasonAssignee)
+}
+
+// mention stores the mentions of users in the description of the given work
+// item
+func (r *GormWorkItemRepository) mention(ctx context.Context, wi WorkItemStorage, actorID uuid.UUID) error {
+	id := strconv.FormatUint(wi.ID, 10)
+	target := mention.Target{SpaceID: wi.SpaceID, WorkItemID: id, Kind: mention.KindWorkItem, ID: id}
+	description := rendering.NewMarkupContentFromValue(wi.Fields[SystemDescription])
+	if description == nil {
+		// the description was removed, so are its mentions
+		return mention.NewRepository(r.db).Sync(ctx, target, "", rendering.SystemMarkupDefault, actorID)
+	}
+	return mention.NewRepository(r.db).Sync(ctx, target, description.Content, description.Markup, actorID)
+}
+
+// nextNumber increments the work item counter of the given space and returns
+// the number for the next work item of the space. The space row stays locked
+// until the end of the transaction, so that concurrent transactions don't
+// hand out the same number.
+func (r *GormWorkItemRepository) nextNumber(ctx context.Context, spaceID uuid.UUID) (int, error) {
+	var number int
+	row := r.db.Raw("UPDATE spaces SET work_item_counter = work_item_counter + 1 WHERE id = ? RETURNING work_item_counter", spaceID).Row()
+	err := row.Scan(&number)
+	if err == sql.ErrNoRows {
+		return 0, errors.NewBadParameterError("spaceID", spaceID)
+	}
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": spaceID,
+			"err":      err,
+		}, "unable to increment the work item counter of the space")
+		return 0, errors.NewInternalError(err)
+	}
+	return number, nil
+}
+
+// defaultValue computes the default value of the given field for a new work
+// item in the given space, in the representation of the REST API layer. nil
+// is returned if the default cannot be determined, e.g. when the space has no
+// root area.
+func (r *GormWorkItemRepository) defaultValue(ctx context.Context, spaceID uuid.UUID, fieldDef FieldDefinition, creatorID uuid.UUID) (interface{}, error) {
+	var value string
+	switch fieldDef.Default.Kind {
+	case DefaultLiteral:
+		return fieldDef.Default.literal(fieldDef.Type)
+	case DefaultNow:
+		return time.Now().UTC(), nil
+	case DefaultCurrentUser:
+		value = creatorID.String()
+	case DefaultRootArea:
+		areas, err := area.NewAreaRepository(r.db).Query(area.FilterBySpaceID(spaceID), area.FilterByPath(path.Path{}))
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if len(areas) == 0 {
+			return nil, nil
+		}
+		value = areas[0].ID.String()
+	case DefaultCurrentIteration, DefaultRootIteration:
+		iterationRepo := iteration.NewIterationRepository(r.db)
+		if fieldDef.Default.Kind == DefaultCurrentIteration {
+			current, err := iterationRepo.Current(ctx, spaceID)
+			if err == nil {
+				value = current.ID.String()
+				break
+			}
+			if _, ok := errs.Cause(err).(errors.NotFoundError); !ok {
+				return nil, errs.WithStack(err)
+			}
+		}
+		root, err := iterationRepo.Root(ctx, spaceID)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if uuid.Equal(root.ID, uuid.Nil) {
+			return nil, nil
+		}
+		value = root.ID.String()
+	default:
+		return nil, nil
+	}
+	if fieldDef.Type.GetKind() == KindList {
+		return []interface{}{value}, nil
+	}
+	return value, nil
+}
+
 // ConvertWorkItemStorageToModel convert work item model to app WI
 func ConvertWorkItemStorageToModel(wiType *WorkItemType, wi *WorkItemStorage) (*WorkItem, error) {
 	result, err := wiType.ConvertWorkItemStorageToModel(*wi)
@@ -543,25 +921,31 @@ func ConvertWorkItemStorageToModel(wiType *WorkItemType, wi *WorkItemStorage) (*
 
 }
 
+// noParentClause restricts a work item query to the items without a parent
+const noParentClause = ` AND
+			id not in (
+				SELECT target_id FROM work_item_links
+				WHERE link_type_id IN (
+					SELECT id FROM work_item_link_types WHERE forward_name = 'parent of'
+				)
+			)`
+
 // extracted this function from List() in order to close the rows object with "defer" for more readability
 // workaround for https://github.com/lib/pq/issues/81
-func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, start *int, limit *int) ([]WorkItemStorage, uint64, error) {
+func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, sort string, start *int, limit *int) ([]WorkItemStorage, uint64, error) {
 	where, parameters, compileError := Compile(criteria)
 	if compileError != nil {
 		return nil, 0, errors.NewBadParameterError("expression", criteria)
 	}
+	order, err := orderBy(sort)
+	if err != nil {
+		return nil, 0, err
+	}
 	where = where + " AND space_id = ?"
 	parameters = append(parameters, spaceID)
 
 	if parentExists != nil && !*parentExists {
-		where += ` AND
-			id not in (
-				SELECT target_id FROM work_item_links
-				WHERE link_type_id IN (
-					SELECT id FROM work_item_link_types WHERE forward_name = 'parent of'
-				)
-			)`
-
+		where += noParentClause
 	}
 	db := r.db.Model(&WorkItemStorage{}).Where(where, parameters...)
 	orgDB := db
@@ -578,7 +962,7 @@ func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uu
 		db = db.Limit(*limit)
 	}
 
-	db = db.Select("count(*) over () as cnt2 , *").Order("execution_order desc")
+	db = db.Select("count(*) over () as cnt2 , *").Order(order)
 
 	rows, err := db.Rows()
 	if err != nil {
@@ -630,9 +1014,42 @@ func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uu
 	return result, count, nil
 }
 
+// sortFields are the field names work items can be sorted by
+var sortFields = map[string]bool{
+	SystemCreatedAt: true,
+	SystemUpdatedAt: true,
+	SystemVotes:     true,
+}
+
+// orderBy returns the order of the work items for the given sort parameter,
+// which is one of the sortFields, prefixed with - for a descending order.
+// Work items are ordered by their position otherwise, and on ties.
+func orderBy(sort string) (string, error) {
+	order := "execution_order desc"
+	if sort == "" {
+		return order, nil
+	}
+	direction := "asc"
+	fieldName := sort
+	if strings.HasPrefix(sort, "-") {
+		direction = "desc"
+		fieldName = strings.TrimPrefix(sort, "-")
+	}
+	if !sortFields[fieldName] {
+		return "", errors.NewBadParameterError("sort", sort).Expected(fmt.Sprintf("%s, %s or %s, optionally prefixed with -", SystemCreatedAt, SystemUpdatedAt, SystemVotes))
+	}
+	return columns[fieldName] + " " + direction + ", " + order, nil
+}
+
 // List returns work item selected by the given criteria.Expression, starting with start (zero-based) and returning at most limit items
 func (r *GormWorkItemRepository) List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, start *int, limit *int) ([]WorkItem, uint64, error) {
-	result, count, err := r.listItemsFromDB(ctx, spaceID, criteria, parentExists, start, limit)
+	return r.ListSorted(ctx, spaceID, criteria, parentExists, "", start, limit)
+}
+
+// ListSorted returns work item selected by the given criteria.Expression in the order given by sort, see orderBy,
+// starting with start (zero-based) and returning at most limit items
+func (r *GormWorkItemRepository) ListSorted(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, sort string, start *int, limit *int) ([]WorkItem, uint64, error) {
+	result, count, err := r.listItemsFromDB(ctx, spaceID, criteria, parentExists, sort, start, limit)
 	if err != nil {
 		return nil, 0, errs.WithStack(err)
 	}
@@ -651,6 +1068,122 @@ func (r *GormWorkItemRepository) List(ctx context.Context, spaceID uuid.UUID, cr
 	return res, count, nil
 }
 
+// asOfQuery selects the latest revision up to a given time of every work
+// item which belonged to a space at that time. Work items which were moved
+// into the space later, or out of it before, are left out. The result has
+// the columns of the work item table, so that compiled criteria can be
+// applied to it.
+const asOfQuery = `SELECT * FROM (
+		SELECT DISTINCT ON (r.work_item_id)
+			r.work_item_id AS id,
+			r.work_item_type_id AS type,
+			r.work_item_version AS version,
+			r.work_item_fields AS fields,
+			r.revision_type,
+			r.space_id,
+			w.execution_order,
+			w.created_at,
+			r.revision_time AS updated_at
+		FROM work_item_revisions r JOIN work_items w ON w.id = r.work_item_id
+		WHERE r.revision_time <= ? AND r.work_item_id IN (SELECT work_item_id FROM work_item_revisions WHERE space_id = ?)
+		ORDER BY r.work_item_id, r.revision_time DESC
+	) AS work_items
+	WHERE space_id = ? AND revision_type != ?`
+
+// listItemsAsOfFromDB returns the work items of a space as they were at the given time
+func (r *GormWorkItemRepository) listItemsAsOfFromDB(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, asOf time.Time, start *int, limit *int) ([]WorkItemStorage, uint64, error) {
+	where, parameters, compileError := Compile(criteria)
+	if compileError != nil {
+		return nil, 0, errors.NewBadParameterError("expression", criteria)
+	}
+	query := asOfQuery + " AND " + where
+	parameters = append([]interface{}{asOf, spaceID, spaceID, RevisionTypeDelete}, parameters...)
+	if parentExists != nil && !*parentExists {
+		// links are not versioned, so the current parent relationships are used
+		query += noParentClause
+	}
+	var count uint64
+	if err := r.db.Raw("SELECT count(*) FROM ("+query+") AS snapshot", parameters...).Row().Scan(&count); err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to count work items"))
+	}
+	query += " ORDER BY execution_order desc"
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		query += " OFFSET ?"
+		parameters = append(parameters, *start)
+	}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		query += " LIMIT ?"
+		parameters = append(parameters, *limit)
+	}
+	result := []WorkItemStorage{}
+	if err := r.db.Raw(query, parameters...).Scan(&result).Error; err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to list work items"))
+	}
+	return result, count, nil
+}
+
+// loadTypeAsOf returns the definition of the work item type which was valid
+// at the given time.
+func (r *GormWorkItemRepository) loadTypeAsOf(ctx context.Context, typeID uuid.UUID, asOf time.Time) (*WorkItemType, error) {
+	return r.witr.LoadTypeAsOf(ctx, typeID, asOf)
+}
+
+// ListAsOf returns the work items selected by the given criteria.Expression as they were at the given time,
+// starting with start (zero-based) and returning at most limit items
+func (r *GormWorkItemRepository) ListAsOf(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, asOf time.Time, start *int, limit *int) ([]WorkItem, uint64, error) {
+	result, count, err := r.listItemsAsOfFromDB(ctx, spaceID, criteria, parentExists, asOf, start, limit)
+	if err != nil {
+		return nil, 0, errs.WithStack(err)
+	}
+	res := make([]WorkItem, len(result))
+	for index, value := range result {
+		wiType, err := r.loadTypeAsOf(ctx, value.Type, asOf)
+		if err != nil {
+			return nil, 0, errors.NewInternalError(err)
+		}
+		modelWI, err := ConvertWorkItemStorageToModel(wiType, &value)
+		if err != nil {
+			return nil, 0, errors.NewInternalError(err)
+		}
+		res[index] = *modelWI
+	}
+	return res, count, nil
+}
+
+// LoadAsOf returns the work item for the given spaceID and item id as it was at the given time
+// returns NotFoundError, ConversionError or InternalError
+func (r *GormWorkItemRepository) LoadAsOf(ctx context.Context, spaceID uuid.UUID, workitemID string, asOf time.Time) (*WorkItem, error) {
+	id, err := strconv.ParseUint(workitemID, 10, 64)
+	if err != nil || id == 0 {
+		// treating this as a not found error: the fact that we're using number internal is implementation detail
+		return nil, errors.NewNotFoundError("work item", workitemID)
+	}
+	log.Info(ctx, map[string]interface{}{
+		"wi_id":    workitemID,
+		"space_id": spaceID,
+		"as_of":    asOf,
+	}, "Loading work item revision")
+	exp := criteria.Equals(criteria.Field("ID"), criteria.Literal(id))
+	result, _, err := r.listItemsAsOfFromDB(ctx, spaceID, exp, nil, asOf, nil, nil)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if len(result) == 0 {
+		return nil, errors.NewNotFoundError("work item", workitemID)
+	}
+	wiType, err := r.loadTypeAsOf(ctx, result[0].Type, asOf)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	return ConvertWorkItemStorageToModel(wiType, &result[0])
+}
+
 // Counts returns the amount of work item that satisfy the given criteria.Expression
 func (r *GormWorkItemRepository) Count(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (int, error) {
 	where, parameters, compileError := Compile(criteria)
@@ -728,3 +1261,282 @@ func (r *GormWorkItemRepository) GetCountsForIteration(ctx context.Context, iter
 	}
 	return countsMap, nil
 }
+
+// GetCountsPerLabel returns a map of labelID->number of work items which have
+// the label attached, for all labels of the given space. It executes
+// 	SELECT labels.id, count(work_items.id) FROM labels LEFT JOIN work_items
+// 		ON work_items.fields @> jsonb_build_object('system.labels', jsonb_build_array(labels.id::text))
+// 		AND work_items.deleted_at IS NULL
+// 		WHERE labels.space_id = '...' AND labels.deleted_at IS NULL GROUP BY labels.id
+func (r *GormWorkItemRepository) GetCountsPerLabel(ctx context.Context, spaceID uuid.UUID) (map[string]int, error) {
+	rows, err := r.db.Raw(`SELECT labels.id, count(work_items.id) FROM labels LEFT JOIN work_items
+				ON work_items.fields @> jsonb_build_object('system.labels', jsonb_build_array(labels.id::text))
+				AND work_items.deleted_at IS NULL
+				WHERE labels.space_id = ? AND labels.deleted_at IS NULL GROUP BY labels.id`, spaceID).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	defer rows.Close()
+	countsMap := map[string]int{}
+	for rows.Next() {
+		var labelID string
+		var count int
+		if err := rows.Scan(&labelID, &count); err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		countsMap[labelID] = count
+	}
+	return countsMap, nil
+}
+
+// DetachLabel removes the label with the given ID from all the work items of
+// the given space. Each work item is saved with a new version and revision.
+func (r *GormWorkItemRepository) DetachLabel(ctx context.Context, spaceID uuid.UUID, labelID uuid.UUID, modifierID uuid.UUID) error {
+	exp := criteria.Equals(criteria.Field(SystemLabels), criteria.Literal([]string{labelID.String()}))
+	workItems, _, err := r.List(ctx, spaceID, exp, nil, nil, nil)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	for _, wi := range workItems {
+		labels, _ := wi.Fields[SystemLabels].([]interface{})
+		remaining := []interface{}{}
+		for _, l := range labels {
+			if l != labelID.String() {
+				remaining = append(remaining, l)
+			}
+		}
+		wi.Fields[SystemLabels] = remaining
+		if _, err := r.Save(ctx, spaceID, wi, modifierID); err != nil {
+			return errs.Wrapf(err, "failed to detach label %s from work item %s", labelID, wi.ID)
+		}
+	}
+	return nil
+}
+
+// checkReferences returns a BadParameterError if the value of a field of kind
+// workitem or label, or of a list of them, refers to work items or labels
+// which do not exist in the given space
+func (r *GormWorkItemRepository) checkReferences(ctx context.Context, spaceID uuid.UUID, fieldName string, fieldType FieldType, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	kind := fieldType.GetKind()
+	var values []interface{}
+	switch t := fieldType.(type) {
+	case ListType:
+		kind = t.ComponentType.Kind
+		values = value.([]interface{})
+	case *ListType:
+		kind = t.ComponentType.Kind
+		values = value.([]interface{})
+	default:
+		values = []interface{}{value}
+	}
+	if kind != KindWorkitemReference && kind != KindLabel {
+		return nil
+	}
+	ids := map[string]bool{}
+	for _, id := range values {
+		ids[id.(string)] = true
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	idList := make([]string, 0, len(ids))
+	for id := range ids {
+		idList = append(idList, id)
+	}
+	if kind == KindLabel {
+		if err := label.NewLabelRepository(r.db).CheckExists(ctx, spaceID, idList); err != nil {
+			if _, ok := errs.Cause(err).(errors.BadParameterError); ok {
+				return errors.NewBadParameterError(fieldName, value).Expected("references to existing labels of the space")
+			}
+			return errs.WithStack(err)
+		}
+		return nil
+	}
+	var count int
+	db := r.db.Model(&WorkItemStorage{}).Where("space_id = ? AND id IN (?)", spaceID, idList).Count(&count)
+	if db.Error != nil {
+		return errors.NewInternalError(db.Error)
+	}
+	if count != len(idList) {
+		return errors.NewBadParameterError(fieldName, value).Expected("references to existing work items of the space")
+	}
+	return nil
+}
+
+// moveReferences returns the given value of a field of kind iteration, area,
+// label or workitem, or of a list of them, with the references replaced by
+// the ones of the given space. Values of other fields are returned as they
+// are.
+func (r *GormWorkItemRepository) moveReferences(ctx context.Context, spaceID uuid.UUID, fieldType FieldType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	kind := fieldType.GetKind()
+	switch t := fieldType.(type) {
+	case ListType:
+		kind = t.ComponentType.Kind
+	case *ListType:
+		kind = t.ComponentType.Kind
+	}
+	switch kind {
+	case KindIteration, KindArea, KindLabel, KindWorkitemReference:
+	default:
+		return value, nil
+	}
+	if values, ok := value.([]interface{}); ok {
+		return r.moveReferenceList(ctx, spaceID, kind, values)
+	}
+	moved, err := r.moveReference(ctx, spaceID, kind, value)
+	if err != nil || moved == nil {
+		return nil, err
+	}
+	return *moved, nil
+}
+
+// moveReferenceList replaces each reference of the given list with the one of
+// the given space and leaves out those which cannot be replaced
+func (r *GormWorkItemRepository) moveReferenceList(ctx context.Context, spaceID uuid.UUID, kind Kind, values []interface{}) (interface{}, error) {
+	result := []interface{}{}
+	for _, value := range values {
+		moved, err := r.moveReference(ctx, spaceID, kind, value)
+		if err != nil {
+			return nil, err
+		}
+		if moved != nil {
+			result = append(result, *moved)
+		}
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// moveReference returns the ID of the iteration, area, label or work item of
+// the given space which replaces the one referred to by the given value, or
+// nil if there is none
+func (r *GormWorkItemRepository) moveReference(ctx context.Context, spaceID uuid.UUID, kind Kind, value interface{}) (*string, error) {
+	id := fmt.Sprint(value)
+	switch kind {
+	case KindIteration:
+		iterationRepo := iteration.NewIterationRepository(r.db)
+		iterationID, err := uuid.FromString(id)
+		if err != nil {
+			return nil, errors.NewBadParameterError("iteration", value)
+		}
+		itr, err := iterationRepo.Load(ctx, iterationID)
+		if _, ok := errs.Cause(err).(errors.NotFoundError); ok {
+			itr = &iteration.Iteration{}
+		} else if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if uuid.Equal(itr.SpaceID, spaceID) {
+			return &id, nil
+		}
+		if !itr.Path.IsEmpty() {
+			iterations, err := iterationRepo.List(ctx, spaceID)
+			if err != nil {
+				return nil, errs.WithStack(err)
+			}
+			for _, candidate := range iterations {
+				if !candidate.Path.IsEmpty() && candidate.Name == itr.Name {
+					result := candidate.ID.String()
+					return &result, nil
+				}
+			}
+		}
+		root, err := iterationRepo.Root(ctx, spaceID)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if uuid.Equal(root.ID, uuid.Nil) {
+			return nil, nil
+		}
+		result := root.ID.String()
+		return &result, nil
+	case KindArea:
+		areaRepo := area.NewAreaRepository(r.db)
+		areaID, err := uuid.FromString(id)
+		if err != nil {
+			return nil, errors.NewBadParameterError("area", value)
+		}
+		a, err := areaRepo.Load(ctx, areaID)
+		if _, ok := errs.Cause(err).(errors.NotFoundError); ok {
+			a = &area.Area{}
+		} else if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if uuid.Equal(a.SpaceID, spaceID) {
+			return &id, nil
+		}
+		var areas []area.Area
+		if !a.Path.IsEmpty() {
+			areas, err = areaRepo.Query(area.FilterBySpaceID(spaceID), area.FilterByName(a.Name))
+			if err != nil {
+				return nil, errs.WithStack(err)
+			}
+		}
+		if len(areas) == 0 {
+			areas, err = areaRepo.Query(area.FilterBySpaceID(spaceID), area.FilterByPath(path.Path{}))
+			if err != nil {
+				return nil, errs.WithStack(err)
+			}
+		}
+		if len(areas) == 0 {
+			return nil, nil
+		}
+		result := areas[0].ID.String()
+		return &result, nil
+	case KindLabel:
+		labelID, err := uuid.FromString(id)
+		if err != nil {
+			return nil, errors.NewBadParameterError("label", value)
+		}
+		var l label.Label
+		db := r.db.Where("id = ?", labelID).First(&l)
+		if db.RecordNotFound() {
+			return nil, nil
+		}
+		if db.Error != nil {
+			return nil, errors.NewInternalError(db.Error)
+		}
+		if uuid.Equal(l.SpaceID, spaceID) {
+			return &id, nil
+		}
+		labels, err := label.NewLabelRepository(r.db).List(ctx, spaceID)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		for _, candidate := range labels {
+			if candidate.Name == l.Name {
+				result := candidate.ID.String()
+				return &result, nil
+			}
+		}
+		return nil, nil
+	case KindWorkitemReference:
+		var count int
+		db := r.db.Model(&WorkItemStorage{}).Where("space_id = ? AND id = ?", spaceID, id).Count(&count)
+		if db.Error != nil {
+			return nil, errors.NewInternalError(db.Error)
+		}
+		if count == 0 {
+			return nil, nil
+		}
+		return &id, nil
+	}
+	return nil, nil
+}
+
+// fieldValueError returns the error for a field value which could not be
+// converted. Violated field constraints are reported as they are, since they
+// tell what was expected.
+func fieldValueError(fieldName string, fieldValue interface{}, err error) error {
+	if badParameter, ok := errs.Cause(err).(errors.BadParameterError); ok {
+		return badParameter
+	}
+	return errors.NewBadParameterError(fieldName, fieldValue)
+}
@@ -2,14 +2,21 @@ package workitem_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/fabric8io/almighty-core/area"
 	"github.com/fabric8io/almighty-core/codebase"
+	"github.com/fabric8io/almighty-core/criteria"
 	"github.com/fabric8io/almighty-core/errors"
 	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
 	"github.com/fabric8io/almighty-core/gormtestsupport"
 	"github.com/fabric8io/almighty-core/iteration"
+	"github.com/fabric8io/almighty-core/label"
 	"github.com/fabric8io/almighty-core/migration"
+	"github.com/fabric8io/almighty-core/path"
+	"github.com/fabric8io/almighty-core/reaction"
 	"github.com/fabric8io/almighty-core/rendering"
 	"github.com/fabric8io/almighty-core/resource"
 	"github.com/fabric8io/almighty-core/space"
@@ -301,3 +308,613 @@ func (s *workItemRepoBlackBoxTest) TestCodebaseAttributes() {
 	assert.Equal(s.T(), file, cb.FileName)
 	assert.Equal(s.T(), line, cb.LineNumber)
 }
+
+func (s *workItemRepoBlackBoxTest) TestListWithComparisonCriteria() {
+	// given
+	start := time.Now().Add(-1 * time.Minute)
+	titles := []string{"compare alpha", "compare beta", "compare gamma"}
+	for _, title := range titles {
+		_, err := s.repo.Create(
+			s.ctx, s.spaceID, workitem.SystemBug,
+			map[string]interface{}{
+				workitem.SystemTitle: title,
+				workitem.SystemState: workitem.SystemStateNew,
+			}, s.creatorID)
+		require.Nil(s.T(), err)
+	}
+	inTest := criteria.Contains(criteria.Field(workitem.SystemTitle), criteria.Literal("COMPARE"))
+	testData := map[string]struct {
+		exp      criteria.Expression
+		expected int
+	}{
+		"contains":     {inTest, 3},
+		"in":           {criteria.And(inTest, criteria.In(criteria.Field(workitem.SystemTitle), criteria.Literal("compare alpha"), criteria.Literal("compare gamma"))), 2},
+		"greater than": {criteria.And(inTest, criteria.GreaterThan(criteria.Field(workitem.SystemTitle), criteria.Literal("compare b"))), 2},
+		"between":      {criteria.And(inTest, criteria.Between(criteria.Field(workitem.SystemCreatedAt), criteria.Literal(start), criteria.Literal(time.Now().Add(time.Minute)))), 3},
+		"updated":      {criteria.And(inTest, criteria.LessThan(criteria.Field(workitem.SystemUpdatedAt), criteria.Literal(start))), 0},
+	}
+	for name, d := range testData {
+		// when
+		_, count, err := s.repo.List(s.ctx, s.spaceID, d.exp, nil, nil, nil)
+		// then
+		require.Nil(s.T(), err, name)
+		assert.Equal(s.T(), uint64(d.expected), count, name)
+	}
+}
+
+func (s *workItemRepoBlackBoxTest) TestAsOf() {
+	// given
+	wi, err := s.repo.Create(
+		s.ctx, s.spaceID, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "as of title",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, s.creatorID)
+	require.Nil(s.T(), err)
+	before := time.Now()
+	wi.Fields[workitem.SystemTitle] = "as of updated title"
+	_, err = s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+	require.Nil(s.T(), err)
+	exp := criteria.Equals(criteria.Field(workitem.SystemTitle), criteria.Literal("as of title"))
+
+	s.T().Run("load", func(t *testing.T) {
+		// when
+		old, err := s.repo.LoadAsOf(s.ctx, s.spaceID, wi.ID, before)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, "as of title", old.Fields[workitem.SystemTitle])
+		assert.Equal(t, wi.Version, old.Version)
+	})
+
+	s.T().Run("load before creation", func(t *testing.T) {
+		_, err := s.repo.LoadAsOf(s.ctx, s.spaceID, wi.ID, before.Add(-1*time.Hour))
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	s.T().Run("list", func(t *testing.T) {
+		// when
+		items, count, err := s.repo.ListAsOf(s.ctx, s.spaceID, exp, nil, before, nil, nil)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, uint64(1), count)
+		require.Len(t, items, 1)
+		assert.Equal(t, wi.ID, items[0].ID)
+		// the current state no longer matches
+		_, count, err = s.repo.List(s.ctx, s.spaceID, exp, nil, nil, nil)
+		require.Nil(t, err)
+		assert.Equal(t, uint64(0), count)
+	})
+
+	s.T().Run("list deleted", func(t *testing.T) {
+		// given
+		require.Nil(t, s.repo.Delete(s.ctx, s.spaceID, wi.ID, s.creatorID))
+		// when
+		_, count, err := s.repo.ListAsOf(s.ctx, s.spaceID, exp, nil, before, nil, nil)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, uint64(1), count)
+		_, err = s.repo.LoadAsOf(s.ctx, s.spaceID, wi.ID, time.Now())
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestRestoreAndListDeleted() {
+	// given
+	wi, err := s.repo.Create(
+		s.ctx, s.spaceID, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "restore title",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, s.creatorID)
+	require.Nil(s.T(), err)
+	before := time.Now()
+	require.Nil(s.T(), s.repo.Delete(s.ctx, s.spaceID, wi.ID, s.creatorID))
+
+	s.T().Run("list deleted", func(t *testing.T) {
+		// when
+		items, count, err := s.repo.ListDeleted(s.ctx, s.spaceID, &before, nil, nil)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, uint64(1), count)
+		require.Len(t, items, 1)
+		assert.Equal(t, wi.ID, items[0].ID)
+		assert.NotNil(t, items[0].Fields[workitem.SystemDeletedAt])
+		// nothing was deleted after now
+		now := time.Now()
+		_, count, err = s.repo.ListDeleted(s.ctx, s.spaceID, &now, nil, nil)
+		require.Nil(t, err)
+		assert.Equal(t, uint64(0), count)
+	})
+
+	s.T().Run("restore", func(t *testing.T) {
+		// when
+		restored, deletedAt, err := s.repo.Restore(s.ctx, s.spaceID, wi.ID, s.creatorID)
+		// then
+		require.Nil(t, err)
+		assert.True(t, deletedAt.After(before))
+		assert.Equal(t, "restore title", restored.Fields[workitem.SystemTitle])
+		assert.Equal(t, wi.Version+1, restored.Version)
+		_, err = s.repo.Load(s.ctx, s.spaceID, wi.ID)
+		require.Nil(t, err)
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.ctx, wi.ID)
+		require.Nil(t, err)
+		require.Len(t, revisions, 3)
+		assert.Equal(t, workitem.RevisionTypeRestore, revisions[2].Type)
+	})
+
+	s.T().Run("restore existing work item", func(t *testing.T) {
+		_, _, err := s.repo.Restore(s.ctx, s.spaceID, wi.ID, s.creatorID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestCreateWithDefaultValues() {
+	// given a space with a root area, a root iteration and a running iteration
+	spaceInstance := space.Space{Name: "Testing space " + uuid.NewV4().String()}
+	_, err := space.NewRepository(s.DB).Create(s.ctx, &spaceInstance)
+	require.Nil(s.T(), err)
+	rootArea := area.Area{Name: "Root area", SpaceID: spaceInstance.ID}
+	require.Nil(s.T(), area.NewAreaRepository(s.DB).Create(s.ctx, &rootArea))
+	iterationRepo := iteration.NewIterationRepository(s.DB)
+	rootIteration := iteration.Iteration{Name: "Root iteration", SpaceID: spaceInstance.ID}
+	require.Nil(s.T(), iterationRepo.Create(s.ctx, &rootIteration))
+	start := time.Now().Add(-24 * time.Hour)
+	sprint := iteration.Iteration{Name: "Sprint 1", SpaceID: spaceInstance.ID, Path: path.Path{rootIteration.ID}, StartAt: &start}
+	require.Nil(s.T(), iterationRepo.Create(s.ctx, &sprint))
+	sprint.State = iteration.IterationStateStart
+	_, err = iterationRepo.Save(s.ctx, sprint)
+	require.Nil(s.T(), err)
+	// and a type with default values
+	wit, err := workitem.NewWorkItemTypeRepository(s.DB).Create(s.ctx, spaceInstance.ID, nil, nil, "defaults", nil, "fa-bomb", map[string]workitem.FieldDefinition{
+		workitem.SystemTitle:     {Type: workitem.SimpleType{Kind: workitem.KindString}, Required: true},
+		workitem.SystemArea:      {Type: workitem.SimpleType{Kind: workitem.KindArea}, Default: &workitem.FieldDefault{Kind: workitem.DefaultRootArea}},
+		workitem.SystemIteration: {Type: workitem.SimpleType{Kind: workitem.KindIteration}, Default: &workitem.FieldDefault{Kind: workitem.DefaultCurrentIteration}},
+		workitem.SystemAssignees: {
+			Type:    workitem.ListType{SimpleType: workitem.SimpleType{Kind: workitem.KindList}, ComponentType: workitem.SimpleType{Kind: workitem.KindUser}},
+			Default: &workitem.FieldDefault{Kind: workitem.DefaultCurrentUser},
+		},
+		"reported": {Type: workitem.SimpleType{Kind: workitem.KindInstant}, Default: &workitem.FieldDefault{Kind: workitem.DefaultNow}},
+		// literals read from JSON are float64
+		"estimate": {Type: workitem.SimpleType{Kind: workitem.KindInteger}, Default: &workitem.FieldDefault{Kind: workitem.DefaultLiteral, Value: 3.0}},
+	})
+	require.Nil(s.T(), err)
+
+	s.T().Run("values left out", func(t *testing.T) {
+		// when
+		before := time.Now()
+		wi, err := s.repo.Create(s.ctx, spaceInstance.ID, wit.ID, map[string]interface{}{
+			workitem.SystemTitle: "Title",
+		}, s.creatorID)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, rootArea.ID.String(), wi.Fields[workitem.SystemArea])
+		assert.Equal(t, sprint.ID.String(), wi.Fields[workitem.SystemIteration])
+		assert.Equal(t, []interface{}{s.creatorID.String()}, wi.Fields[workitem.SystemAssignees])
+		assert.Equal(t, 3, wi.Fields["estimate"])
+		require.IsType(t, time.Time{}, wi.Fields["reported"])
+		assert.False(t, wi.Fields["reported"].(time.Time).Before(before.Add(-time.Second)))
+	})
+
+	s.T().Run("values given", func(t *testing.T) {
+		// when
+		wi, err := s.repo.Create(s.ctx, spaceInstance.ID, wit.ID, map[string]interface{}{
+			workitem.SystemTitle:     "Title",
+			workitem.SystemIteration: rootIteration.ID.String(),
+			"estimate":               8,
+		}, s.creatorID)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, rootIteration.ID.String(), wi.Fields[workitem.SystemIteration])
+		assert.Equal(t, 8, wi.Fields["estimate"])
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestSaveChecksWorkflow() {
+	// given a type with a workflow
+	witRepo := workitem.NewWorkItemTypeRepository(s.DB)
+	wit, err := witRepo.Create(s.ctx, s.spaceID, nil, nil, "workflow "+uuid.NewV4().String(), nil, "fa-bomb", map[string]workitem.FieldDefinition{
+		workitem.SystemTitle: {Type: workitem.SimpleType{Kind: workitem.KindString}, Required: true},
+		workitem.SystemState: {
+			Type: workitem.EnumType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+				BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+				Values:     []interface{}{"new", "open", "resolved", "closed"},
+			},
+		},
+		workitem.SystemAssignees: {Type: workitem.ListType{SimpleType: workitem.SimpleType{Kind: workitem.KindList}, ComponentType: workitem.SimpleType{Kind: workitem.KindUser}}},
+	})
+	require.Nil(s.T(), err)
+	wit.Workflow = workitem.Workflow{
+		States: []string{"new", "open", "closed"},
+		Transitions: []workitem.WorkflowTransition{
+			{From: "new", To: "open"},
+			{From: "open", To: "closed", RequiredFields: []string{workitem.SystemAssignees}},
+		},
+	}
+	wit, err = witRepo.Update(s.ctx, s.spaceID, *wit, s.creatorID, false)
+	require.Nil(s.T(), err)
+	createWorkItem := func(t *testing.T) *workitem.WorkItem {
+		wi, err := s.repo.Create(s.ctx, s.spaceID, wit.ID, map[string]interface{}{
+			workitem.SystemTitle: "Title",
+			workitem.SystemState: "new",
+		}, s.creatorID)
+		require.Nil(t, err)
+		return wi
+	}
+
+	s.T().Run("illegal initial state", func(t *testing.T) {
+		// when creating a work item in a state that is not part of the workflow
+		_, err := s.repo.Create(s.ctx, s.spaceID, wit.ID, map[string]interface{}{
+			workitem.SystemTitle: "Title",
+			workitem.SystemState: "resolved",
+		}, s.creatorID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("allowed transition", func(t *testing.T) {
+		// given
+		wi := createWorkItem(t)
+		// when
+		wi.Fields[workitem.SystemState] = "open"
+		wi, err := s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, "open", wi.Fields[workitem.SystemState])
+	})
+
+	s.T().Run("illegal transition", func(t *testing.T) {
+		// given
+		wi := createWorkItem(t)
+		// when
+		wi.Fields[workitem.SystemState] = "closed"
+		_, err := s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+		// then
+		require.IsType(t, errors.StateTransitionError{}, errs.Cause(err))
+		assert.Equal(t, []string{"open"}, errs.Cause(err).(errors.StateTransitionError).AllowedStates)
+	})
+
+	s.T().Run("guarded transition", func(t *testing.T) {
+		// given
+		wi := createWorkItem(t)
+		wi.Fields[workitem.SystemState] = "open"
+		wi, err := s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+		require.Nil(t, err)
+		// when
+		wi.Fields[workitem.SystemState] = "closed"
+		_, err = s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+		// then
+		require.IsType(t, errors.StateTransitionError{}, errs.Cause(err))
+		// when
+		wi.Fields[workitem.SystemAssignees] = []interface{}{s.creatorID.String()}
+		wi, err = s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, "closed", wi.Fields[workitem.SystemState])
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestNewFieldKinds() {
+	// given
+	wit, err := workitem.NewWorkItemTypeRepository(s.DB).Create(s.ctx, s.spaceID, nil, nil, "kinds "+uuid.NewV4().String(), nil, "fa-bomb", map[string]workitem.FieldDefinition{
+		workitem.SystemTitle: {Type: workitem.SimpleType{Kind: workitem.KindString}, Required: true},
+		"blocked":            {Type: workitem.SimpleType{Kind: workitem.KindBoolean}},
+		"due":                {Type: workitem.SimpleType{Kind: workitem.KindDate}},
+		"duplicate":          {Type: workitem.SimpleType{Kind: workitem.KindWorkitemReference}},
+		"blocks": {Type: workitem.ListType{
+			SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+			ComponentType: workitem.SimpleType{Kind: workitem.KindWorkitemReference},
+		}},
+	})
+	require.Nil(s.T(), err)
+	target, err := s.repo.Create(s.ctx, s.spaceID, wit.ID, map[string]interface{}{
+		workitem.SystemTitle: "Target",
+	}, s.creatorID)
+	require.Nil(s.T(), err)
+
+	s.T().Run("values", func(t *testing.T) {
+		// when
+		wi, err := s.repo.Create(s.ctx, s.spaceID, wit.ID, map[string]interface{}{
+			workitem.SystemTitle: "Source",
+			"blocked":            true,
+			"due":                "2017-06-01",
+			"duplicate":          target.ID,
+			"blocks":             []interface{}{target.ID},
+		}, s.creatorID)
+		// then
+		require.Nil(t, err)
+		loaded, err := s.repo.Load(s.ctx, s.spaceID, wi.ID)
+		require.Nil(t, err)
+		assert.Equal(t, true, loaded.Fields["blocked"])
+		assert.Equal(t, "2017-06-01", loaded.Fields["due"])
+		assert.Equal(t, target.ID, loaded.Fields["duplicate"])
+		assert.Equal(t, []interface{}{target.ID}, loaded.Fields["blocks"])
+		// and the values can be queried
+		day := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+		exp := criteria.And(criteria.Equals(criteria.Field("due"), criteria.Literal(day)), criteria.Equals(criteria.Field("blocked"), criteria.Literal(true)))
+		items, count, err := s.repo.List(s.ctx, s.spaceID, exp, nil, nil, nil)
+		require.Nil(t, err)
+		assert.Equal(t, uint64(1), count)
+		require.Len(t, items, 1)
+		assert.Equal(t, wi.ID, items[0].ID)
+	})
+
+	s.T().Run("unknown work item", func(t *testing.T) {
+		// when
+		_, err := s.repo.Create(s.ctx, s.spaceID, wit.ID, map[string]interface{}{
+			workitem.SystemTitle: "Source",
+			"blocks":             []interface{}{target.ID, "999999999"},
+		}, s.creatorID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestLabels() {
+	// given
+	labelRepo := label.NewLabelRepository(s.DB)
+	urgent := label.Label{SpaceID: s.spaceID, Name: "urgent " + uuid.NewV4().String(), Color: "#ff0000"}
+	require.Nil(s.T(), labelRepo.Create(s.ctx, &urgent))
+	backend := label.Label{SpaceID: s.spaceID, Name: "backend " + uuid.NewV4().String()}
+	require.Nil(s.T(), labelRepo.Create(s.ctx, &backend))
+	wi, err := s.repo.Create(s.ctx, s.spaceID, workitem.SystemBug, map[string]interface{}{
+		workitem.SystemTitle:  "Title",
+		workitem.SystemState:  workitem.SystemStateNew,
+		workitem.SystemLabels: []interface{}{urgent.ID.String(), backend.ID.String()},
+	}, s.creatorID)
+	require.Nil(s.T(), err)
+
+	s.T().Run("filter and count", func(t *testing.T) {
+		// when
+		exp := criteria.Equals(criteria.Field(workitem.SystemLabels), criteria.Literal([]string{urgent.ID.String()}))
+		items, count, err := s.repo.List(s.ctx, s.spaceID, exp, nil, nil, nil)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, uint64(1), count)
+		require.Len(t, items, 1)
+		assert.Equal(t, wi.ID, items[0].ID)
+		counts, err := s.repo.GetCountsPerLabel(s.ctx, s.spaceID)
+		require.Nil(t, err)
+		assert.Equal(t, 1, counts[urgent.ID.String()])
+		assert.Equal(t, 1, counts[backend.ID.String()])
+	})
+
+	s.T().Run("unknown label", func(t *testing.T) {
+		// when
+		_, err := s.repo.Create(s.ctx, s.spaceID, workitem.SystemBug, map[string]interface{}{
+			workitem.SystemTitle:  "Title",
+			workitem.SystemState:  workitem.SystemStateNew,
+			workitem.SystemLabels: []interface{}{uuid.NewV4().String()},
+		}, s.creatorID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("detach and delete the label", func(t *testing.T) {
+		// when
+		err := s.repo.DetachLabel(s.ctx, s.spaceID, urgent.ID, s.creatorID)
+		require.Nil(t, err)
+		err = labelRepo.Delete(s.ctx, s.spaceID, urgent.ID)
+		// then
+		require.Nil(t, err)
+		loaded, err := s.repo.Load(s.ctx, s.spaceID, wi.ID)
+		require.Nil(t, err)
+		assert.Equal(t, []interface{}{backend.ID.String()}, loaded.Fields[workitem.SystemLabels])
+		assert.Equal(t, wi.Version+1, loaded.Version)
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.ctx, wi.ID)
+		require.Nil(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, workitem.RevisionTypeUpdate, revisions[1].Type)
+		counts, err := s.repo.GetCountsPerLabel(s.ctx, s.spaceID)
+		require.Nil(t, err)
+		_, found := counts[urgent.ID.String()]
+		assert.False(t, found)
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestMove() {
+	// given two spaces with an iteration and a label of the same name and a
+	// type of the same name
+	witRepo := workitem.NewWorkItemTypeRepository(s.DB)
+	iterationRepo := iteration.NewIterationRepository(s.DB)
+	labelRepo := label.NewLabelRepository(s.DB)
+	type fixture struct {
+		space     space.Space
+		rootArea  area.Area
+		sprint    iteration.Iteration
+		urgent    label.Label
+		incidents *workitem.WorkItemType
+	}
+	newFixture := func() fixture {
+		f := fixture{space: space.Space{Name: "Moving space " + uuid.NewV4().String()}}
+		_, err := space.NewRepository(s.DB).Create(s.ctx, &f.space)
+		require.Nil(s.T(), err)
+		f.rootArea = area.Area{Name: "Root area", SpaceID: f.space.ID}
+		require.Nil(s.T(), area.NewAreaRepository(s.DB).Create(s.ctx, &f.rootArea))
+		rootIteration := iteration.Iteration{Name: "Root iteration", SpaceID: f.space.ID}
+		require.Nil(s.T(), iterationRepo.Create(s.ctx, &rootIteration))
+		f.sprint = iteration.Iteration{Name: "Sprint 1", SpaceID: f.space.ID, Path: path.Path{rootIteration.ID}}
+		require.Nil(s.T(), iterationRepo.Create(s.ctx, &f.sprint))
+		f.urgent = label.Label{Name: "urgent", SpaceID: f.space.ID}
+		require.Nil(s.T(), labelRepo.Create(s.ctx, &f.urgent))
+		f.incidents, err = witRepo.Create(s.ctx, f.space.ID, nil, &workitem.SystemPlannerItem, "incident", nil, "fa-fire", nil)
+		require.Nil(s.T(), err)
+		return f
+	}
+	source := newFixture()
+	target := newFixture()
+	wi, err := s.repo.Create(s.ctx, source.space.ID, source.incidents.ID, map[string]interface{}{
+		workitem.SystemTitle:     "Filed in the wrong space",
+		workitem.SystemState:     workitem.SystemStateNew,
+		workitem.SystemIteration: source.sprint.ID.String(),
+		workitem.SystemLabels:    []interface{}{source.urgent.ID.String()},
+	}, s.creatorID)
+	require.Nil(s.T(), err)
+
+	s.T().Run("version conflict", func(t *testing.T) {
+		// given
+		outdated := *wi
+		outdated.Version = wi.Version - 1
+		// when
+		_, err := s.repo.Move(s.ctx, source.space.ID, outdated, target.space.ID, s.creatorID)
+		// then
+		require.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+
+	s.T().Run("type not available", func(t *testing.T) {
+		// given a space without an incident type
+		other := space.Space{Name: "Other space " + uuid.NewV4().String()}
+		_, err := space.NewRepository(s.DB).Create(s.ctx, &other)
+		require.Nil(t, err)
+		// when
+		_, err = s.repo.Move(s.ctx, source.space.ID, *wi, other.ID, s.creatorID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		before := time.Now()
+		time.Sleep(10 * time.Millisecond)
+		// when
+		moved, err := s.repo.Move(s.ctx, source.space.ID, *wi, target.space.ID, s.creatorID)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, wi.ID, moved.ID)
+		assert.Equal(t, target.space.ID, moved.SpaceID)
+		assert.Equal(t, target.incidents.ID, moved.Type)
+		assert.Equal(t, target.sprint.ID.String(), moved.Fields[workitem.SystemIteration])
+		assert.Equal(t, target.rootArea.ID.String(), moved.Fields[workitem.SystemArea])
+		assert.Equal(t, []interface{}{target.urgent.ID.String()}, moved.Fields[workitem.SystemLabels])
+		assert.Equal(t, "Filed in the wrong space", moved.Fields[workitem.SystemTitle])
+		_, err = s.repo.Load(s.ctx, source.space.ID, wi.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.ctx, wi.ID)
+		require.Nil(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, workitem.RevisionTypeUpdate, revisions[1].Type)
+		assert.Equal(t, source.space.ID, revisions[0].SpaceID)
+		assert.Equal(t, target.space.ID, revisions[1].SpaceID)
+		// the work item belonged to the source space before the move
+		old, err := s.repo.LoadAsOf(s.ctx, source.space.ID, wi.ID, before)
+		require.Nil(t, err)
+		assert.Equal(t, source.incidents.ID, old.Type)
+		_, err = s.repo.LoadAsOf(s.ctx, target.space.ID, wi.ID, before)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		_, err = s.repo.LoadAsOf(s.ctx, source.space.ID, wi.ID, time.Now())
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestNumbers() {
+	// given a space with a key
+	key := "K" + strings.ToUpper(uuid.NewV4().String()[:8])
+	spaceInstance := space.Space{Name: "Numbered space " + uuid.NewV4().String(), Key: &key}
+	_, err := space.NewRepository(s.DB).Create(s.ctx, &spaceInstance)
+	require.Nil(s.T(), err)
+	fields := map[string]interface{}{
+		workitem.SystemTitle: "Title",
+		workitem.SystemState: workitem.SystemStateNew,
+	}
+	first, err := s.repo.Create(s.ctx, spaceInstance.ID, workitem.SystemBug, fields, s.creatorID)
+	require.Nil(s.T(), err)
+	second, err := s.repo.Create(s.ctx, spaceInstance.ID, workitem.SystemBug, fields, s.creatorID)
+	require.Nil(s.T(), err)
+
+	s.T().Run("numbered per space", func(t *testing.T) {
+		assert.Equal(t, 1, first.Number)
+		assert.Equal(t, 2, second.Number)
+	})
+
+	s.T().Run("load by key", func(t *testing.T) {
+		// when
+		wi, err := s.repo.LoadByID(s.ctx, fmt.Sprintf("%s-2", key))
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, second.ID, wi.ID)
+		assert.Equal(t, 2, wi.Number)
+	})
+
+	s.T().Run("load by numeric ID", func(t *testing.T) {
+		// when
+		wi, err := s.repo.LoadByID(s.ctx, first.ID)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, 1, wi.Number)
+	})
+
+	s.T().Run("unknown key", func(t *testing.T) {
+		// when
+		_, err := s.repo.LoadByID(s.ctx, fmt.Sprintf("%s-3", key))
+		// then
+		require.NotNil(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestListSorted() {
+	// given
+	titles := []string{"vote alpha", "vote beta", "vote gamma"}
+	items := make([]*workitem.WorkItem, len(titles))
+	for i, title := range titles {
+		wi, err := s.repo.Create(
+			s.ctx, s.spaceID, workitem.SystemBug,
+			map[string]interface{}{
+				workitem.SystemTitle: title,
+				workitem.SystemState: workitem.SystemStateNew,
+			}, s.creatorID)
+		require.Nil(s.T(), err)
+		items[i] = wi
+	}
+	voter, err := testsupport.CreateTestIdentity(s.DB, "TestListSorted-"+uuid.NewV4().String(), "test")
+	require.Nil(s.T(), err)
+	reactions := reaction.NewRepository(s.DB)
+	for _, vote := range []struct {
+		wi      *workitem.WorkItem
+		voterID uuid.UUID
+		content string
+	}{
+		{items[0], s.creatorID, reaction.ContentPlusOne},
+		{items[1], s.creatorID, reaction.ContentHeart},
+		{items[2], s.creatorID, reaction.ContentPlusOne},
+		{items[2], voter.ID, reaction.ContentPlusOne},
+	} {
+		_, err := reactions.Toggle(s.ctx, reaction.KindWorkItem, vote.wi.ID, vote.content, vote.voterID)
+		require.Nil(s.T(), err)
+	}
+	exp := criteria.Contains(criteria.Field(workitem.SystemTitle), criteria.Literal("VOTE"))
+
+	s.T().Run("most votes first", func(t *testing.T) {
+		// when
+		result, count, err := s.repo.ListSorted(s.ctx, s.spaceID, exp, nil, "-"+workitem.SystemVotes, nil, nil)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, uint64(3), count)
+		require.Len(t, result, 3)
+		assert.Equal(t, items[2].ID, result[0].ID)
+		assert.Equal(t, items[0].ID, result[1].ID)
+		assert.Equal(t, items[1].ID, result[2].ID)
+	})
+
+	s.T().Run("filter by votes", func(t *testing.T) {
+		// when
+		_, count, err := s.repo.List(s.ctx, s.spaceID, criteria.And(exp, criteria.GreaterThan(criteria.Field(workitem.SystemVotes), criteria.Literal(1))), nil, nil, nil)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, uint64(1), count)
+	})
+
+	s.T().Run("unknown sort field", func(t *testing.T) {
+		// when
+		_, _, err := s.repo.ListSorted(s.ctx, s.spaceID, exp, nil, "-system.title", nil, nil)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("column not to sort by", func(t *testing.T) {
+		// when
+		_, _, err := s.repo.ListSorted(s.ctx, s.spaceID, exp, nil, workitem.SystemChecklistOpen, nil, nil)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
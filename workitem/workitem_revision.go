@@ -18,8 +18,25 @@ const (
 	_                  // ignore 3rd value
 	// RevisionTypeUpdate a work item update
 	RevisionTypeUpdate // 4
+	// RevisionTypeRestore a work item restoration after its deletion
+	RevisionTypeRestore // 5
 )
 
+// String returns the name of the revision type as used in the REST API
+func (t RevisionType) String() string {
+	switch t {
+	case RevisionTypeCreate:
+		return "create"
+	case RevisionTypeDelete:
+		return "delete"
+	case RevisionTypeUpdate:
+		return "update"
+	case RevisionTypeRestore:
+		return "restore"
+	}
+	return "unknown"
+}
+
 // Revision represents a version of a work item
 type Revision struct {
 	ID uuid.UUID `gorm:"primary_key"`
@@ -33,6 +50,8 @@ type Revision struct {
 	WorkItemID uint64 `gorm:"column:work_item_id"`
 	// Id of the type of this work item
 	WorkItemTypeID uuid.UUID `gorm:"column:work_item_type_id"`
+	// the space the work item belonged to, since it can be moved to another one
+	SpaceID uuid.UUID `sql:"type:uuid" gorm:"column:space_id"`
 	// Version of the workitem that was modified
 	WorkItemVersion int `gorm:"column:work_item_version"`
 	// the field values (or empty when the work item was deleted)
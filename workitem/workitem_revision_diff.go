@@ -0,0 +1,137 @@
+package workitem
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// TextDiffOperation defines the kind of a chunk of a text diff
+type TextDiffOperation string
+
+const (
+	// TextDiffInsert marks text which was added
+	TextDiffInsert TextDiffOperation = "insert"
+	// TextDiffDelete marks text which was removed
+	TextDiffDelete TextDiffOperation = "delete"
+	// TextDiffEqual marks text which was left unchanged
+	TextDiffEqual TextDiffOperation = "equal"
+)
+
+// TextDiff is a chunk of the difference between two texts
+type TextDiff struct {
+	Operation TextDiffOperation
+	Text      string
+}
+
+// FieldChange describes how the value of a single field changed from one
+// revision of a work item to the next one
+type FieldChange struct {
+	Name     string
+	OldValue interface{}
+	NewValue interface{}
+	// TextDiff holds the differences between the old and the new text of a
+	// markup field, it is nil for all other kinds of fields
+	TextDiff []TextDiff
+}
+
+// Changes computes the field changes from the previous revision to this one,
+// sorted by field name. previous is nil for the first revision of a work
+// item. The values are converted from their storage representation with the
+// types of the fields of the given work item type, and the values of the
+// markup fields are reported as plain text along with a text diff. Deletions
+// store no fields and thus have no changes.
+func (r Revision) Changes(previous *Revision, wit *WorkItemType) []FieldChange {
+	result := []FieldChange{}
+	if r.Type == RevisionTypeDelete {
+		return result
+	}
+	var oldFields Fields
+	if previous != nil {
+		oldFields = previous.WorkItemFields
+	}
+	names := []string{}
+	for name := range oldFields {
+		names = append(names, name)
+	}
+	for name := range r.WorkItemFields {
+		if _, ok := oldFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		oldValue := oldFields[name]
+		newValue := r.WorkItemFields[name]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		change := FieldChange{Name: name, OldValue: oldValue, NewValue: newValue}
+		var fieldType FieldType
+		if wit != nil {
+			fieldType = wit.Fields[name].Type
+		}
+		if fieldType != nil && fieldType.GetKind() != KindMarkup {
+			change.OldValue = convertFromModel(fieldType, oldValue)
+			change.NewValue = convertFromModel(fieldType, newValue)
+		}
+		if fieldType != nil && fieldType.GetKind() == KindMarkup {
+			oldText := markupText(oldValue)
+			newText := markupText(newValue)
+			if oldText == newText {
+				// only the markup language changed
+				continue
+			}
+			change.OldValue = oldText
+			change.NewValue = newText
+			change.TextDiff = diffText(oldText, newText)
+		}
+		result = append(result, change)
+	}
+	return result
+}
+
+// convertFromModel converts the given stored value with the given field type,
+// the value is left as is if it does not match the type, e.g. because the type
+// of the field changed since then
+func convertFromModel(fieldType FieldType, value interface{}) interface{} {
+	converted, err := fieldType.ConvertFromModel(value)
+	if err != nil {
+		return value
+	}
+	return converted
+}
+
+// markupText returns the text of a stored markup field value
+func markupText(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v[rendering.ContentKey].(string); ok {
+			return rendering.NewMarkupContentFromMap(v).Content
+		}
+	case string:
+		return v
+	}
+	return ""
+}
+
+// diffText computes a human readable diff of the two texts
+func diffText(oldText, newText string) []TextDiff {
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffCleanupSemantic(dmp.DiffMain(oldText, newText, false))
+	result := make([]TextDiff, len(diffs))
+	for i, d := range diffs {
+		result[i].Text = d.Text
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			result[i].Operation = TextDiffInsert
+		case diffmatchpatch.DiffDelete:
+			result[i].Operation = TextDiffDelete
+		default:
+			result[i].Operation = TextDiffEqual
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package workitem_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/resource"
+	"github.com/fabric8io/almighty-core/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisionChanges(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	wit := &workitem.WorkItemType{
+		Fields: map[string]workitem.FieldDefinition{
+			workitem.SystemTitle:       {Type: workitem.SimpleType{Kind: workitem.KindString}},
+			workitem.SystemState:       {Type: workitem.SimpleType{Kind: workitem.KindString}},
+			workitem.SystemDescription: {Type: workitem.SimpleType{Kind: workitem.KindMarkup}},
+			"due":                      {Type: workitem.SimpleType{Kind: workitem.KindDate}},
+		},
+	}
+	created := workitem.Revision{
+		Type: workitem.RevisionTypeCreate,
+		WorkItemFields: workitem.Fields{
+			workitem.SystemTitle:       "Title",
+			workitem.SystemState:       workitem.SystemStateNew,
+			workitem.SystemDescription: map[string]interface{}{rendering.ContentKey: "the quick fox", rendering.MarkupKey: rendering.SystemMarkupPlainText},
+		},
+	}
+
+	t.Run("first revision", func(t *testing.T) {
+		changes := created.Changes(nil, wit)
+		require.Len(t, changes, 3)
+		assert.Equal(t, workitem.SystemDescription, changes[0].Name)
+		assert.Equal(t, "", changes[0].OldValue)
+		assert.Equal(t, "the quick fox", changes[0].NewValue)
+		assert.Equal(t, workitem.SystemState, changes[1].Name)
+		assert.Nil(t, changes[1].OldValue)
+		assert.Equal(t, workitem.SystemStateNew, changes[1].NewValue)
+		assert.Equal(t, workitem.SystemTitle, changes[2].Name)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updated := workitem.Revision{
+			Type: workitem.RevisionTypeUpdate,
+			WorkItemFields: workitem.Fields{
+				workitem.SystemTitle:       "Title",
+				workitem.SystemState:       workitem.SystemStateOpen,
+				workitem.SystemDescription: map[string]interface{}{rendering.ContentKey: "the quick brown fox", rendering.MarkupKey: rendering.SystemMarkupPlainText},
+			},
+		}
+		changes := updated.Changes(&created, wit)
+		require.Len(t, changes, 2)
+		assert.Equal(t, workitem.SystemDescription, changes[0].Name)
+		assert.Equal(t, []workitem.TextDiff{
+			{Operation: workitem.TextDiffEqual, Text: "the quick "},
+			{Operation: workitem.TextDiffInsert, Text: "brown "},
+			{Operation: workitem.TextDiffEqual, Text: "fox"},
+		}, changes[0].TextDiff)
+		assert.Equal(t, workitem.FieldChange{Name: workitem.SystemState, OldValue: workitem.SystemStateNew, NewValue: workitem.SystemStateOpen}, changes[1])
+	})
+
+	t.Run("converted values", func(t *testing.T) {
+		due := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+		updated := workitem.Revision{Type: workitem.RevisionTypeUpdate, WorkItemFields: workitem.Fields{}}
+		for name, value := range created.WorkItemFields {
+			updated.WorkItemFields[name] = value
+		}
+		// dates are stored as nanoseconds, which are decoded as floats
+		updated.WorkItemFields["due"] = float64(due.UnixNano())
+		changes := updated.Changes(&created, wit)
+		require.Len(t, changes, 1)
+		assert.Equal(t, workitem.FieldChange{Name: "due", OldValue: nil, NewValue: "2017-06-01"}, changes[0])
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleted := workitem.Revision{Type: workitem.RevisionTypeDelete, WorkItemFields: workitem.Fields{}}
+		assert.Empty(t, deleted.Changes(&created, wit))
+	})
+}
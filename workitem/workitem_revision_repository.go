@@ -18,6 +18,9 @@ type RevisionRepository interface {
 	Create(ctx context.Context, modifierID uuid.UUID, revisionType RevisionType, workitem WorkItemStorage) error
 	// List retrieves all revisions for a given work item
 	List(ctx context.Context, workitemID string) ([]Revision, error)
+	// ListPage retrieves the revisions for a given work item, starting with start (zero-based)
+	// and returning at most limit revisions, along with the total number of revisions
+	ListPage(ctx context.Context, workitemID string, start int, limit int) ([]Revision, uint64, error)
 }
 
 // NewRevisionRepository creates a GormRevisionRepository
@@ -44,12 +47,26 @@ func (r *GormRevisionRepository) Create(ctx context.Context, modifierID uuid.UUI
 		Type:             revisionType,
 		WorkItemID:       workitem.ID,
 		WorkItemTypeID:   workitem.Type,
+		SpaceID:          workitem.SpaceID,
 		WorkItemVersion:  workitem.Version,
 		WorkItemFields:   workitem.Fields,
 	}
 	// do not store fields when the work item is deleted
 	if workitemRevision.Type == RevisionTypeDelete {
 		workitemRevision.WorkItemFields = Fields{}
+	} else {
+		checklist, err := checklistSnapshot(tx, workitem.ID)
+		if err != nil {
+			return errs.Wrap(err, "failed to load the checklist of the work item")
+		}
+		if checklist != nil {
+			fields := make(Fields, len(workitem.Fields)+1)
+			for name, value := range workitem.Fields {
+				fields[name] = value
+			}
+			fields[RevisionFieldChecklist] = checklist
+			workitemRevision.WorkItemFields = fields
+		}
 	}
 	if err := tx.Create(&workitemRevision).Error; err != nil {
 		return errors.NewInternalError(errs.Wrap(err, "failed to create new work item revision"))
@@ -67,3 +84,25 @@ func (r *GormRevisionRepository) List(ctx context.Context, workitemID string) ([
 	}
 	return revisions, nil
 }
+
+// ListPage retrieves the revisions for a given work item, starting with start (zero-based)
+// and returning at most limit revisions, along with the total number of revisions
+func (r *GormRevisionRepository) ListPage(ctx context.Context, workitemID string, start int, limit int) ([]Revision, uint64, error) {
+	log.Debug(ctx, map[string]interface{}{"wi_id": workitemID, "start": start, "limit": limit}, "List revisions for work item")
+	if start < 0 {
+		return nil, 0, errors.NewBadParameterError("start", start)
+	}
+	if limit <= 0 {
+		return nil, 0, errors.NewBadParameterError("limit", limit)
+	}
+	db := r.db.Model(&Revision{}).Where("work_item_id = ?", workitemID)
+	var count uint64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to count work item revisions"))
+	}
+	revisions := make([]Revision, 0)
+	if err := db.Order("revision_time asc").Offset(start).Limit(limit).Find(&revisions).Error; err != nil {
+		return nil, 0, errors.NewInternalError(errs.Wrap(err, "failed to retrieve work item revisions"))
+	}
+	return revisions, count, nil
+}
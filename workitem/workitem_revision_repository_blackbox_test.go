@@ -140,3 +140,32 @@ func (s *workItemRevisionRepositoryBlackBoxTest) TestStoreRevisions() {
 	assert.Equal(s.T(), s.testIdentity3.ID, revision4.ModifierIdentity)
 	require.Empty(s.T(), revision4.WorkItemFields)
 }
+
+func (s *workItemRevisionRepositoryBlackBoxTest) TestListRevisionPage() {
+	ctx := context.Background()
+	// given
+	workItem, err := s.repository.Create(
+		ctx, space.SystemSpace, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "Title",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, s.testIdentity1.ID)
+	require.Nil(s.T(), err)
+	for _, state := range []string{workitem.SystemStateOpen, workitem.SystemStateInProgress, workitem.SystemStateResolved} {
+		workItem.Fields[workitem.SystemState] = state
+		workItem, err = s.repository.Save(ctx, space.SystemSpace, *workItem, s.testIdentity2.ID)
+		require.Nil(s.T(), err)
+	}
+	// when
+	revisions, count, err := s.revisionRepository.ListPage(ctx, workItem.ID, 1, 2)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), uint64(4), count)
+	require.Len(s.T(), revisions, 2)
+	assert.Equal(s.T(), workitem.SystemStateOpen, revisions[0].WorkItemFields[workitem.SystemState])
+	assert.Equal(s.T(), workitem.SystemStateInProgress, revisions[1].WorkItemFields[workitem.SystemState])
+	// when
+	_, _, err = s.revisionRepository.ListPage(ctx, workItem.ID, 0, 0)
+	// then
+	require.NotNil(s.T(), err)
+}
@@ -1,6 +1,7 @@
 package workitem
 
 import (
+	"regexp"
 	"strconv"
 
 	"github.com/fabric8io/almighty-core/convert"
@@ -24,6 +25,8 @@ type WorkItemStorage struct {
 	ExecutionOrder float64
 	// Reference to one Space
 	SpaceID uuid.UUID `sql:"type:uuid"`
+	// the number of the work item within its space
+	Number int
 }
 
 const (
@@ -64,6 +67,9 @@ func (wi WorkItemStorage) Equal(u convert.Equaler) bool {
 	if wi.SpaceID != other.SpaceID {
 		return false
 	}
+	if wi.Number != other.Number {
+		return false
+	}
 	return wi.Fields.Equal(other.Fields)
 }
 
@@ -75,3 +81,22 @@ func ParseWorkItemIDToUint64(wiIDStr string) (uint64, error) {
 	}
 	return wiID, nil
 }
+
+// workItemKeyRegex matches the human-friendly IDs of work items which consist
+// of the key of the space and the number of the work item, e.g. PLAT-123
+var workItemKeyRegex = regexp.MustCompile(`^([A-Z][A-Z0-9]{1,9})-([1-9][0-9]*)$`)
+
+// ParseWorkItemKey splits a human-friendly work item ID like PLAT-123 into the
+// key of the space and the number of the work item. The last result is false
+// if the given string is not such an ID.
+func ParseWorkItemKey(wiKeyStr string) (string, int, bool) {
+	match := workItemKeyRegex.FindStringSubmatch(wiKeyStr)
+	if match == nil {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(match[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return match[1], number, true
+}
@@ -27,10 +27,18 @@ const (
 	SystemCreator             = "system.creator"
 	SystemCreatedAt           = "system.created_at"
 	SystemUpdatedAt           = "system.updated_at"
+	SystemDeletedAt           = "system.deleted_at"
 	SystemOrder               = "system.order"
 	SystemIteration           = "system.iteration"
 	SystemArea                = "system.area"
 	SystemCodebase            = "system.codebase"
+	SystemLabels              = "system.labels"
+	// SystemChecklistOpen is not a field but can be used in filters: it is
+	// the number of checklist items of a work item which are not done
+	SystemChecklistOpen = "system.checklist.open"
+	// SystemVotes is not a field but can be used in filters and to sort: it
+	// is the number of +1 reactions to a work item
+	SystemVotes = "system.votes"
 
 	SystemStateOpen       = "open"
 	SystemStateNew        = "new"
@@ -70,6 +78,8 @@ type WorkItemType struct {
 	Path string
 	// definitions of the fields this work item type supports
 	Fields FieldDefinitions `sql:"type:jsonb"`
+	// the optional workflow of the system.state field
+	Workflow Workflow `sql:"type:jsonb"`
 	// Reference to one Space
 	SpaceID uuid.UUID `sql:"type:uuid"`
 }
@@ -155,6 +165,9 @@ func (wit WorkItemType) Equal(u convert.Equaler) bool {
 			return false
 		}
 	}
+	if !wit.Workflow.Equal(other.Workflow) {
+		return false
+	}
 	if wit.SpaceID != other.SpaceID {
 		return false
 	}
@@ -169,6 +182,7 @@ func (wit WorkItemType) ConvertWorkItemStorageToModel(workItem WorkItemStorage)
 		Version: workItem.Version,
 		Fields:  map[string]interface{}{},
 		SpaceID: workItem.SpaceID,
+		Number:  workItem.Number,
 	}
 
 	for name, field := range wit.Fields {
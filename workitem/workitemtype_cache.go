@@ -38,6 +38,13 @@ func (c *WorkItemTypeCache) Put(wit WorkItemType) {
 	c.cache[wit.ID] = wit
 }
 
+// Remove removes the work item type with the given ID from the cache
+func (c *WorkItemTypeCache) Remove(id uuid.UUID) {
+	c.mapLock.Lock()
+	defer c.mapLock.Unlock()
+	delete(c.cache, id)
+}
+
 // Clear clears the cache
 func (c *WorkItemTypeCache) Clear() {
 	c.mapLock.Lock()
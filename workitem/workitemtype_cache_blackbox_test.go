@@ -55,6 +55,23 @@ func TestGetReturnNotOkAfterClear(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestGetReturnNotOkAfterRemove(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	c := workitem.NewWorkItemTypeCache()
+	removed := uuid.FromStringOrNil("1f7b4a6e-4f3c-4d2b-9a53-0c1e5d0f8a11")
+	kept := uuid.FromStringOrNil("6d2c9f3a-0b8e-4c1f-8e7d-2a4b6c8d0e22")
+	c.Put(workitem.WorkItemType{ID: removed, Name: "testRemove"})
+	c.Put(workitem.WorkItemType{ID: kept, Name: "testKeep"})
+
+	c.Remove(removed)
+	_, ok := c.Get(removed)
+	assert.False(t, ok)
+	_, ok = c.Get(kept)
+	assert.True(t, ok)
+}
+
 func TestNoFailuresWithConcurrentMapReadAndMapWrite(t *testing.T) {
 	t.Parallel()
 	resource.Require(t, resource.UnitTest)
@@ -2,12 +2,19 @@ package workitem
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"context"
 
 	"github.com/fabric8io/almighty-core/errors"
 	"github.com/fabric8io/almighty-core/log"
 	"github.com/fabric8io/almighty-core/path"
+	"github.com/fabric8io/almighty-core/rendering"
+	"github.com/fabric8io/almighty-core/space"
 
 	"github.com/jinzhu/gorm"
 	errs "github.com/pkg/errors"
@@ -19,9 +26,13 @@ var cache = NewWorkItemTypeCache()
 // WorkItemTypeRepository encapsulates storage & retrieval of work item types
 type WorkItemTypeRepository interface {
 	Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*WorkItemType, error)
+	LoadByID(ctx context.Context, id uuid.UUID) (*WorkItemType, error)
 	Create(ctx context.Context, spaceID uuid.UUID, id *uuid.UUID, extendedTypeID *uuid.UUID, name string, description *string, icon string, fields map[string]FieldDefinition) (*WorkItemType, error)
+	Update(ctx context.Context, spaceID uuid.UUID, wit WorkItemType, modifierID uuid.UUID, convertValues bool) (*WorkItemType, error)
 	List(ctx context.Context, spaceID uuid.UUID, start *int, length *int) ([]WorkItemType, error)
 	ListPlannerItems(ctx context.Context, spaceID uuid.UUID) ([]WorkItemType, error)
+	ListSubtypes(ctx context.Context, id uuid.UUID) ([]WorkItemType, error)
+	LoadForSpace(ctx context.Context, id uuid.UUID, spaceID uuid.UUID) (*WorkItemType, error)
 }
 
 // NewWorkItemTypeRepository creates a wi type repository based on gorm
@@ -109,7 +120,11 @@ func ClearGlobalWorkItemTypeCache() {
 	cache.Clear()
 }
 
-// Create creates a new work item in the repository
+// Create creates a new work item type in the repository. A type can extend a
+// type of its own space or of the system space, in which case it inherits the
+// fields and the workflow of the extended type. Inherited fields can be
+// redefined, e.g. to change their label, as long as their type and whether
+// they are required stay the same.
 // returns BadParameterError, ConversionError or InternalError
 func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UUID, id *uuid.UUID, extendedTypeID *uuid.UUID, name string, description *string, icon string, fields map[string]FieldDefinition) (*WorkItemType, error) {
 	// Make sure this WIT has an ID
@@ -120,6 +135,7 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 
 	allFields := map[string]FieldDefinition{}
 	path := LtreeSafeID(*id)
+	var workflow Workflow
 	if extendedTypeID != nil {
 		extendedType := WorkItemType{}
 		db := r.db.Model(&extendedType).Where("id=?", extendedTypeID).First(&extendedType)
@@ -129,17 +145,21 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 		if err := db.Error; err != nil {
 			return nil, errors.NewInternalError(err)
 		}
+		if !uuid.Equal(extendedType.SpaceID, spaceID) && !uuid.Equal(extendedType.SpaceID, space.SystemSpace) {
+			return nil, errors.NewBadParameterError("extendedTypeID", *extendedTypeID).Expected("work item type of the same space or of the system space")
+		}
 		// copy fields from extended type
 		for key, value := range extendedType.Fields {
 			allFields[key] = value
 		}
 		path = extendedType.Path + pathSep + path
+		workflow = extendedType.Workflow
 	}
 	// now process new fields, checking whether they are already there.
 	for field, definition := range fields {
 		existing, exists := allFields[field]
 		if exists && !compatibleFields(existing, definition) {
-			return nil, fmt.Errorf("incompatible change for field %s", field)
+			return nil, errors.NewBadParameterError("fields", field).Expected("same type and required flag as in the extended type")
 		}
 		allFields[field] = definition
 	}
@@ -152,6 +172,7 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 		Icon:        icon,
 		Path:        path,
 		Fields:      allFields,
+		Workflow:    workflow,
 		SpaceID:     spaceID,
 	}
 
@@ -163,11 +184,274 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 	return &created, nil
 }
 
-// List returns work item types that derives from PlannerItem type
+// Update replaces the name, description, icon and field definitions of the
+// given work item type. Version must be the same as the one in the stored
+// version. Fields can be added as long as they are optional, and the label and
+// description of existing fields can be changed; fields cannot be removed. A
+// change of the type of a field is only accepted if convertValues is true, in
+// which case the values of that field are converted for all work items of the
+// type. The workflow of the type is replaced as well. The previous definition
+// is kept as a revision.
+// The added fields, and the changes of the fields which the subtypes did not
+// redefine, are applied to the subtypes as well, with a revision of each.
+// Since concurrent readers may cache the previous definitions until the
+// transaction is committed, callers must clear the global work item type cache
+// once it ended, see ClearGlobalWorkItemTypeCache.
+// returns NotFoundError, VersionConflictError, BadParameterError, ConversionError or InternalError
+func (r *GormWorkItemTypeRepository) Update(ctx context.Context, spaceID uuid.UUID, witToUpdate WorkItemType, modifierID uuid.UUID, convertValues bool) (*WorkItemType, error) {
+	existing := WorkItemType{}
+	db := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id=? AND space_id=?", witToUpdate.ID, spaceID).First(&existing)
+	if db.RecordNotFound() {
+		return nil, errors.NewNotFoundError("work item type", witToUpdate.ID.String())
+	}
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	if existing.Version != witToUpdate.Version {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	if strings.TrimSpace(witToUpdate.Name) == "" {
+		return nil, errors.NewBadParameterError("name", witToUpdate.Name).Expected("not empty")
+	}
+	// fields whose values need to be converted to a new type
+	converted := []string{}
+	for name, old := range existing.Fields {
+		definition, ok := witToUpdate.Fields[name]
+		if !ok {
+			return nil, errors.NewBadParameterError("fields", name).Expected("all fields of the existing work item type")
+		}
+		if compatibleFields(old, definition) {
+			continue
+		}
+		if old.Required != definition.Required {
+			return nil, errors.NewBadParameterError("fields."+name+".required", definition.Required).Expected(strconv.FormatBool(old.Required))
+		}
+		if !convertValues {
+			return nil, errors.NewBadParameterError("fields."+name+".type", definition.Type.GetKind()).Expected(fmt.Sprintf("%s or an explicit conversion of the existing values", old.Type.GetKind()))
+		}
+		converted = append(converted, name)
+	}
+	for name, definition := range witToUpdate.Fields {
+		if _, ok := existing.Fields[name]; !ok && definition.Required {
+			return nil, errors.NewBadParameterError("fields."+name+".required", true).Expected("new fields to be optional")
+		}
+	}
+	if err := witToUpdate.Workflow.Validate(witToUpdate.Fields); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	subtypes, err := r.ListSubtypes(ctx, existing.ID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	// keep the previous definition
+	revision := newTypeRevision(existing, modifierID)
+	if err := r.db.Create(&revision).Error; err != nil {
+		return nil, errors.NewInternalError(errs.Wrap(err, "failed to create new work item type revision"))
+	}
+	previous := existing
+	existing.Name = witToUpdate.Name
+	existing.Description = witToUpdate.Description
+	if witToUpdate.Icon != "" {
+		existing.Icon = witToUpdate.Icon
+	}
+	existing.Fields = witToUpdate.Fields
+	existing.Workflow = witToUpdate.Workflow
+	existing.Version = existing.Version + 1
+	if err := r.db.Save(&existing).Error; err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	if len(converted) > 0 {
+		sort.Strings(converted)
+		if err := r.convertFieldValues(ctx, previous, existing, converted, modifierID); err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
+	for _, subtype := range subtypes {
+		if err := r.inheritFields(ctx, subtype, previous, existing, modifierID); err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
+	cache.Remove(existing.ID)
+	log.Debug(ctx, map[string]interface{}{"wit_id": existing.ID}, "Work item type updated successfully!")
+	return &existing, nil
+}
+
+// inheritFields applies the changes of the fields of the given supertype to
+// the given subtype: the fields added to the supertype are added to the
+// subtype, and the fields which the subtype inherited unchanged are replaced
+// by their new definition, unless their type was converted.
+// returns BadParameterError or InternalError
+func (r *GormWorkItemTypeRepository) inheritFields(ctx context.Context, subtype, previous, supertype WorkItemType, modifierID uuid.UUID) error {
+	fields := map[string]FieldDefinition{}
+	for name, definition := range subtype.Fields {
+		fields[name] = definition
+	}
+	changed := false
+	for name, definition := range supertype.Fields {
+		current, exists := fields[name]
+		old, existed := previous.Fields[name]
+		switch {
+		case !exists:
+			fields[name] = definition
+		case !existed && !compatibleFields(current, definition):
+			return errors.NewBadParameterError("fields", name).Expected(fmt.Sprintf("same type and required flag as in the subtype %s", subtype.Name))
+		case existed && reflect.DeepEqual(current, old) && compatibleFields(old, definition):
+			fields[name] = definition
+		default:
+			continue
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	revision := newTypeRevision(subtype, modifierID)
+	if err := r.db.Create(&revision).Error; err != nil {
+		return errors.NewInternalError(errs.Wrap(err, "failed to create new work item type revision"))
+	}
+	subtype.Fields = fields
+	subtype.Version = subtype.Version + 1
+	if err := r.db.Save(&subtype).Error; err != nil {
+		return errors.NewInternalError(err)
+	}
+	cache.Remove(subtype.ID)
+	log.Debug(ctx, map[string]interface{}{"wit_id": subtype.ID, "supertype_id": supertype.ID}, "Fields of the supertype inherited")
+	return nil
+}
+
+// convertFieldValues converts the values of the given fields of all work
+// items of the given type from their old field type to their new one.
+func (r *GormWorkItemTypeRepository) convertFieldValues(ctx context.Context, oldType, newType WorkItemType, fieldNames []string, modifierID uuid.UUID) error {
+	var workItems []WorkItemStorage
+	if err := r.db.Unscoped().Where("type = ?", newType.ID).Find(&workItems).Error; err != nil {
+		return errors.NewInternalError(err)
+	}
+	revisionRepository := NewRevisionRepository(r.db)
+	for _, wi := range workItems {
+		changed := false
+		for _, name := range fieldNames {
+			value, ok := wi.Fields[name]
+			if !ok || value == nil {
+				continue
+			}
+			apiValue, err := oldType.Fields[name].Type.ConvertFromModel(value)
+			if err != nil {
+				return errors.NewConversionError(err.Error())
+			}
+			newValue, err := convertValueToFieldType(apiValue, newType.Fields[name].Type)
+			if err != nil {
+				return errors.NewConversionError(fmt.Sprintf("failed to convert the value of field %s of work item %d: %s", name, wi.ID, err.Error()))
+			}
+			wi.Fields[name] = newValue
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		wi.Version = wi.Version + 1
+		db := r.db.Unscoped().Model(&wi).Updates(map[string]interface{}{
+			"fields":  wi.Fields,
+			"version": wi.Version,
+		})
+		if db.Error != nil {
+			return errors.NewInternalError(db.Error)
+		}
+		if wi.DeletedAt == nil {
+			// store a revision of the converted work item
+			if err := revisionRepository.Create(ctx, modifierID, RevisionTypeUpdate, wi); err != nil {
+				return errs.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
+
+// convertValueToFieldType converts a field value as used in the REST API
+// layer to the persistence representation of the given field type. Values
+// which do not fit the field type directly are converted via their textual
+// representation.
+func convertValueToFieldType(value interface{}, fieldType FieldType) (interface{}, error) {
+	if result, err := fieldType.ConvertToModel(value); err == nil {
+		return result, nil
+	}
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case time.Time:
+		text = v.UTC().Format(time.RFC3339)
+	case rendering.MarkupContent:
+		text = v.Content
+	default:
+		text = fmt.Sprint(v)
+	}
+	var candidate interface{} = text
+	switch fieldType.GetKind() {
+	case KindInteger, KindDuration:
+		i, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			f, ferr := strconv.ParseFloat(strings.TrimSpace(text), 64)
+			if ferr != nil || f != float64(int(f)) {
+				return nil, errs.Errorf("value %v cannot be converted to %s", value, fieldType.GetKind())
+			}
+			i = int(f)
+		}
+		candidate = i
+	case KindFloat:
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, errs.Errorf("value %v cannot be converted to %s", value, fieldType.GetKind())
+		}
+		candidate = f
+	case KindInstant:
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, errs.Errorf("value %v cannot be converted to %s", value, fieldType.GetKind())
+		}
+		candidate = t
+	case KindDate:
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(text)); err == nil {
+			candidate = t
+		}
+	case KindBoolean:
+		b, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return nil, errs.Errorf("value %v cannot be converted to %s", value, fieldType.GetKind())
+		}
+		candidate = b
+	case KindMarkup:
+		candidate = rendering.NewMarkupContentFromLegacy(text)
+	}
+	return fieldType.ConvertToModel(candidate)
+}
+
+// LoadTypeAsOf returns the definition of the work item type with the given id
+// which was valid at the given time
+func (r *GormWorkItemTypeRepository) LoadTypeAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*WorkItemType, error) {
+	current, err := r.LoadTypeFromDB(ctx, id)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	// the oldest revision replaced after the given time holds the definition
+	// which was valid at that time
+	revision := TypeRevision{}
+	db := r.db.Where("work_item_type_id = ? AND revision_time > ?", id, asOf).Order("revision_time asc").First(&revision)
+	if db.RecordNotFound() {
+		return current, nil
+	}
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	result := revision.WorkItemType(*current)
+	return &result, nil
+}
+
+// ListPlannerItems returns work item types of the given space and of the
+// system space that derive from PlannerItem type
 func (r *GormWorkItemTypeRepository) ListPlannerItems(ctx context.Context, spaceID uuid.UUID) ([]WorkItemType, error) {
 	var rows []WorkItemType
 	path := path.Path{}
-	db := r.db.Select("id").Where("space_id = ? AND path::text LIKE '"+path.ConvertToLtree(SystemPlannerItem)+".%'", spaceID.String())
+	db := r.db.Select("id").Where("space_id IN (?) AND path::text LIKE '"+path.ConvertToLtree(SystemPlannerItem)+".%'", spaceIDs(spaceID))
 
 	if err := db.Find(&rows).Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -179,13 +463,61 @@ func (r *GormWorkItemTypeRepository) ListPlannerItems(ctx context.Context, space
 	return rows, nil
 }
 
-// List returns work item types selected by the given criteria.Expression,
-// starting with start (zero-based) and returning at most "limit" item types.
+// ListSubtypes returns the work item types of all spaces that extend the
+// given type, directly or indirectly
+func (r *GormWorkItemTypeRepository) ListSubtypes(ctx context.Context, id uuid.UUID) ([]WorkItemType, error) {
+	var rows []WorkItemType
+	tableName := WorkItemType{}.TableName()
+	db := r.db.Where(fmt.Sprintf("id != ? AND path <@ (select supertype.path from %s supertype where supertype.id = ?)", tableName), id, id)
+	if err := db.Find(&rows).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wit_id": id,
+			"err":    err,
+		}, "unable to list the subtypes of the work item type")
+		return nil, errors.NewInternalError(err)
+	}
+	return rows, nil
+}
+
+// LoadForSpace returns the work item type with the given ID if it is available
+// in the given space, or else the type of the space with the same name
+// returns BadParameterError if there is no such type, or InternalError
+func (r *GormWorkItemTypeRepository) LoadForSpace(ctx context.Context, id uuid.UUID, spaceID uuid.UUID) (*WorkItemType, error) {
+	wiType, err := r.LoadTypeFromDB(ctx, id)
+	if err != nil {
+		return nil, errors.NewBadParameterError("typeID", id)
+	}
+	if uuid.Equal(wiType.SpaceID, spaceID) || uuid.Equal(wiType.SpaceID, space.SystemSpace) {
+		return wiType, nil
+	}
+	candidate := WorkItemType{}
+	db := r.db.Where("space_id = ? AND name = ?", spaceID, wiType.Name).First(&candidate)
+	if db.RecordNotFound() {
+		return nil, errors.NewBadParameterError("typeID", id).Expected("work item type which is available in the target space")
+	}
+	if db.Error != nil {
+		return nil, errors.NewInternalError(db.Error)
+	}
+	return r.LoadTypeFromDB(ctx, candidate.ID)
+}
+
+// spaceIDs returns the IDs of the spaces whose work item types can be used in
+// the given space: the space itself and the system space
+func spaceIDs(spaceID uuid.UUID) []uuid.UUID {
+	if uuid.Equal(spaceID, space.SystemSpace) {
+		return []uuid.UUID{spaceID}
+	}
+	return []uuid.UUID{spaceID, space.SystemSpace}
+}
+
+// List returns the work item types of the given space and of the system
+// space, starting with start (zero-based) and returning at most "limit" item
+// types. The types of the space come first.
 func (r *GormWorkItemTypeRepository) List(ctx context.Context, spaceID uuid.UUID, start *int, limit *int) ([]WorkItemType, error) {
 	// Currently we don't implement filtering here, so leave this empty
 	// TODO: (kwk) implement criteria parsing just like for work items
 	var rows []WorkItemType
-	db := r.db.Where("space_id = ?", spaceID)
+	db := r.db.Where("space_id IN (?)", spaceIDs(spaceID)).Order(fmt.Sprintf("space_id = '%s', created_at", space.SystemSpace))
 	if start != nil {
 		db = db.Offset(*start)
 	}
@@ -4,15 +4,20 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
+	"github.com/fabric8io/almighty-core/account"
+	"github.com/fabric8io/almighty-core/errors"
 	"github.com/fabric8io/almighty-core/gormsupport/cleaner"
 	"github.com/fabric8io/almighty-core/gormtestsupport"
 	"github.com/fabric8io/almighty-core/migration"
 	"github.com/fabric8io/almighty-core/space"
+	testsupport "github.com/fabric8io/almighty-core/test"
 	"github.com/fabric8io/almighty-core/workitem"
 
 	"context"
 	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -138,3 +143,264 @@ func (s *workItemTypeRepoBlackBoxTest) TestDoNotCreateWITWithMissingBaseType() {
 	require.NotNil(s.T(), err)
 	require.Nil(s.T(), extendedWit)
 }
+
+func (s *workItemTypeRepoBlackBoxTest) createTypeForUpdate() (*workitem.WorkItemType, account.Identity) {
+	testIdentity, err := testsupport.CreateTestIdentity(s.DB, "TestUpdateWIT user", "test provider")
+	require.Nil(s.T(), err)
+	wit, err := s.repo.Create(s.ctx, space.SystemSpace, nil, nil, "foo_bar", nil, "fa-bomb", map[string]workitem.FieldDefinition{
+		workitem.SystemTitle: {
+			Required: true,
+			Label:    "Title",
+			Type:     &workitem.SimpleType{Kind: workitem.KindString},
+		},
+		"points": {
+			Label: "Points",
+			Type:  &workitem.SimpleType{Kind: workitem.KindString},
+		},
+	})
+	require.Nil(s.T(), err)
+	return wit, testIdentity
+}
+
+func (s *workItemTypeRepoBlackBoxTest) TestUpdateWIT() {
+	s.T().Run("add field and change label", func(t *testing.T) {
+		// given
+		wit, testIdentity := s.createTypeForUpdate()
+		_, err := s.repo.Load(s.ctx, space.SystemSpace, wit.ID) // fill the cache
+		require.Nil(t, err)
+		// when
+		wit.Fields["points"] = workitem.FieldDefinition{Label: "Story Points", Type: wit.Fields["points"].Type}
+		wit.Fields["effort"] = workitem.FieldDefinition{Label: "Effort", Type: &workitem.SimpleType{Kind: workitem.KindFloat}}
+		updated, err := s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, false)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, wit.Version+1, updated.Version)
+		loaded, err := s.repo.Load(s.ctx, space.SystemSpace, wit.ID)
+		require.Nil(t, err)
+		assert.Equal(t, "Story Points", loaded.Fields["points"].Label)
+		assert.Equal(t, workitem.KindFloat, loaded.Fields["effort"].Type.GetKind())
+	})
+
+	s.T().Run("add enum value", func(t *testing.T) {
+		// given
+		wit, testIdentity := s.createTypeForUpdate()
+		enumType := func(values ...interface{}) workitem.EnumType {
+			return workitem.EnumType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+				BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+				Values:     values,
+			}
+		}
+		wit.Fields["priority"] = workitem.FieldDefinition{Label: "Priority", Type: enumType("low", "high")}
+		wit, err := s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, false)
+		require.Nil(t, err)
+		// when
+		wit.Fields["priority"] = workitem.FieldDefinition{Label: "Priority", Type: enumType("low", "medium", "high")}
+		_, err = s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, false)
+		// then
+		require.Nil(t, err)
+		loaded, err := s.repo.Load(s.ctx, space.SystemSpace, wit.ID)
+		require.Nil(t, err)
+		assert.Equal(t, []interface{}{"low", "medium", "high"}, loaded.Fields["priority"].Type.(workitem.EnumType).Values)
+	})
+
+	s.T().Run("add field to supertype", func(t *testing.T) {
+		// given
+		wit, testIdentity := s.createTypeForUpdate()
+		subtype, err := s.repo.Create(s.ctx, space.SystemSpace, nil, &wit.ID, "foo_bar_sub", nil, "fa-bomb", map[string]workitem.FieldDefinition{})
+		require.Nil(t, err)
+		// when
+		wit.Fields["points"] = workitem.FieldDefinition{Label: "Story Points", Type: wit.Fields["points"].Type}
+		wit.Fields["effort"] = workitem.FieldDefinition{Label: "Effort", Type: &workitem.SimpleType{Kind: workitem.KindFloat}}
+		_, err = s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, false)
+		// then
+		require.Nil(t, err)
+		loaded, err := s.repo.Load(s.ctx, space.SystemSpace, subtype.ID)
+		require.Nil(t, err)
+		assert.Equal(t, subtype.Version+1, loaded.Version)
+		assert.Equal(t, "Story Points", loaded.Fields["points"].Label)
+		assert.Equal(t, workitem.KindFloat, loaded.Fields["effort"].Type.GetKind())
+	})
+
+	s.T().Run("version conflict", func(t *testing.T) {
+		// given
+		wit, testIdentity := s.createTypeForUpdate()
+		wit.Version = wit.Version + 1
+		// when
+		_, err := s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, false)
+		// then
+		require.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+
+	s.T().Run("remove field", func(t *testing.T) {
+		// given
+		wit, testIdentity := s.createTypeForUpdate()
+		delete(wit.Fields, "points")
+		// when
+		_, err := s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, false)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("add required field", func(t *testing.T) {
+		// given
+		wit, testIdentity := s.createTypeForUpdate()
+		wit.Fields["owner"] = workitem.FieldDefinition{Required: true, Label: "Owner", Type: &workitem.SimpleType{Kind: workitem.KindString}}
+		// when
+		_, err := s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, false)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("change field type without conversion", func(t *testing.T) {
+		// given
+		wit, testIdentity := s.createTypeForUpdate()
+		wit.Fields["points"] = workitem.FieldDefinition{Label: "Points", Type: &workitem.SimpleType{Kind: workitem.KindInteger}}
+		// when
+		_, err := s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, false)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("change field type with conversion", func(t *testing.T) {
+		// given
+		wit, testIdentity := s.createTypeForUpdate()
+		wiRepo := workitem.NewWorkItemRepository(s.DB)
+		wi, err := wiRepo.Create(s.ctx, space.SystemSpace, wit.ID, map[string]interface{}{
+			workitem.SystemTitle: "Title",
+			"points":             " 5 ",
+		}, testIdentity.ID)
+		require.Nil(t, err)
+		wit.Fields["points"] = workitem.FieldDefinition{Label: "Points", Type: &workitem.SimpleType{Kind: workitem.KindInteger}}
+		// when
+		_, err = s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, true)
+		// then
+		require.Nil(t, err)
+		converted, err := wiRepo.Load(s.ctx, space.SystemSpace, wi.ID)
+		require.Nil(t, err)
+		assert.Equal(t, 5, converted.Fields["points"])
+		assert.Equal(t, wi.Version+1, converted.Version)
+	})
+
+	s.T().Run("change field type with failing conversion", func(t *testing.T) {
+		// given
+		wit, testIdentity := s.createTypeForUpdate()
+		wiRepo := workitem.NewWorkItemRepository(s.DB)
+		_, err := wiRepo.Create(s.ctx, space.SystemSpace, wit.ID, map[string]interface{}{
+			workitem.SystemTitle: "Title",
+			"points":             "many",
+		}, testIdentity.ID)
+		require.Nil(t, err)
+		wit.Fields["points"] = workitem.FieldDefinition{Label: "Points", Type: &workitem.SimpleType{Kind: workitem.KindInteger}}
+		// when
+		_, err = s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, true)
+		// then
+		require.IsType(t, errors.ConversionError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemTypeRepoBlackBoxTest) TestLoadTypeAsOf() {
+	// given
+	wit, testIdentity := s.createTypeForUpdate()
+	before := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	wit.Fields["points"] = workitem.FieldDefinition{Label: "Story Points", Type: wit.Fields["points"].Type}
+	_, err := s.repo.Update(s.ctx, space.SystemSpace, *wit, testIdentity.ID, false)
+	require.Nil(s.T(), err)
+	gormRepo := workitem.NewWorkItemTypeRepository(s.DB)
+
+	s.T().Run("before the update", func(t *testing.T) {
+		// when
+		old, err := gormRepo.LoadTypeAsOf(s.ctx, wit.ID, before)
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, "Points", old.Fields["points"].Label)
+		assert.Equal(t, wit.Version, old.Version)
+	})
+
+	s.T().Run("after the update", func(t *testing.T) {
+		// when
+		current, err := gormRepo.LoadTypeAsOf(s.ctx, wit.ID, time.Now())
+		// then
+		require.Nil(t, err)
+		assert.Equal(t, "Story Points", current.Fields["points"].Label)
+	})
+}
+
+func (s *workItemTypeRepoBlackBoxTest) TestCreateWITInSpace() {
+	// given a custom space and the planner item type of the system space
+	sp := space.Space{Name: "Inheritance " + uuid.NewV4().String()}
+	_, err := space.NewRepository(s.DB).Create(s.ctx, &sp)
+	require.Nil(s.T(), err)
+	plannerItem, err := s.repo.LoadByID(s.ctx, workitem.SystemPlannerItem)
+	require.Nil(s.T(), err)
+	titleDef := plannerItem.Fields[workitem.SystemTitle]
+	titleDef.Label = "Summary"
+
+	// when
+	wit, err := s.repo.Create(s.ctx, sp.ID, nil, &workitem.SystemPlannerItem, "incident", nil, "fa-fire", map[string]workitem.FieldDefinition{
+		workitem.SystemTitle: titleDef,
+		"severity": {
+			Label: "Severity",
+			Type:  &workitem.SimpleType{Kind: workitem.KindInteger},
+		},
+	})
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), "Summary", wit.Fields[workitem.SystemTitle].Label)
+	assert.Equal(s.T(), "Severity", wit.Fields["severity"].Label)
+	assert.Equal(s.T(), plannerItem.Fields[workitem.SystemState], wit.Fields[workitem.SystemState])
+	assert.True(s.T(), wit.IsTypeOrSubtypeOf(workitem.SystemPlannerItem))
+
+	s.T().Run("list includes system types", func(t *testing.T) {
+		wits, err := s.repo.List(s.ctx, sp.ID, nil, nil)
+		require.Nil(t, err)
+		require.NotEmpty(t, wits)
+		assert.Equal(t, wit.ID, wits[0].ID)
+		ids := []uuid.UUID{}
+		for _, w := range wits {
+			ids = append(ids, w.ID)
+		}
+		assert.Contains(t, ids, workitem.SystemBug)
+	})
+
+	s.T().Run("list planner items", func(t *testing.T) {
+		wits, err := s.repo.ListPlannerItems(s.ctx, sp.ID)
+		require.Nil(t, err)
+		ids := []uuid.UUID{}
+		for _, w := range wits {
+			ids = append(ids, w.ID)
+		}
+		assert.Contains(t, ids, wit.ID)
+		assert.Contains(t, ids, workitem.SystemBug)
+	})
+
+	s.T().Run("list subtypes", func(t *testing.T) {
+		wits, err := s.repo.ListSubtypes(s.ctx, workitem.SystemPlannerItem)
+		require.Nil(t, err)
+		ids := []uuid.UUID{}
+		for _, w := range wits {
+			ids = append(ids, w.ID)
+		}
+		assert.Contains(t, ids, wit.ID)
+		assert.Contains(t, ids, workitem.SystemBug)
+		assert.NotContains(t, ids, workitem.SystemPlannerItem)
+	})
+
+	s.T().Run("incompatible field", func(t *testing.T) {
+		titleDef := plannerItem.Fields[workitem.SystemTitle]
+		titleDef.Type = &workitem.SimpleType{Kind: workitem.KindMarkup}
+		_, err := s.repo.Create(s.ctx, sp.ID, nil, &workitem.SystemPlannerItem, "broken", nil, "fa-fire", map[string]workitem.FieldDefinition{
+			workitem.SystemTitle: titleDef,
+		})
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("type of another space", func(t *testing.T) {
+		other := space.Space{Name: "Other " + uuid.NewV4().String()}
+		_, err := space.NewRepository(s.DB).Create(s.ctx, &other)
+		require.Nil(t, err)
+		_, err = s.repo.Create(s.ctx, other.ID, nil, &wit.ID, "foreign", nil, "fa-fire", map[string]workitem.FieldDefinition{})
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
@@ -0,0 +1,70 @@
+package workitem
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// TypeRevision holds the definition of a work item type as it was before an
+// update of the type. It is used to interpret the historical versions of the
+// work items of that type.
+type TypeRevision struct {
+	ID uuid.UUID `gorm:"primary_key"`
+	// the timestamp of the update which replaced this definition
+	Time time.Time `gorm:"column:revision_time"`
+	// the identity of the author of the update
+	ModifierIdentity uuid.UUID `sql:"type:uuid" gorm:"column:modifier_id"`
+	// the id of the work item type that changed
+	WorkItemTypeID uuid.UUID `sql:"type:uuid" gorm:"column:work_item_type_id"`
+	// the version of the work item type before the update
+	WorkItemTypeVersion int `gorm:"column:work_item_type_version"`
+	// the name of the work item type before the update
+	WorkItemTypeName string `gorm:"column:work_item_type_name"`
+	// the description of the work item type before the update
+	WorkItemTypeDescription *string `gorm:"column:work_item_type_description"`
+	// the icon of the work item type before the update
+	WorkItemTypeIcon string `gorm:"column:work_item_type_icon"`
+	// the field definitions of the work item type before the update
+	WorkItemTypeFields FieldDefinitions `sql:"type:jsonb" gorm:"column:work_item_type_fields"`
+	// the workflow of the work item type before the update
+	WorkItemTypeWorkflow Workflow `sql:"type:jsonb" gorm:"column:work_item_type_workflow"`
+}
+
+const (
+	typeRevisionTableName = "work_item_type_revisions"
+)
+
+// TableName implements gorm.tabler
+func (r TypeRevision) TableName() string {
+	return typeRevisionTableName
+}
+
+// newTypeRevision returns a revision holding the current definition of the
+// given work item type
+func newTypeRevision(wit WorkItemType, modifierID uuid.UUID) TypeRevision {
+	return TypeRevision{
+		ID:                      uuid.NewV4(),
+		Time:                    time.Now(),
+		ModifierIdentity:        modifierID,
+		WorkItemTypeID:          wit.ID,
+		WorkItemTypeVersion:     wit.Version,
+		WorkItemTypeName:        wit.Name,
+		WorkItemTypeDescription: wit.Description,
+		WorkItemTypeIcon:        wit.Icon,
+		WorkItemTypeFields:      wit.Fields,
+		WorkItemTypeWorkflow:    wit.Workflow,
+	}
+}
+
+// WorkItemType returns the given work item type with the definition stored in
+// this revision
+func (r TypeRevision) WorkItemType(current WorkItemType) WorkItemType {
+	current.Version = r.WorkItemTypeVersion
+	current.Name = r.WorkItemTypeName
+	current.Description = r.WorkItemTypeDescription
+	current.Icon = r.WorkItemTypeIcon
+	current.Fields = r.WorkItemTypeFields
+	current.Workflow = r.WorkItemTypeWorkflow
+	return current
+}